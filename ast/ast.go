@@ -3,6 +3,7 @@ package ast
 import (
 	"bytes"
 	"strings"
+	"time"
 
 	"github.com/abs-lang/abs/token"
 )
@@ -229,6 +230,26 @@ func (nl *NumberLiteral) expressionNode()      {}
 func (nl *NumberLiteral) TokenLiteral() string { return nl.Token.Literal }
 func (nl *NumberLiteral) String() string       { return nl.Token.Literal }
 
+// 5s, 200ms
+type DurationLiteral struct {
+	Token token.Token
+	Value time.Duration
+}
+
+func (dl *DurationLiteral) expressionNode()      {}
+func (dl *DurationLiteral) TokenLiteral() string { return dl.Token.Literal }
+func (dl *DurationLiteral) String() string       { return dl.Token.Literal }
+
+// 10MB, 4KiB
+type SizeLiteral struct {
+	Token token.Token
+	Value int64 // bytes
+}
+
+func (sl *SizeLiteral) expressionNode()      {}
+func (sl *SizeLiteral) TokenLiteral() string { return sl.Token.Literal }
+func (sl *SizeLiteral) String() string       { return sl.Token.Literal }
+
 type PrefixExpression struct {
 	Token    token.Token // The prefix token, e.g. !
 	Operator string
@@ -269,6 +290,29 @@ func (ie *InfixExpression) String() string {
 	return out.String()
 }
 
+// 0 <= x < 10
+type ComparisonChain struct {
+	Token     token.Token // The first operator token, e.g. <=
+	Operands  []Expression
+	Operators []string
+}
+
+func (cc *ComparisonChain) expressionNode()      {}
+func (cc *ComparisonChain) TokenLiteral() string { return cc.Token.Literal }
+func (cc *ComparisonChain) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(cc.Operands[0].String())
+	for i, op := range cc.Operators {
+		out.WriteString(" " + op + " ")
+		out.WriteString(cc.Operands[i+1].String())
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
+
 type CompoundAssignment struct {
 	Token    token.Token // The operator token, e.g. +
 	Left     Expression
@@ -455,6 +499,7 @@ type FunctionLiteral struct {
 	Name       string      // identifier for this function
 	Parameters []*Parameter
 	Body       *BlockStatement
+	Doc        string // comment right above the function
 }
 
 func (fl *FunctionLiteral) expressionNode()      {}
@@ -574,6 +619,7 @@ type IndexExpression struct {
 	Index   Expression  // the left-most index eg. 1 in array[1] or array[1:10]
 	IsRange bool        // whether the expression is a range (1:10)
 	End     Expression  // the end of the range, if the expression is a range
+	Step    Expression  // the step of the range, if any eg. -1 in array[::-1]
 }
 
 func (ie *IndexExpression) expressionNode()      {}
@@ -598,6 +644,10 @@ func (ie *IndexExpression) String() string {
 			end = ie.End.String()
 		}
 		out.WriteString(start + ":" + end)
+
+		if ie.Step != nil {
+			out.WriteString(":" + ie.Step.String())
+		}
 	} else {
 		out.WriteString(ie.Index.String())
 	}
@@ -1,7 +1,11 @@
 package ast
 
 import (
+	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/abs-lang/abs/token"
 )
@@ -27,3 +31,93 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong. got=%q", program.String())
 	}
 }
+
+func TestTree(t *testing.T) {
+	program := &Program{
+		Statements: []Statement{
+			&AssignStatement{
+				Name: &Identifier{Value: "x"},
+				Value: &InfixExpression{
+					Operator: "+",
+					Left:     &NumberLiteral{Value: 1},
+					Right: &ArrayLiteral{Elements: []Expression{
+						&StringLiteral{Value: "a"},
+						&HashLiteral{Pairs: map[Expression]Expression{
+							&StringLiteral{Value: "k"}: &Boolean{Value: true},
+						}},
+					}},
+				},
+			},
+		},
+	}
+
+	expected := `Program
+  Statements[0]: AssignStatement
+    Name: Identifier Value="x"
+    Value: InfixExpression Operator="+"
+      Left: NumberLiteral Value=1
+      Right: ArrayLiteral
+        Elements[0]: StringLiteral Value="a"
+        Elements[1]: HashLiteral
+          Key: StringLiteral Value="k"
+            Value: Boolean Value=true`
+
+	if got := Tree(program); got != expected {
+		t.Errorf("Tree(program) wrong. got=\n%s", got)
+	}
+}
+
+func TestDump(t *testing.T) {
+	program := &Program{
+		Statements: []Statement{
+			&ExpressionStatement{
+				Expression: &InfixExpression{
+					Token:    token.Token{Type: token.PLUS, Literal: "+", Position: 2},
+					Operator: "+",
+					Left:     &NumberLiteral{Value: 1},
+					Right:    &DurationLiteral{Token: token.Token{Position: 4}, Value: 2 * time.Second},
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal(Dump(program))
+	if err != nil {
+		t.Fatalf("unable to encode the dump: %s", err)
+	}
+
+	expected := `{"Statements":[{"Expression":{"Left":{"Value":1,"position":0,"type":"NumberLiteral"},"Operator":"+","Right":{"Value":"2s","position":4,"type":"DurationLiteral"},"position":2,"type":"InfixExpression"},"position":0,"type":"ExpressionStatement"}],"type":"Program"}`
+	if string(b) != expected {
+		t.Errorf("Dump(program) wrong. got=%s", b)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	program := &Program{
+		Statements: []Statement{
+			&ExpressionStatement{
+				Expression: &CallExpression{
+					Function:  &Identifier{Value: "require"},
+					Arguments: []Expression{&StringLiteral{Value: "lib.abs"}},
+				},
+			},
+			&ReturnStatement{
+				ReturnValue: &InfixExpression{
+					Operator: "+",
+					Left:     &Identifier{Value: "x"},
+					Right:    &NumberLiteral{Value: 1},
+				},
+			},
+		},
+	}
+
+	visited := []string{}
+	Walk(program, func(node Node) {
+		visited = append(visited, reflect.TypeOf(node).Elem().Name())
+	})
+
+	expected := "Program ExpressionStatement CallExpression Identifier StringLiteral ReturnStatement InfixExpression Identifier NumberLiteral"
+	if got := strings.Join(visited, " "); got != expected {
+		t.Errorf("Walk(program) wrong. got=%s", got)
+	}
+}
@@ -0,0 +1,230 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abs-lang/abs/token"
+)
+
+var tokenType = reflect.TypeOf(token.Token{})
+
+// Tree (node)
+// Renders a node and its children as an indented
+// tree, one node per line, eg:
+//
+//	ExpressionStatement
+//	  Expression: InfixExpression Operator="+"
+//	    Left: NumberLiteral Value=1
+//	    Right: NumberLiteral Value=2
+func Tree(node Node) string {
+	lines := []string{}
+	writeTree(&lines, 0, "", reflect.ValueOf(node))
+
+	return strings.Join(lines, "\n")
+}
+
+// Dump (node)
+// Converts a node and its children into maps, slices
+// and values that can be encoded as JSON: every node
+// is a map holding its "type", the "position" of its
+// token in the source and its fields, eg.
+//
+//	{"type": "Identifier", "position": 0, "Value": "x"}
+func Dump(node Node) any {
+	return dumpValue(reflect.ValueOf(node))
+}
+
+// Walk (node, fn)
+// Calls fn for a node and, depth-first, for all
+// of its children, eg. to find all the calls
+// to a function within a program
+func Walk(node Node, fn func(Node)) {
+	walkValue(reflect.ValueOf(node), fn)
+}
+
+// Follows pointers and interfaces down
+// to the struct of a node, if any
+func nodeStruct(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+
+	return v, v.Kind() == reflect.Struct
+}
+
+// Calls fn for every exported field of a node but
+// its token, flattening embedded nodes (eg. the
+// Identifier of a Parameter)
+func eachField(v reflect.Value, fn func(name string, fv reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		fv := v.Field(i)
+
+		if !field.IsExported() || field.Type == tokenType {
+			continue
+		}
+
+		if field.Anonymous {
+			if embedded, ok := nodeStruct(fv); ok {
+				eachField(embedded, fn)
+			}
+			continue
+		}
+
+		fn(field.Name, fv)
+	}
+}
+
+// Map keys (eg. in a HashLiteral) sorted
+// by their source representation
+func sortedKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(a, b int) bool {
+		return keys[a].Interface().(Node).String() < keys[b].Interface().(Node).String()
+	})
+
+	return keys
+}
+
+func writeTree(lines *[]string, depth int, label string, v reflect.Value) {
+	v, ok := nodeStruct(v)
+	if !ok {
+		return
+	}
+
+	attrs := []string{v.Type().Name()}
+	children := []func(){}
+
+	eachField(v, func(name string, fv reflect.Value) {
+		switch fv.Kind() {
+		case reflect.Interface, reflect.Pointer:
+			children = append(children, func() { writeTree(lines, depth+1, name+": ", fv) })
+		case reflect.Slice:
+			if fv.Type().Elem().Kind() == reflect.String {
+				if fv.Len() > 0 {
+					attrs = append(attrs, fmt.Sprintf("%s=%q", name, fv.Interface()))
+				}
+				return
+			}
+
+			children = append(children, func() {
+				for j := 0; j < fv.Len(); j++ {
+					writeTree(lines, depth+1, fmt.Sprintf("%s[%d]: ", name, j), fv.Index(j))
+				}
+			})
+		case reflect.Map:
+			children = append(children, func() {
+				for _, k := range sortedKeys(fv) {
+					writeTree(lines, depth+1, "Key: ", k)
+					writeTree(lines, depth+2, "Value: ", fv.MapIndex(k))
+				}
+			})
+		case reflect.String:
+			if fv.String() != "" {
+				attrs = append(attrs, fmt.Sprintf("%s=%q", name, fv.String()))
+			}
+		default:
+			if !fv.IsZero() {
+				attrs = append(attrs, fmt.Sprintf("%s=%v", name, fv.Interface()))
+			}
+		}
+	})
+
+	*lines = append(*lines, strings.Repeat("  ", depth)+label+strings.Join(attrs, " "))
+
+	for _, child := range children {
+		child()
+	}
+}
+
+func dumpValue(v reflect.Value) any {
+	if v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		s, ok := nodeStruct(v)
+		if !ok {
+			return nil
+		}
+
+		return dumpNode(s)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return dumpNode(v)
+	case reflect.Slice:
+		items := []any{}
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, dumpValue(v.Index(i)))
+		}
+
+		return items
+	case reflect.Map:
+		pairs := []any{}
+		for _, k := range sortedKeys(v) {
+			pairs = append(pairs, map[string]any{"key": dumpValue(k), "value": dumpValue(v.MapIndex(k))})
+		}
+
+		return pairs
+	}
+
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+
+	return v.Interface()
+}
+
+func dumpNode(v reflect.Value) map[string]any {
+	node := map[string]any{"type": v.Type().Name()}
+
+	if f, ok := v.Type().FieldByName("Token"); ok && f.Type == tokenType {
+		if tok, err := v.FieldByIndexErr(f.Index); err == nil {
+			node["position"] = tok.Interface().(token.Token).Position
+		}
+	}
+
+	eachField(v, func(name string, fv reflect.Value) {
+		node[name] = dumpValue(fv)
+	})
+
+	return node
+}
+
+func walkValue(v reflect.Value, fn func(Node)) {
+	if v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+
+		if node, ok := v.Interface().(Node); ok {
+			fn(node)
+		}
+	}
+
+	v, ok := nodeStruct(v)
+	if !ok {
+		return
+	}
+
+	eachField(v, func(name string, fv reflect.Value) {
+		switch fv.Kind() {
+		case reflect.Interface, reflect.Pointer:
+			walkValue(fv, fn)
+		case reflect.Slice:
+			for i := 0; i < fv.Len(); i++ {
+				walkValue(fv.Index(i), fn)
+			}
+		case reflect.Map:
+			for _, k := range sortedKeys(fv) {
+				walkValue(k, fn)
+				walkValue(fv.MapIndex(k), fn)
+			}
+		}
+	})
+}
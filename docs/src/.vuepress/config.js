@@ -91,6 +91,8 @@ module.exports = {
           children: [
             'types/string',
             'types/number',
+            'types/bytes',
+            'types/duration-and-size',
             'types/array',
             'types/hash',
             'types/function',
@@ -106,6 +108,20 @@ module.exports = {
             'standard-lib/runtime',
             'standard-lib/cli',
             'standard-lib/util',
+            'standard-lib/secrets',
+            'standard-lib/clipboard',
+            'standard-lib/tty',
+            'standard-lib/ps',
+            'standard-lib/aws',
+            'standard-lib/metrics',
+            'standard-lib/fs',
+            'standard-lib/semver',
+            'standard-lib/humanize',
+            'standard-lib/gen',
+            'standard-lib/jwt',
+            'standard-lib/docker',
+            'standard-lib/git',
+            'standard-lib/webhook',
           ]
         },
         {
@@ -0,0 +1,90 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/abs-lang/abs/util"
+)
+
+// AuditRecord describes a command
+// that has been executed
+type AuditRecord struct {
+	Time     time.Time `json:"time"`
+	Command  string    `json:"command"`
+	Dir      string    `json:"cwd"`
+	Duration int64     `json:"duration_ms"`
+	ExitCode int       `json:"exit_code"`
+}
+
+// AuditFunc, when set, is called after every command
+// (eg. `ls -la`) is executed. Embedders usually set it
+// through runner.Options.
+var AuditFunc func(record AuditRecord)
+
+// File every command is appended to, from the
+// ABS_AUDIT_LOG env variable. Like the allow / deny
+// lists, it's read once so scripts can't change it.
+var auditLog = os.Getenv("ABS_AUDIT_LOG")
+var auditMux = &sync.Mutex{}
+
+// Records a command that has been executed,
+// once it's done. The audit func is passed explicitly
+// as background commands might finish after the
+// program (and its options) are gone.
+func auditCommand(c *exec.Cmd, cmd string, start time.Time, audit func(AuditRecord)) {
+	if auditLog == "" && audit == nil {
+		return
+	}
+
+	dir := c.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+
+	record := AuditRecord{
+		Time:     start,
+		Command:  cmd,
+		Dir:      dir,
+		Duration: time.Since(start).Milliseconds(),
+		ExitCode: c.ProcessState.ExitCode(),
+	}
+
+	if audit != nil {
+		audit(record)
+	}
+
+	if auditLog != "" {
+		if err := appendAuditLog(record); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to write to the audit log %s: %s\n", auditLog, err.Error())
+		}
+	}
+}
+
+func appendAuditLog(record AuditRecord) error {
+	auditMux.Lock()
+	defer auditMux.Unlock()
+
+	path, err := util.ExpandPath(auditLog)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
@@ -1,9 +1,18 @@
 package evaluator
 
 import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/abs-lang/abs/lexer"
 	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/parser"
+	"github.com/abs-lang/abs/util"
 )
 
 type Tests struct {
@@ -94,7 +103,8 @@ func TestLen(t *testing.T) {
 		{`len("")`, 0},
 		{`len("four")`, 4},
 		{`len("hello world")`, 11},
-		{`len(1)`, "argument 0 to len(...) is not supported (got: 1, allowed: STRING, ARRAY)"},
+		{`len(1)`, "argument 0 to len(...) is not supported (got: 1, allowed: STRING, ARRAY, BYTES)"},
+		{`len(bytes([0, 1, 255]))`, 3},
 		{`len("one", "two")`, "wrong number of arguments to len(...): got=2, want=1"},
 		{`len([1, 2, 3])`, 3},
 		{`len([])`, 0},
@@ -736,6 +746,71 @@ func TestPartition(t *testing.T) {
 	testBuiltinFunction(tests, t)
 }
 
+func TestPsListCommand(t *testing.T) {
+	records := []AuditRecord{}
+	AuditFunc = func(r AuditRecord) {
+		if strings.HasPrefix(r.Command, "ps ") {
+			records = append(records, r)
+		}
+	}
+	defer func() {
+		AuditFunc = nil
+		deniedCommands = []string{}
+	}()
+
+	testBuiltinFunction([]Tests{
+		{`ps_list().len() > 0`, true},
+		{`ABS_DRY_RUN = true; ps_list()`, []string{}},
+	}, t)
+
+	if len(records) != 1 || records[0].Command != "ps -A -o pid= -o pcpu= -o rss= -o args=" {
+		t.Errorf("expected ps to be audited, got %+v", records)
+	}
+
+	deniedCommands = []string{"ps"}
+	testBuiltinFunction([]Tests{
+		{`ps_list()`, "ps: command not allowed: 'ps' is in the list of denied commands"},
+	}, t)
+}
+
+func TestCheckpoint(t *testing.T) {
+	os.Setenv("ABS_CHECKPOINT_FILE", filepath.Join(t.TempDir(), "test.checkpoint"))
+	defer os.Unsetenv("ABS_CHECKPOINT_FILE")
+
+	tests := []Tests{
+		{`resume()`, nil},
+		{`checkpoint_clear()`, false},
+		{`a = 1; b = {"x": [1, "2", null, true]}; c = false; fn = f() {}; checkpoint("step-1")`, nil},
+		{`resume()`, "step-1"},
+		{`resume(); a`, 1},
+		{`resume(); b.x[1]`, "2"},
+		{`resume(); b.x[3]`, true},
+		{`resume(); b.x[3] == true`, true},
+		{`resume(); c == false`, true},
+		{`resume(); type(fn)`, "identifier not found: fn"},
+		{`f step() { c = 1; checkpoint("step-2") }; step(); resume()`, "step-2"},
+		{`checkpoint_clear()`, true},
+		{`resume()`, nil},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestSetDryRun(t *testing.T) {
+	defer os.Unsetenv("ABS_DRY_RUN")
+
+	tests := []Tests{
+		{"set_dry_run(true); r = `echo hello`; set_dry_run(false); r", ""},
+		{"set_dry_run(true); r = `exit 1`; set_dry_run(false); r.ok", true},
+		{"set_dry_run(true); set_dry_run(false); `echo hello`", "hello"},
+		{"ABS_DRY_RUN = true; `echo hello`", ""},
+		{"ABS_DRY_RUN = true; set_dry_run(false); `echo hello`", "hello"},
+		{"set_dry_run(1)", "argument 0 to set_dry_run(...) is not supported (got: 1, allowed: BOOLEAN)"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
 func testBuiltinFunction(tests []Tests, t *testing.T) {
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
@@ -808,3 +883,461 @@ func testBuiltinFunction(tests []Tests, t *testing.T) {
 		}
 	}
 }
+
+func TestBytes(t *testing.T) {
+	tests := []Tests{
+		{`type(bytes("abc"))`, "BYTES"},
+		{`bytes("abc").hex()`, "616263"},
+		{`bytes("abc").base64()`, "YWJj"},
+		{`bytes([0, 255, 16]).hex()`, "00ff10"},
+		{`bytes([256])`, "bytes(...) can only be built from numbers between 0 and 255, got 256"},
+		{`bytes(bytes("abc")).str()`, "abc"},
+		{"bytes(`printf 'a\\n\\n'`).hex()", "610a0a"},
+		{"`printf 'a\\n\\n'`.hex()", "cannot call method 'hex()' on 'STRING'"},
+		{`bytes("abc")[0]`, 97},
+		{`bytes("abc")[-1]`, 99},
+		{`bytes("abc")[5]`, nil},
+		{`bytes("abcd")[1:3].hex()`, "6263"},
+		{`bytes("abcd")[2:].str()`, "cd"},
+		{`bytes("abc") == bytes("abc")`, true},
+		{`bytes("abc") != bytes("abd")`, true},
+		{`(bytes("ab") + bytes("c")).str()`, "abc"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestDurationsAndSizes(t *testing.T) {
+	tests := []Tests{
+		{`type(5s)`, "DURATION"},
+		{`type(10MB)`, "SIZE"},
+		{`(1s + 500ms).str()`, "1500ms"},
+		{`(2 * 30s).str()`, "1min"},
+		{`(1h / 4).str()`, "15min"},
+		{`1h / 30min`, 2},
+		{`(-5s).str()`, "-5s"},
+		{`(10MB - 2MB).str()`, "8MB"},
+		{`(2KiB * 3).str()`, "6KiB"},
+		{`(1500 * 1KB).str()`, "1500KB"},
+		{`1KiB > 1KB`, true},
+		{`1min == 60s`, true},
+		{`200ms <=> 1s`, -1},
+		{`number(1.5s)`, 1500},
+		{`number(1KiB)`, 1024},
+		{`1s + 1`, "type mismatch: DURATION + NUMBER"},
+		{`1s + 1KB`, "type mismatch: DURATION + SIZE"},
+		{`1 / 1s`, "type mismatch: NUMBER / DURATION"},
+		{`1s / 0`, "division by zero"},
+		{`sleep(10ms)`, nil},
+		{`1m`, 1000000},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestFreeze(t *testing.T) {
+	tests := []Tests{
+		{`c = freeze({"a": 1}); c.a = 2`, "cannot modify a frozen hash"},
+		{`c = freeze({"a": 1}); c["b"] = 2`, "cannot modify a frozen hash"},
+		{`c = freeze({"a": 1}); c.a += 1`, "cannot modify a frozen hash"},
+		{`c = freeze({"a": {"b": [1]}}); c.a.b[0] = 2`, "cannot modify a frozen array"},
+		{`c = freeze({"a": {"b": [1]}}); c.a.b.push(2)`, "cannot modify a frozen array"},
+		{`c = freeze([1, 2]); c.pop()`, "cannot modify a frozen array"},
+		{`c = freeze([1, 2]); c.shift()`, "cannot modify a frozen array"},
+		{`c = freeze([1, 2]); c[0:1] = [3]`, "cannot modify a frozen array"},
+		{`c = freeze({"a": 1}); c.pop("a")`, "cannot modify a frozen hash"},
+		{`c = freeze({"a": 1}); c.a`, 1},
+		{`c = freeze([1, 2]); c.map(f(x) { x * 2 }).str()`, "[2, 4]"},
+		{`c = freeze({"a": 1}); d = c + {"b": 2}; [c.keys().len(), d.keys().len(), d.is_frozen()].str()`, "[1, 2, false]"},
+		{`c = freeze([1]); c += [2]; c.str()`, "[1, 2]"},
+		{`h = {"a": [1]}; c = freeze(h); h.a.push(2); [h.a.len(), c.a.len()].str()`, "[2, 1]"},
+		{`freeze({"a": 1}).is_frozen()`, true},
+		{`freeze({"a": [1]}).a.is_frozen()`, true},
+		{`[1].is_frozen()`, false},
+		{`freeze(1)`, 1},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestCache(t *testing.T) {
+	tests := []Tests{
+		{`c = cache(2); c.set("a", 1); c.get("a")`, 1},
+		{`c = cache(2); c.get("a")`, nil},
+		{`c = cache(2); c.get("a", "default")`, "default"},
+		{`c = cache(2); c.set(1, "one"); c.get(1)`, "one"},
+		{`c = cache(2); c.set("a", 1); c.set("b", 2); c.get("a"); c.set("c", 3); [c.get("a"), c.get("b"), c.get("c")].str()`, "[1, null, 3]"},
+		{`c = cache(2); c.set("a", 1); [c.delete("a"), c.delete("a"), c.get("a")].str()`, "[true, false, null]"},
+		{`c = cache(2); c.set("a", 1); c.clear(); c.get("a")`, nil},
+		{`c = cache(1, 20ms); c.set("a", 1); sleep(40); c.get("a")`, nil},
+		{`c = cache(1, 1h); c.set("a", 1); sleep(10); c.get("a")`, 1},
+		{`c = cache(2); c.set("a", 1); c.set("b", 2); c.set("c", 3); c.get("c"); c.get("a"); s = c.stats(); [s.size, s.hits, s.misses, s.evictions].str()`, "[2, 1, 1, 1]"},
+		{`cache(0)`, "cache(...) needs to hold at least 1 entry, got 0"},
+		{`c = cache(1); c.set([1], 1)`, "unusable as cache key: ARRAY"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestEvents(t *testing.T) {
+	tests := []Tests{
+		{`e = events(); got = []; e.on("done", f(x) { got.push(x) }); e.emit("done", 1); e.emit("done", 2); got.str()`, "[1, 2]"},
+		{`e = events(); e.on("a", f(x) { x }); e.on("a", f(x) { x }); e.emit("a", 1)`, 2},
+		{`e = events(); e.emit("nothing")`, 0},
+		{`e = events(); got = []; e.on("tick", f() { got.push(1) }); e.emit("tick"); got.len()`, 1},
+		{`e = events(); h = f(x) { x }; e.on("a", h); e.on("a", f(x) { x }); [e.off("a", h), e.emit("a", 1)].str()`, "[1, 1]"},
+		{`e = events(); e.on("a", f(x) { x }); e.off("a"); e.emit("a", 1)`, 0},
+		{`e = events(); e.on("b", f() {}); e.on("a", f() {}); e.events().str()`, `["a", "b"]`},
+		{`e = events(); e.on("a", f(x) { x.nope() }); e.emit("a", 1)`, "NUMBER does not have method 'nope()'"},
+		{`e = events(); e.emit(1)`, "emit(...) requires the name of the event as its first argument"},
+		{`e = events(); e.on("a", 1)`, "argument 1 to on(...) is not supported (got: 1, allowed: FUNCTION, BUILTIN)"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestLastCommand(t *testing.T) {
+	tests := []Tests{
+		{"`echo hello`; last_command().stdout", "hello"},
+		{"`echo hello`; last_command().cmd", "echo hello"},
+		{"`echo oops >&2; exit 3`; c = last_command(); [c.status, c.ok, c.stderr].str()", `[3, false, "oops"]`},
+		{"`true`; last_command().ok", true},
+		{"`sleep 0.02`; last_command().duration >= 20ms", true},
+		{"x = 'world'; `echo $x`; last_command().cmd", "echo world"},
+		{"`echo bg; exit 2 &`.wait(); c = last_command(); [c.cmd, c.status, c.stdout].str()", `["echo bg; exit 2", 2, "bg"]`},
+	}
+
+	testBuiltinFunction(tests, t)
+
+	// commands that can't even start
+	executor := os.Getenv("ABS_COMMAND_EXECUTOR")
+	os.Setenv("ABS_COMMAND_EXECUTOR", "/nonexistent/sh -c")
+	defer os.Setenv("ABS_COMMAND_EXECUTOR", executor)
+	tests = []Tests{
+		{"`echo hello`; c = last_command(); [c.cmd, c.status, c.ok].str()", `["echo hello", -1, false]`},
+		{"`echo hello`; last_command().stderr", "fork/exec /nonexistent/sh: no such file or directory"},
+		{"`echo hello &`; c = last_command(); [c.cmd, c.status, c.ok].str()", `["echo hello", -1, false]`},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestCommandTemplate(t *testing.T) {
+	tests := []Tests{
+		{`command("echo {x}", {"x": "it's; rm -rf /"})`, "it's; rm -rf /"},
+		{`command("echo {x}", {"x": "$(whoami)"})`, "$(whoami)"},
+		{`command("printf '%s,' {files}", {"files": ["a b", "c"]})`, "a b,c,"},
+		{`command("echo {a}{b}", {"a": 1, "b": 2})`, "12"},
+		{`command("echo {x} {y}", {"x": 1})`, "command(...) has no value for placeholder {y}"},
+		{`command("echo {x}", {"x": "\$HOME"})`, "$HOME"},
+		{`command("echo \${ABS_TEMPLATE_MISSING}x", {})`, "x"},
+		{`command(1, {})`, "argument 0 to command(...) is not supported"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestShellQuoting(t *testing.T) {
+	tests := []Tests{
+		{`"abc".shell_escape("posix")`, "abc"},
+		{`"it's here".shell_escape("posix")`, `'it'\''s here'`},
+		{`"a b".shell_escape("windows")`, `"a b"`},
+		{`"a".shell_escape("cmd")`, "shell_escape(...) supports either posix or windows quoting, got cmd"},
+		{`s = "it's; rm -rf /".shell_escape("posix"); ` + "`echo $s`", "it's; rm -rf /"},
+		{`"ls -la 'my dir' \"other dir\"".shell_split("posix").str()`, `["ls", "-la", "my dir", "other dir"]`},
+		{`"a\ b 'c'\"d\"".shell_split("posix").str()`, `["a b", "cd"]`},
+		{`'"C:\Program Files\abs" -v'.shell_split("windows")[0]`, `C:\Program Files\abs`},
+		{`'"C:\dir\\" -v'.shell_split("windows")[0]`, `C:\dir\`},
+		{`"'abc".shell_split("posix")`, "shell_split(...) is unable to split 'abc: unterminated single quote"},
+		{`"a b".shell_escape("posix").shell_split("posix").str()`, `["a b"]`},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestHashUtilities(t *testing.T) {
+	tests := []Tests{
+		{`{"a": "x", "b": "y"}.invert().str()`, `{"x": "a", "y": "b"}`},
+		{`{"a": 1, "b": true}.invert().str()`, `{"1": "a", "true": "b"}`},
+		{`{"a": [1]}.invert()`, "invert(...) can only use strings, numbers and booleans as keys, got ARRAY"},
+		{`{"a": 1, "b": 2, "c": 3}.pick(["a", "c", "z"]).str()`, `{"a": 1, "c": 3}`},
+		{`{"a": 1, "b": 2, "c": 3}.omit(["a", "c"]).str()`, `{"b": 2}`},
+		{`{"1": "one"}.pick([1]).str()`, `{"1": "one"}`},
+		{`{"a": 1}.pick([[1]])`, "pick(...) requires an array of keys, got ARRAY"},
+		{`h = {"a": 1}; h.omit(["a"]); h.str()`, `{"a": 1}`},
+		{`{"a": 1, "b": 2}.map_values(f(v) { v * 10 }).str()`, `{"a": 10, "b": 20}`},
+		{`{"a": 1}.map_values(f(v, k) { k + v.str() }).str()`, `{"a": "a1"}`},
+		{`{"a": 1}.map_values(f(v) { v.nope() })`, "NUMBER does not have method 'nope()'"},
+		{`{"b": 2, "a": 1}.entries().str()`, `[["a", 1], ["b", 2]]`},
+		{`{}.entries().str()`, `[]`},
+		{`[["a", 1], ["b", 2]].from_entries().str()`, `{"a": 1, "b": 2}`},
+		{`[[1, "one"]].from_entries()["1"]`, "one"},
+		{`[["a", 1]].from_entries().entries().from_entries().str()`, `{"a": 1}`},
+		{`[["a"]].from_entries()`, `from_entries(...) requires an array of [key, value] pairs, got ["a"]`},
+		{`[[{}, 1]].from_entries()`, "from_entries(...) can only use strings, numbers and booleans as keys, got HASH"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestArrayStatistics(t *testing.T) {
+	tests := []Tests{
+		{`[1, "2", " 3\n"].sum()`, 6},
+		{`["1.5", "2.5"].max()`, 2.5},
+		{`[1s, 500ms].sum().str()`, "1500ms"},
+		{`[1KB, 3KB].max().str()`, "3KB"},
+		{`[1s, 1].sum()`, "sum(...) can only be called on an homogeneous array, got [1000, 1]"},
+		{`[1, "abc"].min()`, `min(...) can only be called on an homogeneous array, got [1, "abc"]`},
+		{`["a", "b"].min()`, `min(...) can only be called on arrays of numbers, got ["a", "b"]`},
+		{`[].avg()`, nil},
+		{`[1, 2, 3, 4].avg()`, 2.5},
+		{`[2s, 4s].avg().str()`, "3s"},
+		{`[].median()`, nil},
+		{`[3, 1, 2].median()`, 2},
+		{`[4, 1, 3, 2].median()`, 2.5},
+		{`["10", 30, "20"].median()`, 20},
+		{`[].percentile(95)`, nil},
+		{`[1, 2, 3, 4, 5].percentile(0)`, 1},
+		{`[1, 2, 3, 4, 5].percentile(100)`, 5},
+		{`[1, 2, 3, 4, 5].percentile(90)`, 4.6},
+		{`[5].percentile(99)`, 5},
+		{`[100ms, 200ms, 300ms].percentile(50).str()`, "200ms"},
+		{`[1].percentile(101)`, "percentile(...) requires a percentile between 0 and 100, got 101"},
+		{`[null].avg()`, "avg(...) can only be called on arrays of numbers, got [null]"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestEachLine(t *testing.T) {
+	tests := []Tests{
+		{"lines = []; `printf 'a\\nb\\n'`.each_line(f(l) { lines.push(l) }); lines.str()", `["a", "b"]`},
+		{"lines = []; `pwd`.cwd('/tmp').each_line(f(l) { lines.push(l) }); lines.str()", `["/tmp"]`},
+		{"lines = []; r = `yes`.each_line(f(l) { lines.push(l); lines.len() < 3 }); [lines.len(), r.ok].str()", "[3, true]"},
+		{"`echo a; exit 2`.each_line(f(l) { l }).ok", false},
+		{"`echo a`.each_line(f(l) { l.nope() })", "STRING does not have method 'nope()'"},
+		{"`echo a`.each_line(f(l) { l }).cwd('/tmp')", "each_line(...) needs to be the last method called on a command"},
+		{"`echo a &`.each_line(f(l) { l })", "each_line(...) cannot be used on background commands"},
+		{"`echo a`.each_line(1)", "argument 0 to each_line(...) is not supported"},
+		{`lines = []; "a\nb\n".each_line(f(l) { lines.push(l) }); lines.str()`, `["a", "b"]`},
+		{`lines = []; "a\nb\nc".each_line(f(l) { lines.push(l); l != "b" }); lines.str()`, `["a", "b"]`},
+		{`lines = []; "".each_line(f(l) { lines.push(l) }); lines.len()`, 0},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestWithTimeout(t *testing.T) {
+	tests := []Tests{
+		{`with_timeout(1s, f() { 42 })`, 42},
+		{`with_timeout(1000, f() { "done" })`, "done"},
+		{`with_timeout(50ms, f() { while true { 1 } })`, "with_timeout(...) timed out after 50ms"},
+		{`with_timeout(50ms, f() { sleep(5s) })`, "with_timeout(...) timed out after 50ms"},
+		{"with_timeout(50ms, f() { `sleep 5; echo done` })", "with_timeout(...) timed out after 50ms"},
+		{`with_timeout(50ms, f() { with_timeout(5s, f() { sleep(5s) }) })`, "with_timeout(...) timed out after 50ms"},
+		{`with_timeout(5s, f() { with_timeout(50ms, f() { sleep(5s) }) })`, "with_timeout(...) timed out after 50ms"},
+		{`with_timeout(50ms, f() { 1 }); sleep(60ms); "still running"`, "still running"},
+		{`with_timeout("1s", f() { 1 })`, "argument 0 to with_timeout(...) is not supported"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestWithTimeoutConcurrent(t *testing.T) {
+	// The deadline only applies to the
+	// evaluation that set it
+	done := make(chan object.Object)
+	go func() { done <- testEval(`sleep(200ms); "still running"`) }()
+
+	timedOut := testEval(`with_timeout(20ms, f() { sleep(5s) })`)
+	if !strings.Contains(timedOut.Inspect(), "with_timeout(...) timed out after 20ms") {
+		t.Errorf("expected the evaluation to time out, got %q", timedOut.Inspect())
+	}
+
+	if res := <-done; res.Inspect() != "still running" {
+		t.Errorf("expected the other evaluation to keep running, got %q", res.Inspect())
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []Tests{
+		{`retry(f() { "ok" })`, "ok"},
+		{`calls = []; retry(f(n) { calls.push(n); if n < 3 { return 1.nope() }; "ok" }, {"delay": 1}); calls.str()`, "[1, 2, 3]"},
+		{`calls = []; retry(f(n) { calls.push(n); 1.nope() }, {"attempts": 2, "delay": 1ms})`, "NUMBER does not have method 'nope()'"},
+		{"calls = []; r = retry(f(n) { calls.push(n); `exit 1` }, {\"attempts\": 3, \"delay\": 1}); [calls.len(), r.ok].str()", "[3, false]"},
+		{"retry(f(n) { `test $n -eq 2 && echo done || exit 1` }, {\"delay\": 1})", "done"},
+		{`seen = []; retry(f(n) { if n == 1 { return 1.nope() }; n }, {"delay": 5ms, "on_retry": f(attempt, err, delay) { seen.push([attempt, delay.str()]) }}); seen.str()`, `[[1, "5ms"]]`},
+		{`seen = []; retry(f(n) { 1.nope() }, {"attempts": 4, "delay": 1ms, "on_retry": f(a, e, d) { seen.push(d.str()) }}); seen.str()`, "NUMBER does not have method 'nope()'"},
+		{`seen = []; retry(f(n) { if n < 4 { return 1.nope() } }, {"attempts": 4, "delay": 1ms, "on_retry": f(a, e, d) { seen.push(d.str()) }}); seen.str()`, `["1ms", "2ms", "4ms"]`},
+		{`seen = []; retry(f(n) { if n < 4 { return 1.nope() } }, {"attempts": 4, "delay": 1ms, "backoff": "linear", "on_retry": f(a, e, d) { seen.push(d.str()) }}); seen.str()`, `["1ms", "2ms", "3ms"]`},
+		{`seen = []; retry(f(n) { if n < 4 { return 1.nope() } }, {"attempts": 4, "delay": 1ms, "max_delay": 2ms, "on_retry": f(a, e, d) { seen.push(d.str()) }}); seen.str()`, `["1ms", "2ms", "2ms"]`},
+		{`seen = []; retry(f(n) { if n < 2 { return 1.nope() } }, {"delay": 10ms, "jitter": true, "on_retry": f(a, e, d) { seen.push((d >= 5ms && d <= 10ms).str()) }}); seen.str()`, `["true"]`},
+		{`retry(f() { 1 }, {"attempts": 0})`, "retry(...) option 'attempts' must be a number greater than 0, got 0"},
+		{`retry(f() { 1 }, {"backoff": "random"})`, "retry(...) option 'backoff' must be one of constant, linear or exponential, got random"},
+		{`retry(f() { 1 }, {"jitter": 2})`, "retry(...) option 'jitter' must be between 0 and 1, got 2"},
+		{`retry(f() { 1 }, {"tries": 2})`, "retry(...) doesn't support the option 'tries'"},
+		{`with_timeout(50ms, f() { retry(f() { 1.nope() }, {"attempts": 100, "delay": 1s}) })`, "with_timeout(...) timed out after 50ms"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestRateLimit(t *testing.T) {
+	tests := []Tests{
+		{`l = rate_limit(100); l.wait().str()`, "0s"},
+		{`l = rate_limit(50); s = unix_ms(); l.wait(); l.wait(); l.wait(); unix_ms() - s >= 35`, true},
+		{`l = rate_limit(50, 3); s = unix_ms(); l.wait(); l.wait(); l.wait(); unix_ms() - s < 15`, true},
+		{`l = rate_limit(50); l.wait(); l.wait() > 0s`, true},
+		{`l = rate_limit(1); [l.try(), l.try()].str()`, "[true, false]"},
+		{`l = rate_limit(100); l.try(); sleep(15); l.try()`, true},
+		{`l = rate_limit(0.5); l.wait(); with_timeout(50ms, f() { l.wait() })`, "with_timeout(...) timed out after 50ms"},
+		{`rate_limit(0)`, "rate_limit(...) requires a rate greater than 0, got 0"},
+		{`rate_limit(1, 0)`, "rate_limit(...) requires a burst of at least 1, got 0"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestParseTable(t *testing.T) {
+	tests := []Tests{
+		{`parse_table("NAME    READY   STATUS\nweb     1/1     Running\ndb      0/1     Pending").map(f(r) { r.NAME + ":" + r.STATUS }).str()`, `["web:Running", "db:Pending"]`},
+		{`parse_table("CONTAINER ID   IMAGE\n4c01db0b339c   nginx")[0]["CONTAINER ID"]`, "4c01db0b339c"},
+		{`parse_table("Filesystem  Use% Mounted on\n/dev/sda1    27% /")[0]["Mounted on"]`, "/"},
+		{`parse_table("1  init\n42  vim notes.txt", {"headers": ["pid", "cmd"]})[1].cmd`, "vim notes.txt"},
+		{`parse_table("NAME  SIZE\na     10\nb     20").map(f(r) { r.SIZE }).sum()`, 30},
+		{`parse_table("").len()`, 0},
+		{`parse_table("NAME").len()`, 0},
+		{`parse_table("a", {"headers": []})`, "parse_table(...) requires headers to be a non-empty array, got []"},
+		{`parse_table("a", {"cols": 1})`, "parse_table(...) doesn't support the option 'cols'"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestLocaleFormatting(t *testing.T) {
+	tests := []Tests{
+		{`format_number(1234567.891, {"locale": "en-US"})`, "1,234,567.891"},
+		{`format_number(1234567.891, {"locale": "de-DE", "decimals": 2})`, "1.234.567,89"},
+		{`format_number(1234.5, {"locale": "fr", "decimals": 1})`, "1\u202f234,5"},
+		{`format_number(1234.5, {"locale": "en-US", "decimals": 0})`, "1,235"},
+		{`format_number(-1234.5, {"locale": "en-US", "thousands_sep": "'", "decimals": 2})`, "-1'234.50"},
+		{`format_number(1234.5, {"locale": "xx"})`, "format_number(...) doesn't support the locale 'xx'"},
+		{`format_number(1, {"decimals": 1.5})`, "format_number(...) option 'decimals' must be a whole number greater than or equal to 0, got 1.5"},
+		{`format_number(1, {"precision": 1})`, "format_number(...) doesn't support the option 'precision'"},
+		{`format_date("2024-03-05T14:07:00Z", {"locale": "en-US", "utc": true})`, "Mar 5, 2024"},
+		{`format_date("2024-03-05T14:07:00Z", {"locale": "de-DE", "style": "full", "utc": true})`, "Dienstag, 5. März 2024"},
+		{`format_date("2024-03-05T14:07:00Z", {"locale": "en-GB", "style": "short", "time": true, "utc": true})`, "05/03/2024 14:07"},
+		{`format_date(1709647620000, {"locale": "es", "style": "long", "utc": true})`, "5 de marzo de 2024"},
+		{`format_date("2024-03-05T14:07:00+01:00", {"locale": "en-US", "time": true, "utc": true})`, "Mar 5, 2024 1:07 PM"},
+		{`format_date("2024-03-05", {"locale": "en-US"})`, "format_date(...) requires unix ms or an RFC 3339 time (eg. 2024-01-01T12:00:00Z), got 2024-03-05"},
+		{`format_date(0, {"style": "tiny"})`, "format_date(...) unknown date style 'tiny' (valid styles are short, medium, long and full)"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestMocks(t *testing.T) {
+	tests := []Tests{
+		{`reset_mocks(); mock_command("kubectl get *", "web-1 Running"); ` + "`kubectl get pods -n prod`", "web-1 Running"},
+		{`reset_mocks(); mock_command("kubectl *", "a"); mock_command("kubectl get *", "b"); ` + "`kubectl get pods`", "b"},
+		{`reset_mocks(); mock_command("false*", {"stderr": "boom", "exit_code": 2}); x = ` + "`false now`" + `; [x.ok, x, last_command().status].join(" ")`, "false boom 2"},
+		{`reset_mocks(); mock_command("deploy *", f(cmd) { cmd.split(" ")[1] + " deployed" }); ` + "`deploy web`", "web deployed"},
+		{`reset_mocks(); m = mock_command("deploy *", ""); ` + "`deploy web`; `deploy db`" + `; m.calls()`, []string{"deploy web", "deploy db"}},
+		{`reset_mocks(); m = mock_command("echo *", "mocked"); m.restore(); ` + "`echo real`", "real"},
+		{`reset_mocks(); mock_command("ls", {"stdout": 1}); ` + "`ls`", "mock_command(...) response 'stdout' must be a string, got NUMBER"},
+		{`reset_mocks(); mock_command("ls", {"code": 1}); ` + "`ls`", "mock_command(...) response doesn't support 'code' (use stdout, stderr and exit_code)"},
+		{`reset_mocks(); mock_command("ls", f(cmd) { 1 }); ` + "`ls`", "mock_command(...) response must be a string or a hash {stdout, stderr, exit_code}, got NUMBER"},
+		{`mock_http(1, "")`, "argument 0 to mock_http(...) is not supported"},
+		{`reset_mocks()`, nil},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestMockHttp(t *testing.T) {
+	tests := []struct {
+		input  string
+		method string
+		url    string
+		status int
+		body   string
+		header string
+	}{
+		{`mock_http("GET https://api.example.com/*", "ok")`, "GET", "https://api.example.com/v1/pods", 200, "ok", ""},
+		{`mock_http("https://api.example.com/*", 204)`, "POST", "https://api.example.com/v1", 204, "", ""},
+		{`mock_http("GET https://api.example.com/*", {"status": 404, "body": {"error": "nope"}, "headers": {"X-Mock": "yes"}})`, "GET", "https://api.example.com/v1", 404, `{"error": "nope"}`, "yes"},
+		{`mock_http("* https://api.example.com/*", f(req) { req.method + " " + req.url })`, "DELETE", "https://api.example.com/v1", 200, "DELETE https://api.example.com/v1", ""},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(`reset_mocks(); ` + tt.input)
+		if isError(evaluated) {
+			t.Fatalf("unexpected error for %s: %s", tt.input, evaluated.Inspect())
+		}
+
+		req, _ := http.NewRequest(tt.method, tt.url, nil)
+		res, err := util.NewHTTPClient(time.Second).Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", tt.input, err)
+		}
+
+		body, _ := io.ReadAll(res.Body)
+		if res.StatusCode != tt.status || string(body) != tt.body || res.Header.Get("X-Mock") != tt.header {
+			t.Errorf("%s: expected %d %q (X-Mock: %q), got %d %q (X-Mock: %q)", tt.input, tt.status, tt.body, tt.header, res.StatusCode, string(body), res.Header.Get("X-Mock"))
+		}
+	}
+
+	testEval(`reset_mocks()`)
+}
+
+func TestAssertGolden(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "testdata"), 0755)
+	os.WriteFile(filepath.Join(dir, "testdata", "report.golden"), []byte("a\nb"), 0644)
+	os.WriteFile(filepath.Join(dir, "testdata", "hash.golden"), []byte(`{"a": 1}`), 0644)
+
+	tests := []struct {
+		input    string
+		update   bool
+		expected string
+	}{
+		{`assert_golden("report", "a\nb")`, false, "true"},
+		{`assert_golden("hash", {"a": 1})`, false, "true"},
+		{`assert_golden("report", "a\nc")`, false, "ERROR: assert_golden(...) value doesn't match testdata/report.golden (run abs test -update to accept it), first difference at line 2:\n  want: b\n  got:  c"},
+		{`assert_golden("report", "a")`, false, "ERROR: assert_golden(...) value doesn't match testdata/report.golden (run abs test -update to accept it), first difference at line 2:\n  want: b\n  got:  <EOF>"},
+		{`assert_golden("missing", 1)`, false, "ERROR: assert_golden(...) testdata/missing.golden doesn't exist (run abs test -update to create it)"},
+		{`assert_golden("../escape", 1)`, false, "ERROR: assert_golden(...) requires a name relative to testdata, got '../escape'"},
+		{`assert_golden("nested/list", [1, 2])`, true, "true"},
+		{`assert_golden("nested/list", [1, 2])`, false, "true"},
+		{`assert_golden("nested/list", [1, 3])`, false, "ERROR: assert_golden(...) value doesn't match testdata/nested/list.golden"},
+		{`ABS_UPDATE_GOLDEN = "1"; assert_golden("written", 1)`, false, "ERROR: assert_golden(...) testdata/written.golden doesn't exist (run abs test -update to create it)"},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment(object.SystemStdio, dir, "test", false)
+		if tt.update {
+			t.Setenv("ABS_UPDATE_GOLDEN", "true")
+		} else {
+			t.Setenv("ABS_UPDATE_GOLDEN", "")
+		}
+
+		lex := lexer.New(tt.input)
+		evaluated := BeginEval(parser.New(lex).ParseProgram(), env, lex)
+
+		if !strings.HasPrefix(evaluated.Inspect(), tt.expected) {
+			t.Errorf("%s: expected %q, got %q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestBundleModule(t *testing.T) {
+	tests := []Tests{
+		{`bundle_module("bundle://lib/five.abs", f() { return 5 }); require("bundle://lib/five.abs")`, 5},
+		{`bundle_module("bundle://lib/count.abs", f() { return [1] }); require("bundle://lib/count.abs").push(2); require("bundle://lib/count.abs").len()`, 2},
+		{`bundle_module("bundle://lib/vars.abs", f() { bundled = 1 }); source("bundle://lib/vars.abs"); bundled`, 1},
+		{`bundle_module("lib/util.abs", f() { 1 })`, "bundle_module(...) requires a path starting with bundle://, got lib/util.abs"},
+		{`require("bundle://lib/unknown.abs")`, "bundle://lib/unknown.abs isn't part of this bundle (was it built by abs bundle?)"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
@@ -0,0 +1,52 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/abs-lang/abs/ast"
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// BundlePrefix is how bundles (see "abs bundle") refer to
+// the files they inline, eg. require("bundle://lib/util.abs")
+const BundlePrefix = "bundle://"
+
+// The bodies of the files inlined in a bundle,
+// registered through bundle_module(...)
+var bundledModules = map[string]*ast.BlockStatement{}
+
+// Whether a path refers to a file
+// inlined in a bundle
+func isBundled(path string) bool {
+	return strings.HasPrefix(path, BundlePrefix)
+}
+
+// Evaluates a file inlined in a bundle the way
+// require(...) or source(...) would have evaluated
+// the original file, in the given environment
+func evalBundledModule(tok token.Token, path string, env *object.Environment) object.Object {
+	body, ok := bundledModules[path]
+	if !ok {
+		return newError(tok, "%s isn't part of this bundle (was it built by abs bundle?)", path)
+	}
+
+	return unwrapReturnValue(Eval(body, env))
+}
+
+// bundle_module("bundle://lib/util.abs", f() { ... })
+func bundleModuleFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "bundle_module", args, 2, [][]string{{object.STRING_OBJ}, {object.FUNCTION_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	path := args[0].(*object.String).Value
+	if !isBundled(path) {
+		return newError(tok, "bundle_module(...) requires a path starting with %s, got %s", BundlePrefix, path)
+	}
+
+	bundledModules[path] = args[1].(*object.Function).Body
+
+	return NULL
+}
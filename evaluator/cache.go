@@ -0,0 +1,223 @@
+package evaluator
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// An LRU cache with an optional TTL, created through
+// cache(max_entries, ttl). Once full, the least recently
+// used entry is evicted to make room for new ones.
+type lruCache struct {
+	sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	entries    map[object.HashKey]*list.Element
+	order      *list.List // front is the most recently used
+	hits       int
+	misses     int
+	evictions  int
+}
+
+type cacheEntry struct {
+	key     object.HashKey
+	value   object.Object
+	expires time.Time
+}
+
+func newLRUCache(maxEntries int, ttl time.Duration) *lruCache {
+	return &lruCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		entries:    map[object.HashKey]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+func (c *lruCache) get(key object.HashKey) (object.Object, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	el, ok := c.entries[key]
+	if ok && c.expired(el.Value.(*cacheEntry)) {
+		c.remove(el)
+		ok = false
+	}
+
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	c.hits++
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).value, true
+}
+
+func (c *lruCache) set(key object.HashKey, value object.Object) {
+	c.Lock()
+	defer c.Unlock()
+
+	entry := &cacheEntry{key: key, value: value}
+	if c.ttl > 0 {
+		entry.expires = time.Now().Add(c.ttl)
+	}
+
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+
+	if c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+func (c *lruCache) delete(key object.HashKey) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	el, ok := c.entries[key]
+	if ok {
+		c.remove(el)
+	}
+
+	return ok
+}
+
+func (c *lruCache) clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.entries = map[object.HashKey]*list.Element{}
+	c.order.Init()
+}
+
+// size is the number of entries that haven't expired
+func (c *lruCache) size() int {
+	c.Lock()
+	defer c.Unlock()
+
+	for el := c.order.Back(); el != nil; {
+		prev := el.Prev()
+		if c.expired(el.Value.(*cacheEntry)) {
+			c.remove(el)
+		}
+		el = prev
+	}
+
+	return c.order.Len()
+}
+
+func (c *lruCache) expired(e *cacheEntry) bool {
+	return !e.expires.IsZero() && time.Now().After(e.expires)
+}
+
+func (c *lruCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).key)
+}
+
+// Builds the hash scripts use to interact with
+// a cache, eg. c.get("key") or c.stats()
+func cacheObject(tok token.Token, c *lruCache) *object.Hash {
+	cacheKey := func(tok token.Token, name string, args []object.Object) (object.HashKey, object.Object) {
+		if len(args) == 0 {
+			return object.HashKey{}, newError(tok, "%s(...) requires a key", name)
+		}
+
+		switch args[0].Type() {
+		case object.STRING_OBJ, object.NUMBER_OBJ, object.BOOLEAN_OBJ:
+			return object.HashKey{Type: args[0].Type(), Value: args[0].Inspect()}, nil
+		default:
+			return object.HashKey{}, newError(tok, "unusable as cache key: %s", args[0].Type())
+		}
+	}
+
+	methods := map[string]object.BuiltinFunction{
+		// c.get("key") or c.get("key", default)
+		"get": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			k, err := cacheKey(tok, "get", args)
+			if err != nil {
+				return err
+			}
+
+			if v, ok := c.get(k); ok {
+				return v
+			}
+
+			if len(args) > 1 {
+				return args[1]
+			}
+
+			return NULL
+		},
+		// c.set("key", value)
+		"set": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			k, err := cacheKey(tok, "set", args)
+			if err != nil {
+				return err
+			}
+
+			if len(args) < 2 {
+				return newError(tok, "set(...) requires a key and a value")
+			}
+
+			c.set(k, args[1])
+			return args[1]
+		},
+		// c.delete("key")
+		"delete": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			k, err := cacheKey(tok, "delete", args)
+			if err != nil {
+				return err
+			}
+
+			return nativeBoolToBooleanObject(c.delete(k))
+		},
+		// c.clear()
+		"clear": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			c.clear()
+			return NULL
+		},
+		// c.stats()
+		"stats": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			size := c.size()
+
+			c.Lock()
+			defer c.Unlock()
+
+			stats := map[string]int{
+				"size":        size,
+				"max_entries": c.maxEntries,
+				"hits":        c.hits,
+				"misses":      c.misses,
+				"evictions":   c.evictions,
+			}
+
+			pairs := make(map[object.HashKey]object.HashPair)
+			for k, v := range stats {
+				key := &object.String{Token: tok, Value: k}
+				pairs[key.HashKey()] = object.HashPair{Key: key, Value: &object.Number{Token: tok, Value: float64(v)}}
+			}
+
+			return &object.Hash{Token: tok, Pairs: pairs}
+		},
+	}
+
+	pairs := make(map[object.HashKey]object.HashPair)
+	for name, fn := range methods {
+		key := &object.String{Token: tok, Value: name}
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: &object.Builtin{Token: tok, Fn: fn}}
+	}
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
@@ -0,0 +1,166 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+	"github.com/abs-lang/abs/util"
+)
+
+// A checkpoint, as persisted on disk
+type checkpointFile struct {
+	Name string                 `json:"name"`
+	Time string                 `json:"time"`
+	Vars map[string]interface{} `json:"vars"`
+}
+
+// Returns where checkpoints should be stored:
+// ABS_CHECKPOINT_FILE, if set, or a file next to
+// the script being run (eg. script.abs.checkpoint)
+func checkpointPath(env *object.Environment) string {
+	def := filepath.Join(env.Dir, ".abs.checkpoint")
+
+	if !env.Interactive && len(os.Args) > 1 {
+		def = os.Args[1] + ".checkpoint"
+	}
+
+	path, err := util.ExpandPath(util.GetEnvVar(env, "ABS_CHECKPOINT_FILE", def))
+	if err != nil {
+		return def
+	}
+
+	return path
+}
+
+// Saves all variables of the global environment
+// that can be serialized (functions, for example,
+// are skipped since they will be re-declared when
+// the script runs again).
+func saveCheckpoint(env *object.Environment, name string) error {
+	root := env.Root()
+	vars := map[string]interface{}{}
+
+	for _, k := range root.GetKeys() {
+		if k == "ABS_VERSION" || k == "ABS_INTERACTIVE" {
+			continue
+		}
+
+		v, _ := root.Get(k)
+		if native, ok := objectToNative(v); ok {
+			vars[k] = native
+		}
+	}
+
+	b, err := json.Marshal(checkpointFile{Name: name, Time: time.Now().Format(time.RFC3339), Vars: vars})
+	if err != nil {
+		return err
+	}
+
+	// Write and rename, so that a crash while
+	// writing won't leave us with a broken checkpoint
+	path := checkpointPath(env)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Restores the variables saved in the last checkpoint,
+// returning its name (or an empty string if there's
+// no checkpoint to resume from)
+func loadCheckpoint(tok token.Token, env *object.Environment) (string, error) {
+	b, err := os.ReadFile(checkpointPath(env))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	c := checkpointFile{}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return "", fmt.Errorf("invalid checkpoint %s: %s", checkpointPath(env), err.Error())
+	}
+
+	root := env.Root()
+	for k, v := range c.Vars {
+		root.Set(k, nativeToObject(tok, v))
+	}
+
+	return c.Name, nil
+}
+
+// Converts an ABS object to a value that
+// can be serialized with encoding/json
+func objectToNative(o object.Object) (interface{}, bool) {
+	switch o := o.(type) {
+	case *object.Null:
+		return nil, true
+	case *object.Boolean:
+		return o.Value, true
+	case *object.Number:
+		return o.Value, true
+	case *object.String:
+		return o.Value, true
+	case *object.Array:
+		elements := []interface{}{}
+		for _, e := range o.Elements {
+			native, ok := objectToNative(e)
+			if !ok {
+				return nil, false
+			}
+			elements = append(elements, native)
+		}
+		return elements, true
+	case *object.Hash:
+		pairs := map[string]interface{}{}
+		for _, pair := range o.Pairs {
+			if pair.Key.Type() != object.STRING_OBJ {
+				return nil, false
+			}
+
+			native, ok := objectToNative(pair.Value)
+			if !ok {
+				return nil, false
+			}
+			pairs[pair.Key.Inspect()] = native
+		}
+		return pairs, true
+	}
+
+	return nil, false
+}
+
+// Converts a value decoded by encoding/json to an ABS object
+func nativeToObject(tok token.Token, v interface{}) object.Object {
+	switch v := v.(type) {
+	case bool:
+		return nativeBoolToBooleanObject(v)
+	case float64:
+		return &object.Number{Token: tok, Value: v}
+	case string:
+		return &object.String{Token: tok, Value: v}
+	case []interface{}:
+		elements := []object.Object{}
+		for _, e := range v {
+			elements = append(elements, nativeToObject(tok, e))
+		}
+		return &object.Array{Token: tok, Elements: elements}
+	case map[string]interface{}:
+		pairs := make(map[object.HashKey]object.HashPair)
+		for k, e := range v {
+			key := &object.String{Token: tok, Value: k}
+			pairs[key.HashKey()] = object.HashPair{Key: key, Value: nativeToObject(tok, e)}
+		}
+		return &object.Hash{Token: tok, Pairs: pairs}
+	}
+
+	return NULL
+}
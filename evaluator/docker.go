@@ -0,0 +1,254 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+	"github.com/abs-lang/abs/util"
+)
+
+// The client of the engine DOCKER_HOST points to
+func dockerClient(tok token.Token, env *object.Environment) (*util.DockerClient, object.Object) {
+	c, err := util.NewDockerClient(util.GetEnvVar(env, "DOCKER_HOST", ""))
+	if err != nil {
+		return nil, newError(tok, "%s", err.Error())
+	}
+
+	return c, nil
+}
+
+// docker_ps() or docker_ps(true), to list stopped containers as well
+func dockerPsFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "docker_ps", args, [][][]string{
+		{},
+		{{object.BOOLEAN_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	c, err := dockerClient(tok, env)
+	if err != nil {
+		return err
+	}
+
+	containers, e := c.Containers(spec == 1 && args[0].(*object.Boolean).Value)
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	elements := []object.Object{}
+	for _, ct := range containers {
+		name := ""
+		if len(ct.Names) > 0 {
+			name = strings.TrimPrefix(ct.Names[0], "/")
+		}
+
+		labels := map[string]interface{}{}
+		for k, v := range ct.Labels {
+			labels[k] = v
+		}
+
+		elements = append(elements, nativeToObject(tok, map[string]interface{}{
+			"id":      ct.ID,
+			"name":    name,
+			"image":   ct.Image,
+			"command": ct.Command,
+			"created": float64(ct.Created),
+			"state":   ct.State,
+			"status":  ct.Status,
+			"labels":  labels,
+		}))
+	}
+
+	return &object.Array{Token: tok, Elements: elements}
+}
+
+// Reads the options of docker_run(image, options)
+func parseDockerRunOptions(tok token.Token, image string, h *object.Hash) (util.DockerRunOptions, bool, bool, object.Object) {
+	opts := util.DockerRunOptions{Image: image, Env: map[string]string{}, Ports: map[string]string{}}
+	detach, remove := false, false
+
+	for _, pair := range h.Pairs {
+		name := pair.Key.Inspect()
+		v := pair.Value
+
+		switch name {
+		case "name":
+			opts.Name = v.Inspect()
+		case "workdir":
+			opts.Workdir = v.Inspect()
+		case "cmd":
+			switch cmd := v.(type) {
+			case *object.String:
+				// like commands in ABS, strings
+				// are run through the shell
+				opts.Cmd = []string{"sh", "-c", cmd.Value}
+			case *object.Array:
+				for _, e := range cmd.Elements {
+					opts.Cmd = append(opts.Cmd, e.Inspect())
+				}
+			default:
+				return opts, false, false, newError(tok, "docker_run(...) option 'cmd' must be a string or an array, got %s", v.Type())
+			}
+		case "env", "ports":
+			hash, ok := v.(*object.Hash)
+			if !ok {
+				return opts, false, false, newError(tok, "docker_run(...) option '%s' must be a hash, got %s", name, v.Type())
+			}
+
+			for _, p := range hash.Pairs {
+				if name == "env" {
+					opts.Env[p.Key.Inspect()] = p.Value.Inspect()
+				} else {
+					opts.Ports[p.Key.Inspect()] = p.Value.Inspect()
+				}
+			}
+		case "volumes":
+			volumes, ok := v.(*object.Array)
+			if !ok {
+				return opts, false, false, newError(tok, "docker_run(...) option 'volumes' must be an array, got %s", v.Type())
+			}
+
+			for _, e := range volumes.Elements {
+				opts.Volumes = append(opts.Volumes, e.Inspect())
+			}
+		case "detach", "rm":
+			b, ok := v.(*object.Boolean)
+			if !ok {
+				return opts, false, false, newError(tok, "docker_run(...) option '%s' must be a boolean, got %s", name, v.Type())
+			}
+
+			if name == "detach" {
+				detach = b.Value
+			} else {
+				remove = b.Value
+			}
+		default:
+			return opts, false, false, newError(tok, "unknown docker_run(...) option '%s' (available: cmd, detach, env, name, ports, rm, volumes, workdir)", name)
+		}
+	}
+
+	return opts, detach, remove, nil
+}
+
+// docker_run("alpine", {"cmd": "echo hello", "rm": true})
+func dockerRunFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "docker_run", args, [][][]string{
+		{{object.STRING_OBJ}},
+		{{object.STRING_OBJ}, {object.HASH_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	h := &object.Hash{Pairs: map[object.HashKey]object.HashPair{}}
+	if spec == 1 {
+		h = args[1].(*object.Hash)
+	}
+
+	opts, detach, remove, err := parseDockerRunOptions(tok, args[0].Inspect(), h)
+	if err != nil {
+		return err
+	}
+
+	c, err := dockerClient(tok, env)
+	if err != nil {
+		return err
+	}
+
+	id, e := c.Create(opts)
+	if e == nil {
+		e = c.Start(id)
+	}
+
+	if e != nil {
+		if id != "" {
+			c.Remove(id)
+		}
+
+		return newError(tok, "%s", e.Error())
+	}
+
+	if detach {
+		return nativeToObject(tok, map[string]interface{}{"id": id})
+	}
+
+	// like a command, we return once the
+	// container is done, with its output
+	status, e := c.Wait(id)
+	output := []string{}
+	if e == nil {
+		e = c.Logs(id, false, func(line string) bool {
+			output = append(output, line)
+			return true
+		})
+	}
+
+	if remove {
+		c.Remove(id)
+	}
+
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return nativeToObject(tok, map[string]interface{}{
+		"id":     id,
+		"status": float64(status),
+		"ok":     status == 0,
+		"output": strings.Join(output, "\n"),
+	})
+}
+
+// docker_logs(id) or docker_logs(id, f(line) {...}) to follow them
+func dockerLogsFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "docker_logs", args, [][][]string{
+		{{object.STRING_OBJ}},
+		{{object.STRING_OBJ}, {object.FUNCTION_OBJ, object.BUILTIN_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	c, err := dockerClient(tok, env)
+	if err != nil {
+		return err
+	}
+
+	if spec == 0 {
+		output := []string{}
+		e := c.Logs(args[0].Inspect(), false, func(line string) bool {
+			output = append(output, line)
+			return true
+		})
+
+		if e != nil {
+			return newError(tok, "%s", e.Error())
+		}
+
+		return &object.String{Token: tok, Value: strings.Join(output, "\n")}
+	}
+
+	// the function is called with each line, as soon
+	// as it's printed, till the container stops or
+	// the function returns false
+	var errObj object.Object
+	e := c.Logs(args[0].Inspect(), true, func(line string) bool {
+		stop, res := callLineFn(tok, args[1], env, line)
+		errObj = res
+
+		return !stop
+	})
+
+	if errObj != nil {
+		return errObj
+	}
+
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return NULL
+}
@@ -0,0 +1,150 @@
+package evaluator
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+	"github.com/abs-lang/abs/util"
+)
+
+// Lines longer than this (1MB) can't be
+// streamed through each_line(...)
+const maxLineLength = 1024 * 1024
+
+// Calls fn with the given line: returns whether the caller
+// should stop, as fn returned false, or an error
+func callLineFn(tok token.Token, fn object.Object, env *object.Environment, line string) (bool, object.Object) {
+	res := applyFunction(tok, fn, env, []object.Object{&object.String{Token: tok, Value: line}})
+	if isError(res) {
+		return true, res
+	}
+
+	b, ok := res.(*object.Boolean)
+
+	return ok && !b.Value, nil
+}
+
+// Runs the command, calling fn with each line of its output as
+// soon as it's printed (see `cmd`.each_line(fn)) rather than
+// once the command is done. If fn returns false the command is
+// killed and considered successful.
+func streamCommandLines(tok token.Token, c *exec.Cmd, fn object.Object, env *object.Environment) (error, object.Object) {
+	c.Stdout = nil
+	pipe, err := c.StdoutPipe()
+	if err != nil {
+		return err, nil
+	}
+
+	if err := c.Start(); err != nil {
+		return err, nil
+	}
+
+	scanner := bufio.NewScanner(pipe)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+
+	for scanner.Scan() {
+		stop, errObj := callLineFn(tok, fn, env, scanner.Text())
+		if stop {
+			c.Process.Kill()
+			c.Wait()
+
+			return nil, errObj
+		}
+	}
+
+	// Lines that are too long are an error, but
+	// the command still needs to be waited for
+	if err := scanner.Err(); err != nil {
+		c.Process.Kill()
+		c.Wait()
+
+		return nil, newError(tok, "each_line(...) is unable to read the output of the command: %s", err.Error())
+	}
+
+	return c.Wait(), nil
+}
+
+// "a\nb".each_line(f(line) { echo(line) })
+func eachLineFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "each_line", args, 2, [][]string{{object.STRING_OBJ}, {object.FUNCTION_OBJ, object.BUILTIN_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	s := args[0].(*object.String)
+	if s.Value == "" {
+		return s
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(s.Value, "\n"), "\n") {
+		stop, err := callLineFn(tok, args[1], env, line)
+		if err != nil {
+			return err
+		}
+
+		if stop {
+			break
+		}
+	}
+
+	return s
+}
+
+// fs_lines("/var/log/syslog") returns an iterator over the lines
+// of the file, which are read one at a time:
+//
+// for i, line in fs_lines(path) { ... }
+func fsLinesFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "fs_lines", args, 1, [][]string{{object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	path, e := util.ExpandPath(args[0].Inspect())
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	f, e := os.Open(path)
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	reader := bufio.NewReader(f)
+	index := 0
+
+	// The file is closed as soon as
+	// we get to the end of it
+	next := func() (object.Object, object.Object) {
+		if reader == nil {
+			return nil, EOF
+		}
+
+		line, e := reader.ReadString('\n')
+		if e != nil && (e != io.EOF || line == "") {
+			f.Close()
+			reader = nil
+
+			return nil, EOF
+		}
+
+		index++
+
+		return &object.Number{Token: tok, Value: float64(index - 1)}, &object.String{Token: tok, Value: strings.TrimRight(line, "\r\n")}
+	}
+
+	return &object.Builtin{
+		Token: tok,
+		Next:  next,
+		Types: []string{},
+		Fn: func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			_, line := next()
+			return line
+		},
+	}
+}
@@ -2,16 +2,21 @@ package evaluator
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
+	"maps"
 	"math"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/abs-lang/abs/ast"
+	"github.com/abs-lang/abs/i18n"
 	"github.com/abs-lang/abs/lexer"
 	"github.com/abs-lang/abs/object"
 	"github.com/abs-lang/abs/token"
@@ -46,7 +51,16 @@ func newError(tok token.Token, format string, a ...interface{}) *object.Error {
 	// get the token position from the error node and append the offending line to the error message
 	lineNum, column, errorLine := lex.ErrorLine(tok.Position)
 	errorPosition := fmt.Sprintf("\n\t[%d:%d]\t%s", lineNum, column, errorLine)
-	return &object.Error{Message: fmt.Sprintf(format, a...) + errorPosition}
+	// In bundles, point to the file the code comes from
+	if file, line, ok := lex.SourceMap().Lookup(lineNum); ok {
+		errorPosition = fmt.Sprintf("\n\t[%s:%d:%d]\t%s", file, line, column, errorLine)
+	}
+	msg := fmt.Sprintf(format, a...)
+	if t := i18n.T(format); t != format {
+		msg = fmt.Sprintf(t, a...)
+	}
+
+	return &object.Error{Message: msg + errorPosition}
 }
 
 func newBreakError(tok token.Token, format string, a ...interface{}) *object.BreakError {
@@ -63,11 +77,20 @@ func newContinueError(tok token.Token, format string, a ...interface{}) *object.
 func BeginEval(program ast.Node, env *object.Environment, lexer *lexer.Lexer) object.Object {
 	// global lexer
 	lex = lexer
+	defer forgetWarnings(lexer)
 	// run the evaluator
 	return Eval(program, env)
 }
 
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	if interrupted.Load() {
+		return &object.Error{Message: "program interrupted"}
+	}
+
+	if deadlineExpired(env) {
+		return &object.Error{Message: deadlineExceeded}
+	}
+
 	switch node := node.(type) {
 	// Statements
 	case *ast.Program:
@@ -98,6 +121,12 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.NumberLiteral:
 		return &object.Number{Token: node.Token, Value: node.Value}
 
+	case *ast.DurationLiteral:
+		return &object.Duration{Token: node.Token, Value: node.Value}
+
+	case *ast.SizeLiteral:
+		return &object.Size{Token: node.Token, Value: node.Value}
+
 	case *ast.NullLiteral:
 		return NULL
 
@@ -120,6 +149,9 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.InfixExpression:
 		return evalInfixExpression(node.Token, node.Operator, node.Left, node.Right, env)
 
+	case *ast.ComparisonChain:
+		return evalComparisonChain(node, env)
+
 	case *ast.CompoundAssignment:
 		return evalCompoundAssignment(node, env)
 
@@ -182,6 +214,13 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return applyFunction(node.Token, function, env, args)
 
 	case *ast.MethodExpression:
+		// `cmd`.cwd(dir) and `cmd`.env({...}) change
+		// how the command runs, so they need to be
+		// handled before the command is executed
+		if isCommandModifier(node) {
+			return evalCommandWithModifiers(node, env)
+		}
+
 		o := Eval(node.Object, env)
 		if isError(o) {
 			return o
@@ -211,7 +250,7 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalHashLiteral(node, env)
 
 	case *ast.CommandExpression:
-		return evalCommandExpression(node.Token, node.Value, env)
+		return evalCommandExpression(node.Token, node.Value, env, commandOptions{dir: commandDir(env)})
 
 	// break and continue are treated just like errors: they will stop
 	// the execution of the current code. Within FOR blocks, though, they
@@ -298,39 +337,97 @@ func evalBlockStatement(
 	return result
 }
 
+// x += 1, h["k"] *= 2, arr[0] ||= default, x ??= fallback
+//
+// The target of the assignment (eg. the index in h[f()] += 1)
+// is only evaluated once, as is the value on the right side,
+// which ||= and ??= skip entirely when not needed.
 func evalCompoundAssignment(node *ast.CompoundAssignment, env *object.Environment) object.Object {
-	left := Eval(node.Left, env)
-	if isError(left) {
-		return left
-	}
-	right := Eval(node.Right, env)
-	if isError(right) {
-		return right
-	}
-	// multi-character operators like "+=" and "**=" are reduced to "+" or "**" for evalInfixExpression()
-	op := node.Operator
-	if len(op) >= 2 {
-		op = op[:len(op)-1]
-	}
-	// get the result of the infix operation
-	expr := evalInfixExpression(node.Token, op, node.Left, node.Right, env)
-	if isError(expr) {
-		return expr
-	}
+	var current object.Object
+	var assign func(object.Object) object.Object
+
 	switch nodeLeft := node.Left.(type) {
 	case *ast.Identifier:
-		env.Set(nodeLeft.String(), expr)
-		return NULL
+		value, ok := env.Get(nodeLeft.Value)
+		if !ok {
+			// x ??= 1 is a way to give x a default
+			if node.Operator != "??=" {
+				return newError(nodeLeft.Token, "identifier not found: %s", nodeLeft.Value)
+			}
+			value = NULL
+		}
+		current = value
+		assign = func(value object.Object) object.Object {
+			env.Set(nodeLeft.Value, value)
+			return NULL
+		}
 	case *ast.IndexExpression:
 		// support index assignment expressions: a[0] += 1, h["a"] += 1
-		return evalIndexAssignment(nodeLeft, expr, env)
+		if nodeLeft.IsRange {
+			return newError(node.Token, "cannot use %s on a range", node.Operator)
+		}
+		left := Eval(nodeLeft.Left, env)
+		if isError(left) {
+			return left
+		}
+		index := Eval(nodeLeft.Index, env)
+		if isError(index) {
+			return index
+		}
+		current = indexObject(nodeLeft, left, index, NULL)
+		if isError(current) {
+			return current
+		}
+		assign = func(value object.Object) object.Object {
+			return assignIndex(nodeLeft, left, index, value)
+		}
 	case *ast.PropertyExpression:
 		// support assignment to hash property: h.a += 1
-		return evalPropertyAssignment(nodeLeft, expr, env)
+		left := Eval(nodeLeft.Object, env)
+		if isError(left) {
+			return left
+		}
+		hash, ok := left.(*object.Hash)
+		if !ok {
+			return newError(nodeLeft.Token, "can only assign to hash property, got %s", left.Type())
+		}
+		current = NULL
+		if pair, ok := hash.GetPair(nodeLeft.Property.String()); ok {
+			current = pair.Value
+		}
+		assign = func(value object.Object) object.Object {
+			return assignProperty(nodeLeft, left, value)
+		}
+	default:
+		return newError(node.Token, "cannot assign to %s", node.Left.String())
 	}
-	// otherwise
-	env.Set(node.Left.String(), expr)
-	return NULL
+
+	switch node.Operator {
+	case "||=":
+		if isTruthy(current) {
+			return NULL
+		}
+	case "??=":
+		if current != NULL {
+			return NULL
+		}
+	}
+
+	right := Eval(node.Right, env)
+	if isError(right) {
+		return right
+	}
+
+	value := right
+	if node.Operator != "||=" && node.Operator != "??=" {
+		// multi-character operators like "+=" and "**=" are reduced to "+" or "**" for evalInfixOperator()
+		value = evalInfixOperator(node.Token, strings.TrimSuffix(node.Operator, "="), current, right, env)
+		if isError(value) {
+			return value
+		}
+	}
+
+	return assign(value)
 }
 
 func evalDecorator(node *ast.Decorator, env *object.Environment) object.Object {
@@ -436,8 +533,22 @@ func getDecoratedName(decorated ast.Expression) (string, bool) {
 
 // support index assignment expressions: a[0] = 1, h["a"] = 1
 func evalIndexAssignment(iex *ast.IndexExpression, expr object.Object, env *object.Environment) object.Object {
+	if iex.IsRange {
+		return evalSliceAssignment(iex, expr, env)
+	}
+
 	leftObj := Eval(iex.Left, env)
 	index := Eval(iex.Index, env)
+	return assignIndex(iex, leftObj, index, expr)
+}
+
+// Assigns expr to index, within leftObj
+// (either an array or a hash)
+func assignIndex(iex *ast.IndexExpression, leftObj, index, expr object.Object) object.Object {
+	if err := checkFrozen(iex.Token, leftObj); err != nil {
+		return err
+	}
+
 	if leftObj.Type() == object.ARRAY_OBJ {
 		arrayObject := leftObj.(*object.Array)
 		idx := index.(*object.Number).Int()
@@ -472,6 +583,14 @@ func evalIndexAssignment(iex *ast.IndexExpression, expr object.Object, env *obje
 // support assignment to hash property: h.a = 1
 func evalPropertyAssignment(pex *ast.PropertyExpression, expr object.Object, env *object.Environment) object.Object {
 	leftObj := Eval(pex.Object, env)
+	return assignProperty(pex, leftObj, expr)
+}
+
+func assignProperty(pex *ast.PropertyExpression, leftObj, expr object.Object) object.Object {
+	if err := checkFrozen(pex.Token, leftObj); err != nil {
+		return err
+	}
+
 	if leftObj.Type() == object.HASH_OBJ {
 		hashObject := leftObj.(*object.Hash)
 		prop := &object.String{Token: pex.Token, Value: pex.Property.String()}
@@ -598,6 +717,46 @@ func evalInfixExpression(
 		return right
 	}
 
+	return evalInfixOperator(tok, operator, left, right, env)
+}
+
+// 0 <= x < 10
+// Each operand is evaluated at most once, and
+// we stop at the first comparison that is false.
+func evalComparisonChain(node *ast.ComparisonChain, env *object.Environment) object.Object {
+	left := Eval(node.Operands[0], env)
+	if isError(left) {
+		return left
+	}
+
+	for i, operator := range node.Operators {
+		right := Eval(node.Operands[i+1], env)
+		if isError(right) {
+			return right
+		}
+
+		result := evalInfixOperator(node.Token, operator, left, right, env)
+		if isError(result) || !isTruthy(result) {
+			return result
+		}
+
+		left = right
+	}
+
+	return TRUE
+}
+
+// Applies operator to operands that have
+// already been evaluated.
+func evalInfixOperator(
+	tok token.Token, operator string,
+	left, right object.Object,
+	env *object.Environment,
+) object.Object {
+	if result, ok := evalOverloadedOperator(tok, operator, left, right, env); ok {
+		return result
+	}
+
 	switch {
 	case left.Type() == object.NUMBER_OBJ && right.Type() == object.NUMBER_OBJ:
 		return evalNumberInfixExpression(tok, operator, left, right)
@@ -607,16 +766,36 @@ func evalInfixExpression(
 		return evalArrayInfixExpression(tok, operator, left, right)
 	case left.Type() == object.HASH_OBJ && right.Type() == object.HASH_OBJ:
 		return evalHashInfixExpression(tok, operator, left, right)
-	case operator == "in":
+	case left.Type() == object.BYTES_OBJ && right.Type() == object.BYTES_OBJ:
+		return evalBytesInfixExpression(tok, operator, left, right)
+	case isQuantity(left) && (left.Type() == right.Type() || right.Type() == object.NUMBER_OBJ),
+		isQuantity(right) && left.Type() == object.NUMBER_OBJ && operator == "*":
+		return evalQuantityInfixExpression(tok, operator, left, right)
+	case operator == "in" || operator == "!in":
+		// 1 in "123" or 1 in {"1": true}
+		if (right.Type() == object.STRING_OBJ || right.Type() == object.HASH_OBJ) && left.Type() != object.STRING_OBJ {
+			if err := implicitConversion(tok, env, "'%s' on a %s only matches strings, %s given, use %s.str()", operator, right.Type(), left.Type(), left.Inspect()); err != nil {
+				return err
+			}
+		}
+
+		if operator == "!in" {
+			return evalNotInExpression(tok, left, right)
+		}
 		return evalInExpression(tok, left, right)
-	case operator == "!in":
-		return evalNotInExpression(tok, left, right)
-	case operator == "==":
+	case operator == "==" || operator == "!=":
+		if isImplicitEquality(left, right) {
+			if err := implicitConversion(tok, env, "comparing %s with %s is always %v%s", left.Type(), right.Type(), operator == "!=", conversionHint(left, right)); err != nil {
+				return err
+			}
+		}
+
+		if operator == "!=" {
+			return nativeBoolToBooleanObject(left != right)
+		}
 		return nativeBoolToBooleanObject(left == right)
-	case operator == "!=":
-		return nativeBoolToBooleanObject(left != right)
 	case left.Type() != right.Type():
-		return newError(tok, "type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		return newError(tok, "type mismatch: %s %s %s%s", left.Type(), operator, right.Type(), conversionHint(left, right))
 	default:
 		return newError(tok, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
@@ -640,6 +819,10 @@ func evalTildePrefixOperatorExpression(tok token.Token, right object.Object) obj
 }
 
 func evalMinusPrefixOperatorExpression(tok token.Token, right object.Object) object.Object {
+	if d, ok := right.(*object.Duration); ok {
+		return &object.Duration{Token: tok, Value: -d.Value}
+	}
+
 	if right.Type() != object.NUMBER_OBJ {
 		return newError(tok, "unknown operator: -%s", right.Type())
 	}
@@ -819,6 +1002,128 @@ func evalArrayInfixExpression(
 	return newError(tok, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 }
 
+func evalBytesInfixExpression(
+	tok token.Token,
+	operator string,
+	left, right object.Object,
+) object.Object {
+	leftVal := left.(*object.Bytes).Value
+	rightVal := right.(*object.Bytes).Value
+
+	switch operator {
+	case "+":
+		return &object.Bytes{Token: tok, Value: append(bytes.Clone(leftVal), rightVal...)}
+	case "==":
+		return nativeBoolToBooleanObject(bytes.Equal(leftVal, rightVal))
+	case "!=":
+		return nativeBoolToBooleanObject(!bytes.Equal(leftVal, rightVal))
+	}
+
+	return newError(tok, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+}
+
+// Durations and sizes are quantities: they can be
+// added to / subtracted from / compared with quantities
+// of the same type, and scaled by numbers.
+func isQuantity(o object.Object) bool {
+	return o.Type() == object.DURATION_OBJ || o.Type() == object.SIZE_OBJ
+}
+
+func quantityValue(o object.Object) float64 {
+	switch o := o.(type) {
+	case *object.Duration:
+		return float64(o.Value)
+	case *object.Size:
+		return float64(o.Value)
+	default:
+		return o.(*object.Number).Value
+	}
+}
+
+// newQuantity builds a quantity of the same type as like
+func newQuantity(tok token.Token, like object.Object, value float64) object.Object {
+	if like.Type() == object.DURATION_OBJ {
+		return &object.Duration{Token: tok, Value: time.Duration(value)}
+	}
+
+	return &object.Size{Token: tok, Value: int64(value)}
+}
+
+// 5s + 200ms
+// 10MB * 2
+// 2 * 1h
+// 1h / 30min
+func evalQuantityInfixExpression(
+	tok token.Token,
+	operator string,
+	left, right object.Object,
+) object.Object {
+	leftVal := quantityValue(left)
+	rightVal := quantityValue(right)
+
+	// A number can only scale a quantity
+	if left.Type() == object.NUMBER_OBJ || right.Type() == object.NUMBER_OBJ {
+		like := left
+		if left.Type() == object.NUMBER_OBJ {
+			like = right
+		}
+
+		switch operator {
+		case "*":
+			return newQuantity(tok, like, leftVal*rightVal)
+		case "/":
+			if right.Type() != object.NUMBER_OBJ {
+				break
+			}
+			if rightVal == 0 {
+				return newError(tok, "division by zero")
+			}
+			return newQuantity(tok, like, leftVal/rightVal)
+		}
+
+		return newError(tok, "type mismatch: %s %s %s", left.Type(), operator, right.Type())
+	}
+
+	switch operator {
+	case "+":
+		return newQuantity(tok, left, leftVal+rightVal)
+	case "-":
+		return newQuantity(tok, left, leftVal-rightVal)
+	case "%":
+		if rightVal == 0 {
+			return newError(tok, "division by zero")
+		}
+		return newQuantity(tok, left, math.Mod(leftVal, rightVal))
+	case "/":
+		if rightVal == 0 {
+			return newError(tok, "division by zero")
+		}
+		return &object.Number{Token: tok, Value: leftVal / rightVal}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	case "<=>":
+		switch {
+		case leftVal < rightVal:
+			return &object.Number{Token: tok, Value: -1}
+		case leftVal > rightVal:
+			return &object.Number{Token: tok, Value: 1}
+		}
+		return &object.Number{Token: tok, Value: 0}
+	}
+
+	return newError(tok, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+}
+
 func evalHashInfixExpression(
 	tok token.Token,
 	operator string,
@@ -827,6 +1132,11 @@ func evalHashInfixExpression(
 	leftHashObject := left.(*object.Hash)
 	rightHashObject := right.(*object.Hash)
 	if operator == "+" {
+		// A frozen hash can still be extended,
+		// into a new (unfrozen) one
+		if leftHashObject.Frozen {
+			leftHashObject = &object.Hash{Pairs: maps.Clone(leftHashObject.Pairs)}
+		}
 		leftVal := leftHashObject.Pairs
 		rightVal := rightHashObject.Pairs
 		for _, rightPair := range rightVal {
@@ -1103,12 +1413,22 @@ func evalIdentifier(
 	}
 
 	if builtin, ok := Fns[node.Value]; ok {
+		if err := checkBuiltin(node.Value); err != nil {
+			return newError(node.Token, "%s", err.Error())
+		}
+
 		return builtin
 	}
 
 	return newError(node.Token, "identifier not found: %s", node.Value)
 }
 
+// IsTruthy tells whether an object is truthy,
+// as it would be in an if condition.
+func IsTruthy(obj object.Object) bool {
+	return isTruthy(obj)
+}
+
 // This is the core of ABS's logical
 // evaluation, and epic quirks we'll
 // remember for years are to be found
@@ -1198,6 +1518,17 @@ func evalPropertyExpression(pe *ast.PropertyExpression, env *object.Environment)
 
 			return FALSE
 		}
+		// Special .stdout / .stderr properties of commands,
+		// holding what the command wrote to each stream
+		if obj.Stdout != nil && (pe.Property.String() == "stdout" || pe.Property.String() == "stderr") {
+			out := obj.Stdout
+
+			if pe.Property.String() == "stderr" {
+				out = obj.Stderr
+			}
+
+			return &object.String{Token: pe.Token, Value: strings.TrimSpace(out.String())}
+		}
 	case *object.Hash:
 		return evalHashIndexExpression(obj.Token, obj, &object.String{Token: pe.Token, Value: pe.Property.String()})
 	}
@@ -1212,13 +1543,21 @@ func evalPropertyExpression(pe *ast.PropertyExpression, env *object.Environment)
 func applyFunction(tok token.Token, fn object.Object, env *object.Environment, args []object.Object) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
-		extendedEnv, err := extendFunctionEnv(fn, args)
+		extendedEnv, err := extendFunctionEnv(fn, env, args)
 
 		if err != nil {
 			return err
 		}
-		evaluated := Eval(fn.Body, extendedEnv)
-		return unwrapReturnValue(evaluated)
+
+		name := fn.Name
+		if name == "" {
+			name = "anonymous"
+		}
+		span := StartSpan("f "+name, map[string]interface{}{"abs.function.name": name, "abs.function.args": len(args)})
+		evaluated := unwrapReturnValue(Eval(fn.Body, extendedEnv))
+		span.End(isError(evaluated))
+
+		return evaluated
 
 	case *object.Builtin:
 		return fn.Fn(tok, env, args...)
@@ -1235,9 +1574,11 @@ func applyMethod(tok token.Token, o object.Object, me *ast.MethodExpression, env
 	hash, isHash := o.(*object.Hash)
 
 	// If so, run the user-defined function
-	if isHash && hash.GetKeyType(method) == object.FUNCTION_OBJ {
+	// (or the builtin one stored in the hash,
+	// as native modules do eg. require('@secrets').get)
+	if isHash && (hash.GetKeyType(method) == object.FUNCTION_OBJ || hash.GetKeyType(method) == object.BUILTIN_OBJ) {
 		pair, _ := hash.GetPair(method)
-		return applyFunction(tok, pair.Value.(*object.Function), env, args)
+		return applyFunction(tok, pair.Value, env, args)
 	}
 
 	// Now, check if there is a builtin function with the given name
@@ -1251,6 +1592,10 @@ func applyMethod(tok token.Token, o object.Object, me *ast.MethodExpression, env
 		return newError(tok, "%s does not have method '%s()'", o.Type(), method)
 	}
 
+	if err := checkBuiltin(method); err != nil {
+		return newError(tok, "%s", err.Error())
+	}
+
 	// Make sure the builtin function can be called on the given type
 	if !CanCallMethod(f, o) {
 		return newError(tok, "cannot call method '%s()' on '%s'", method, o.Type())
@@ -1269,11 +1614,23 @@ func CanCallMethod(f *object.Builtin, o object.Object) bool {
 	return util.Contains(f.Types, string(o.Type()))
 }
 
+// MaxCallDepth is how deep function calls can be nested:
+// past it, calls return an error rather than overflowing
+// the stack, which would crash the whole process
+var MaxCallDepth = 10000
+
 func extendFunctionEnv(
 	fn *object.Function,
+	caller *object.Environment,
 	args []object.Object,
 ) (*object.Environment, *object.Error) {
+	if caller.CallDepth >= MaxCallDepth {
+		return nil, newError(fn.Token, "maximum call depth exceeded (%d nested calls)", MaxCallDepth)
+	}
+
 	env := object.NewEnclosedEnvironment(fn.Env, args)
+	env.Context = caller.Context
+	env.CallDepth = caller.CallDepth + 1
 
 	for paramIdx, param := range fn.Parameters {
 		argumentPassed := len(args) > paramIdx
@@ -1304,7 +1661,10 @@ func unwrapReturnValue(obj object.Object) object.Object {
 }
 
 func evalIndexExpression(node *ast.IndexExpression, env *object.Environment) object.Object {
-	tok := node.Token
+	if node.Step != nil {
+		return evalSteppedIndexExpression(node, env)
+	}
+
 	left := Eval(node.Left, env)
 	if isError(left) {
 		return left
@@ -1318,6 +1678,15 @@ func evalIndexExpression(node *ast.IndexExpression, env *object.Environment) obj
 		return end
 	}
 
+	return indexObject(node, left, index, end)
+}
+
+// Indexes left (eg. an array) with values that
+// have already been evaluated: end is only
+// used for ranges (x[index:end]).
+func indexObject(node *ast.IndexExpression, left, index, end object.Object) object.Object {
+	tok := node.Token
+
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.NUMBER_OBJ:
 		return evalArrayIndexExpression(tok, left, index, end, node.IsRange)
@@ -1325,6 +1694,8 @@ func evalIndexExpression(node *ast.IndexExpression, env *object.Environment) obj
 		return evalHashIndexExpression(tok, left, index)
 	case left.Type() == object.STRING_OBJ && index.Type() == object.NUMBER_OBJ:
 		return evalStringIndexExpression(tok, left, index, end, node.IsRange)
+	case left.Type() == object.BYTES_OBJ && index.Type() == object.NUMBER_OBJ:
+		return evalBytesIndexExpression(tok, left, index, end, node.IsRange)
 	default:
 		return newError(tok, "index operator not supported: %s on %s", index.Inspect(), left.Type())
 	}
@@ -1393,6 +1764,57 @@ func evalStringIndexExpression(tok token.Token, array, index object.Object, end
 	return &object.String{Token: tok, Value: string(stringObject.Value[idx])}
 }
 
+// Indexing bytes returns the numeric value of
+// a single byte, slicing them returns bytes.
+func evalBytesIndexExpression(tok token.Token, bytesObj, index object.Object, end object.Object, isRange bool) object.Object {
+	b := bytesObj.(*object.Bytes)
+	idx := index.(*object.Number).Int()
+	max := len(b.Value) - 1
+
+	if isRange {
+		max++
+		// A range's minimum value is 0
+		if idx < 0 {
+			idx = 0
+		}
+		endIdx, ok := end.(*object.Number)
+
+		// check if the range end is a number
+		if ok {
+			if endIdx.Int() < 0 {
+				max = int(math.Max(float64(max+endIdx.Int()), 0))
+			} else if endIdx.Int() < max {
+				max = endIdx.Int()
+			}
+		} else if end != NULL {
+			return newError(tok, `index ranges can only be numerical: got "%s" (type %s)`, end.Inspect(), end.Type())
+		}
+
+		if idx > max {
+			return &object.Bytes{Token: tok, Value: []byte{}}
+		}
+
+		return &object.Bytes{Token: tok, Value: b.Value[idx:max]}
+	}
+
+	// Out of bounds? Return a null element
+	if idx > max {
+		return NULL
+	}
+
+	if idx < 0 {
+		length := max + 1
+
+		if math.Abs(float64(idx)) > float64(length) {
+			return NULL
+		}
+
+		idx = length + idx
+	}
+
+	return &object.Number{Token: tok, Value: float64(b.Value[idx])}
+}
+
 func evalArrayIndexExpression(tok token.Token, array, index object.Object, end object.Object, isRange bool) object.Object {
 	arrayObject := array.(*object.Array)
 	idx := index.(*object.Number).Int()
@@ -1497,11 +1919,171 @@ func evalHashIndexExpression(tok token.Token, hash, index object.Object) object.
 	return pair.Value
 }
 
-func evalCommandExpression(tok token.Token, cmd string, env *object.Environment) object.Object {
+// The key the directory set through with_cwd(dir, fn)
+// is stored under, in the context of the code it runs
+type commandDirKey struct{}
+
+// Directory commands run in, when set
+// through with_cwd(dir, fn)
+func commandDir(env *object.Environment) string {
+	if env.Context == nil {
+		return ""
+	}
+
+	dir, _ := env.Context.Value(commandDirKey{}).(string)
+	return dir
+}
+
+// The context code evaluated in env runs in
+func envContext(env *object.Environment) context.Context {
+	if env.Context == nil {
+		return context.Background()
+	}
+
+	return env.Context
+}
+
+// Options a command runs with, set through
+// modifiers such as `cmd`.cwd(dir)
+type commandOptions struct {
+	dir string
+	env map[string]string
+	// raw commands are run as they are, without
+	// interpolating $vars (see command(...))
+	raw bool
+	// function called with each line of output,
+	// see `cmd`.each_line(fn)
+	eachLine object.Object
+}
+
+// Methods that, when called directly on a command
+// (eg. `ls`.cwd("/tmp")), configure the command
+// rather than being called on its output
+var commandModifiers = map[string]bool{
+	"cwd":       true,
+	"env":       true,
+	"each_line": true,
+}
+
+// Whether the method expression is a modifier
+// applied to a command, eg. `ls`.cwd("/tmp")
+func isCommandModifier(node *ast.MethodExpression) bool {
+	if !commandModifiers[node.Method.String()] {
+		return false
+	}
+
+	switch o := node.Object.(type) {
+	case *ast.CommandExpression:
+		return true
+	case *ast.MethodExpression:
+		return isCommandModifier(o)
+	}
+
+	return false
+}
+
+// Evaluates a chain of modifiers such as
+// `ls`.cwd("/tmp").env({"LANG": "C"}) and runs the command they
+// are applied to with the resulting options.
+func evalCommandWithModifiers(node *ast.MethodExpression, env *object.Environment) object.Object {
+	modifiers := []*ast.MethodExpression{}
+	var command *ast.CommandExpression
+
+	var n ast.Expression = node
+	for command == nil {
+		switch e := n.(type) {
+		case *ast.MethodExpression:
+			modifiers = append([]*ast.MethodExpression{e}, modifiers...)
+			n = e.Object
+		case *ast.CommandExpression:
+			command = e
+		}
+	}
+
+	opts := commandOptions{dir: commandDir(env)}
+
+	for _, m := range modifiers {
+		args := evalExpressions(m.Arguments, env)
+		if len(args) == 1 && isError(args[0]) {
+			return args[0]
+		}
+
+		switch m.Method.String() {
+		case "cwd":
+			err := validateArgs(m.Token, "cwd", args, 1, [][]string{{object.STRING_OBJ}})
+			if err != nil {
+				return err
+			}
+
+			dir, e := resolveCommandDir(env, args[0].Inspect())
+			if e != nil {
+				return newError(m.Token, "%s", e.Error())
+			}
+			opts.dir = dir
+		case "env":
+			err := validateArgs(m.Token, "env", args, 1, [][]string{{object.HASH_OBJ}})
+			if err != nil {
+				return err
+			}
+
+			if opts.env == nil {
+				opts.env = map[string]string{}
+			}
+
+			for _, pair := range args[0].(*object.Hash).Pairs {
+				opts.env[pair.Key.Inspect()] = pair.Value.Inspect()
+			}
+		case "each_line":
+			err := validateArgs(m.Token, "each_line", args, 1, [][]string{{object.FUNCTION_OBJ, object.BUILTIN_OBJ}})
+			if err != nil {
+				return err
+			}
+
+			// The command's output is consumed by
+			// each_line(...), there's nothing left
+			// for other modifiers to work with
+			if m != node {
+				return newError(m.Token, "each_line(...) needs to be the last method called on a command")
+			}
+			opts.eachLine = args[0]
+		}
+	}
+
+	return evalCommandExpression(command.Token, command.Value, env, opts)
+}
+
+// Resolves the directory a command should run in:
+// "~" is expanded and relative paths are relative
+// to the with_cwd(...) directory, if any.
+func resolveCommandDir(env *object.Environment, dir string) (string, error) {
+	dir, err := util.ExpandPath(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if cwd := commandDir(env); !filepath.IsAbs(dir) && cwd != "" {
+		dir = filepath.Join(cwd, dir)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("unable to run commands in %s: %s", dir, err.Error())
+	}
+
+	if !info.IsDir() {
+		return "", fmt.Errorf("unable to run commands in %s: not a directory", dir)
+	}
+
+	return dir, nil
+}
+
+func evalCommandExpression(tok token.Token, cmd string, env *object.Environment, opts commandOptions) object.Object {
 	cmd = strings.Trim(cmd, " ")
 
 	// interpolate any $vars in the cmd string
-	cmd = util.InterpolateStringVars(cmd, env)
+	if !opts.raw {
+		cmd = util.InterpolateStringVars(cmd, env)
+	}
 
 	// A background command ends with a '&'
 	background := len(cmd) > 1 && cmd[len(cmd)-1] == '&'
@@ -1512,12 +2094,57 @@ func evalCommandExpression(tok token.Token, cmd string, env *object.Environment)
 		cmd = cmd[:len(cmd)-2]
 	}
 
+	if background && opts.eachLine != nil {
+		return newError(tok, "each_line(...) cannot be used on background commands")
+	}
+
+	if err := checkCommand(env, cmd); err != nil {
+		return newError(tok, "command not allowed: %s", err.Error())
+	}
+
+	// Mocked commands (see mock_command(...)) don't run
+	// at all, background ones included: they're answered
+	// straight away by their mock
+	if s, ok := mockedCommand(tok, cmd, env, opts); ok {
+		return s
+	}
+
 	// The string holding the command
 	s := &object.String{}
 
+	// In dry-run mode we just print the command
+	// and pretend it ran successfully
+	if isDryRun(env) {
+		fmt.Fprintf(env.Stdio.Stdout, "dry-run: %s\n", cmd)
+		s.Token = tok
+		s.Stdout = &bytes.Buffer{}
+		s.Stderr = &bytes.Buffer{}
+		s.SetCmdResult(TRUE)
+		recordLastCommand(s, cmd, 0, 0)
+		return s
+	}
+
+	// Foreground commands are killed once the
+	// with_timeout(...) deadline, if any, passes
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if !background {
+		ctx, cancel = deadlineContext(env)
+	}
+	defer cancel()
+
 	parts := strings.Split(os.Getenv("ABS_COMMAND_EXECUTOR"), " ")
-	c := exec.Command(parts[0], append(parts[1:], cmd)...)
+	c := exec.CommandContext(ctx, parts[0], append(parts[1:], cmd)...)
+	if _, ok := ctx.Deadline(); ok {
+		// processes started by the command could keep
+		// its output open after it's killed: we don't
+		// wait for them
+		c.WaitDelay = 100 * time.Millisecond
+	}
 	c.Env = os.Environ()
+	for k, v := range opts.env {
+		c.Env = append(c.Env, k+"="+v)
+	}
+	c.Dir = opts.dir
 	c.Stdin = env.Stdio.Stdin
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -1529,6 +2156,8 @@ func evalCommandExpression(tok token.Token, cmd string, env *object.Environment)
 	s.Cmd = c
 	s.Token = tok
 
+	attrs := map[string]interface{}{"abs.command": cmd, "abs.command.background": background}
+
 	var err error
 	if background {
 		// If we want to run the command in background,
@@ -1536,15 +2165,49 @@ func evalCommandExpression(tok token.Token, cmd string, env *object.Environment)
 		// wait for it by calling s.Wait().
 		s.SetRunning()
 
+		span := startDetachedSpan("command", attrs)
+		start := time.Now()
 		err := c.Start()
 		if err != nil {
+			span.End(true)
+			stderr.WriteString(err.Error())
+			recordLastCommand(s, cmd, commandNotStarted, time.Since(start))
 			s.SetCmdResult(FALSE)
 			return FALSE
 		}
 
-		go evalCommandInBackground(s)
+		go evalCommandInBackground(s, span, cmd, start, AuditFunc)
 	} else {
-		err = c.Run()
+		span := StartSpan("command", attrs)
+		start := time.Now()
+		var lineErr object.Object
+		if opts.eachLine != nil {
+			err, lineErr = streamCommandLines(tok, c, opts.eachLine, env)
+		} else {
+			err = c.Run()
+		}
+		if c.ProcessState == nil {
+			// the command couldn't even start
+			span.End(true)
+			if err != nil {
+				stderr.WriteString(err.Error())
+			}
+			recordLastCommand(s, cmd, commandNotStarted, time.Since(start))
+			s.SetCmdResult(FALSE)
+			return s
+		}
+		auditCommand(c, cmd, start, AuditFunc)
+		span.SetAttribute("abs.command.exit_code", c.ProcessState.ExitCode())
+		span.End(err != nil)
+		recordLastCommand(s, cmd, c.ProcessState.ExitCode(), time.Since(start))
+
+		if lineErr != nil {
+			return lineErr
+		}
+
+		if ctx.Err() == context.DeadlineExceeded {
+			return &object.Error{Message: deadlineExceeded}
+		}
 	}
 
 	if !background {
@@ -1558,14 +2221,25 @@ func evalCommandExpression(tok token.Token, cmd string, env *object.Environment)
 	return s
 }
 
+// Whether commands should be printed
+// rather than executed, see set_dry_run(...)
+func isDryRun(env *object.Environment) bool {
+	v := util.GetEnvVar(env, "ABS_DRY_RUN", "")
+	return v == "true" || v == "1"
+}
+
 // Runs a background command.
 // We will start it, set its result
 // and then mark it as done, so that
 // callers stuck on s.Wait() can resume.
-func evalCommandInBackground(s *object.String) {
+func evalCommandInBackground(s *object.String, span *Span, cmd string, start time.Time, audit func(AuditRecord)) {
 	defer s.SetDone()
 
 	err := s.Cmd.Wait()
+	auditCommand(s.Cmd, cmd, start, audit)
+	span.SetAttribute("abs.command.exit_code", s.Cmd.ProcessState.ExitCode())
+	span.End(err != nil)
+	recordLastCommand(s, cmd, s.Cmd.ProcessState.ExitCode(), time.Since(start))
 
 	if err != nil {
 		s.SetCmdResult(FALSE)
@@ -1,14 +1,20 @@
 package evaluator
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/abs-lang/abs/i18n"
 	"github.com/abs-lang/abs/lexer"
 	"github.com/abs-lang/abs/object"
 	"github.com/abs-lang/abs/parser"
@@ -155,6 +161,54 @@ func TestCompoundExpression(t *testing.T) {
 	}
 }
 
+func TestSlices(t *testing.T) {
+	tests := []Tests{
+		{`[1, 2, 3, 4][::-1].str()`, "[4, 3, 2, 1]"},
+		{`[1, 2, 3, 4, 5][::2].str()`, "[1, 3, 5]"},
+		{`[1, 2, 3, 4, 5][1::2].str()`, "[2, 4]"},
+		{`[1, 2, 3, 4, 5][3:0:-1].str()`, "[4, 3, 2]"},
+		{`[1, 2, 3, 4, 5][-2::-1].str()`, "[4, 3, 2, 1]"},
+		{`[1, 2, 3][5::-1].str()`, "[3, 2, 1]"},
+		{`[1, 2, 3][:-1:-1].str()`, "[]"},
+		{`"hello"[::-1]`, "olleh"},
+		{`"héllo"[::2]`, "hlo"},
+		{`[1, 2][::0]`, "range step cannot be zero"},
+		{`[1, 2]["a"::1]`, `index ranges can only be numerical: got "a" (type STRING)`},
+		{`a = [1, 2, 3, 4, 5]; a[1:3] = ["x"]; a.str()`, `[1, "x", 4, 5]`},
+		{`a = [1, 2, 3]; a[:1] = [7, 8]; a.str()`, "[7, 8, 2, 3]"},
+		{`a = [1, 2, 3]; a[1:] = []; a.str()`, "[1]"},
+		{`a = [1, 2, 3]; a[3:] = [4]; a.str()`, "[1, 2, 3, 4]"},
+		{`a = [1, 2, 3]; a[1:-1] = [0, 0]; a.str()`, "[1, 0, 0, 3]"},
+		{`a = [1, 2, 3, 4]; a[::2] = [0, 0]; a.str()`, "[0, 2, 0, 4]"},
+		{`a = [1, 2, 3, 4]; a[::-1] = a[::1]; a.str()`, "[4, 3, 2, 1]"},
+		{`a = [1, 2, 3, 4]; a[::2] = [0]`, "cannot assign 1 elements to a range of 2"},
+		{`a = [1, 2]; a[0:1] = 1`, "can only assign an array to a range, got NUMBER"},
+		{`a = "abc"; a[0:1] = ["x"]`, "can only assign to a range of an array, got STRING"},
+		{`b = [1, 2, 3]; c = b[0:2]; b[0:1] = [9]; c.str()`, "[1, 2]"},
+	}
+	testBuiltinFunction(tests, t)
+}
+
+func TestCompoundAssignmentTargets(t *testing.T) {
+	tests := []Tests{
+		{`h = {"k": 1}; h["k"] += 1; h.k`, 2},
+		{`h = {"k": 1}; h.k *= 3; h.k`, 3},
+		{`a = [1, 2]; a[1] -= 1; a.str()`, "[1, 1]"},
+		{`calls = []; i = f() { calls.push(1); 0 }; a = [1]; a[i()] += 1; [a[0], calls.len()].str()`, "[2, 1]"},
+		{`a = [0, 5]; a[0] ||= 10; a[1] ||= 10; a.str()`, "[10, 5]"},
+		{`h = {}; h.name ||= "abs"; h.name`, "abs"},
+		{`x = null; x ??= 1; x`, 1},
+		{`x = 0; x ??= 1; x`, 0},
+		{`y ??= 2; y`, 2},
+		{`h = {}; h["k"] ??= []; h["k"] ??= [1]; h.k.len()`, 0},
+		{`calls = []; g = f() { calls.push(1); 1 }; x = 1; x ||= g(); x ??= g(); calls.len()`, 0},
+		{`z += 1`, "identifier not found: z"},
+		{`a = [1]; a[0:1] += 1`, "cannot use += on a range"},
+		{`a = [1]; a["x"] += 1`, "index operator not supported: x on ARRAY"},
+	}
+	testBuiltinFunction(tests, t)
+}
+
 func TestEvalStringExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -721,6 +775,49 @@ if (10 > 1) {
 	}
 }
 
+func TestLocalizedErrors(t *testing.T) {
+	i18n.SetLanguage("it")
+	defer i18n.SetLanguage("")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"5 + true;", "tipi incompatibili: NUMBER + BOOLEAN"},
+		{"foobar", "identificatore non trovato: foobar"},
+		{"1s / 0", "divisione per zero"},
+		// messages without a translation stay in english
+		{`999[1]`, "index operator not supported: 1 on NUMBER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+			continue
+		}
+		logErrorWithPosition(t, errObj.Message, tt.expected)
+	}
+}
+
+func TestSourceMappedErrors(t *testing.T) {
+	bundle := "# bundled\nbundle_module(\"bundle://lib/util.abs\", f() {\nreturn {\"fail\": f() { 1 + nope }}\n})\nrequire(\"bundle://lib/util.abs\").fail()\n"
+	bundle += `# abs-source-map: {"version":1,"files":["main.abs","lib/util.abs"],"lines":[[],[],[1,7],[],[0,3]]}`
+
+	evaluated := testEval(bundle)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+
+	expected := "identifier not found: nope\n\t[lib/util.abs:7:27]\treturn {\"fail\": f() { 1 + nope }}"
+	if errObj.Message != expected {
+		t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+	}
+}
+
 func TestAssignStatements(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -786,6 +883,15 @@ func TestFunctionObject(t *testing.T) {
 	}
 }
 
+func TestMaxCallDepth(t *testing.T) {
+	tests := []Tests{
+		{"g = f(n) { if n == 0 { return 0 }; return g(n - 1) }; g(9999)", 0},
+		{"g = f() { g() }; g()", "maximum call depth exceeded (10000 nested calls)"},
+		{"g = f() { [1].map(f(x) { g() }) }; g()", "maximum call depth exceeded (10000 nested calls)"},
+	}
+	testBuiltinFunction(tests, t)
+}
+
 func TestFunctionApplication(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1132,6 +1238,10 @@ func TestBuiltinProperties(t *testing.T) {
 		{`"a".ok`, false},
 		{`"a".inv`, "invalid property 'inv' on type STRING"},
 		{"a = $(echo hello);\na.ok", true},
+		{"a = `echo hello; echo world >&2`; a.stdout", "hello"},
+		{"a = `echo hello; echo world >&2`; a.stderr", "world"},
+		{"`echo hello >&2; false`.stdout", ""},
+		{`"a".stderr`, "invalid property 'stderr' on type STRING"},
 		{`{}.a`, nil},
 		{`{"a": 1}.a`, 1},
 		{`{1: 1}.1`, "unusable as hash key: NUMBER"},
@@ -1281,6 +1391,321 @@ func TestCommand(t *testing.T) {
 	}
 }
 
+func TestCommandPolicy(t *testing.T) {
+	defer func() {
+		allowedCommands = []string{}
+		deniedCommands = []string{}
+		CommandFilter = nil
+	}()
+
+	allowedCommands = []string{"echo", "/usr/bin/env"}
+	tests := []Tests{
+		{"`echo hello`", "hello"},
+		{"`/bin/echo hello`", "hello"},
+		{"`env true`", "command not allowed: 'env' is not in the list of allowed commands"},
+		{"`/usr/bin/env echo hello`", "hello"},
+		{"`/bin/env true`", "command not allowed: '/bin/env' is not in the list of allowed commands"},
+		{"`echo hello | grep hello`", "command not allowed: 'grep' is not in the list of allowed commands"},
+		{"`echo $(whoami)`", "command not allowed: 'whoami' is not in the list of allowed commands"},
+		{"`echo \"$(id -u)\"`", "command not allowed: 'id' is not in the list of allowed commands"},
+		{"exec('ls')", "command not allowed: 'ls' is not in the list of allowed commands"},
+	}
+	testBuiltinFunction(tests, t)
+
+	allowedCommands = []string{}
+	deniedCommands = []string{"rm"}
+	tests = []Tests{
+		{"`echo hello`", "hello"},
+		{"`echo hello && rm -rf /tmp/nope`", "command not allowed: 'rm' is in the list of denied commands"},
+	}
+	testBuiltinFunction(tests, t)
+
+	deniedCommands = []string{}
+	CommandFilter = func(cmd string) error {
+		if strings.Contains(cmd, "secret") {
+			return fmt.Errorf("no secrets here")
+		}
+		return nil
+	}
+	tests = []Tests{
+		{"`echo hello`", "hello"},
+		{"`echo secret`", "command not allowed: no secrets here"},
+	}
+	testBuiltinFunction(tests, t)
+}
+
+func TestSandbox(t *testing.T) {
+	defer func() { Sandboxed = false }()
+
+	Sandboxed = true
+	tests := []Tests{
+		{"1 + 1", 2},
+		{"[1, 2].map(f(x) { x * 2 }).sum()", 6},
+		{"`echo hello`", "command not allowed: commands are not available in the sandbox"},
+		{"exec('ls')", "exec(...) is not available in the sandbox"},
+		{"exit(1)", "exit(...) is not available in the sandbox"},
+		{"require('@util')", "require(...) is not available in the sandbox"},
+		{"'HOME'.env()", "env(...) is not available in the sandbox"},
+		{"fs_lines('/etc/hostname')", "fs_lines(...) is not available in the sandbox"},
+		{"'/etc/hostname'.fs_lines()", "fs_lines(...) is not available in the sandbox"},
+		{"assert_golden('x', 1)", "assert_golden(...) is not available in the sandbox"},
+	}
+	testBuiltinFunction(tests, t)
+}
+
+func TestComparisonChain(t *testing.T) {
+	tests := []Tests{
+		{"x = 5; 0 <= x < 10", true},
+		{"x = 10; 0 <= x < 10", false},
+		{"x = -1; 0 <= x < 10", false},
+		{"1 < 2 < 3 < 4", true},
+		{"4 > 3 > 3", false},
+		{"1s < 2s <= 2s", true},
+		{"calls = []; f1 = f() { calls.push(1); 5 }; 0 < f1() < 10; calls.len()", 1},
+		{"calls = []; f1 = f() { calls.push(1); 5 }; 10 < 1 < f1(); calls.len()", 0},
+		{"1 < 2 < \"a\"", "type mismatch: NUMBER < STRING"},
+		{"(1 < 2) < 3", "type mismatch: BOOLEAN < NUMBER"},
+	}
+	testBuiltinFunction(tests, t)
+}
+
+func TestOperatorOverloading(t *testing.T) {
+	vector := `
+vector = f(x, y) {
+	return {
+		"x": x,
+		"y": y,
+		"__add__": f(self, other) { vector(self.x + other.x, self.y + other.y) },
+		"__mul__": f(self, n) { vector(self.x * n, self.y * n) },
+		"__eq__": f(self, other) { self.x == other.x && self.y == other.y },
+		"__lt__": f(self, other) { self.x ** 2 + self.y ** 2 < other.x ** 2 + other.y ** 2 },
+		"__str__": f(self) { "Vector(%s, %s)".fmt(self.x, self.y) },
+	}
+}
+`
+	tests := []Tests{
+		{vector + `v = vector(1, 2) + vector(3, 4); v.str()`, "Vector(4, 6)"},
+		{vector + `v = vector(1, 2) * 3; v.x`, 3},
+		{vector + `vector(1, 2) == vector(1, 2)`, true},
+		{vector + `vector(1, 2) != vector(1, 2)`, false},
+		{vector + `vector(1, 2) < vector(3, 4)`, true},
+		{vector + `"v: " + vector(1, 2).str()`, "v: Vector(1, 2)"},
+		{vector + `vector(1, 2) - vector(3, 4)`, "unknown operator: HASH - HASH"},
+		{`({"a": 1} + {"b": 2}).str()`, `{"a": 1, "b": 2}`},
+		{`{"__add__": f(self, other) { self.x + other }} + 1`, "type mismatch: NULL + NUMBER"},
+	}
+	testBuiltinFunction(tests, t)
+
+	// hashes can be stringified from several
+	// goroutines at once (see go test -race)
+	v := testEval(vector + `vector(1, 2)`)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v.Inspect()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStrict(t *testing.T) {
+	defer func() { Strict = false }()
+
+	tests := []Tests{
+		{`"1" + 1`, `type mismatch: STRING + NUMBER, use int("1")`},
+		{`1 + "1.5"`, `type mismatch: NUMBER + STRING, use number("1.5")`},
+		{`"a" + 1`, `type mismatch: STRING + NUMBER, use 1.str()`},
+		{`"1" == 1`, false},
+		{`1 in "123"`, false},
+	}
+	testBuiltinFunction(tests, t)
+
+	Strict = true
+	tests = []Tests{
+		{`"1" == 1`, `comparing STRING with NUMBER is always false, use int("1")`},
+		{`"1" != 1`, `comparing STRING with NUMBER is always true, use int("1")`},
+		{`1 in "123"`, `'in' on a STRING only matches strings, NUMBER given, use 1.str()`},
+		{`1 in {"1": true}`, `'in' on a HASH only matches strings, NUMBER given, use 1.str()`},
+		{`x = null; x == null`, true},
+		{`1 == 1`, true},
+		{`"1" in "123"`, true},
+	}
+	testBuiltinFunction(tests, t)
+}
+
+func TestImplicitConversionWarnings(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	env := object.NewEnvironment(&object.Stdio{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: stderr}, "", "test_version", false)
+	run := func(input string) {
+		lex := lexer.New(input)
+		BeginEval(parser.New(lex).ParseProgram(), env, lex)
+	}
+
+	// once per location within a run...
+	run(`for i in 1..3 { "1" == 1 }`)
+	if n := strings.Count(stderr.String(), "warning:"); n != 1 {
+		t.Errorf("expected 1 warning, got %d: %s", n, stderr.String())
+	}
+
+	// ...but again in the next one, even when
+	// it's at the same line and column
+	stderr.Reset()
+	run(`"1" == 1`)
+	run(`"1" == 1`)
+	if n := strings.Count(stderr.String(), "warning:"); n != 2 {
+		t.Errorf("expected 2 warnings, got %d: %s", n, stderr.String())
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	defer ResetInterrupt()
+
+	time.AfterFunc(50*time.Millisecond, Interrupt)
+	start := time.Now()
+	tests := []Tests{
+		{"while true { 1 }", "program interrupted"},
+	}
+	testBuiltinFunction(tests, t)
+
+	ResetInterrupt()
+	time.AfterFunc(50*time.Millisecond, Interrupt)
+	tests = []Tests{
+		{"sleep(10000); 1", "program interrupted"},
+	}
+	testBuiltinFunction(tests, t)
+
+	// commands running are killed as well
+	ResetInterrupt()
+	time.AfterFunc(50*time.Millisecond, Interrupt)
+	tests = []Tests{
+		{"`sleep 10`; 1", "program interrupted"},
+	}
+	testBuiltinFunction(tests, t)
+
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("interrupted programs took %s to stop", time.Since(start))
+	}
+}
+
+func TestDrain(t *testing.T) {
+	defer ResetInterrupt()
+
+	// the program stops once it sleeps...
+	Drain()
+	tests := []Tests{
+		{"x = 0; while true { x += 1; sleep(10) }", "program interrupted"},
+	}
+	testBuiltinFunction(tests, t)
+
+	// ...and not before, when it's busy
+	ResetInterrupt()
+	Drain()
+	tests = []Tests{
+		{"x = 0; while x < 1000 { x += 1 }; x", 1000},
+	}
+	testBuiltinFunction(tests, t)
+}
+
+func TestCommandCwd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "sub"), 0755)
+	cwd, _ := os.Getwd()
+
+	tests := []Tests{
+		{"`pwd`.cwd('" + dir + "')", dir},
+		{"`pwd`.cwd('" + dir + "').ok", true},
+		{"`pwd &`.cwd('" + dir + "').wait()", dir},
+		{"with_cwd('" + dir + "', f() { `pwd` })", dir},
+		{"with_cwd('" + dir + "', f() { `pwd`.cwd('sub') })", filepath.Join(dir, "sub")},
+		{"with_cwd('" + dir + "', f() { with_cwd('sub', f() { `pwd` }) })", filepath.Join(dir, "sub")},
+		{"with_cwd('" + dir + "', f() { pwd() })", cwd},
+		{"with_cwd('" + dir + "', f() { 1 }); `pwd`", cwd},
+		{"run = f() { `pwd` }; with_cwd('" + dir + "', run)", dir},
+		{"run = f() { `pwd` }; with_cwd('" + dir + "', f() { [1].map(f(x) { run() })[0] })", dir},
+		{"run = f() { `pwd` }; with_cwd('" + dir + "', f() { 1 }); run()", cwd},
+		{"`pwd`.cwd('" + dir + "/nope')", "unable to run commands in " + dir + "/nope: stat " + dir + "/nope: no such file or directory"},
+		{"`pwd`.cwd(1)", "argument 0 to cwd(...) is not supported (got: 1, allowed: STRING)"},
+		{"with_cwd('" + dir + "', 1)", "argument 1 to with_cwd(...) is not supported (got: 1, allowed: FUNCTION, BUILTIN)"},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestCommandEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	dir := t.TempDir()
+
+	tests := []Tests{
+		{"`echo \\$ABS_TOKEN`.env({'ABS_TOKEN': 'secret'})", "secret"},
+		{"`echo \\$A \\$B`.env({'A': 1}).env({'B': 2})", "1 2"},
+		{"`echo \\$CONTEXT`.env({'CONTEXT': 'overridden'})", "overridden"},
+		{"`echo \\$ABS_TOKEN &`.env({'ABS_TOKEN': 'secret'}).wait()", "secret"},
+		{"`echo \\$ABS_TOKEN`.env({'ABS_TOKEN': 'secret'}); `echo \\$ABS_TOKEN`", ""},
+		{"`echo \\$ABS_TOKEN; pwd`.cwd('" + dir + "').env({'ABS_TOKEN': 'secret'}).lines()", []string{"secret", dir}},
+		{"`pwd`.env('x')", "argument 0 to env(...) is not supported (got: x, allowed: HASH)"},
+		{"env('ABS_TOKEN')", ""},
+	}
+
+	testBuiltinFunction(tests, t)
+}
+
+func TestCommandAudit(t *testing.T) {
+	// Background commands from other tests might
+	// complete while we're running, so we only look
+	// at the commands of this test
+	mux := sync.Mutex{}
+	records := []AuditRecord{}
+	auditLog = filepath.Join(t.TempDir(), "audit.log")
+	AuditFunc = func(r AuditRecord) {
+		mux.Lock()
+		defer mux.Unlock()
+		if strings.Contains(r.Command, "audit") {
+			records = append(records, r)
+		}
+	}
+	defer func() {
+		auditLog = ""
+		AuditFunc = nil
+	}()
+
+	testEval("`echo audit`")
+	testEval("`exit 3 # audit`")
+	testEval("`sleep 0.01 # audit &`.wait()")
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	if len(records) != 3 {
+		t.Fatalf("expected 3 audit records, got %d", len(records))
+	}
+
+	if records[0].Command != "echo audit" || records[0].ExitCode != 0 {
+		t.Errorf("wrong audit record: %+v", records[0])
+	}
+
+	if records[1].Command != "exit 3 # audit" || records[1].ExitCode != 3 {
+		t.Errorf("wrong audit record: %+v", records[1])
+	}
+
+	if records[2].Command != "sleep 0.01 # audit" || records[2].Duration < 10 {
+		t.Errorf("wrong audit record: %+v", records[2])
+	}
+
+	log, _ := os.ReadFile(auditLog)
+	if !strings.Contains(string(log), `"command":"exit 3 # audit","cwd":`) || !strings.Contains(string(log), `"exit_code":3}`) {
+		t.Errorf("wrong audit log: %s", log)
+	}
+}
+
 func TestArrayLiterals(t *testing.T) {
 	input := "[1, 2 * 2, 3 + 3]"
 
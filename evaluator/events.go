@@ -0,0 +1,135 @@
+package evaluator
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// An event emitter, created through events():
+// handlers registered with on(...) are called,
+// in order, every time the event is emitted.
+type emitter struct {
+	sync.Mutex
+	handlers map[string][]object.Object
+}
+
+func (e *emitter) on(name string, fn object.Object) {
+	e.Lock()
+	defer e.Unlock()
+
+	e.handlers[name] = append(e.handlers[name], fn)
+}
+
+// off removes fn from the handlers of name,
+// or all of them if fn is nil
+func (e *emitter) off(name string, fn object.Object) int {
+	e.Lock()
+	defer e.Unlock()
+
+	before := len(e.handlers[name])
+	if fn == nil {
+		delete(e.handlers, name)
+		return before
+	}
+
+	handlers := []object.Object{}
+	for _, h := range e.handlers[name] {
+		if h != fn {
+			handlers = append(handlers, h)
+		}
+	}
+	e.handlers[name] = handlers
+
+	return before - len(handlers)
+}
+
+// listeners returns a copy of the handlers of name, so
+// that handlers can register (or remove) other handlers
+// while an event is being emitted
+func (e *emitter) listeners(name string) []object.Object {
+	e.Lock()
+	defer e.Unlock()
+
+	return append([]object.Object{}, e.handlers[name]...)
+}
+
+// Builds the hash scripts use to interact
+// with an emitter, eg. e.on("done", f(data) {...})
+func emitterObject(tok token.Token, e *emitter) *object.Hash {
+	methods := map[string]object.BuiltinFunction{
+		// e.on("name", f(data) {...})
+		"on": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			err := validateArgs(tok, "on", args, 2, [][]string{{object.STRING_OBJ}, {object.FUNCTION_OBJ, object.BUILTIN_OBJ}})
+			if err != nil {
+				return err
+			}
+
+			e.on(args[0].Inspect(), args[1])
+			return args[1]
+		},
+		// e.off("name") or e.off("name", handler)
+		"off": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			err, spec := validateVarArgs(tok, "off", args, [][][]string{
+				{{object.STRING_OBJ}},
+				{{object.STRING_OBJ}, {object.FUNCTION_OBJ, object.BUILTIN_OBJ}},
+			})
+			if err != nil {
+				return err
+			}
+
+			var fn object.Object
+			if spec == 1 {
+				fn = args[1]
+			}
+
+			return &object.Number{Token: tok, Value: float64(e.off(args[0].Inspect(), fn))}
+		},
+		// e.emit("name") or e.emit("name", data)
+		"emit": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			if len(args) == 0 || args[0].Type() != object.STRING_OBJ {
+				return newError(tok, "emit(...) requires the name of the event as its first argument")
+			}
+
+			handlers := e.listeners(args[0].Inspect())
+			for _, fn := range handlers {
+				result := applyFunction(tok, fn, env, args[1:])
+				if isError(result) {
+					return result
+				}
+			}
+
+			return &object.Number{Token: tok, Value: float64(len(handlers))}
+		},
+		// e.events()
+		"events": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			e.Lock()
+			defer e.Unlock()
+
+			names := []string{}
+			for name, handlers := range e.handlers {
+				if len(handlers) > 0 {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+
+			elements := []object.Object{}
+			for _, name := range names {
+				elements = append(elements, &object.String{Token: tok, Value: name})
+			}
+
+			return &object.Array{Token: tok, Elements: elements}
+		},
+	}
+
+	pairs := make(map[object.HashKey]object.HashPair)
+	for name, fn := range methods {
+		key := &object.String{Token: tok, Value: name}
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: &object.Builtin{Token: tok, Fn: fn}}
+	}
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
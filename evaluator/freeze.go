@@ -0,0 +1,48 @@
+package evaluator
+
+import (
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// checkFrozen returns an error if o is a
+// frozen array or hash, which can't be modified
+func checkFrozen(tok token.Token, o object.Object) *object.Error {
+	switch o := o.(type) {
+	case *object.Array:
+		if o.Frozen {
+			return newError(tok, "cannot modify a frozen array")
+		}
+	case *object.Hash:
+		if o.Frozen {
+			return newError(tok, "cannot modify a frozen hash")
+		}
+	}
+
+	return nil
+}
+
+// deepFreeze returns a frozen copy of o, where all
+// the arrays and hashes it contains are frozen as
+// well. Other values are immutable already, so
+// they're returned as they are.
+func deepFreeze(o object.Object) object.Object {
+	switch o := o.(type) {
+	case *object.Array:
+		elements := make([]object.Object, len(o.Elements))
+		for i, e := range o.Elements {
+			elements[i] = deepFreeze(e)
+		}
+
+		return &object.Array{Token: o.Token, Elements: elements, Frozen: true}
+	case *object.Hash:
+		pairs := make(map[object.HashKey]object.HashPair, len(o.Pairs))
+		for k, pair := range o.Pairs {
+			pairs[k] = object.HashPair{Key: pair.Key, Value: deepFreeze(pair.Value)}
+		}
+
+		return &object.Hash{Token: o.Token, Pairs: pairs, Frozen: true}
+	default:
+		return o
+	}
+}
@@ -2,10 +2,15 @@ package evaluator
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"crypto/rand"
+	"encoding/base64"
 	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	mrand "math/rand"
@@ -13,6 +18,8 @@ import (
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -25,6 +32,7 @@ import (
 	"github.com/abs-lang/abs/parser"
 	"github.com/abs-lang/abs/token"
 	"github.com/abs-lang/abs/util"
+	"github.com/charmbracelet/x/term"
 	"github.com/iancoleman/strcase"
 )
 
@@ -40,6 +48,13 @@ func init() {
 	requireCache = make(map[string]object.Object)
 }
 
+// ResetRequireCache forgets the modules required so
+// far, so that they're loaded again, eg. once they
+// changed.
+func ResetRequireCache() {
+	requireCache = make(map[string]object.Object)
+}
+
 /*
 Here be the hairy map to all the Builtin Functions ... ARRRGH, matey
 */
@@ -48,7 +63,7 @@ func GetFns() map[string]*object.Builtin {
 	return map[string]*object.Builtin{
 		// len(var:"hello")
 		"len": &object.Builtin{
-			Types: []string{object.STRING_OBJ, object.ARRAY_OBJ},
+			Types: []string{object.STRING_OBJ, object.ARRAY_OBJ, object.BYTES_OBJ},
 			Fn:    lenFn,
 			Doc:   "returns the length of the given variable",
 		},
@@ -148,7 +163,7 @@ func GetFns() map[string]*object.Builtin {
 		},
 		// number(string:"1.23456")
 		"number": &object.Builtin{
-			Types: []string{object.STRING_OBJ, object.NUMBER_OBJ},
+			Types: []string{object.STRING_OBJ, object.NUMBER_OBJ, object.DURATION_OBJ, object.SIZE_OBJ},
 			Fn:    numberFn,
 			Doc:   "converts the given variable to a number",
 		},
@@ -248,6 +263,24 @@ func GetFns() map[string]*object.Builtin {
 			Fn:    minFn,
 			Doc:   "returns the smallest element in an array",
 		},
+		// avg(array:[1, 2, 3])
+		"avg": &object.Builtin{
+			Types: []string{object.ARRAY_OBJ},
+			Fn:    avgFn,
+			Doc:   "returns the average of the elements in an array",
+		},
+		// median(array:[1, 2, 3])
+		"median": &object.Builtin{
+			Types: []string{object.ARRAY_OBJ},
+			Fn:    medianFn,
+			Doc:   "returns the median of the elements in an array",
+		},
+		// percentile(array:[1, 2, 3], 95)
+		"percentile": &object.Builtin{
+			Types: []string{object.ARRAY_OBJ},
+			Fn:    percentileFn,
+			Doc:   "returns the p-th percentile of the elements in an array, interpolating between them",
+		},
 		// reduce(array:[1, 2, 3], f(){}, accumulator)
 		"reduce": &object.Builtin{
 			Types: []string{object.ARRAY_OBJ},
@@ -475,9 +508,9 @@ func GetFns() map[string]*object.Builtin {
 			Types: []string{object.ARRAY_OBJ},
 			Fn:    joinFn,
 		},
-		// sleep(3000)
+		// sleep(3000) or sleep(3s)
 		"sleep": &object.Builtin{
-			Types: []string{object.NUMBER_OBJ},
+			Types: []string{object.NUMBER_OBJ, object.DURATION_OBJ},
 			Fn:    sleepFn,
 		},
 		// source("file.abs") -- source a file, with access to the global environment
@@ -518,6 +551,551 @@ func GetFns() map[string]*object.Builtin {
 			Standalone: true,
 			Doc:        "returns the current unix epoch, in milliseconds",
 		},
+		// secret_get("service", "account") -- reads a secret from the OS keychain
+		"secret_get": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         secretGetFn,
+			Standalone: true,
+			Doc:        "reads a secret from the OS keychain, returning null if it doesn't exist",
+		},
+		// secret_set("service", "account", "value") -- stores a secret in the OS keychain
+		"secret_set": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         secretSetFn,
+			Standalone: true,
+			Doc:        "stores a secret in the OS keychain",
+		},
+		// secret_delete("service", "account") -- removes a secret from the OS keychain
+		"secret_delete": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         secretDeleteFn,
+			Standalone: true,
+			Doc:        "removes a secret from the OS keychain",
+		},
+		// clipboard_read() -- returns the text on the clipboard
+		"clipboard_read": &object.Builtin{
+			Types:      []string{},
+			Fn:         clipboardReadFn,
+			Standalone: true,
+			Doc:        "returns the text on the clipboard",
+		},
+		// clipboard_write("text") -- puts text on the clipboard
+		"clipboard_write": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         clipboardWriteFn,
+			Standalone: true,
+			Doc:        "puts text on the clipboard",
+		},
+		// tty_size() -- {"width": 120, "height": 40}
+		"tty_size": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ},
+			Fn:         ttySizeFn,
+			Standalone: true,
+			Doc:        "returns the width and height of the terminal",
+		},
+		// tty_is_tty(1) -- whether stdout is a terminal
+		"tty_is_tty": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ},
+			Fn:         ttyIsTTYFn,
+			Standalone: true,
+			Doc:        "returns whether a file descriptor (0 for stdin, 1 for stdout, 2 for stderr) is a terminal",
+		},
+		// tty_supports_color() -- whether colors can be printed to stdout
+		"tty_supports_color": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ},
+			Fn:         ttySupportsColorFn,
+			Standalone: true,
+			Doc:        "returns whether colors can be printed, honoring NO_COLOR and FORCE_COLOR",
+		},
+		// ps_list() -- [{"pid": 1, "cmd": "/sbin/init", "cpu": 0.1, "mem": 9678848}, ...]
+		"ps_list": &object.Builtin{
+			Types:      []string{},
+			Fn:         psListFn,
+			Standalone: true,
+			Doc:        "returns the processes running on the system, with their pid, command, CPU and memory usage",
+		},
+		// ps_exists(1234) -- whether process 1234 is running
+		"ps_exists": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ},
+			Fn:         psExistsFn,
+			Standalone: true,
+			Doc:        "returns whether a process is running",
+		},
+		// aws_sign({"url": "https://...", "region": "us-east-1", "service": "s3"}, {"access_key": "...", "secret_key": "..."})
+		"aws_sign": &object.Builtin{
+			Types:      []string{object.HASH_OBJ},
+			Fn:         awsSignFn,
+			Standalone: true,
+			Doc:        "signs a request with AWS SigV4, returning the headers to send it with",
+		},
+		// aws_metadata("placement/region") -- reads from the EC2 instance metadata service
+		"aws_metadata": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         awsMetadataFn,
+			Standalone: true,
+			Doc:        "reads a value from the EC2 instance metadata service",
+		},
+		// webhook_verify_github(body, headers["X-Hub-Signature-256"], "secret")
+		"webhook_verify_github": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         webhookVerifyGitHubFn,
+			Standalone: true,
+			Doc:        "checks the signature of a GitHub webhook, in constant time",
+		},
+		// webhook_verify_stripe(body, headers["Stripe-Signature"], "whsec_...")
+		"webhook_verify_stripe": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         webhookVerifyStripeFn,
+			Standalone: true,
+			Doc:        "checks the signature and age of a Stripe webhook, in constant time",
+		},
+		// webhook_verify_slack(body, headers["X-Slack-Request-Timestamp"], headers["X-Slack-Signature"], "secret")
+		"webhook_verify_slack": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         webhookVerifySlackFn,
+			Standalone: true,
+			Doc:        "checks the signature and age of a Slack request, in constant time",
+		},
+		// jwt_sign({"sub": "me"}, "secret", "HS256") -- encodes claims into a signed JWT
+		"jwt_sign": &object.Builtin{
+			Types:      []string{object.HASH_OBJ},
+			Fn:         jwtSignFn,
+			Standalone: true,
+			Doc:        "encodes claims into a JWT, signed with HS256 or RS256",
+		},
+		// jwt_decode(token, {"key": "secret"}) -- verifies a JWT and returns its claims
+		"jwt_decode": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         jwtDecodeFn,
+			Standalone: true,
+			Doc:        "returns the claims of a JWT, verifying its signature and expiry",
+		},
+		// docker_ps() -- lists the running containers
+		"docker_ps": &object.Builtin{
+			Types:      []string{object.BOOLEAN_OBJ},
+			Fn:         dockerPsFn,
+			Standalone: true,
+			Doc:        "lists the containers running on the docker engine",
+		},
+		// docker_run("alpine", {"cmd": "echo hello"}) -- runs a container
+		"docker_run": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         dockerRunFn,
+			Standalone: true,
+			Doc:        "runs a container, returning its output once it's done",
+		},
+		// docker_logs(id, f(line) {...}) -- reads or follows the logs of a container
+		"docker_logs": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         dockerLogsFn,
+			Standalone: true,
+			Doc:        "returns the logs of a container, or calls a function with each line as it's printed",
+		},
+		// git_status() -- {"branch": "master", "clean": false, "files": [...], ...}
+		"git_status": &object.Builtin{
+			Types:      []string{},
+			Fn:         gitStatusFn,
+			Standalone: true,
+			Doc:        "returns the branch of the git repository and the files that changed",
+		},
+		// git_log({"n": 10}) -- lists the last commits
+		"git_log": &object.Builtin{
+			Types:      []string{object.HASH_OBJ},
+			Fn:         gitLogFn,
+			Standalone: true,
+			Doc:        "lists the commits of the git repository",
+		},
+		// git_branches() -- lists the local branches
+		"git_branches": &object.Builtin{
+			Types:      []string{},
+			Fn:         gitBranchesFn,
+			Standalone: true,
+			Doc:        "lists the local branches of the git repository",
+		},
+		// git_branch("release/1.2", {"checkout": true}) -- creates a branch
+		"git_branch": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         gitBranchFn,
+			Standalone: true,
+			Doc:        "creates a branch at HEAD, optionally switching to it",
+		},
+		// git_commit("Release 1.2", {"all": true}) -- commits, returning the hash of the commit
+		"git_commit": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         gitCommitFn,
+			Standalone: true,
+			Doc:        "commits the staged (or given) files, returning the hash of the commit",
+		},
+		// git_clone("https://github.com/abs-lang/abs.git", "abs") -- clones a repository
+		"git_clone": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         gitCloneFn,
+			Standalone: true,
+			Doc:        "clones a git repository into a directory",
+		},
+		// git_diff({"staged": true}) -- lists the files that changed, with the lines added and deleted
+		"git_diff": &object.Builtin{
+			Types:      []string{object.HASH_OBJ},
+			Fn:         gitDiffFn,
+			Standalone: true,
+			Doc:        "lists the files that changed in the git repository, with the lines added and deleted",
+		},
+		// metrics_counter("jobs_total", "Jobs processed") -- registers a Prometheus counter
+		"metrics_counter": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         metricsCounterFn,
+			Standalone: true,
+			Doc:        "registers a Prometheus counter",
+		},
+		// metrics_gauge("queue_size", "Jobs waiting") -- registers a Prometheus gauge
+		"metrics_gauge": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         metricsGaugeFn,
+			Standalone: true,
+			Doc:        "registers a Prometheus gauge",
+		},
+		// metrics_histogram("job_seconds", "Job duration", [0.1, 1, 10]) -- registers a Prometheus histogram
+		"metrics_histogram": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         metricsHistogramFn,
+			Standalone: true,
+			Doc:        "registers a Prometheus histogram",
+		},
+		// metrics_render() -- renders all metrics in the Prometheus text format
+		"metrics_render": &object.Builtin{
+			Types:      []string{},
+			Fn:         metricsRenderFn,
+			Standalone: true,
+			Doc:        "renders all metrics in the Prometheus text format",
+		},
+		// metrics_serve(9100) -- exposes metrics at /metrics on the given port
+		"metrics_serve": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ},
+			Fn:         metricsServeFn,
+			Standalone: true,
+			Doc:        "exposes metrics at /metrics on the given port, in the background",
+		},
+		// checkpoint("step-7") -- persists the global environment to disk
+		"checkpoint": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         checkpointFn,
+			Standalone: true,
+			Doc:        "persists the global environment to disk, so that the script can resume() from here",
+		},
+		// resume() -- restores the environment from the last checkpoint
+		"resume": &object.Builtin{
+			Types:      []string{},
+			Fn:         resumeFn,
+			Standalone: true,
+			Doc:        "restores the environment saved by the last checkpoint, returning its name",
+		},
+		// checkpoint_clear() -- removes the last checkpoint
+		"checkpoint_clear": &object.Builtin{
+			Types:      []string{},
+			Fn:         checkpointClearFn,
+			Standalone: true,
+			Doc:        "removes the last checkpoint, so that the next run starts from scratch",
+		},
+		// set_dry_run(true) -- prints commands instead of executing them
+		"set_dry_run": &object.Builtin{
+			Types:      []string{object.BOOLEAN_OBJ},
+			Fn:         setDryRunFn,
+			Standalone: true,
+			Doc:        "when enabled, commands are printed instead of being executed",
+		},
+		// with_cwd("/tmp", f() { `ls` }) -- runs commands within fn in the given directory
+		"with_cwd": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         withCwdFn,
+			Standalone: true,
+			Doc:        "calls the function, running the commands it executes in the given directory",
+		},
+		// bytes("abc") -- raw bytes of a string or command output
+		"bytes": &object.Builtin{
+			Types: []string{object.STRING_OBJ, object.ARRAY_OBJ, object.BYTES_OBJ},
+			Fn:    bytesFn,
+			Doc:   "converts the given string, command output or array of numbers to bytes",
+		},
+		// hex(bytes("abc"))
+		"hex": &object.Builtin{
+			Types: []string{object.BYTES_OBJ},
+			Fn:    hexFn,
+			Doc:   "returns the hexadecimal encoding of the bytes",
+		},
+		// base64(bytes("abc"))
+		"base64": &object.Builtin{
+			Types: []string{object.BYTES_OBJ},
+			Fn:    base64Fn,
+			Doc:   "returns the base64 encoding of the bytes",
+		},
+		// freeze({"a": [1, 2]})
+		"freeze": &object.Builtin{
+			Types: []string{},
+			Fn:    freezeFn,
+			Doc:   "returns a deeply immutable copy of the given array or hash",
+		},
+		// is_frozen({"a": 1})
+		"is_frozen": &object.Builtin{
+			Types: []string{object.ARRAY_OBJ, object.HASH_OBJ},
+			Fn:    isFrozenFn,
+			Doc:   "checks whether the given array or hash is frozen",
+		},
+		// cache(100, 5min) -- an LRU cache, with an optional TTL
+		"cache": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ},
+			Fn:         cacheFn,
+			Standalone: true,
+			Doc:        "creates an LRU cache holding up to max_entries, with an optional TTL",
+		},
+		// events() -- an event emitter, eg. events().on("done", f(data) {...})
+		"events": &object.Builtin{
+			Types:      []string{},
+			Fn:         eventsFn,
+			Standalone: true,
+			Doc:        "creates an event emitter, with on(name, fn) and emit(name, data)",
+		},
+		// last_command() -- status, output and duration of the latest command
+		"last_command": &object.Builtin{
+			Types:      []string{},
+			Fn:         lastCommandFn,
+			Standalone: true,
+			Doc:        "returns the status, output and duration of the latest command",
+		},
+		// command("ls {dir}", {"dir": dir}) -- runs a command, shell-escaping its arguments
+		"command": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         commandFn,
+			Standalone: true,
+			Doc:        "runs a command template, replacing {placeholders} with shell-escaped arguments",
+		},
+		// shell_escape("it's") -- quotes a string as a single shell word
+		"shell_escape": &object.Builtin{
+			Types: []string{object.STRING_OBJ},
+			Fn:    shellEscapeFn,
+			Doc:   "quotes the string as a single shell word, with POSIX or Windows rules",
+		},
+		// shell_split("a 'b c'") -- splits a command line into words
+		"shell_split": &object.Builtin{
+			Types: []string{object.STRING_OBJ},
+			Fn:    shellSplitFn,
+			Doc:   "splits the string into words the way a POSIX or Windows shell would",
+		},
+		// invert({"a": "x"}) -- swaps keys and values: {"x": "a"}
+		"invert": &object.Builtin{
+			Types: []string{object.HASH_OBJ},
+			Fn:    invertFn,
+			Doc:   "returns a new hash with keys and values swapped",
+		},
+		// pick({"a": 1, "b": 2}, ["a"]) -- {"a": 1}
+		"pick": &object.Builtin{
+			Types: []string{object.HASH_OBJ},
+			Fn:    pickFn,
+			Doc:   "returns a new hash with only the given keys",
+		},
+		// omit({"a": 1, "b": 2}, ["a"]) -- {"b": 2}
+		"omit": &object.Builtin{
+			Types: []string{object.HASH_OBJ},
+			Fn:    omitFn,
+			Doc:   "returns a new hash without the given keys",
+		},
+		// map_values({"a": 1}, f(v, k) { v * 2 }) -- {"a": 2}
+		"map_values": &object.Builtin{
+			Types: []string{object.HASH_OBJ},
+			Fn:    mapValuesFn,
+			Doc:   "returns a new hash with each value replaced by fn(value, key)",
+		},
+		// entries({"a": 1, "b": 2}) -- [["a", 1], ["b", 2]], sorted by key
+		"entries": &object.Builtin{
+			Types: []string{object.HASH_OBJ},
+			Fn:    entriesFn,
+			Doc:   "returns the [key, value] pairs of the hash, sorted by key",
+		},
+		// from_entries([["a", 1], ["b", 2]]) -- {"a": 1, "b": 2}
+		"from_entries": &object.Builtin{
+			Types: []string{object.ARRAY_OBJ},
+			Fn:    fromEntriesFn,
+			Doc:   "builds a hash out of an array of [key, value] pairs",
+		},
+		// each_line("a\nb", f(line) {...}) -- also streams `cmd`.each_line(f(line) {...})
+		"each_line": &object.Builtin{
+			Types: []string{object.STRING_OBJ},
+			Fn:    eachLineFn,
+			Doc:   "calls a function with each line of the string, or of a command's output as it's printed",
+		},
+		// fs_lines("/var/log/syslog") -- iterates over the lines of a file
+		"fs_lines": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         fsLinesFn,
+			Standalone: true,
+			Doc:        "returns an iterator over the lines of a file, reading one line at a time",
+		},
+		// with_timeout(5s, f() {...}) -- bounds how long a function can run
+		"with_timeout": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ, object.DURATION_OBJ},
+			Fn:         withTimeoutFn,
+			Standalone: true,
+			Doc:        "calls a function, returning an error if it doesn't complete within the given time",
+		},
+		// retry(f() {...}, {"attempts": 5, "backoff": "exponential"}) -- retries a function until it succeeds
+		"retry": &object.Builtin{
+			Types:      []string{object.FUNCTION_OBJ},
+			Fn:         retryFn,
+			Standalone: true,
+			Doc:        "calls a function until it succeeds, waiting longer and longer between attempts",
+		},
+		// rate_limit(10) or rate_limit(10, 5) -- a limiter allowing 10 calls per second, in bursts of 5
+		"rate_limit": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ},
+			Fn:         rateLimitFn,
+			Standalone: true,
+			Doc:        "creates a rate limiter, whose wait() blocks to allow n calls per second",
+		},
+		// semver_parse("1.2.3-beta") -- {"major": 1, "minor": 2, "patch": 3, "prerelease": "beta", "build": ""}
+		"semver_parse": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         semverParseFn,
+			Standalone: true,
+			Doc:        "parses a semantic version into its major, minor, patch, prerelease and build parts",
+		},
+		// semver_valid("1.2.3") -- true
+		"semver_valid": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         semverValidFn,
+			Standalone: true,
+			Doc:        "checks whether the string is a valid semantic version",
+		},
+		// semver_compare("1.2.3", "1.10.0") -- -1
+		"semver_compare": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         semverCompareFn,
+			Standalone: true,
+			Doc:        "compares 2 semantic versions, returning -1, 0 or 1",
+		},
+		// semver_satisfies("1.5.0", "^1.2") -- true
+		"semver_satisfies": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         semverSatisfiesFn,
+			Standalone: true,
+			Doc:        "checks whether a semantic version satisfies a range such as ^1.2 or >=1.0 <2",
+		},
+		// parse_table(`docker ps`) -- [{"CONTAINER ID": "4c01db0b339c", "IMAGE": "nginx", ...}]
+		"parse_table": &object.Builtin{
+			Types: []string{object.STRING_OBJ},
+			Fn:    parseTableFn,
+			Doc:   "parses column-aligned text, such as the output of docker ps, into an array of hashes",
+		},
+		// humanize_bytes(1500000) -- "1.5 MB"
+		"humanize_bytes": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ, object.SIZE_OBJ},
+			Fn:         humanizeBytesFn,
+			Standalone: true,
+			Doc:        "formats a number of bytes with the most readable unit, eg. 1.5 MB",
+		},
+		// humanize_duration(5400000) -- "1h 30m"
+		"humanize_duration": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ, object.DURATION_OBJ},
+			Fn:         humanizeDurationFn,
+			Standalone: true,
+			Doc:        "formats a duration, in ms, with its 2 largest units, eg. 1h 30m",
+		},
+		// humanize_number(1234567) -- "1,234,567"
+		"humanize_number": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ},
+			Fn:         humanizeNumberFn,
+			Standalone: true,
+			Doc:        "formats a number with thousands separators, eg. 1,234,567",
+		},
+		// humanize_time_ago(unix_ms() - 300000) -- "5 minutes ago"
+		"humanize_time_ago": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ, object.STRING_OBJ},
+			Fn:         humanizeTimeAgoFn,
+			Standalone: true,
+			Doc:        "describes how long ago a time (unix ms or RFC 3339) was, eg. 5 minutes ago",
+		},
+		// format_number(1234.5, {"locale": "de-DE", "decimals": 2}) -- "1.234,50"
+		"format_number": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ},
+			Fn:         formatNumberFn,
+			Standalone: true,
+			Doc:        "formats a number with the separators of a locale, eg. 1.234,50 in de-DE",
+		},
+		// format_date(unix_ms(), {"locale": "fr-FR", "style": "long"}) -- "5 mars 2024"
+		"format_date": &object.Builtin{
+			Types:      []string{object.NUMBER_OBJ, object.STRING_OBJ},
+			Fn:         formatDateFn,
+			Standalone: true,
+			Doc:        "formats a date (unix ms or RFC 3339) the way a locale writes it, eg. 5 mars 2024 in fr-FR",
+		},
+		// mock_command("kubectl get pods*", "web-1 Running")
+		"mock_command": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         mockCommandFn,
+			Standalone: true,
+			Doc:        "answers commands matching a pattern (eg. kubectl *) with a canned output, rather than running them",
+		},
+		// mock_http("GET https://api.example.com/*", {"status": 200, "body": "ok"})
+		"mock_http": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         mockHttpFn,
+			Standalone: true,
+			Doc:        "answers HTTP requests matching a pattern (eg. GET https://example.com/*) with a canned response",
+		},
+		// reset_mocks()
+		"reset_mocks": &object.Builtin{
+			Types:      []string{},
+			Fn:         resetMocksFn,
+			Standalone: true,
+			Doc:        "removes all mocks registered through mock_command(...) and mock_http(...)",
+		},
+		// assert_golden("report", report)
+		"assert_golden": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         assertGoldenFn,
+			Standalone: true,
+			Doc:        "compares a value against testdata/name.golden, which abs test -update (re)writes",
+		},
+		// gen_int(0, 10)
+		"gen_int": &object.Builtin{
+			Types:      []string{},
+			Fn:         genIntFn,
+			Standalone: true,
+			Doc:        "returns a generator of whole numbers, between min and max if given, for quickcheck(...)",
+		},
+		// gen_string(10)
+		"gen_string": &object.Builtin{
+			Types:      []string{},
+			Fn:         genStringFn,
+			Standalone: true,
+			Doc:        "returns a generator of strings, up to max_len characters if given, for quickcheck(...)",
+		},
+		// gen_array_of(gen_int())
+		"gen_array_of": &object.Builtin{
+			Types:      []string{object.HASH_OBJ},
+			Fn:         genArrayOfFn,
+			Standalone: true,
+			Doc:        "returns a generator of arrays whose elements come from another generator, for quickcheck(...)",
+		},
+		// gen_hash_of(gen_int())
+		"gen_hash_of": &object.Builtin{
+			Types:      []string{object.HASH_OBJ},
+			Fn:         genHashOfFn,
+			Standalone: true,
+			Doc:        "returns a generator of hashes whose values come from another generator, for quickcheck(...)",
+		},
+		// quickcheck(f(x = gen_int()) { x + 0 == x }, {"runs": 100})
+		"quickcheck": &object.Builtin{
+			Types:      []string{object.FUNCTION_OBJ},
+			Fn:         quickcheckFn,
+			Standalone: true,
+			Doc:        "checks a property against random inputs, shrinking the first counterexample it finds",
+		},
+		// bundle_module("bundle://lib/util.abs", f() { ... })
+		"bundle_module": &object.Builtin{
+			Types:      []string{object.STRING_OBJ},
+			Fn:         bundleModuleFn,
+			Standalone: true,
+			Doc:        "registers a file inlined by abs bundle, so that require(...) and source(...) can find it",
+		},
 	}
 }
 
@@ -611,7 +1189,7 @@ func usageVarArgs(name string, specs [][][]string) string {
 
 // len(var:"hello")
 func lenFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
-	err := validateArgs(tok, "len", args, 1, [][]string{{object.STRING_OBJ, object.ARRAY_OBJ}})
+	err := validateArgs(tok, "len", args, 1, [][]string{{object.STRING_OBJ, object.ARRAY_OBJ, object.BYTES_OBJ}})
 	if err != nil {
 		return err
 	}
@@ -621,6 +1199,8 @@ func lenFn(tok token.Token, env *object.Environment, args ...object.Object) obje
 		return &object.Number{Token: tok, Value: float64(len(arg.Elements))}
 	case *object.String:
 		return &object.Number{Token: tok, Value: float64(len(arg.Value))}
+	case *object.Bytes:
+		return &object.Number{Token: tok, Value: float64(len(arg.Value))}
 	default:
 		return newError(tok, "argument to `len` not supported, got %s", args[0].Type())
 	}
@@ -643,6 +1223,12 @@ func randFn(tok token.Token, env *object.Environment, args ...object.Object) obj
 	return &object.Number{Token: tok, Value: float64(r.Int64())}
 }
 
+// ExitHook, when set, is called by exit(...) right
+// before the process terminates, with the exit code,
+// since whoever is running the script won't get
+// control back.
+var ExitHook func(code int)
+
 // exit(code:0)
 // exit(code:0, message:"Adios!")
 func exitFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
@@ -665,6 +1251,14 @@ func exitFn(tok token.Token, env *object.Environment, args ...object.Object) obj
 	}
 
 	arg := args[0].(*object.Number)
+	// we're about to leave without returning
+	// to the runner, let's not lose any span
+	FlushTraces()
+
+	if ExitHook != nil {
+		ExitHook(int(arg.Value))
+	}
+
 	os.Exit(int(arg.Value))
 	return arg
 }
@@ -936,8 +1530,9 @@ func applyMathFunction(tok token.Token, arg object.Object, fn func(float64) floa
 }
 
 // number(string:"1.23456")
+// number(duration:5s)
 func numberFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
-	err := validateArgs(tok, "number", args, 1, [][]string{{object.NUMBER_OBJ, object.STRING_OBJ}})
+	err := validateArgs(tok, "number", args, 1, [][]string{{object.NUMBER_OBJ, object.STRING_OBJ, object.DURATION_OBJ, object.SIZE_OBJ}})
 	if err != nil {
 		return err
 	}
@@ -945,6 +1540,12 @@ func numberFn(tok token.Token, env *object.Environment, args ...object.Object) o
 	switch arg := args[0].(type) {
 	case *object.Number:
 		return arg
+	// Durations are converted to milliseconds,
+	// like the ones sleep(...) accepts
+	case *object.Duration:
+		return &object.Number{Token: tok, Value: float64(arg.Value) / float64(time.Millisecond)}
+	case *object.Size:
+		return &object.Number{Token: tok, Value: float64(arg.Value)}
 	case *object.String:
 		i, err := strconv.ParseFloat(arg.Value, 64)
 
@@ -1248,27 +1849,18 @@ func sumFn(tok token.Token, env *object.Environment, args ...object.Object) obje
 		return err
 	}
 
-	arr := args[0].(*object.Array)
-	if arr.Empty() {
-		return &object.Number{Token: tok, Value: float64(0)}
-	}
-
-	if !arr.Homogeneous() {
-		return newError(tok, "sum(...) can only be called on an homogeneous array, got %s", arr.Inspect())
-	}
-
-	if arr.Elements[0].Type() != object.NUMBER_OBJ {
-		return newError(tok, "sum(...) can only be called on arrays of numbers, got %s", arr.Inspect())
+	values, like, err := statValues(tok, "sum", args[0].(*object.Array))
+	if err != nil {
+		return err
 	}
 
 	var sum float64 = 0
 
-	for _, v := range arr.Elements {
-		elem := v.(*object.Number)
-		sum += elem.Value
+	for _, v := range values {
+		sum += v
 	}
 
-	return &object.Number{Token: tok, Value: sum}
+	return statResult(tok, like, sum)
 }
 
 // max(array:[1, 2, 3])
@@ -1278,30 +1870,9 @@ func maxFn(tok token.Token, env *object.Environment, args ...object.Object) obje
 		return err
 	}
 
-	arr := args[0].(*object.Array)
-	if arr.Empty() {
-		return object.NULL
-	}
-
-	if !arr.Homogeneous() {
-		return newError(tok, "max(...) can only be called on an homogeneous array, got %s", arr.Inspect())
-	}
-
-	if arr.Elements[0].Type() != object.NUMBER_OBJ {
-		return newError(tok, "max(...) can only be called on arrays of numbers, got %s", arr.Inspect())
-	}
-
-	max := arr.Elements[0].(*object.Number).Value
-
-	for _, v := range arr.Elements[1:] {
-		elem := v.(*object.Number)
-
-		if elem.Value > max {
-			max = elem.Value
-		}
-	}
-
-	return &object.Number{Token: tok, Value: max}
+	return arrayStat(tok, "max", args[0].(*object.Array), func(sorted []float64) float64 {
+		return sorted[len(sorted)-1]
+	})
 }
 
 // min(array:[1, 2, 3])
@@ -1311,37 +1882,16 @@ func minFn(tok token.Token, env *object.Environment, args ...object.Object) obje
 		return err
 	}
 
-	arr := args[0].(*object.Array)
-	if arr.Empty() {
-		return object.NULL
-	}
-
-	if !arr.Homogeneous() {
-		return newError(tok, "min(...) can only be called on an homogeneous array, got %s", arr.Inspect())
-	}
-
-	if arr.Elements[0].Type() != object.NUMBER_OBJ {
-		return newError(tok, "min(...) can only be called on arrays of numbers, got %s", arr.Inspect())
-	}
-
-	min := arr.Elements[0].(*object.Number).Value
-
-	for _, v := range arr.Elements[1:] {
-		elem := v.(*object.Number)
-
-		if elem.Value < min {
-			min = elem.Value
-		}
-	}
-
-	return &object.Number{Token: tok, Value: min}
-}
-
-// reduce(array:[1, 2, 3], f(){}, accumulator)
-func reduceFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
-	err := validateArgs(tok, "reduce", args, 3, [][]string{{object.ARRAY_OBJ}, {object.FUNCTION_OBJ}, {object.ANY_OBJ}})
-	if err != nil {
-		return err
+	return arrayStat(tok, "min", args[0].(*object.Array), func(sorted []float64) float64 {
+		return sorted[0]
+	})
+}
+
+// reduce(array:[1, 2, 3], f(){}, accumulator)
+func reduceFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "reduce", args, 3, [][]string{{object.ARRAY_OBJ}, {object.FUNCTION_OBJ}, {object.ANY_OBJ}})
+	if err != nil {
+		return err
 	}
 
 	accumulator := args[2]
@@ -2007,6 +2557,9 @@ func shiftFn(tok token.Token, env *object.Environment, args ...object.Object) ob
 	}
 
 	array := args[0].(*object.Array)
+	if err := checkFrozen(tok, array); err != nil {
+		return err
+	}
 	if len(array.Elements) == 0 {
 		return NULL
 	}
@@ -2079,6 +2632,9 @@ func pushFn(tok token.Token, env *object.Environment, args ...object.Object) obj
 	}
 
 	array := args[0].(*object.Array)
+	if err := checkFrozen(tok, array); err != nil {
+		return err
+	}
 	array.Elements = append(array.Elements, args[1])
 
 	return array
@@ -2102,6 +2658,9 @@ func popFn(tok token.Token, env *object.Environment, args ...object.Object) obje
 	if len(args) < 1 {
 		return NULL
 	}
+	if err := checkFrozen(tok, args[0]); err != nil {
+		return err
+	}
 	switch arg := args[0].(type) {
 	case *object.Array:
 		if len(arg.Elements) > 0 {
@@ -2213,13 +2772,25 @@ func joinFn(tok token.Token, env *object.Environment, args ...object.Object) obj
 }
 
 func sleepFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
-	err := validateArgs(tok, "sleep", args, 1, [][]string{{object.NUMBER_OBJ}})
+	err := validateArgs(tok, "sleep", args, 1, [][]string{{object.NUMBER_OBJ, object.DURATION_OBJ}})
 	if err != nil {
 		return err
 	}
 
-	ms := args[0].(*object.Number)
-	time.Sleep(time.Duration(ms.Value) * time.Millisecond)
+	switch arg := args[0].(type) {
+	case *object.Duration:
+		idleSleep(env, arg.Value)
+	case *object.Number:
+		idleSleep(env, time.Duration(arg.Value)*time.Millisecond)
+	}
+
+	if interrupted.Load() {
+		return &object.Error{Message: "program interrupted"}
+	}
+
+	if deadlineExpired(env) {
+		return &object.Error{Message: deadlineExceeded}
+	}
 
 	return NULL
 }
@@ -2231,6 +2802,10 @@ var sourceDepth, _ = strconv.Atoi(ABS_SOURCE_DEPTH)
 var sourceLevel = 0
 
 func sourceFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	if isBundled(args[0].Inspect()) {
+		return evalBundledModule(tok, args[0].Inspect(), env)
+	}
+
 	file, _ := util.ExpandPath(args[0].Inspect())
 	return doSource(tok, env, file, args...)
 }
@@ -2257,6 +2832,23 @@ func requireFn(tok token.Token, env *object.Environment, args ...object.Object)
 		packageAliasesLoaded = true
 	}
 
+	// Files inlined in a bundle are evaluated
+	// just once, as any other required file
+	if isBundled(args[0].Inspect()) {
+		if evaluated, ok := requireCache[args[0].Inspect()]; ok {
+			return evaluated
+		}
+
+		e := object.NewEnvironment(object.SystemStdio, env.Dir, env.Version, env.Interactive)
+		e.Context = env.Context
+		evaluated := evalBundledModule(tok, args[0].Inspect(), e)
+		if !isError(evaluated) {
+			requireCache[args[0].Inspect()] = evaluated
+		}
+
+		return evaluated
+	}
+
 	file := util.UnaliasPath(args[0].Inspect(), packageAliases)
 
 	if !strings.HasPrefix(file, "@") {
@@ -2268,6 +2860,7 @@ func requireFn(tok token.Token, env *object.Environment, args ...object.Object)
 	}
 
 	e := object.NewEnvironment(object.SystemStdio, filepath.Dir(file), env.Version, env.Interactive)
+	e.Context = env.Context
 	evaluated := doSource(tok, e, file, args...)
 
 	// If a module fails to be imported, let's
@@ -2525,6 +3118,15 @@ func tsvFn(tok token.Token, env *object.Environment, args ...object.Object) obje
 	return &object.String{Value: strings.TrimSpace(out.String())}
 }
 
+// InteractiveExec, when set, is used by exec(...) to
+// run commands that need the real terminal: the REPL
+// sets it to hand its TTY over to programs such as vim
+// or ssh, and to take it back once they're done. Commands
+// in backticks don't go through it, as their output is
+// captured rather than printed to the terminal.
+var InteractiveExec func(c *exec.Cmd) error
+
+// exec(command:"vim file.txt")
 func execFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
 	err := validateArgs(tok, "exec", args, 1, [][]string{{object.STRING_OBJ}})
 	if err != nil {
@@ -2536,10 +3138,20 @@ func execFn(tok token.Token, env *object.Environment, args ...object.Object) obj
 	// interpolate any $vars in the cmd string
 	cmd = util.InterpolateStringVars(cmd, env)
 
+	if err := checkCommand(env, cmd); err != nil {
+		return newError(tok, "command not allowed: %s", err.Error())
+	}
+
+	if isDryRun(env) {
+		fmt.Fprintf(env.Stdio.Stdout, "dry-run: %s\n", cmd)
+		return NULL
+	}
+
 	// set up command to execute using our stdIO
 	parts := strings.Split(os.Getenv("ABS_COMMAND_EXECUTOR"), " ")
 	c := exec.Command(parts[0], append(parts[1:], cmd)...)
 	c.Env = os.Environ()
+	c.Dir = commandDir(env)
 	c.Stdin = env.Stdio.Stdin
 	c.Stdout = env.Stdio.Stdout
 	c.Stderr = env.Stdio.Stderr
@@ -2547,10 +3159,1227 @@ func execFn(tok token.Token, env *object.Environment, args ...object.Object) obj
 	// N.B. that a bash command may end with '&' --
 	// in this case bash will launch it as a daemon process and then exit c.Run() immediately
 	// this may require pkill to terminate the daemon process using the pid
-	runErr := c.Run()
+	start := time.Now()
+	var runErr error
+	if InteractiveExec != nil {
+		runErr = InteractiveExec(c)
+	} else {
+		runErr = c.Run()
+	}
+	auditCommand(c, cmd, start, AuditFunc)
 
 	if runErr != nil {
 		return &object.String{Value: runErr.Error()}
 	}
 	return NULL
 }
+
+// secret_get("service", "account")
+func secretGetFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "secret_get", args, 2, [][]string{{object.STRING_OBJ}, {object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	secret, e := util.GetSecret(args[0].Inspect(), args[1].Inspect())
+
+	if e == util.ErrSecretNotFound {
+		return NULL
+	}
+
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return &object.String{Token: tok, Value: secret}
+}
+
+// secret_set("service", "account", "value")
+func secretSetFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "secret_set", args, 3, [][]string{{object.STRING_OBJ}, {object.STRING_OBJ}, {object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	e := util.SetSecret(args[0].Inspect(), args[1].Inspect(), args[2].Inspect())
+
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return NULL
+}
+
+// secret_delete("service", "account")
+func secretDeleteFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "secret_delete", args, 2, [][]string{{object.STRING_OBJ}, {object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	e := util.DeleteSecret(args[0].Inspect(), args[1].Inspect())
+
+	if e == util.ErrSecretNotFound {
+		return FALSE
+	}
+
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return TRUE
+}
+
+// clipboard_read()
+func clipboardReadFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, _ := validateVarArgs(tok, "clipboard_read", args, [][][]string{{}})
+	if err != nil {
+		return err
+	}
+
+	text, e := util.ReadClipboard()
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return &object.String{Token: tok, Value: text}
+}
+
+// clipboard_write("text")
+func clipboardWriteFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "clipboard_write", args, 1, [][]string{{object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	// without a clipboard (eg. over SSH) we can
+	// ask the terminal to take care of it
+	var terminal io.Writer
+	if term.IsTerminal(os.Stderr.Fd()) {
+		terminal = os.Stderr
+	}
+
+	if e := util.WriteClipboard(args[0].Inspect(), terminal); e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return NULL
+}
+
+// The file descriptor passed to a tty_* function,
+// stdout by default
+func ttyFd(tok token.Token, name string, args []object.Object) (uintptr, object.Object) {
+	err, spec := validateVarArgs(tok, name, args, [][][]string{
+		{},
+		{{object.NUMBER_OBJ}},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if spec == 0 {
+		return 1, nil
+	}
+
+	n := args[0].(*object.Number)
+	if !n.IsInt() || n.Value < 0 {
+		return 0, newError(tok, "%s(...) expects a file descriptor, got %s", name, n.Inspect())
+	}
+
+	return uintptr(n.Int()), nil
+}
+
+// tty_size() or tty_size(2)
+func ttySizeFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	fd, err := ttyFd(tok, "tty_size", args)
+	if err != nil {
+		return err
+	}
+
+	width, height, e := util.TTYSize(fd, func(name string) string { return util.GetEnvVar(env, name, "") })
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return nativeToObject(tok, map[string]interface{}{"width": float64(width), "height": float64(height)})
+}
+
+// tty_is_tty(1)
+func ttyIsTTYFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	fd, err := ttyFd(tok, "tty_is_tty", args)
+	if err != nil {
+		return err
+	}
+
+	return nativeBoolToBooleanObject(util.IsTTY(fd))
+}
+
+// tty_supports_color() or tty_supports_color(2)
+func ttySupportsColorFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	fd, err := ttyFd(tok, "tty_supports_color", args)
+	if err != nil {
+		return err
+	}
+
+	return nativeBoolToBooleanObject(util.SupportsColor(fd, func(name string) string { return util.GetEnvVar(env, name, "") }))
+}
+
+// ps_list()
+func psListFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, _ := validateVarArgs(tok, "ps_list", args, [][][]string{{}})
+	if err != nil {
+		return err
+	}
+
+	processes, e := util.ListProcesses(commandRunner(env))
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	list := []interface{}{}
+	for _, p := range processes {
+		list = append(list, map[string]interface{}{
+			"pid": float64(p.Pid),
+			"cmd": p.Cmd,
+			"cpu": p.Cpu,
+			"mem": float64(p.Mem),
+		})
+	}
+
+	return nativeToObject(tok, list)
+}
+
+// ps_exists(1234)
+func psExistsFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "ps_exists", args, 1, [][]string{{object.NUMBER_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	pid := args[0].(*object.Number)
+	if !pid.IsInt() {
+		return newError(tok, "ps_exists(...) expects a pid, got %s", pid.Inspect())
+	}
+
+	exists, e := util.ProcessExists(pid.Int())
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return nativeBoolToBooleanObject(exists)
+}
+
+// aws_sign({"method": "GET", "url": "https://...", "headers": {}, "body": "", "region": "us-east-1", "service": "s3"}, {"access_key": "...", "secret_key": "...", "session_token": "..."})
+func awsSignFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "aws_sign", args, 2, [][]string{{object.HASH_OBJ}, {object.HASH_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	request := args[0].(*object.Hash)
+	creds := args[1].(*object.Hash)
+
+	req := util.AWSRequest{
+		Method:  hashString(request, "method"),
+		URL:     hashString(request, "url"),
+		Body:    hashString(request, "body"),
+		Region:  hashString(request, "region"),
+		Service: hashString(request, "service"),
+		Headers: map[string]string{},
+	}
+
+	if headers, ok := request.GetPair("headers"); ok {
+		h, ok := headers.Value.(*object.Hash)
+		if !ok {
+			return newError(tok, "headers to sign must be a hash, got %s", headers.Value.Type())
+		}
+
+		for _, pair := range h.Pairs {
+			req.Headers[pair.Key.Inspect()] = pair.Value.Inspect()
+		}
+	}
+
+	if date := hashString(request, "date"); date != "" {
+		t, e := time.Parse("20060102T150405Z", date)
+		if e != nil {
+			return newError(tok, "invalid date '%s', expected a format like 20150830T123600Z", date)
+		}
+		req.Time = t
+	}
+
+	// Keys can be passed either in snake case
+	// or as returned by the metadata service
+	c := util.AWSCredentials{
+		AccessKey:    hashString(creds, "access_key", "AccessKeyId"),
+		SecretKey:    hashString(creds, "secret_key", "SecretAccessKey"),
+		SessionToken: hashString(creds, "session_token", "Token"),
+	}
+
+	headers, e := util.SignAWSRequest(req, c)
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	pairs := make(map[object.HashKey]object.HashPair)
+	for k, v := range headers {
+		key := &object.String{Token: tok, Value: k}
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: &object.String{Token: tok, Value: v}}
+	}
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
+
+// aws_metadata("placement/region")
+func awsMetadataFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "aws_metadata", args, 1, [][]string{{object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	endpoint := util.GetEnvVar(env, "ABS_AWS_METADATA_ENDPOINT", "http://169.254.169.254")
+	value, e := util.AWSMetadata(endpoint, args[0].Inspect())
+
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return &object.String{Token: tok, Value: value}
+}
+
+// webhook_verify_github(body, "sha256=...", "secret")
+func webhookVerifyGitHubFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "webhook_verify_github", args, 3, [][]string{{object.STRING_OBJ}, {object.STRING_OBJ}, {object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	e := util.VerifyGitHubWebhook(args[0].Inspect(), args[1].Inspect(), args[2].Inspect())
+
+	return nativeBoolToBooleanObject(e == nil)
+}
+
+// webhook_verify_stripe(body, "t=...,v1=...", "whsec_...", 5m)
+func webhookVerifyStripeFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	tolerance, err := webhookTolerance(tok, "webhook_verify_stripe", args, 3)
+	if err != nil {
+		return err
+	}
+
+	e := util.VerifyStripeWebhook(args[0].Inspect(), args[1].Inspect(), args[2].Inspect(), tolerance, time.Now())
+
+	return nativeBoolToBooleanObject(e == nil)
+}
+
+// webhook_verify_slack(body, "1531420618", "v0=...", "secret", 5m)
+func webhookVerifySlackFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	tolerance, err := webhookTolerance(tok, "webhook_verify_slack", args, 4)
+	if err != nil {
+		return err
+	}
+
+	e := util.VerifySlackWebhook(args[0].Inspect(), args[1].Inspect(), args[2].Inspect(), args[3].Inspect(), tolerance, time.Now())
+
+	return nativeBoolToBooleanObject(e == nil)
+}
+
+// Validates the arguments of webhook verifiers taking n strings
+// and an optional tolerance, either as a duration or in milliseconds
+func webhookTolerance(tok token.Token, name string, args []object.Object, n int) (time.Duration, object.Object) {
+	strs := make([][]string, n)
+	for i := range strs {
+		strs[i] = []string{object.STRING_OBJ}
+	}
+
+	err, _ := validateVarArgs(tok, name, args, [][][]string{
+		strs,
+		append(strs, []string{object.NUMBER_OBJ, object.DURATION_OBJ}),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(args) == n {
+		return util.WebhookTolerance, nil
+	}
+
+	switch arg := args[n].(type) {
+	case *object.Duration:
+		return arg.Value, nil
+	case *object.Number:
+		return time.Duration(arg.Value) * time.Millisecond, nil
+	}
+
+	return util.WebhookTolerance, nil
+}
+
+// jwt_sign({"sub": "me"}, "secret", "HS256")
+func jwtSignFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "jwt_sign", args, [][][]string{
+		{{object.HASH_OBJ}, {object.STRING_OBJ}},
+		{{object.HASH_OBJ}, {object.STRING_OBJ}, {object.STRING_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	claims, ok := objectToNative(args[0])
+	if !ok {
+		return newError(tok, "claims can only hold strings, numbers, booleans, arrays, hashes and null")
+	}
+
+	alg := "HS256"
+	if spec == 1 {
+		alg = args[2].Inspect()
+	}
+
+	jwt, e := util.SignJWT(claims.(map[string]interface{}), args[1].Inspect(), alg)
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return &object.String{Token: tok, Value: jwt}
+}
+
+// jwt_decode(token, {"verify": true, "key": "secret"})
+func jwtDecodeFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "jwt_decode", args, [][][]string{
+		{{object.STRING_OBJ}},
+		{{object.STRING_OBJ}, {object.HASH_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	verify, key := true, ""
+	if spec == 1 {
+		opts := args[1].(*object.Hash)
+		key = hashString(opts, "key")
+
+		if pair, ok := opts.GetPair("verify"); ok {
+			verify = pair.Value.Type() == object.BOOLEAN_OBJ && pair.Value.(*object.Boolean).Value
+		}
+	}
+
+	var claims map[string]interface{}
+	var e error
+
+	if verify {
+		if key == "" {
+			return newError(tok, "a key is required to verify the token, or pass {\"verify\": false} to skip verification")
+		}
+
+		claims, e = util.VerifyJWT(args[0].Inspect(), key, time.Now())
+	} else {
+		_, claims, e = util.DecodeJWT(args[0].Inspect())
+	}
+
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return nativeToObject(tok, claims)
+}
+
+// Returns the value of the first of the given keys
+// that's found in the hash, as a string.
+func hashString(h *object.Hash, keys ...string) string {
+	for _, k := range keys {
+		if pair, ok := h.GetPair(k); ok && pair.Value.Type() != object.NULL_OBJ {
+			return pair.Value.Inspect()
+		}
+	}
+
+	return ""
+}
+
+var metricNameRegex = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// metrics_counter("jobs_total", "Jobs processed")
+func metricsCounterFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	return registerMetric(tok, "metrics_counter", "counter", args)
+}
+
+// metrics_gauge("queue_size", "Jobs waiting")
+func metricsGaugeFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	return registerMetric(tok, "metrics_gauge", "gauge", args)
+}
+
+// metrics_histogram("job_seconds", "Job duration", [0.1, 1, 10])
+func metricsHistogramFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	return registerMetric(tok, "metrics_histogram", "histogram", args)
+}
+
+func registerMetric(tok token.Token, name string, kind string, args []object.Object) object.Object {
+	specs := [][][]string{
+		{{object.STRING_OBJ}},
+		{{object.STRING_OBJ}, {object.STRING_OBJ}},
+	}
+
+	if kind == "histogram" {
+		specs = append(specs, [][]string{{object.STRING_OBJ}, {object.STRING_OBJ}, {object.ARRAY_OBJ}})
+	}
+
+	err, spec := validateVarArgs(tok, name, args, specs)
+	if err != nil {
+		return err
+	}
+
+	metricName := args[0].Inspect()
+	if !metricNameRegex.MatchString(metricName) {
+		return newError(tok, "invalid metric name '%s'", metricName)
+	}
+
+	help := ""
+	if spec > 0 {
+		help = args[1].Inspect()
+	}
+
+	buckets := []float64{}
+	if kind == "histogram" {
+		buckets = defaultBuckets
+	}
+
+	if spec == 2 {
+		buckets = []float64{}
+		for _, b := range args[2].(*object.Array).Elements {
+			n, ok := b.(*object.Number)
+			if !ok {
+				return newError(tok, "histogram buckets must be numbers, got %s", b.Inspect())
+			}
+			buckets = append(buckets, n.Value)
+		}
+		sort.Float64s(buckets)
+	}
+
+	m, e := metricsRegistry.register(metricName, help, kind, buckets)
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return metricObject(tok, m)
+}
+
+// metrics_render()
+func metricsRenderFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	return &object.String{Token: tok, Value: metricsRegistry.Render()}
+}
+
+// metrics_serve(9100)
+func metricsServeFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "metrics_serve", args, 1, [][]string{{object.NUMBER_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	e := serveMetrics(args[0].(*object.Number).Int())
+	if e != nil {
+		return newError(tok, "unable to serve metrics: %s", e.Error())
+	}
+
+	return NULL
+}
+
+// checkpoint("step-7")
+func checkpointFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "checkpoint", args, 1, [][]string{{object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	e := saveCheckpoint(env, args[0].Inspect())
+	if e != nil {
+		return newError(tok, "unable to save checkpoint: %s", e.Error())
+	}
+
+	return NULL
+}
+
+// resume()
+func resumeFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	name, e := loadCheckpoint(tok, env)
+	if e != nil {
+		return newError(tok, "unable to resume: %s", e.Error())
+	}
+
+	if name == "" {
+		return NULL
+	}
+
+	return &object.String{Token: tok, Value: name}
+}
+
+// checkpoint_clear()
+func checkpointClearFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	e := os.Remove(checkpointPath(env))
+
+	if os.IsNotExist(e) {
+		return FALSE
+	}
+
+	if e != nil {
+		return newError(tok, "unable to clear checkpoint: %s", e.Error())
+	}
+
+	return TRUE
+}
+
+// set_dry_run(true)
+func setDryRunFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "set_dry_run", args, 1, [][]string{{object.BOOLEAN_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	// The ABS env takes precedence over the OS one,
+	// so we need to make sure we're not shadowed
+	env.Root().Delete("ABS_DRY_RUN")
+	os.Setenv("ABS_DRY_RUN", args[0].Inspect())
+
+	return NULL
+}
+
+// with_cwd("/tmp", f() { `ls` })
+func withCwdFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "with_cwd", args, 2, [][]string{{object.STRING_OBJ}, {object.FUNCTION_OBJ, object.BUILTIN_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	dir, e := resolveCommandDir(env, args[0].Inspect())
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	// The process' CWD is left untouched, we only
+	// change where the commands fn runs are executed
+	scoped := object.NewEnclosedEnvironment(env, env.CurrentArgs)
+	scoped.Context = context.WithValue(envContext(env), commandDirKey{}, dir)
+
+	return applyFunction(tok, args[1], scoped, []object.Object{})
+}
+
+// bytes("abc")
+func bytesFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "bytes", args, 1, [][]string{{object.STRING_OBJ, object.ARRAY_OBJ, object.BYTES_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Bytes:
+		return arg
+	case *object.String:
+		// Commands keep their raw output around, so
+		// we can skip the (lossy) trimmed string value
+		if arg.Ok != nil && arg.Ok.Value && arg.Stdout != nil {
+			return &object.Bytes{Token: tok, Value: bytes.Clone(arg.Stdout.Bytes())}
+		}
+
+		return &object.Bytes{Token: tok, Value: []byte(arg.Value)}
+	case *object.Array:
+		b := make([]byte, len(arg.Elements))
+
+		for i, e := range arg.Elements {
+			n, ok := e.(*object.Number)
+
+			if !ok || !n.IsInt() || n.Value < 0 || n.Value > 255 {
+				return newError(tok, "bytes(...) can only be built from numbers between 0 and 255, got %s", e.Inspect())
+			}
+
+			b[i] = byte(n.Value)
+		}
+
+		return &object.Bytes{Token: tok, Value: b}
+	}
+
+	return NULL
+}
+
+// hex(bytes("abc"))
+func hexFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "hex", args, 1, [][]string{{object.BYTES_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Token: tok, Value: hex.EncodeToString(args[0].(*object.Bytes).Value)}
+}
+
+// base64(bytes("abc"))
+func base64Fn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "base64", args, 1, [][]string{{object.BYTES_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Token: tok, Value: base64.StdEncoding.EncodeToString(args[0].(*object.Bytes).Value)}
+}
+
+// freeze({"a": [1, 2]})
+func freezeFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "freeze", args, 1, [][]string{{object.ANY_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	return deepFreeze(args[0])
+}
+
+// is_frozen({"a": 1})
+func isFrozenFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "is_frozen", args, 1, [][]string{{object.ARRAY_OBJ, object.HASH_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Array:
+		return nativeBoolToBooleanObject(arg.Frozen)
+	case *object.Hash:
+		return nativeBoolToBooleanObject(arg.Frozen)
+	}
+
+	return FALSE
+}
+
+// cache(100) or cache(100, 5min) or cache(100, 300000)
+func cacheFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "cache", args, [][][]string{
+		{{object.NUMBER_OBJ}},
+		{{object.NUMBER_OBJ}, {object.DURATION_OBJ, object.NUMBER_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	maxEntries := args[0].(*object.Number).Int()
+	if maxEntries < 1 {
+		return newError(tok, "cache(...) needs to hold at least 1 entry, got %d", maxEntries)
+	}
+
+	var ttl time.Duration
+	if spec == 1 {
+		switch arg := args[1].(type) {
+		case *object.Duration:
+			ttl = arg.Value
+		case *object.Number:
+			ttl = time.Duration(arg.Value) * time.Millisecond
+		}
+	}
+
+	return cacheObject(tok, newLRUCache(maxEntries, ttl))
+}
+
+// events()
+func eventsFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	return emitterObject(tok, &emitter{handlers: map[string][]object.Object{}})
+}
+
+// last_command()
+func lastCommandFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	return lastCommandObject(tok)
+}
+
+// Placeholders in command templates, eg. {dir}
+var commandPlaceholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// command("grep {pattern} {files}", {"pattern": p, "files": ["a", "b"]})
+func commandFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "command", args, 2, [][]string{{object.STRING_OBJ}, {object.HASH_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	tpl := args[0].Inspect()
+	values := args[1].(*object.Hash)
+	cmd := strings.Builder{}
+	last := 0
+
+	for _, m := range commandPlaceholder.FindAllStringSubmatchIndex(tpl, -1) {
+		// ${VAR} is left to the shell
+		if m[0] > 0 && tpl[m[0]-1] == '$' {
+			continue
+		}
+
+		name := tpl[m[2]:m[3]]
+		pair, ok := values.GetPair(name)
+		if !ok {
+			return newError(tok, "command(...) has no value for placeholder {%s}", name)
+		}
+
+		// Arrays expand to multiple arguments
+		words := []string{}
+		if arr, ok := pair.Value.(*object.Array); ok {
+			for _, e := range arr.Elements {
+				words = append(words, util.ShellEscape(e.Inspect()))
+			}
+		} else {
+			words = append(words, util.ShellEscape(pair.Value.Inspect()))
+		}
+
+		cmd.WriteString(tpl[last:m[0]])
+		cmd.WriteString(strings.Join(words, " "))
+		last = m[1]
+	}
+	cmd.WriteString(tpl[last:])
+
+	return evalCommandExpression(tok, cmd.String(), env, commandOptions{dir: commandDir(env), raw: true})
+}
+
+// Quoting rules used by shell_escape(...) and shell_split(...)
+// when none are given: Windows ones on Windows, POSIX elsewhere
+func shellStyle(tok token.Token, name string, args []object.Object) (string, object.Object) {
+	style := "posix"
+	if runtime.GOOS == "windows" {
+		style = "windows"
+	}
+
+	if len(args) > 1 {
+		style = args[1].Inspect()
+	}
+
+	if style != "posix" && style != "windows" {
+		return "", newError(tok, "%s(...) supports either posix or windows quoting, got %s", name, style)
+	}
+
+	return style, nil
+}
+
+// "it's".shell_escape() or "it's".shell_escape("windows")
+func shellEscapeFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, _ := validateVarArgs(tok, "shell_escape", args, [][][]string{
+		{{object.STRING_OBJ}},
+		{{object.STRING_OBJ}, {object.STRING_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	style, err := shellStyle(tok, "shell_escape", args)
+	if err != nil {
+		return err
+	}
+
+	if style == "windows" {
+		return &object.String{Token: tok, Value: util.WindowsShellEscape(args[0].Inspect())}
+	}
+
+	return &object.String{Token: tok, Value: util.ShellEscape(args[0].Inspect())}
+}
+
+// "a 'b c'".shell_split() or "a \"b c\"".shell_split("windows")
+func shellSplitFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, _ := validateVarArgs(tok, "shell_split", args, [][][]string{
+		{{object.STRING_OBJ}},
+		{{object.STRING_OBJ}, {object.STRING_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	style, err := shellStyle(tok, "shell_split", args)
+	if err != nil {
+		return err
+	}
+
+	var words []string
+	if style == "windows" {
+		words = util.WindowsShellSplit(args[0].Inspect())
+	} else {
+		var e error
+		words, e = util.ShellSplit(args[0].Inspect())
+		if e != nil {
+			return newError(tok, "shell_split(...) is unable to split %s: %s", args[0].Inspect(), e.Error())
+		}
+	}
+
+	result := &object.Array{Token: tok, Elements: []object.Object{}}
+	for _, w := range words {
+		result.Elements = append(result.Elements, &object.String{Token: tok, Value: w})
+	}
+
+	return result
+}
+
+// Hash keys are strings: numbers and booleans
+// are converted, anything else can't be a key
+func hashKeyOf(o object.Object) (*object.String, bool) {
+	switch o := o.(type) {
+	case *object.String:
+		return o, true
+	case *object.Number, *object.Boolean:
+		return &object.String{Value: o.Inspect()}, true
+	}
+
+	return nil, false
+}
+
+// Keys passed to pick(...) and omit(...)
+func hashKeysArg(tok token.Token, name string, arr *object.Array) (map[object.HashKey]bool, object.Object) {
+	keys := map[object.HashKey]bool{}
+
+	for _, e := range arr.Elements {
+		key, ok := hashKeyOf(e)
+		if !ok {
+			return nil, newError(tok, "%s(...) requires an array of keys, got %s", name, e.Type())
+		}
+
+		keys[key.HashKey()] = true
+	}
+
+	return keys, nil
+}
+
+// {"a": "x"}.invert()
+func invertFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "invert", args, 1, [][]string{{object.HASH_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	pairs := map[object.HashKey]object.HashPair{}
+	for _, pair := range args[0].(*object.Hash).Pairs {
+		key, ok := hashKeyOf(pair.Value)
+		if !ok {
+			return newError(tok, "invert(...) can only use strings, numbers and booleans as keys, got %s", pair.Value.Type())
+		}
+
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: pair.Key}
+	}
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
+
+// {"a": 1, "b": 2}.pick(["a"])
+func pickFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	return filterHashKeys(tok, "pick", true, args)
+}
+
+// {"a": 1, "b": 2}.omit(["a"])
+func omitFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	return filterHashKeys(tok, "omit", false, args)
+}
+
+func filterHashKeys(tok token.Token, name string, keep bool, args []object.Object) object.Object {
+	err := validateArgs(tok, name, args, 2, [][]string{{object.HASH_OBJ}, {object.ARRAY_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	keys, err := hashKeysArg(tok, name, args[1].(*object.Array))
+	if err != nil {
+		return err
+	}
+
+	pairs := map[object.HashKey]object.HashPair{}
+	for k, pair := range args[0].(*object.Hash).Pairs {
+		if keys[k] == keep {
+			pairs[k] = pair
+		}
+	}
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
+
+// {"a": 1}.map_values(f(v, k) { v * 2 })
+func mapValuesFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "map_values", args, 2, [][]string{{object.HASH_OBJ}, {object.FUNCTION_OBJ, object.BUILTIN_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	pairs := map[object.HashKey]object.HashPair{}
+	for k, pair := range args[0].(*object.Hash).Pairs {
+		evaluated := applyFunction(tok, args[1], env, []object.Object{pair.Value, pair.Key})
+		if isError(evaluated) {
+			return evaluated
+		}
+
+		pairs[k] = object.HashPair{Key: pair.Key, Value: evaluated}
+	}
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
+
+// {"a": 1, "b": 2}.entries()
+func entriesFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "entries", args, 1, [][]string{{object.HASH_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	hash := args[0].(*object.Hash)
+	keys := []string{}
+	for k := range hash.Pairs {
+		keys = append(keys, k.Value)
+	}
+	sort.Strings(keys)
+
+	entries := []object.Object{}
+	for _, k := range keys {
+		pair, _ := hash.GetPair(k)
+		entries = append(entries, &object.Array{Token: tok, Elements: []object.Object{pair.Key, pair.Value}})
+	}
+
+	return &object.Array{Token: tok, Elements: entries}
+}
+
+// [["a", 1], ["b", 2]].from_entries()
+func fromEntriesFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "from_entries", args, 1, [][]string{{object.ARRAY_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	pairs := map[object.HashKey]object.HashPair{}
+	for _, e := range args[0].(*object.Array).Elements {
+		entry, ok := e.(*object.Array)
+		if !ok || len(entry.Elements) != 2 {
+			return newError(tok, "from_entries(...) requires an array of [key, value] pairs, got %s", e.Inspect())
+		}
+
+		key, ok := hashKeyOf(entry.Elements[0])
+		if !ok {
+			return newError(tok, "from_entries(...) can only use strings, numbers and booleans as keys, got %s", entry.Elements[0].Type())
+		}
+
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: entry.Elements[1]}
+	}
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
+
+// rate_limit(10) or rate_limit(10, 5)
+func rateLimitFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "rate_limit", args, [][][]string{
+		{{object.NUMBER_OBJ}},
+		{{object.NUMBER_OBJ}, {object.NUMBER_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	perSecond := args[0].(*object.Number).Value
+	if perSecond <= 0 {
+		return newError(tok, "rate_limit(...) requires a rate greater than 0, got %s", args[0].Inspect())
+	}
+
+	burst := 1
+	if spec == 1 {
+		burst = args[1].(*object.Number).Int()
+		if burst < 1 {
+			return newError(tok, "rate_limit(...) requires a burst of at least 1, got %s", args[1].Inspect())
+		}
+	}
+
+	return rateLimiterObject(tok, newRateLimiter(perSecond, burst))
+}
+
+// Parses the version in args[i] for the given semver_* function
+func semverArg(tok token.Token, args []object.Object, i int) (util.Version, object.Object) {
+	v, err := util.ParseVersion(args[i].Inspect())
+	if err != nil {
+		return v, newError(tok, "%s", err.Error())
+	}
+
+	return v, nil
+}
+
+// semver_parse("1.2.3-beta+build")
+func semverParseFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "semver_parse", args, 1, [][]string{{object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	v, err := semverArg(tok, args, 0)
+	if err != nil {
+		return err
+	}
+
+	parts := map[string]object.Object{
+		"major":      &object.Number{Token: tok, Value: float64(v.Major)},
+		"minor":      &object.Number{Token: tok, Value: float64(v.Minor)},
+		"patch":      &object.Number{Token: tok, Value: float64(v.Patch)},
+		"prerelease": &object.String{Token: tok, Value: strings.Join(v.Prerelease, ".")},
+		"build":      &object.String{Token: tok, Value: v.Build},
+		"version":    &object.String{Token: tok, Value: v.String()},
+	}
+
+	pairs := make(map[object.HashKey]object.HashPair)
+	for k, v := range parts {
+		key := &object.String{Token: tok, Value: k}
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: v}
+	}
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
+
+// semver_valid("1.2.3")
+func semverValidFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "semver_valid", args, 1, [][]string{{object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	_, e := util.ParseVersion(args[0].Inspect())
+
+	return nativeBoolToBooleanObject(e == nil)
+}
+
+// semver_compare("1.2.3", "1.10.0")
+func semverCompareFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "semver_compare", args, 2, [][]string{{object.STRING_OBJ}, {object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	a, err := semverArg(tok, args, 0)
+	if err != nil {
+		return err
+	}
+
+	b, err := semverArg(tok, args, 1)
+	if err != nil {
+		return err
+	}
+
+	return &object.Number{Token: tok, Value: float64(util.CompareVersions(a, b))}
+}
+
+// semver_satisfies("1.5.0", "^1.2")
+func semverSatisfiesFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "semver_satisfies", args, 2, [][]string{{object.STRING_OBJ}, {object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	v, err := semverArg(tok, args, 0)
+	if err != nil {
+		return err
+	}
+
+	ok, e := util.SatisfiesVersion(v, args[1].Inspect())
+	if e != nil {
+		return newError(tok, "invalid range '%s': %s", args[1].Inspect(), e.Error())
+	}
+
+	return nativeBoolToBooleanObject(ok)
+}
+
+// parse_table(`docker ps`) or parse_table(`ps -e`, {"headers": ["pid", "tty", "time", "cmd"]})
+func parseTableFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "parse_table", args, [][][]string{
+		{{object.STRING_OBJ}},
+		{{object.STRING_OBJ}, {object.HASH_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	headers := []string{}
+	if spec == 1 {
+		for _, pair := range args[1].(*object.Hash).Pairs {
+			if pair.Key.Inspect() != "headers" {
+				return newError(tok, "parse_table(...) doesn't support the option '%s'", pair.Key.Inspect())
+			}
+
+			arr, ok := pair.Value.(*object.Array)
+			if !ok || arr.Empty() {
+				return newError(tok, "parse_table(...) requires headers to be a non-empty array, got %s", pair.Value.Inspect())
+			}
+
+			for _, h := range arr.Elements {
+				headers = append(headers, h.Inspect())
+			}
+		}
+	}
+
+	headers, rows := util.ParseTable(args[0].Inspect(), headers)
+
+	result := &object.Array{Token: tok, Elements: []object.Object{}}
+	for _, row := range rows {
+		pairs := make(map[object.HashKey]object.HashPair)
+		for i, h := range headers {
+			key := &object.String{Token: tok, Value: h}
+			pairs[key.HashKey()] = object.HashPair{Key: key, Value: &object.String{Token: tok, Value: row[i]}}
+		}
+
+		result.Elements = append(result.Elements, &object.Hash{Token: tok, Pairs: pairs})
+	}
+
+	return result
+}
+
+// humanize_bytes(1500000) or humanize_bytes(1536, true)
+func humanizeBytesFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "humanize_bytes", args, [][][]string{
+		{{object.NUMBER_OBJ, object.SIZE_OBJ}},
+		{{object.NUMBER_OBJ, object.SIZE_OBJ}, {object.BOOLEAN_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	binary := spec == 1 && args[1].(*object.Boolean).Value
+
+	return &object.String{Token: tok, Value: util.HumanizeBytes(quantityValue(args[0]), binary)}
+}
+
+// humanize_duration(5400000) or humanize_duration(90min)
+func humanizeDurationFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "humanize_duration", args, 1, [][]string{{object.NUMBER_OBJ, object.DURATION_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	var d time.Duration
+	switch arg := args[0].(type) {
+	case *object.Duration:
+		d = arg.Value
+	case *object.Number:
+		d = time.Duration(arg.Value * float64(time.Millisecond))
+	}
+
+	return &object.String{Token: tok, Value: util.HumanizeDuration(d)}
+}
+
+// humanize_number(1234567)
+func humanizeNumberFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "humanize_number", args, 1, [][]string{{object.NUMBER_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Token: tok, Value: util.HumanizeNumber(args[0].(*object.Number).Value)}
+}
+
+// Reads a time given either in unix ms
+// (as returned by unix_ms()) or as an RFC 3339 string
+func timeArg(tok token.Token, fn string, o object.Object) (time.Time, object.Object) {
+	if s, ok := o.(*object.String); ok {
+		t, err := time.Parse(time.RFC3339, s.Value)
+		if err != nil {
+			return t, newError(tok, "%s(...) requires unix ms or an RFC 3339 time (eg. 2024-01-01T12:00:00Z), got %s", fn, s.Value)
+		}
+
+		return t, nil
+	}
+
+	return time.UnixMilli(int64(o.(*object.Number).Int())), nil
+}
+
+// humanize_time_ago(1700000000000) or humanize_time_ago("2024-01-01T12:00:00Z")
+func humanizeTimeAgoFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "humanize_time_ago", args, 1, [][]string{{object.NUMBER_OBJ, object.STRING_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	t, err := timeArg(tok, "humanize_time_ago", args[0])
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Token: tok, Value: util.HumanizeTimeAgo(t, time.Now())}
+}
@@ -0,0 +1,285 @@
+package evaluator
+
+import (
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+	"github.com/abs-lang/abs/util"
+)
+
+// The repository commands would run in, so that git_*
+// functions follow with_cwd(...). Like any other command
+// git is subject to the allow / deny lists, dry-run mode
+// and is audited.
+func gitRepo(env *object.Environment) util.GitRepo {
+	return util.GitRepo{Dir: commandDir(env), Run: commandRunner(env)}
+}
+
+// Reads the options hash of a git_* function, erroring
+// out on the ones it doesn't know or of the wrong type
+func parseGitOptions(tok token.Token, name string, h *object.Hash, types map[string]object.ObjectType) (map[string]object.Object, object.Object) {
+	options := map[string]object.Object{}
+
+	for _, pair := range h.Pairs {
+		key := pair.Key.Inspect()
+		t, ok := types[key]
+		if !ok {
+			return nil, newError(tok, "unknown %s(...) option '%s'", name, key)
+		}
+
+		if pair.Value.Type() != t {
+			return nil, newError(tok, "%s(...) option '%s' must be a %s, got %s", name, key, t, pair.Value.Type())
+		}
+
+		options[key] = pair.Value
+	}
+
+	return options, nil
+}
+
+// The options hash passed as args[i], if any
+func gitOptionsArg(args []object.Object, i int) *object.Hash {
+	if len(args) > i {
+		return args[i].(*object.Hash)
+	}
+
+	return &object.Hash{Pairs: map[object.HashKey]object.HashPair{}}
+}
+
+func gitString(options map[string]object.Object, key string) string {
+	if v, ok := options[key]; ok {
+		return v.Inspect()
+	}
+
+	return ""
+}
+
+func gitBool(options map[string]object.Object, key string) bool {
+	v, ok := options[key]
+	return ok && v.(*object.Boolean).Value
+}
+
+func gitInt(options map[string]object.Object, key string) int {
+	if v, ok := options[key]; ok {
+		return int(v.(*object.Number).Value)
+	}
+
+	return 0
+}
+
+// git_status()
+func gitStatusFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, _ := validateVarArgs(tok, "git_status", args, [][][]string{{}})
+	if err != nil {
+		return err
+	}
+
+	status, e := gitRepo(env).Status()
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	files := []interface{}{}
+	for _, f := range status.Files {
+		files = append(files, map[string]interface{}{
+			"path":      f.Path,
+			"orig_path": f.OrigPath,
+			"staged":    f.Staged,
+			"unstaged":  f.Unstaged,
+		})
+	}
+
+	return nativeToObject(tok, map[string]interface{}{
+		"branch":   status.Branch,
+		"head":     status.Head,
+		"upstream": status.Upstream,
+		"ahead":    float64(status.Ahead),
+		"behind":   float64(status.Behind),
+		"clean":    len(status.Files) == 0,
+		"files":    files,
+	})
+}
+
+// git_log() or git_log({"n": 10, "ref": "v1.0..HEAD", "path": "docs"})
+func gitLogFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, _ := validateVarArgs(tok, "git_log", args, [][][]string{
+		{},
+		{{object.HASH_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	options, err := parseGitOptions(tok, "git_log", gitOptionsArg(args, 0), map[string]object.ObjectType{
+		"n":    object.NUMBER_OBJ,
+		"ref":  object.STRING_OBJ,
+		"path": object.STRING_OBJ,
+	})
+	if err != nil {
+		return err
+	}
+
+	commits, e := gitRepo(env).Log(gitInt(options, "n"), gitString(options, "ref"), gitString(options, "path"))
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	elements := []object.Object{}
+	for _, c := range commits {
+		elements = append(elements, nativeToObject(tok, map[string]interface{}{
+			"hash":    c.Hash,
+			"author":  c.Author,
+			"email":   c.Email,
+			"date":    float64(c.Date),
+			"subject": c.Subject,
+			"body":    c.Body,
+		}))
+	}
+
+	return &object.Array{Token: tok, Elements: elements}
+}
+
+// git_branches()
+func gitBranchesFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, _ := validateVarArgs(tok, "git_branches", args, [][][]string{{}})
+	if err != nil {
+		return err
+	}
+
+	branches, e := gitRepo(env).Branches()
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	elements := []object.Object{}
+	for _, b := range branches {
+		elements = append(elements, nativeToObject(tok, map[string]interface{}{
+			"name":     b.Name,
+			"hash":     b.Hash,
+			"upstream": b.Upstream,
+			"current":  b.Current,
+		}))
+	}
+
+	return &object.Array{Token: tok, Elements: elements}
+}
+
+// git_branch("release/1.2") or git_branch("release/1.2", {"checkout": true})
+func gitBranchFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, _ := validateVarArgs(tok, "git_branch", args, [][][]string{
+		{{object.STRING_OBJ}},
+		{{object.STRING_OBJ}, {object.HASH_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	options, err := parseGitOptions(tok, "git_branch", gitOptionsArg(args, 1), map[string]object.ObjectType{
+		"checkout": object.BOOLEAN_OBJ,
+	})
+	if err != nil {
+		return err
+	}
+
+	if e := gitRepo(env).CreateBranch(args[0].Inspect(), gitBool(options, "checkout")); e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return NULL
+}
+
+// git_commit("Release 1.2", {"all": true}) or git_commit("Bump", {"files": ["VERSION"]})
+func gitCommitFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, _ := validateVarArgs(tok, "git_commit", args, [][][]string{
+		{{object.STRING_OBJ}},
+		{{object.STRING_OBJ}, {object.HASH_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	options, err := parseGitOptions(tok, "git_commit", gitOptionsArg(args, 1), map[string]object.ObjectType{
+		"all":   object.BOOLEAN_OBJ,
+		"files": object.ARRAY_OBJ,
+	})
+	if err != nil {
+		return err
+	}
+
+	files := []string{}
+	if arr, ok := options["files"].(*object.Array); ok {
+		for _, e := range arr.Elements {
+			files = append(files, e.Inspect())
+		}
+	}
+
+	hash, e := gitRepo(env).Commit(args[0].Inspect(), files, gitBool(options, "all"))
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return &object.String{Token: tok, Value: hash}
+}
+
+// git_clone("https://github.com/abs-lang/abs.git", "abs", {"depth": 1})
+func gitCloneFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, _ := validateVarArgs(tok, "git_clone", args, [][][]string{
+		{{object.STRING_OBJ}, {object.STRING_OBJ}},
+		{{object.STRING_OBJ}, {object.STRING_OBJ}, {object.HASH_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	options, err := parseGitOptions(tok, "git_clone", gitOptionsArg(args, 2), map[string]object.ObjectType{
+		"branch": object.STRING_OBJ,
+		"depth":  object.NUMBER_OBJ,
+	})
+	if err != nil {
+		return err
+	}
+
+	e := gitRepo(env).Clone(args[0].Inspect(), args[1].Inspect(), gitString(options, "branch"), gitInt(options, "depth"))
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	return NULL
+}
+
+// git_diff() or git_diff({"ref": "v1.0", "staged": true, "path": "docs"})
+func gitDiffFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, _ := validateVarArgs(tok, "git_diff", args, [][][]string{
+		{},
+		{{object.HASH_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	options, err := parseGitOptions(tok, "git_diff", gitOptionsArg(args, 0), map[string]object.ObjectType{
+		"ref":    object.STRING_OBJ,
+		"staged": object.BOOLEAN_OBJ,
+		"path":   object.STRING_OBJ,
+	})
+	if err != nil {
+		return err
+	}
+
+	files, e := gitRepo(env).Diff(gitString(options, "ref"), gitBool(options, "staged"), gitString(options, "path"))
+	if e != nil {
+		return newError(tok, "%s", e.Error())
+	}
+
+	elements := []object.Object{}
+	for _, f := range files {
+		elements = append(elements, nativeToObject(tok, map[string]interface{}{
+			"path":      f.Path,
+			"orig_path": f.OrigPath,
+			"status":    f.Status,
+			"additions": float64(f.Additions),
+			"deletions": float64(f.Deletions),
+		}))
+	}
+
+	return &object.Array{Token: tok, Elements: elements}
+}
@@ -0,0 +1,97 @@
+package evaluator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// Whether golden files should be (re)written rather
+// than compared against, see "abs test -update".
+// Only the process environment counts: a script
+// can't turn it on by setting a variable.
+func isUpdatingGolden() bool {
+	v := os.Getenv("ABS_UPDATE_GOLDEN")
+	return v == "true" || v == "1"
+}
+
+// The content of a golden file: strings are
+// stored as they are, anything else as JSON
+func goldenContent(o object.Object) string {
+	if s, ok := o.(*object.String); ok {
+		return s.Value
+	}
+
+	return o.Json()
+}
+
+// Describes the first line where two
+// versions of a golden file differ
+func goldenDiff(want string, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		w, g := "<EOF>", "<EOF>"
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+
+		if w != g {
+			return fmt.Sprintf("line %d:\n  want: %s\n  got:  %s", i+1, w, g)
+		}
+	}
+
+	return ""
+}
+
+// assert_golden("report", report)
+func assertGoldenFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "assert_golden", args, 2, [][]string{{object.STRING_OBJ}, {object.STRING_OBJ, object.NUMBER_OBJ, object.BOOLEAN_OBJ, object.ARRAY_OBJ, object.HASH_OBJ, object.NULL_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	name := args[0].(*object.String).Value
+	if name == "" || strings.Contains(name, "..") || filepath.IsAbs(name) {
+		return newError(tok, "assert_golden(...) requires a name relative to testdata, got '%s'", name)
+	}
+
+	rel := filepath.Join("testdata", name+".golden")
+	path := filepath.Join(env.Dir, rel)
+	got := goldenContent(args[1])
+
+	if isUpdatingGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return newError(tok, "assert_golden(...) unable to write %s: %s", rel, err.Error())
+		}
+
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			return newError(tok, "assert_golden(...) unable to write %s: %s", rel, err.Error())
+		}
+
+		return TRUE
+	}
+
+	want, e := os.ReadFile(path)
+	if os.IsNotExist(e) {
+		return newError(tok, "assert_golden(...) %s doesn't exist (run abs test -update to create it)", rel)
+	}
+
+	if e != nil {
+		return newError(tok, "assert_golden(...) unable to read %s: %s", rel, e.Error())
+	}
+
+	if string(want) != got {
+		return newError(tok, "assert_golden(...) value doesn't match %s (run abs test -update to accept it), first difference at %s", rel, goldenDiff(string(want), got))
+	}
+
+	return TRUE
+}
@@ -0,0 +1,82 @@
+package evaluator
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// The latest command (eg. `ls -la`) that completed,
+// see last_command()
+var lastCommand *commandRecord
+var lastCommandMux = &sync.Mutex{}
+
+// How many commands have run so far, see CommandsRun()
+var commandsRun int
+
+// The status of commands that couldn't even start
+// (eg. as ABS_COMMAND_EXECUTOR doesn't exist)
+const commandNotStarted = -1
+
+type commandRecord struct {
+	cmd      string
+	status   int
+	stdout   string
+	stderr   string
+	duration time.Duration
+}
+
+// Records a command that just completed,
+// so that last_command() can return it
+func recordLastCommand(s *object.String, cmd string, status int, duration time.Duration) {
+	lastCommandMux.Lock()
+	defer lastCommandMux.Unlock()
+
+	commandsRun++
+	lastCommand = &commandRecord{
+		cmd:      cmd,
+		status:   status,
+		stdout:   strings.TrimSpace(s.Stdout.String()),
+		stderr:   strings.TrimSpace(s.Stderr.String()),
+		duration: duration,
+	}
+}
+
+// CommandsRun returns how many commands have run so
+// far, so that the REPL can tell whether evaluating
+// some code ran any
+func CommandsRun() int {
+	lastCommandMux.Lock()
+	defer lastCommandMux.Unlock()
+
+	return commandsRun
+}
+
+// Builds the hash returned by last_command(),
+// or null if no command has run yet
+func lastCommandObject(tok token.Token) object.Object {
+	lastCommandMux.Lock()
+	defer lastCommandMux.Unlock()
+
+	if lastCommand == nil {
+		return NULL
+	}
+
+	pairs := make(map[object.HashKey]object.HashPair)
+	add := func(k string, v object.Object) {
+		key := &object.String{Token: tok, Value: k}
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: v}
+	}
+
+	add("cmd", &object.String{Token: tok, Value: lastCommand.cmd})
+	add("status", &object.Number{Token: tok, Value: float64(lastCommand.status)})
+	add("ok", nativeBoolToBooleanObject(lastCommand.status == 0))
+	add("stdout", &object.String{Token: tok, Value: lastCommand.stdout})
+	add("stderr", &object.String{Token: tok, Value: lastCommand.stderr})
+	add("duration", &object.Duration{Token: tok, Value: lastCommand.duration})
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
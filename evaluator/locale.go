@@ -0,0 +1,153 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+	"github.com/abs-lang/abs/util"
+)
+
+// Reads the 'locale' option of format_number(...)
+// and format_date(...), defaulting to the
+// locale set in the environment
+func localeOption(tok token.Token, fn string, h *object.Hash) (*util.Locale, object.Object) {
+	pair, ok := h.GetPair("locale")
+	if !ok {
+		return util.DefaultLocale(), nil
+	}
+
+	name, ok := pair.Value.(*object.String)
+	if !ok {
+		return nil, newError(tok, "%s(...) option 'locale' must be a string, got %s", fn, pair.Value.Type())
+	}
+
+	l, ok := util.LookupLocale(name.Value)
+	if !ok {
+		return nil, newError(tok, "%s(...) doesn't support the locale '%s' (supported locales are %s)", fn, name.Value, strings.Join(util.LocaleNames(), ", "))
+	}
+
+	return l, nil
+}
+
+// format_number(1234.5) or format_number(1234.5, {"locale": "de-DE", "decimals": 2})
+func formatNumberFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "format_number", args, [][][]string{
+		{{object.NUMBER_OBJ}},
+		{{object.NUMBER_OBJ}, {object.HASH_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	opts := &object.Hash{Pairs: map[object.HashKey]object.HashPair{}}
+	if spec == 1 {
+		opts = args[1].(*object.Hash)
+	}
+
+	locale, err := localeOption(tok, "format_number", opts)
+	if err != nil {
+		return err
+	}
+
+	decimals := -1
+	thousandsSep := locale.ThousandsSep
+	decimalSep := locale.DecimalSep
+
+	for _, pair := range opts.Pairs {
+		name := pair.Key.Inspect()
+		v := pair.Value
+
+		switch name {
+		case "locale":
+		case "decimals":
+			n, ok := v.(*object.Number)
+			if !ok || n.Value < 0 || n.Value != float64(n.Int()) {
+				return newError(tok, "format_number(...) option 'decimals' must be a whole number greater than or equal to 0, got %s", v.Inspect())
+			}
+			decimals = n.Int()
+		case "thousands_sep", "decimal_sep":
+			s, ok := v.(*object.String)
+			if !ok {
+				return newError(tok, "format_number(...) option '%s' must be a string, got %s", name, v.Type())
+			}
+
+			if name == "thousands_sep" {
+				thousandsSep = s.Value
+			} else {
+				decimalSep = s.Value
+			}
+		default:
+			return newError(tok, "format_number(...) doesn't support the option '%s'", name)
+		}
+	}
+
+	n := args[0].(*object.Number).Value
+
+	return &object.String{Token: tok, Value: util.FormatNumber(n, decimals, thousandsSep, decimalSep)}
+}
+
+// format_date(unix_ms()) or format_date("2024-03-05T14:07:00Z", {"locale": "fr-FR", "style": "long"})
+func formatDateFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "format_date", args, [][][]string{
+		{{object.NUMBER_OBJ, object.STRING_OBJ}},
+		{{object.NUMBER_OBJ, object.STRING_OBJ}, {object.HASH_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	t, err := timeArg(tok, "format_date", args[0])
+	if err != nil {
+		return err
+	}
+
+	opts := &object.Hash{Pairs: map[object.HashKey]object.HashPair{}}
+	if spec == 1 {
+		opts = args[1].(*object.Hash)
+	}
+
+	locale, err := localeOption(tok, "format_date", opts)
+	if err != nil {
+		return err
+	}
+
+	style := "medium"
+	withTime := false
+	t = t.Local()
+
+	for _, pair := range opts.Pairs {
+		name := pair.Key.Inspect()
+		v := pair.Value
+
+		switch name {
+		case "locale":
+		case "style":
+			s, ok := v.(*object.String)
+			if !ok {
+				return newError(tok, "format_date(...) option 'style' must be a string, got %s", v.Type())
+			}
+			style = s.Value
+		case "time", "utc":
+			b, ok := v.(*object.Boolean)
+			if !ok {
+				return newError(tok, "format_date(...) option '%s' must be a boolean, got %s", name, v.Type())
+			}
+
+			if name == "time" {
+				withTime = b.Value
+			} else if b.Value {
+				t = t.UTC()
+			}
+		default:
+			return newError(tok, "format_date(...) doesn't support the option '%s'", name)
+		}
+	}
+
+	s, e := locale.FormatDate(t, style, withTime)
+	if e != nil {
+		return newError(tok, "format_date(...) %s", e.Error())
+	}
+
+	return &object.String{Token: tok, Value: s}
+}
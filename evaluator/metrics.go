@@ -0,0 +1,333 @@
+package evaluator
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// Metrics registered through the @metrics module,
+// exposed in the Prometheus text format.
+var metricsRegistry = &registry{metrics: map[string]*metric{}}
+
+var defaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type registry struct {
+	sync.Mutex
+	metrics map[string]*metric
+}
+
+// A metric is made of one or more series,
+// one for each set of labels it's been updated with.
+type metric struct {
+	name    string
+	help    string
+	kind    string
+	buckets []float64
+	series  map[string]*series
+}
+
+type series struct {
+	labels string
+	value  float64
+	// histograms only
+	counts []float64
+	count  float64
+}
+
+func (r *registry) register(name, help, kind string, buckets []float64) (*metric, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if m, ok := r.metrics[name]; ok {
+		if m.kind != kind {
+			return nil, fmt.Errorf("metric '%s' is already registered as a %s", name, m.kind)
+		}
+
+		return m, nil
+	}
+
+	m := &metric{name: name, help: help, kind: kind, buckets: buckets, series: map[string]*series{}}
+	r.metrics[name] = m
+
+	return m, nil
+}
+
+func (r *registry) update(m *metric, labels *object.Hash, fn func(s *series)) {
+	r.Lock()
+	defer r.Unlock()
+
+	key := formatLabels(labels)
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labels: key, counts: make([]float64, len(m.buckets))}
+		m.series[key] = s
+	}
+
+	fn(s)
+}
+
+func (r *registry) value(m *metric, labels *object.Hash) float64 {
+	r.Lock()
+	defer r.Unlock()
+
+	if s, ok := m.series[formatLabels(labels)]; ok {
+		return s.value
+	}
+
+	return 0
+}
+
+// Render returns all metrics in the Prometheus text exposition format
+func (r *registry) Render() string {
+	r.Lock()
+	defer r.Unlock()
+
+	names := []string{}
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out strings.Builder
+	for _, name := range names {
+		m := r.metrics[name]
+
+		if m.help != "" {
+			fmt.Fprintf(&out, "# HELP %s %s\n", name, strings.ReplaceAll(m.help, "\n", `\n`))
+		}
+		fmt.Fprintf(&out, "# TYPE %s %s\n", name, m.kind)
+
+		keys := []string{}
+		for k := range m.series {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			s := m.series[k]
+
+			if m.kind != "histogram" {
+				fmt.Fprintf(&out, "%s%s %s\n", name, wrapLabels(s.labels), formatMetricValue(s.value))
+				continue
+			}
+
+			for i, b := range m.buckets {
+				fmt.Fprintf(&out, "%s_bucket%s %s\n", name, wrapLabels(joinLabels(s.labels, `le="`+formatMetricValue(b)+`"`)), formatMetricValue(s.counts[i]))
+			}
+			fmt.Fprintf(&out, "%s_bucket%s %s\n", name, wrapLabels(joinLabels(s.labels, `le="+Inf"`)), formatMetricValue(s.count))
+			fmt.Fprintf(&out, "%s_sum%s %s\n", name, wrapLabels(s.labels), formatMetricValue(s.value))
+			fmt.Fprintf(&out, "%s_count%s %s\n", name, wrapLabels(s.labels), formatMetricValue(s.count))
+		}
+	}
+
+	return out.String()
+}
+
+func formatLabels(labels *object.Hash) string {
+	if labels == nil {
+		return ""
+	}
+
+	pairs := []string{}
+	for _, pair := range labels.Pairs {
+		v := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(pair.Value.Inspect())
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, pair.Key.Inspect(), v))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func joinLabels(labels, extra string) string {
+	if labels == "" {
+		return extra
+	}
+
+	return labels + "," + extra
+}
+
+func wrapLabels(labels string) string {
+	if labels == "" {
+		return ""
+	}
+
+	return "{" + labels + "}"
+}
+
+func formatMetricValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Builds the ABS object representing a metric:
+// a hash whose methods update the metric, eg.
+// c = metrics.counter("jobs_total"); c.inc()
+func metricObject(tok token.Token, m *metric) object.Object {
+	methods := map[string]object.BuiltinFunction{}
+
+	// Parses the optional (amount, labels) arguments
+	// accepted by most methods.
+	parse := func(tok token.Token, name string, args []object.Object, def float64) (float64, *object.Hash, object.Object) {
+		err, spec := validateVarArgs(tok, name, args, [][][]string{
+			{},
+			{{object.NUMBER_OBJ}},
+			{{object.HASH_OBJ}},
+			{{object.NUMBER_OBJ}, {object.HASH_OBJ}},
+		})
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch spec {
+		case 1:
+			return args[0].(*object.Number).Value, nil, nil
+		case 2:
+			return def, args[0].(*object.Hash), nil
+		case 3:
+			return args[0].(*object.Number).Value, args[1].(*object.Hash), nil
+		}
+
+		return def, nil, nil
+	}
+
+	methods["value"] = func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+		err, spec := validateVarArgs(tok, "value", args, [][][]string{{}, {{object.HASH_OBJ}}})
+		if err != nil {
+			return err
+		}
+
+		var labels *object.Hash
+		if spec == 1 {
+			labels = args[0].(*object.Hash)
+		}
+
+		return &object.Number{Token: tok, Value: metricsRegistry.value(m, labels)}
+	}
+
+	switch m.kind {
+	case "counter":
+		methods["inc"] = func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			n, labels, err := parse(tok, "inc", args, 1)
+			if err != nil {
+				return err
+			}
+
+			if n < 0 {
+				return newError(tok, "counters can only go up, got %s", formatMetricValue(n))
+			}
+
+			metricsRegistry.update(m, labels, func(s *series) { s.value += n })
+			return NULL
+		}
+	case "gauge":
+		methods["inc"] = func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			n, labels, err := parse(tok, "inc", args, 1)
+			if err != nil {
+				return err
+			}
+
+			metricsRegistry.update(m, labels, func(s *series) { s.value += n })
+			return NULL
+		}
+		methods["dec"] = func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			n, labels, err := parse(tok, "dec", args, 1)
+			if err != nil {
+				return err
+			}
+
+			metricsRegistry.update(m, labels, func(s *series) { s.value -= n })
+			return NULL
+		}
+		methods["set"] = func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			if len(args) == 0 {
+				return newError(tok, "set(...) requires a value")
+			}
+
+			n, labels, err := parse(tok, "set", args, 0)
+			if err != nil {
+				return err
+			}
+
+			metricsRegistry.update(m, labels, func(s *series) { s.value = n })
+			return NULL
+		}
+	case "histogram":
+		methods["observe"] = func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			if len(args) == 0 {
+				return newError(tok, "observe(...) requires a value")
+			}
+
+			n, labels, err := parse(tok, "observe", args, 0)
+			if err != nil {
+				return err
+			}
+
+			metricsRegistry.update(m, labels, func(s *series) {
+				for i, b := range m.buckets {
+					if n <= b {
+						s.counts[i]++
+					}
+				}
+				s.count++
+				s.value += n
+			})
+			return NULL
+		}
+	}
+
+	pairs := make(map[object.HashKey]object.HashPair)
+	add := func(k string, v object.Object) {
+		key := &object.String{Token: tok, Value: k}
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: v}
+	}
+
+	add("name", &object.String{Token: tok, Value: m.name})
+	add("type", &object.String{Token: tok, Value: m.kind})
+	for name, fn := range methods {
+		add(name, &object.Builtin{Token: tok, Fn: fn})
+	}
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
+
+// Ports the metrics are served on
+var (
+	metricsPortsMu sync.Mutex
+	metricsPorts   = map[int]bool{}
+)
+
+// Starts serving the metrics at /metrics
+// on the given port, in the background.
+func serveMetrics(port int) error {
+	metricsPortsMu.Lock()
+	defer metricsPortsMu.Unlock()
+
+	// a script reloaded by "abs daemon" serves
+	// the metrics it was serving already
+	if metricsPorts[port] {
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+	metricsPorts[port] = true
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write([]byte(metricsRegistry.Render()))
+	})
+
+	go http.Serve(ln, mux)
+
+	return nil
+}
@@ -0,0 +1,330 @@
+package evaluator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+	"github.com/abs-lang/abs/util"
+)
+
+// Mocks registered through mock_command(...) and mock_http(...),
+// so that scripts (eg. runbooks) can be tested without running
+// commands or hitting the network. The last mock registered for
+// a command or request wins.
+type mock struct {
+	// "" (or "*") matches any method, mock_http(...) only
+	method   string
+	pattern  *regexp.Regexp
+	response object.Object
+	tok      token.Token
+	env      *object.Environment
+	// commands (as strings) or requests
+	// (as hashes) the mock answered
+	calls []object.Object
+}
+
+var mocksMux sync.Mutex
+var commandMocks []*mock
+var httpMocks []*mock
+
+// Turns a pattern such as "kubectl get *" into
+// a regexp: * matches anything, ? a single character
+func globRegexp(pattern string) *regexp.Regexp {
+	re := regexp.QuoteMeta(pattern)
+	re = strings.ReplaceAll(re, `\*`, ".*")
+	re = strings.ReplaceAll(re, `\?`, ".")
+
+	return regexp.MustCompile("^" + re + "$")
+}
+
+func findMock(mocks []*mock, method string, subject string) *mock {
+	for i := len(mocks) - 1; i >= 0; i-- {
+		m := mocks[i]
+
+		if (m.method == "" || m.method == method) && m.pattern.MatchString(subject) {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func isCallable(o object.Object) bool {
+	return o.Type() == object.FUNCTION_OBJ || o.Type() == object.BUILTIN_OBJ
+}
+
+// Answers a command through its mock, if any: the returned
+// object is the result of the command (or an error), while
+// the bool tells whether the command was mocked at all.
+func mockedCommand(tok token.Token, cmd string, env *object.Environment, opts commandOptions) (object.Object, bool) {
+	mocksMux.Lock()
+	m := findMock(commandMocks, "", cmd)
+	if m != nil {
+		m.calls = append(m.calls, &object.String{Token: tok, Value: cmd})
+	}
+	mocksMux.Unlock()
+
+	if m == nil {
+		return nil, false
+	}
+
+	res := m.response
+	if isCallable(res) {
+		res = applyFunction(tok, res, env, []object.Object{&object.String{Token: tok, Value: cmd}})
+		if isError(res) {
+			return res, true
+		}
+	}
+
+	stdout, stderr, status := "", "", 0
+	switch res := res.(type) {
+	case *object.String:
+		stdout = res.Value
+	case *object.Hash:
+		for _, pair := range res.Pairs {
+			switch k := pair.Key.Inspect(); k {
+			case "stdout", "stderr":
+				s, ok := pair.Value.(*object.String)
+				if !ok {
+					return newError(tok, "mock_command(...) response '%s' must be a string, got %s", k, pair.Value.Type()), true
+				}
+
+				if k == "stdout" {
+					stdout = s.Value
+				} else {
+					stderr = s.Value
+				}
+			case "exit_code":
+				n, ok := pair.Value.(*object.Number)
+				if !ok {
+					return newError(tok, "mock_command(...) response 'exit_code' must be a number, got %s", pair.Value.Type()), true
+				}
+				status = n.Int()
+			default:
+				return newError(tok, "mock_command(...) response doesn't support '%s' (use stdout, stderr and exit_code)", k), true
+			}
+		}
+	default:
+		return newError(tok, "mock_command(...) response must be a string or a hash {stdout, stderr, exit_code}, got %s", res.Type()), true
+	}
+
+	s := &object.String{Token: tok, Stdout: bytes.NewBufferString(stdout), Stderr: bytes.NewBufferString(stderr)}
+	s.SetCmdResult(nativeBoolToBooleanObject(status == 0))
+	recordLastCommand(s, cmd, status, 0)
+
+	if opts.eachLine != nil {
+		if err := eachLineFn(tok, env, &object.String{Token: tok, Value: stdout}, opts.eachLine); isError(err) {
+			return err, true
+		}
+	}
+
+	return s, true
+}
+
+// Answers requests sent on behalf of scripts
+// (see util.NewHTTPClient) through their mock
+func interceptHTTP(req *http.Request) (*http.Response, error) {
+	mocksMux.Lock()
+	m := findMock(httpMocks, req.Method, req.URL.String())
+	mocksMux.Unlock()
+
+	if m == nil {
+		return nil, nil
+	}
+
+	body := []byte{}
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+
+	headers := map[string]object.Object{}
+	for k := range req.Header {
+		headers[k] = &object.String{Token: m.tok, Value: req.Header.Get(k)}
+	}
+
+	request := hashFromObjects(m.tok, map[string]object.Object{
+		"method":  &object.String{Token: m.tok, Value: req.Method},
+		"url":     &object.String{Token: m.tok, Value: req.URL.String()},
+		"headers": hashFromObjects(m.tok, headers),
+		"body":    &object.String{Token: m.tok, Value: string(body)},
+	})
+
+	mocksMux.Lock()
+	m.calls = append(m.calls, request)
+	mocksMux.Unlock()
+
+	res := m.response
+	if isCallable(res) {
+		res = applyFunction(m.tok, res, m.env, []object.Object{request})
+		if isError(res) {
+			return nil, errors.New(res.Inspect())
+		}
+	}
+
+	response := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Request: req}
+	resBody := ""
+
+	switch res := res.(type) {
+	case *object.String:
+		resBody = res.Value
+	case *object.Number:
+		response.StatusCode = res.Int()
+	case *object.Hash:
+		for _, pair := range res.Pairs {
+			switch k := pair.Key.Inspect(); k {
+			case "status":
+				n, ok := pair.Value.(*object.Number)
+				if !ok {
+					return nil, fmt.Errorf("mock_http(...) response 'status' must be a number, got %s", pair.Value.Type())
+				}
+				response.StatusCode = n.Int()
+			case "body":
+				if s, ok := pair.Value.(*object.String); ok {
+					resBody = s.Value
+				} else {
+					resBody = pair.Value.Json()
+				}
+			case "headers":
+				h, ok := pair.Value.(*object.Hash)
+				if !ok {
+					return nil, fmt.Errorf("mock_http(...) response 'headers' must be a hash, got %s", pair.Value.Type())
+				}
+
+				for _, header := range h.Pairs {
+					response.Header.Set(header.Key.Inspect(), header.Value.Inspect())
+				}
+			default:
+				return nil, fmt.Errorf("mock_http(...) response doesn't support '%s' (use status, body and headers)", k)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("mock_http(...) response must be a string, a status code or a hash {status, body, headers}, got %s", res.Type())
+	}
+
+	response.Status = fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode))
+	response.Body = io.NopCloser(strings.NewReader(resBody))
+	response.ContentLength = int64(len(resBody))
+
+	return response, nil
+}
+
+func hashFromObjects(tok token.Token, values map[string]object.Object) *object.Hash {
+	pairs := make(map[object.HashKey]object.HashPair)
+
+	keys := []string{}
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key := &object.String{Token: tok, Value: k}
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: values[k]}
+	}
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
+
+// The handle returned by mock_command(...) and mock_http(...)
+func mockObject(tok token.Token, m *mock, registry *[]*mock) *object.Hash {
+	methods := map[string]object.BuiltinFunction{
+		// m.calls()
+		"calls": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			mocksMux.Lock()
+			defer mocksMux.Unlock()
+
+			return &object.Array{Token: tok, Elements: append([]object.Object{}, m.calls...)}
+		},
+		// m.restore()
+		"restore": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			mocksMux.Lock()
+			defer mocksMux.Unlock()
+
+			for i, other := range *registry {
+				if other == m {
+					*registry = append((*registry)[:i], (*registry)[i+1:]...)
+					break
+				}
+			}
+
+			return NULL
+		},
+	}
+
+	pairs := make(map[object.HashKey]object.HashPair)
+	for name, fn := range methods {
+		key := &object.String{Token: tok, Value: name}
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: &object.Builtin{Token: tok, Fn: fn}}
+	}
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
+
+// mock_command("kubectl get pods*", "web-1 Running")
+func mockCommandFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "mock_command", args, 2, [][]string{{object.STRING_OBJ}, {object.STRING_OBJ, object.HASH_OBJ, object.FUNCTION_OBJ, object.BUILTIN_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	m := &mock{pattern: globRegexp(strings.TrimSpace(args[0].(*object.String).Value)), response: args[1], tok: tok, env: env}
+
+	mocksMux.Lock()
+	commandMocks = append(commandMocks, m)
+	mocksMux.Unlock()
+
+	return mockObject(tok, m, &commandMocks)
+}
+
+// mock_http("GET https://api.example.com/*", {"status": 200, "body": "ok"})
+func mockHttpFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "mock_http", args, 2, [][]string{{object.STRING_OBJ}, {object.STRING_OBJ, object.NUMBER_OBJ, object.HASH_OBJ, object.FUNCTION_OBJ, object.BUILTIN_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	method := ""
+	pattern := strings.TrimSpace(args[0].(*object.String).Value)
+	if verb, url, ok := strings.Cut(pattern, " "); ok && verb == strings.ToUpper(verb) {
+		method, pattern = verb, strings.TrimSpace(url)
+	}
+
+	if method == "*" {
+		method = ""
+	}
+
+	m := &mock{method: method, pattern: globRegexp(pattern), response: args[1], tok: tok, env: env}
+
+	mocksMux.Lock()
+	httpMocks = append(httpMocks, m)
+	mocksMux.Unlock()
+	util.SetHTTPInterceptor(interceptHTTP)
+
+	return mockObject(tok, m, &httpMocks)
+}
+
+// ResetMocks () -- removes all mocks, eg. between
+// the test files run by "abs test"
+func ResetMocks() {
+	mocksMux.Lock()
+	commandMocks = nil
+	httpMocks = nil
+	mocksMux.Unlock()
+	util.SetHTTPInterceptor(nil)
+}
+
+// reset_mocks()
+func resetMocksFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	ResetMocks()
+
+	return NULL
+}
@@ -0,0 +1,102 @@
+package evaluator
+
+import (
+	"sync"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// Magic methods hashes can define to overload operators,
+// eg. {"__add__": f(self, other) { ... }}
+var magicMethods = map[string]string{
+	"+":   "__add__",
+	"-":   "__sub__",
+	"*":   "__mul__",
+	"/":   "__div__",
+	"%":   "__mod__",
+	"**":  "__pow__",
+	"==":  "__eq__",
+	"<":   "__lt__",
+	"<=":  "__le__",
+	">":   "__gt__",
+	">=":  "__ge__",
+	"<=>": "__cmp__",
+}
+
+func init() {
+	object.HashStringer = hashStringer
+}
+
+// magicMethod returns the function a hash
+// defines under the given name, if any
+func magicMethod(o object.Object, name string) (object.Object, bool) {
+	hash, ok := o.(*object.Hash)
+	if !ok || hash.GetKeyType(name) != object.FUNCTION_OBJ {
+		return nil, false
+	}
+
+	pair, _ := hash.GetPair(name)
+	return pair.Value, true
+}
+
+// evalOverloadedOperator applies the magic method the left
+// operand defines for operator, passing both operands to it.
+// When the left operand doesn't overload the operator, it
+// returns false. != is the negation of __eq__.
+func evalOverloadedOperator(tok token.Token, operator string, left, right object.Object, env *object.Environment) (object.Object, bool) {
+	name := magicMethods[operator]
+	if operator == "!=" {
+		name = magicMethods["=="]
+	}
+
+	fn, ok := magicMethod(left, name)
+	if !ok {
+		return nil, false
+	}
+
+	result := applyFunction(tok, fn, env, []object.Object{left, right})
+	if operator == "!=" && !isError(result) {
+		return nativeBoolToBooleanObject(!isTruthy(result)), true
+	}
+
+	return result, true
+}
+
+// Hashes whose __str__ is running: hashes can be
+// stringified by background jobs too, so it's guarded
+var stringifying = map[*object.Hash]bool{}
+var stringifyingMux = sync.Mutex{}
+
+// hashStringer calls the __str__ magic method of a hash,
+// so that its string representation can be customized
+//
+// A hash being stringified is printed as JSON if __str__
+// refers to it (eg. "$self"), rather than recursing forever.
+func hashStringer(h *object.Hash) (string, bool) {
+	fn, ok := magicMethod(h, "__str__")
+	if !ok {
+		return "", false
+	}
+
+	stringifyingMux.Lock()
+	if stringifying[h] {
+		stringifyingMux.Unlock()
+		return "", false
+	}
+	stringifying[h] = true
+	stringifyingMux.Unlock()
+
+	defer func() {
+		stringifyingMux.Lock()
+		delete(stringifying, h)
+		stringifyingMux.Unlock()
+	}()
+
+	result := applyFunction(token.Token{}, fn, fn.(*object.Function).Env, []object.Object{h})
+	if hash, ok := result.(*object.Hash); ok {
+		return hash.Json(), true
+	}
+
+	return result.Inspect(), true
+}
@@ -0,0 +1,103 @@
+package evaluator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/util"
+)
+
+// CommandFilter, when set, is called before any command
+// (eg. `ls -la`) is executed: if it returns an error the
+// command won't run. Embedders usually set it through
+// runner.Options.
+var CommandFilter func(cmd string) error
+
+// Programs commands are allowed (or not) to invoke,
+// from the ABS_ALLOWED_COMMANDS and ABS_DENIED_COMMANDS
+// env variables (comma-separated lists). They're read
+// once, at startup, so that scripts can't change them.
+var allowedCommands = commandList(os.Getenv("ABS_ALLOWED_COMMANDS"))
+var deniedCommands = commandList(os.Getenv("ABS_DENIED_COMMANDS"))
+
+// Checks whether a command is allowed to run,
+// based on the allow / deny lists and CommandFilter.
+func checkCommand(env *object.Environment, cmd string) error {
+	if Sandboxed {
+		return errSandboxedCommand
+	}
+
+	allowed := allowedCommands
+	denied := deniedCommands
+
+	if len(allowed) > 0 || len(denied) > 0 {
+		for _, binary := range util.CommandBinaries(cmd) {
+			if len(allowed) > 0 && !matchesCommand(allowed, binary) {
+				return fmt.Errorf("'%s' is not in the list of allowed commands", binary)
+			}
+
+			if matchesCommand(denied, binary) {
+				return fmt.Errorf("'%s' is in the list of denied commands", binary)
+			}
+		}
+	}
+
+	if CommandFilter != nil {
+		return CommandFilter(cmd)
+	}
+
+	return nil
+}
+
+func commandList(list string) []string {
+	commands := []string{}
+
+	for _, c := range strings.Split(list, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			commands = append(commands, c)
+		}
+	}
+
+	return commands
+}
+
+// A program matches an entry of the list either by
+// its full path (/bin/ls) or by its name (ls)
+func matchesCommand(list []string, binary string) bool {
+	return util.Contains(list, binary) || util.Contains(list, filepath.Base(binary))
+}
+
+// Runs the commands builtin functions invoke on their
+// own (eg. git or ps) like any other: subject to the
+// allow / deny lists and dry-run mode, and audited.
+func commandRunner(env *object.Environment) func(c *exec.Cmd) error {
+	return func(c *exec.Cmd) error {
+		words := []string{}
+		for _, arg := range c.Args {
+			words = append(words, util.ShellEscape(arg))
+		}
+		cmd := strings.Join(words, " ")
+
+		if err := checkCommand(env, cmd); err != nil {
+			return fmt.Errorf("command not allowed: %s", err.Error())
+		}
+
+		if isDryRun(env) {
+			fmt.Fprintf(env.Stdio.Stdout, "dry-run: %s\n", cmd)
+			return nil
+		}
+
+		start := time.Now()
+		err := c.Run()
+		if c.ProcessState != nil {
+			auditCommand(c, cmd, start, AuditFunc)
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,563 @@
+package evaluator
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// Generators (see the @gen module) are hashes with:
+//
+//   - generate(size): returns a random value, size
+//     being a hint of how big it should be
+//   - shrink(value): returns an array of values
+//     "smaller" than the given one, the simplest
+//     first, used to simplify counterexamples
+//
+// so that users can write their own in ABS, while
+// the builtin ones are native.
+type generator struct {
+	generate func(size int) object.Object
+	shrink   func(o object.Object) []object.Object
+}
+
+// Randomness used by generators, seeded by
+// quickcheck(...) so that runs can be replayed
+var genRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+var genRandMux sync.Mutex
+
+func randInt(n int) int {
+	genRandMux.Lock()
+	defer genRandMux.Unlock()
+
+	if n <= 0 {
+		return 0
+	}
+
+	return genRand.Intn(n)
+}
+
+// The characters random strings are made of:
+// mostly printable ASCII, with a few multi-byte
+// ones to catch byte vs character confusion
+var genRunes = []rune(" !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~\t\néü€ß日本😀")
+
+const genKeyRunes = "abcdefghijklmnopqrstuvwxyz"
+
+// Caps the number of candidates tried for
+// each position when shrinking collections
+const maxShrinkCandidates = 20
+
+func generatorObject(tok token.Token, g generator) *object.Hash {
+	methods := map[string]object.BuiltinFunction{
+		// gen.generate(size)
+		"generate": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			err := validateArgs(tok, "generate", args, 1, [][]string{{object.NUMBER_OBJ}})
+			if err != nil {
+				return err
+			}
+
+			return g.generate(max(args[0].(*object.Number).Int(), 0))
+		},
+		// gen.shrink(value)
+		"shrink": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			err := validateArgs(tok, "shrink", args, 1, [][]string{{object.ANY_OBJ}})
+			if err != nil {
+				return err
+			}
+
+			return &object.Array{Token: tok, Elements: g.shrink(args[0])}
+		},
+	}
+
+	values := map[string]object.Object{}
+	for name, fn := range methods {
+		values[name] = &object.Builtin{Token: tok, Fn: fn}
+	}
+
+	return hashFromObjects(tok, values)
+}
+
+// Wraps a generator hash (builtin or user-defined)
+// so that it can be used from Go
+func toGenerator(tok token.Token, env *object.Environment, o object.Object) (generator, bool) {
+	h, ok := o.(*object.Hash)
+	if !ok || (h.GetKeyType("generate") != object.FUNCTION_OBJ && h.GetKeyType("generate") != object.BUILTIN_OBJ) {
+		return generator{}, false
+	}
+
+	generate, _ := h.GetPair("generate")
+	shrink, canShrink := h.GetPair("shrink")
+
+	return generator{
+		generate: func(size int) object.Object {
+			return applyFunction(tok, generate.Value, env, []object.Object{&object.Number{Token: tok, Value: float64(size)}})
+		},
+		shrink: func(o object.Object) []object.Object {
+			if !canShrink {
+				return nil
+			}
+
+			candidates, ok := applyFunction(tok, shrink.Value, env, []object.Object{o}).(*object.Array)
+			if !ok {
+				return nil
+			}
+
+			return candidates.Elements
+		},
+	}, true
+}
+
+// Candidates to shrink n towards target: the target
+// itself, then numbers halfway closer and so on
+func shrinkInt(n int, target int) []int {
+	candidates := []int{}
+	if n == target {
+		return candidates
+	}
+
+	candidates = append(candidates, target)
+	for d := (n - target) / 2; d != 0; d /= 2 {
+		if n-d != target {
+			candidates = append(candidates, n-d)
+		}
+	}
+
+	return candidates
+}
+
+// Candidates to shrink a list of values: no values,
+// either half of them, all but one and finally all
+// of them with one shrunk by its own generator
+func shrinkList(values []object.Object, shrinkValue func(object.Object) []object.Object) [][]object.Object {
+	n := len(values)
+	if n == 0 {
+		return nil
+	}
+
+	candidates := [][]object.Object{{}}
+	if n > 1 {
+		candidates = append(candidates, values[:n/2], values[n/2:])
+	}
+
+	for i := 0; n > 1 && i < n && i < maxShrinkCandidates; i++ {
+		candidates = append(candidates, append(append([]object.Object{}, values[:i]...), values[i+1:]...))
+	}
+
+	for i := 0; i < n && i < maxShrinkCandidates; i++ {
+		for _, smaller := range shrinkValue(values[i]) {
+			candidate := append([]object.Object{}, values...)
+			candidate[i] = smaller
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates
+}
+
+// gen_int([min, max])
+func genIntFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "gen_int", args, [][][]string{
+		{},
+		{{object.NUMBER_OBJ}, {object.NUMBER_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	bounded := spec == 1
+	lo, hi := 0, 0
+	if bounded {
+		lo, hi = args[0].(*object.Number).Int(), args[1].(*object.Number).Int()
+
+		if lo > hi {
+			return newError(tok, "gen_int(...) requires min to be lower than or equal to max, got %d and %d", lo, hi)
+		}
+	}
+
+	// Values shrink towards 0, or the
+	// bound closest to it if out of range
+	target := min(max(0, lo), hi)
+
+	return generatorObject(tok, generator{
+		generate: func(size int) object.Object {
+			from, to := -size, size
+			if bounded {
+				from, to = lo, hi
+			}
+
+			return &object.Number{Token: tok, Value: float64(from + randInt(to-from+1))}
+		},
+		shrink: func(o object.Object) []object.Object {
+			n, ok := o.(*object.Number)
+			if !ok {
+				return nil
+			}
+
+			candidates := []object.Object{}
+			for _, c := range shrinkInt(n.Int(), target) {
+				candidates = append(candidates, &object.Number{Token: tok, Value: float64(c)})
+			}
+
+			return candidates
+		},
+	})
+}
+
+// gen_string([max_len])
+func genStringFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "gen_string", args, [][][]string{
+		{},
+		{{object.NUMBER_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	maxLen := -1
+	if spec == 1 {
+		maxLen = args[0].(*object.Number).Int()
+	}
+
+	return generatorObject(tok, generator{
+		generate: func(size int) object.Object {
+			if maxLen >= 0 {
+				size = min(size, maxLen)
+			}
+
+			runes := make([]rune, randInt(size+1))
+			for i := range runes {
+				runes[i] = genRunes[randInt(len(genRunes))]
+			}
+
+			return &object.String{Token: tok, Value: string(runes)}
+		},
+		shrink: func(o object.Object) []object.Object {
+			s, ok := o.(*object.String)
+			if !ok {
+				return nil
+			}
+
+			chars := []object.Object{}
+			for _, r := range s.Value {
+				chars = append(chars, &object.String{Token: tok, Value: string(r)})
+			}
+
+			// Characters shrink to "a"
+			shrinkChar := func(c object.Object) []object.Object {
+				if c.Inspect() == "a" {
+					return nil
+				}
+
+				return []object.Object{&object.String{Token: tok, Value: "a"}}
+			}
+
+			candidates := []object.Object{}
+			for _, c := range shrinkList(chars, shrinkChar) {
+				var b strings.Builder
+				for _, char := range c {
+					b.WriteString(char.Inspect())
+				}
+				candidates = append(candidates, &object.String{Token: tok, Value: b.String()})
+			}
+
+			return candidates
+		},
+	})
+}
+
+// gen_array_of(gen_int() [, max_len])
+func genArrayOfFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "gen_array_of", args, [][][]string{
+		{{object.HASH_OBJ}},
+		{{object.HASH_OBJ}, {object.NUMBER_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	g, ok := toGenerator(tok, env, args[0])
+	if !ok {
+		return newError(tok, "gen_array_of(...) requires a generator (a hash with a generate(size) function), got %s", args[0].Inspect())
+	}
+
+	maxLen := -1
+	if spec == 1 {
+		maxLen = args[1].(*object.Number).Int()
+	}
+
+	return generatorObject(tok, generator{
+		generate: func(size int) object.Object {
+			if maxLen >= 0 {
+				size = min(size, maxLen)
+			}
+
+			elements := make([]object.Object, randInt(size+1))
+			for i := range elements {
+				elements[i] = g.generate(size)
+				if isError(elements[i]) {
+					return elements[i]
+				}
+			}
+
+			return &object.Array{Token: tok, Elements: elements}
+		},
+		shrink: func(o object.Object) []object.Object {
+			arr, ok := o.(*object.Array)
+			if !ok {
+				return nil
+			}
+
+			candidates := []object.Object{}
+			for _, c := range shrinkList(arr.Elements, g.shrink) {
+				candidates = append(candidates, &object.Array{Token: tok, Elements: c})
+			}
+
+			return candidates
+		},
+	})
+}
+
+// gen_hash_of(gen_int() [, max_keys])
+func genHashOfFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "gen_hash_of", args, [][][]string{
+		{{object.HASH_OBJ}},
+		{{object.HASH_OBJ}, {object.NUMBER_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	g, ok := toGenerator(tok, env, args[0])
+	if !ok {
+		return newError(tok, "gen_hash_of(...) requires a generator (a hash with a generate(size) function), got %s", args[0].Inspect())
+	}
+
+	maxKeys := -1
+	if spec == 1 {
+		maxKeys = args[1].(*object.Number).Int()
+	}
+
+	// Hashes are shrunk as lists of [key, value]
+	// pairs, so that keys can be dropped and
+	// values shrunk by their own generator
+	toPairs := func(h *object.Hash) []object.Object {
+		pairs := []object.Object{}
+		for _, pair := range h.Pairs {
+			pairs = append(pairs, &object.Array{Token: tok, Elements: []object.Object{pair.Key, pair.Value}})
+		}
+
+		sort.Slice(pairs, func(a, b int) bool {
+			return pairs[a].(*object.Array).Elements[0].Inspect() < pairs[b].(*object.Array).Elements[0].Inspect()
+		})
+
+		return pairs
+	}
+
+	fromPairs := func(pairs []object.Object) *object.Hash {
+		values := map[string]object.Object{}
+		for _, p := range pairs {
+			kv := p.(*object.Array).Elements
+			values[kv[0].Inspect()] = kv[1]
+		}
+
+		return hashFromObjects(tok, values)
+	}
+
+	shrinkPair := func(p object.Object) []object.Object {
+		kv := p.(*object.Array).Elements
+		candidates := []object.Object{}
+		for _, v := range g.shrink(kv[1]) {
+			candidates = append(candidates, &object.Array{Token: tok, Elements: []object.Object{kv[0], v}})
+		}
+
+		return candidates
+	}
+
+	return generatorObject(tok, generator{
+		generate: func(size int) object.Object {
+			if maxKeys >= 0 {
+				size = min(size, maxKeys)
+			}
+
+			values := map[string]object.Object{}
+			for i := randInt(size + 1); i > 0; i-- {
+				key := make([]byte, 1+randInt(8))
+				for j := range key {
+					key[j] = genKeyRunes[randInt(len(genKeyRunes))]
+				}
+
+				v := g.generate(size)
+				if isError(v) {
+					return v
+				}
+				values[string(key)] = v
+			}
+
+			return hashFromObjects(tok, values)
+		},
+		shrink: func(o object.Object) []object.Object {
+			h, ok := o.(*object.Hash)
+			if !ok {
+				return nil
+			}
+
+			candidates := []object.Object{}
+			for _, c := range shrinkList(toPairs(h), shrinkPair) {
+				candidates = append(candidates, fromPairs(c))
+			}
+
+			return candidates
+		},
+	})
+}
+
+// quickcheck(f(x = gen_int()) { x + 0 == x }, {"runs": 100})
+func quickcheckFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "quickcheck", args, [][][]string{
+		{{object.FUNCTION_OBJ}},
+		{{object.FUNCTION_OBJ}, {object.HASH_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	runs, maxSize, seed := 100, 100, time.Now().UnixNano()
+	if spec == 1 {
+		for _, pair := range args[1].(*object.Hash).Pairs {
+			k := pair.Key.Inspect()
+			n, ok := pair.Value.(*object.Number)
+
+			switch k {
+			case "runs", "max_size", "seed":
+				if !ok || !n.IsInt() || n.Value < 1 {
+					return newError(tok, "quickcheck(...) option '%s' must be a whole number greater than 0, got %s", k, pair.Value.Inspect())
+				}
+			default:
+				return newError(tok, "quickcheck(...) doesn't support the option '%s'", k)
+			}
+
+			switch k {
+			case "runs":
+				runs = n.Int()
+			case "max_size":
+				maxSize = n.Int()
+			case "seed":
+				seed = int64(n.Value)
+			}
+		}
+	}
+
+	// Generators are the default values
+	// of the parameters of the property
+	prop := args[0].(*object.Function)
+	if len(prop.Parameters) == 0 {
+		return newError(tok, "quickcheck(...) requires a property with at least one parameter, eg. f(x = gen.int()) { ... }")
+	}
+
+	gens := []generator{}
+	for _, param := range prop.Parameters {
+		if param.Default == nil {
+			return newError(tok, "quickcheck(...) requires every parameter of the property to default to a generator, eg. f(%s = gen.int()), got %s", param.Value, param.Value)
+		}
+
+		o := Eval(param.Default, prop.Env)
+		if isError(o) {
+			return o
+		}
+
+		g, ok := toGenerator(tok, env, o)
+		if !ok {
+			return newError(tok, "quickcheck(...) parameter %s doesn't default to a generator (a hash with a generate(size) function), got %s", param.Value, o.Inspect())
+		}
+		gens = append(gens, g)
+	}
+
+	genRandMux.Lock()
+	genRand.Seed(seed)
+	genRandMux.Unlock()
+
+	// Whether the property fails for the given
+	// arguments, and the error it raised, if any
+	misuse := object.Object(nil)
+	fails := func(values []object.Object) (bool, object.Object) {
+		res := applyFunction(tok, prop, env, values)
+		if isError(res) {
+			return true, res
+		}
+
+		b, ok := res.(*object.Boolean)
+		if !ok {
+			misuse = newError(tok, "quickcheck(...) property must return a boolean, got %s", res.Inspect())
+			return false, nil
+		}
+
+		return !b.Value, nil
+	}
+
+	for run := 0; run < runs; run++ {
+		size := 1 + run*maxSize/runs
+		values := []object.Object{}
+
+		for _, g := range gens {
+			v := g.generate(size)
+			if isError(v) {
+				return v
+			}
+			values = append(values, v)
+		}
+
+		failed, cause := fails(values)
+		if misuse != nil {
+			return misuse
+		}
+
+		if !failed {
+			continue
+		}
+
+		// Shrink the counterexample: keep replacing
+		// arguments with simpler values that still
+		// make the property fail, until there are none
+		// (or we've tried for long enough)
+		shrinks, attempts := 0, 0
+	shrinking:
+		for attempts < 10000 {
+			for i, g := range gens {
+				for _, smaller := range g.shrink(values[i]) {
+					attempts++
+					candidate := append([]object.Object{}, values...)
+					candidate[i] = smaller
+
+					if failed, c := fails(candidate); failed {
+						values, cause = candidate, c
+						shrinks++
+						continue shrinking
+					}
+				}
+			}
+
+			break
+		}
+
+		counterexample := []string{}
+		for i, param := range prop.Parameters {
+			counterexample = append(counterexample, fmt.Sprintf("%s = %s", param.Value, values[i].Json()))
+		}
+
+		msg := fmt.Sprintf("failed after %d runs (%d shrinks, seed %d), counterexample: %s", run+1, shrinks, seed, strings.Join(counterexample, ", "))
+		if cause != nil {
+			msg += "\n" + cause.(*object.Error).Message
+		}
+
+		return newError(tok, "quickcheck(...) %s", msg)
+	}
+
+	return TRUE
+}
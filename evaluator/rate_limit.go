@@ -0,0 +1,98 @@
+package evaluator
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// A token bucket, created through rate_limit(n_per_second, burst):
+// the bucket holds up to burst tokens, refilled at the given rate,
+// and each call to wait() takes one out.
+type rateLimiter struct {
+	sync.Mutex
+	interval time.Duration // how long it takes to refill 1 token
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+func newRateLimiter(perSecond float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		interval: time.Duration(float64(time.Second) / perSecond),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// Adds the tokens refilled since the last call
+func (l *rateLimiter) refill() {
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+float64(now.Sub(l.last))/float64(l.interval))
+	l.last = now
+}
+
+// Takes a token out of the bucket, returning how
+// long to wait before it can be used: tokens can
+// be borrowed from the future, so that callers
+// queue up in order
+func (l *rateLimiter) reserve() time.Duration {
+	l.Lock()
+	defer l.Unlock()
+
+	l.refill()
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens * float64(l.interval))
+}
+
+// Takes a token out of the bucket only if
+// one is available right away
+func (l *rateLimiter) take() bool {
+	l.Lock()
+	defer l.Unlock()
+
+	l.refill()
+	if l.tokens < 1 {
+		return false
+	}
+
+	l.tokens--
+	return true
+}
+
+func rateLimiterObject(tok token.Token, l *rateLimiter) *object.Hash {
+	methods := map[string]object.BuiltinFunction{
+		// l.wait()
+		"wait": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			d := l.reserve()
+			interruptibleSleep(env, d)
+
+			if deadlineExpired(env) {
+				return &object.Error{Message: deadlineExceeded}
+			}
+
+			return &object.Duration{Token: tok, Value: d.Round(time.Millisecond)}
+		},
+		// l.try()
+		"try": func(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+			return nativeBoolToBooleanObject(l.take())
+		},
+	}
+
+	pairs := make(map[object.HashKey]object.HashPair)
+	for name, fn := range methods {
+		key := &object.String{Token: tok, Value: name}
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: &object.Builtin{Token: tok, Fn: fn}}
+	}
+
+	return &object.Hash{Token: tok, Pairs: pairs}
+}
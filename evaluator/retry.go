@@ -0,0 +1,177 @@
+package evaluator
+
+import (
+	"math"
+	mrand "math/rand"
+	"time"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// Options of retry(fn, options)
+type retryOptions struct {
+	attempts int
+	delay    time.Duration
+	maxDelay time.Duration
+	backoff  string
+	// fraction of the delay that's randomized,
+	// so that clients don't retry in lockstep
+	jitter  float64
+	onRetry object.Object
+}
+
+var defaultRetryOptions = retryOptions{attempts: 3, delay: time.Second, backoff: "exponential"}
+
+// Reads a duration option, given either in ms or as a Duration
+func durationOption(tok token.Token, o object.Object, name string) (time.Duration, object.Object) {
+	switch o := o.(type) {
+	case *object.Duration:
+		return o.Value, nil
+	case *object.Number:
+		return time.Duration(o.Value) * time.Millisecond, nil
+	}
+
+	return 0, newError(tok, "retry(...) option '%s' must be a number of ms or a duration, got %s", name, o.Type())
+}
+
+func parseRetryOptions(tok token.Token, h *object.Hash) (retryOptions, object.Object) {
+	opts := defaultRetryOptions
+
+	for _, pair := range h.Pairs {
+		name := pair.Key.Inspect()
+		v := pair.Value
+
+		switch name {
+		case "attempts":
+			n, ok := v.(*object.Number)
+			if !ok || n.Int() < 1 {
+				return opts, newError(tok, "retry(...) option 'attempts' must be a number greater than 0, got %s", v.Inspect())
+			}
+			opts.attempts = n.Int()
+		case "delay", "max_delay":
+			d, err := durationOption(tok, v, name)
+			if err != nil {
+				return opts, err
+			}
+
+			if name == "delay" {
+				opts.delay = d
+			} else {
+				opts.maxDelay = d
+			}
+		case "backoff":
+			b := v.Inspect()
+			if b != "constant" && b != "linear" && b != "exponential" {
+				return opts, newError(tok, "retry(...) option 'backoff' must be one of constant, linear or exponential, got %s", b)
+			}
+			opts.backoff = b
+		case "jitter":
+			switch j := v.(type) {
+			case *object.Boolean:
+				if j.Value {
+					opts.jitter = 0.5
+				}
+			case *object.Number:
+				if j.Value < 0 || j.Value > 1 {
+					return opts, newError(tok, "retry(...) option 'jitter' must be between 0 and 1, got %s", j.Inspect())
+				}
+				opts.jitter = j.Value
+			default:
+				return opts, newError(tok, "retry(...) option 'jitter' must be a boolean or a number, got %s", v.Type())
+			}
+		case "on_retry":
+			if v.Type() != object.FUNCTION_OBJ && v.Type() != object.BUILTIN_OBJ {
+				return opts, newError(tok, "retry(...) option 'on_retry' must be a function, got %s", v.Type())
+			}
+			opts.onRetry = v
+		default:
+			return opts, newError(tok, "retry(...) doesn't support the option '%s'", name)
+		}
+	}
+
+	return opts, nil
+}
+
+// How long to wait after the given (failed) attempt
+func (o retryOptions) delayAfter(attempt int) time.Duration {
+	d := o.delay
+
+	switch o.backoff {
+	case "linear":
+		d = o.delay * time.Duration(attempt)
+	case "exponential":
+		d = time.Duration(float64(o.delay) * math.Pow(2, float64(attempt-1)))
+	}
+
+	if o.maxDelay > 0 && d > o.maxDelay {
+		d = o.maxDelay
+	}
+
+	if o.jitter == 0 {
+		return d
+	}
+
+	// jittered delays are rounded to the ms, as
+	// there's no point in being more precise
+	return (d - time.Duration(mrand.Float64()*o.jitter*float64(d))).Round(time.Millisecond)
+}
+
+// Whether the result of an attempt is a failure:
+// an error or a command that didn't succeed
+func retryFailed(o object.Object) (bool, string) {
+	switch o := o.(type) {
+	case *object.Error:
+		return true, o.Message
+	case *object.String:
+		if o.Ok != nil && !o.Ok.Value {
+			return true, o.Value
+		}
+	}
+
+	return false, ""
+}
+
+// retry(f() {...}) or retry(f() {...}, {"attempts": 5, "backoff": "linear"})
+func retryFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err, spec := validateVarArgs(tok, "retry", args, [][][]string{
+		{{object.FUNCTION_OBJ, object.BUILTIN_OBJ}},
+		{{object.FUNCTION_OBJ, object.BUILTIN_OBJ}, {object.HASH_OBJ}},
+	})
+	if err != nil {
+		return err
+	}
+
+	opts := defaultRetryOptions
+	if spec == 1 {
+		opts, err = parseRetryOptions(tok, args[1].(*object.Hash))
+		if err != nil {
+			return err
+		}
+	}
+
+	var res object.Object
+	for attempt := 1; ; attempt++ {
+		res = applyFunction(tok, args[0], env, []object.Object{&object.Number{Token: tok, Value: float64(attempt)}})
+
+		failed, reason := retryFailed(res)
+		if !failed || attempt == opts.attempts || interrupted.Load() || deadlineExpired(env) {
+			return res
+		}
+
+		delay := opts.delayAfter(attempt)
+
+		if opts.onRetry != nil {
+			r := applyFunction(tok, opts.onRetry, env, []object.Object{
+				&object.Number{Token: tok, Value: float64(attempt)},
+				&object.String{Token: tok, Value: reason},
+				&object.Duration{Token: tok, Value: delay},
+			})
+			if isError(r) {
+				return r
+			}
+		}
+
+		interruptibleSleep(env, delay)
+	}
+}
@@ -0,0 +1,154 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/abs-lang/abs/object"
+)
+
+// Sandboxed, when set, prevents programs from
+// running commands and from calling builtins that
+// reach outside of the program itself (the process,
+// the filesystem, the network...). Embedders usually
+// set it through runner.Options.
+var Sandboxed bool
+
+// Builtins that aren't available within a sandbox
+var sandboxedBuiltins = map[string]bool{
+	"assert_golden":      true,
+	"aws_metadata":       true,
+	"cd":                 true,
+	"clipboard_read":     true,
+	"clipboard_write":    true,
+	"docker_logs":        true,
+	"docker_ps":          true,
+	"docker_run":         true,
+	"checkpoint":         true,
+	"checkpoint_clear":   true,
+	"env":                true,
+	"exec":               true,
+	"exit":               true,
+	"fs_lines":           true,
+	"git_branch":         true,
+	"git_branches":       true,
+	"git_clone":          true,
+	"git_commit":         true,
+	"git_diff":           true,
+	"git_log":            true,
+	"git_status":         true,
+	"metrics_serve":      true,
+	"ps_exists":          true,
+	"ps_list":            true,
+	"require":            true,
+	"resume":             true,
+	"secret_delete":      true,
+	"secret_get":         true,
+	"secret_set":         true,
+	"set_dry_run":        true,
+	"source":             true,
+	"stdin":              true,
+	"tty_is_tty":         true,
+	"tty_size":           true,
+	"tty_supports_color": true,
+}
+
+var errSandboxedCommand = errors.New("commands are not available in the sandbox")
+
+// Checks whether the builtin with the given
+// name can be called, returning an error if
+// not.
+func checkBuiltin(name string) error {
+	if Sandboxed && sandboxedBuiltins[name] {
+		return errors.New(name + "(...) is not available in the sandbox")
+	}
+
+	return nil
+}
+
+// Set when the program being evaluated should
+// stop, eg. because it ran out of time: evaluation
+// bails out at the next node it reaches.
+var interrupted atomic.Bool
+
+// Set when the program should stop once it's idle,
+// see Drain()
+var draining atomic.Bool
+
+// Canceled on Interrupt(), so that the
+// commands running are killed as well
+var (
+	interruptMu     sync.Mutex
+	interruptCtx    context.Context
+	cancelInterrupt context.CancelFunc
+)
+
+func init() {
+	interruptCtx, cancelInterrupt = context.WithCancel(context.Background())
+}
+
+// Interrupt stops the program being evaluated,
+// which returns an error.
+func Interrupt() {
+	interrupted.Store(true)
+
+	interruptMu.Lock()
+	defer interruptMu.Unlock()
+	cancelInterrupt()
+}
+
+// Drain stops the program being evaluated at its next
+// sleep(...) -- ie. once it's done with the work at hand,
+// eg. between the iterations of a loop -- rather than
+// right away like Interrupt().
+func Drain() {
+	draining.Store(true)
+}
+
+// ResetInterrupt allows programs to run
+// again after an Interrupt() or a Drain().
+func ResetInterrupt() {
+	interrupted.Store(false)
+	draining.Store(false)
+
+	interruptMu.Lock()
+	defer interruptMu.Unlock()
+	interruptCtx, cancelInterrupt = context.WithCancel(context.Background())
+}
+
+// The context commands run in until
+// the program is interrupted
+func interruptContext() context.Context {
+	interruptMu.Lock()
+	defer interruptMu.Unlock()
+
+	return interruptCtx
+}
+
+// Sleeps for the given duration, like interruptibleSleep, but
+// a program that's drained gets interrupted: when it sleeps
+// it isn't in the middle of something.
+func idleSleep(env *object.Environment, d time.Duration) {
+	end := time.Now().Add(d)
+
+	for !interrupted.Load() && !draining.Load() && !deadlineExpired(env) && time.Now().Before(end) {
+		time.Sleep(min(time.Until(end), 10*time.Millisecond))
+	}
+
+	if draining.Load() {
+		Interrupt()
+	}
+}
+
+// Sleeps for the given duration, waking up early if the
+// program is interrupted or a with_timeout(...) expires.
+func interruptibleSleep(env *object.Environment, d time.Duration) {
+	end := time.Now().Add(d)
+
+	for !interrupted.Load() && !deadlineExpired(env) && time.Now().Before(end) {
+		time.Sleep(min(time.Until(end), 10*time.Millisecond))
+	}
+}
@@ -0,0 +1,208 @@
+package evaluator
+
+import (
+	"github.com/abs-lang/abs/ast"
+	"github.com/abs-lang/abs/object"
+)
+
+// x[start:end:step]
+//
+// Unlike plain ranges, stepped ones follow Python's
+// semantics: negative indexes count from the end, and
+// a negative step walks the sequence backwards, so that
+// x[::-1] reverses x.
+func evalSteppedIndexExpression(node *ast.IndexExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	start, end, step, err := evalSliceArgs(node, env)
+	if err != nil {
+		return err
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		elements := []object.Object{}
+		for _, i := range sliceIndexes(len(left.Elements), start, end, step) {
+			elements = append(elements, left.Elements[i])
+		}
+
+		return &object.Array{Token: node.Token, Elements: elements}
+	case *object.String:
+		runes := []rune(left.Value)
+		result := []rune{}
+		for _, i := range sliceIndexes(len(runes), start, end, step) {
+			result = append(result, runes[i])
+		}
+
+		return &object.String{Token: node.Token, Value: string(result)}
+	default:
+		return newError(node.Token, "index operator not supported: %s on %s", node.String(), left.Type())
+	}
+}
+
+// arr[2:5] = [1, 2]
+//
+// A plain range is replaced by the given elements,
+// which can be more or less than the ones in the
+// range. A stepped range needs as many elements
+// as the ones it selects.
+func evalSliceAssignment(iex *ast.IndexExpression, expr object.Object, env *object.Environment) object.Object {
+	left := Eval(iex.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	array, ok := left.(*object.Array)
+	if !ok {
+		return newError(iex.Token, "can only assign to a range of an array, got %s", left.Type())
+	}
+
+	if err := checkFrozen(iex.Token, array); err != nil {
+		return err
+	}
+
+	values, ok := expr.(*object.Array)
+	if !ok {
+		return newError(iex.Token, "can only assign an array to a range, got %s", expr.Type())
+	}
+
+	start, end, step, err := evalSliceArgs(iex, env)
+	if err != nil {
+		return err
+	}
+
+	n := len(array.Elements)
+
+	if iex.Step == nil {
+		lo, hi := rangeBounds(start, end, n)
+		elements := make([]object.Object, 0, n-(hi-lo)+len(values.Elements))
+		elements = append(elements, array.Elements[:lo]...)
+		elements = append(elements, values.Elements...)
+		elements = append(elements, array.Elements[hi:]...)
+		array.Elements = elements
+
+		return NULL
+	}
+
+	indexes := sliceIndexes(n, start, end, step)
+	if len(indexes) != len(values.Elements) {
+		return newError(iex.Token, "cannot assign %d elements to a range of %d", len(values.Elements), len(indexes))
+	}
+
+	for i, idx := range indexes {
+		array.Elements[idx] = values.Elements[i]
+	}
+
+	return NULL
+}
+
+// evalSliceArgs evaluates the bounds of a range: start
+// and end are nil when omitted, step defaults to 1.
+func evalSliceArgs(node *ast.IndexExpression, env *object.Environment) (start, end *int, step int, err *object.Error) {
+	bound := func(exp ast.Expression) (*int, *object.Error) {
+		if exp == nil {
+			return nil, nil
+		}
+
+		value := Eval(exp, env)
+		if isError(value) {
+			return nil, value.(*object.Error)
+		}
+
+		switch value := value.(type) {
+		case *object.Null:
+			return nil, nil
+		case *object.Number:
+			i := value.Int()
+			return &i, nil
+		default:
+			return nil, newError(node.Token, `index ranges can only be numerical: got "%s" (type %s)`, value.Inspect(), value.Type())
+		}
+	}
+
+	if start, err = bound(node.Index); err != nil {
+		return
+	}
+
+	if end, err = bound(node.End); err != nil {
+		return
+	}
+
+	s, err := bound(node.Step)
+	if err != nil {
+		return
+	}
+
+	step = 1
+	if s != nil {
+		step = *s
+	}
+
+	if step == 0 {
+		err = newError(node.Token, "range step cannot be zero")
+	}
+
+	return
+}
+
+// rangeBounds returns the boundaries of x[start:end]
+// following the semantics of plain ranges: the start
+// is at least 0, and a negative end counts from the
+// end of x.
+func rangeBounds(start, end *int, n int) (lo, hi int) {
+	hi = n
+	if start != nil && *start > 0 {
+		lo = min(*start, n)
+	}
+
+	if end != nil {
+		if *end < 0 {
+			hi = max(n+*end, 0)
+		} else if *end < n {
+			hi = *end
+		}
+	}
+
+	return lo, max(lo, hi)
+}
+
+// sliceIndexes returns the indexes x[start:end:step]
+// selects in a sequence of n elements.
+func sliceIndexes(n int, start, end *int, step int) []int {
+	// Negative indexes count from the end, then we
+	// clamp them to the sequence (or right before it
+	// when walking backwards)
+	bound := func(i *int, def, lo, hi int) int {
+		if i == nil {
+			return def
+		}
+
+		v := *i
+		if v < 0 {
+			v += n
+		}
+
+		return min(max(v, lo), hi)
+	}
+
+	indexes := []int{}
+
+	if step > 0 {
+		last := bound(end, n, 0, n)
+		for i := bound(start, 0, 0, n); i < last; i += step {
+			indexes = append(indexes, i)
+		}
+
+		return indexes
+	}
+
+	last := bound(end, -1, -1, n-1)
+	for i := bound(start, n-1, -1, n-1); i > last; i += step {
+		indexes = append(indexes, i)
+	}
+
+	return indexes
+}
@@ -0,0 +1,131 @@
+package evaluator
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// statValues returns the numeric values of the array's elements
+// for sum(...), avg(...) and friends. Numbers can be mixed with
+// numeric strings, such as the ones parsed out of a command's
+// output. Durations and sizes are supported as long as they
+// are not mixed with anything else: like is then the element
+// the result should take the type of.
+func statValues(tok token.Token, name string, arr *object.Array) (values []float64, like object.Object, err object.Object) {
+	if !arr.Empty() && isQuantity(arr.Elements[0]) {
+		like = arr.Elements[0]
+	}
+
+	for _, e := range arr.Elements {
+		switch {
+		case like != nil && e.Type() == like.Type():
+			values = append(values, quantityValue(e))
+			continue
+		case like == nil && e.Type() == object.NUMBER_OBJ:
+			values = append(values, e.(*object.Number).Value)
+			continue
+		case like == nil && e.Type() == object.STRING_OBJ:
+			if v, perr := strconv.ParseFloat(strings.TrimSpace(e.Inspect()), 64); perr == nil {
+				values = append(values, v)
+				continue
+			}
+		}
+
+		if !arr.Homogeneous() {
+			return nil, nil, newError(tok, "%s(...) can only be called on an homogeneous array, got %s", name, arr.Inspect())
+		}
+
+		return nil, nil, newError(tok, "%s(...) can only be called on arrays of numbers, got %s", name, arr.Inspect())
+	}
+
+	return values, like, nil
+}
+
+// statResult wraps v in a number, or in a
+// duration / size if the values were ones
+func statResult(tok token.Token, like object.Object, v float64) object.Object {
+	if like != nil {
+		return newQuantity(tok, like, v)
+	}
+
+	return &object.Number{Token: tok, Value: v}
+}
+
+// percentile of the sorted values, interpolating
+// linearly between the 2 closest ranks
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// arrayStat is the skeleton of avg(...), median(...) and
+// percentile(...): empty arrays have no statistics, so
+// they return null
+func arrayStat(tok token.Token, name string, arr *object.Array, fn func(sorted []float64) float64) object.Object {
+	values, like, err := statValues(tok, name, arr)
+	if err != nil {
+		return err
+	}
+
+	if len(values) == 0 {
+		return NULL
+	}
+
+	sort.Float64s(values)
+
+	return statResult(tok, like, fn(values))
+}
+
+// avg(array:[1, 2, 3])
+func avgFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "avg", args, 1, [][]string{{object.ARRAY_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	return arrayStat(tok, "avg", args[0].(*object.Array), func(sorted []float64) float64 {
+		sum := 0.0
+		for _, v := range sorted {
+			sum += v
+		}
+
+		return sum / float64(len(sorted))
+	})
+}
+
+// median(array:[1, 2, 3])
+func medianFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "median", args, 1, [][]string{{object.ARRAY_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	return arrayStat(tok, "median", args[0].(*object.Array), func(sorted []float64) float64 {
+		return percentile(sorted, 50)
+	})
+}
+
+// percentile(array:[1, 2, 3], 95)
+func percentileFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "percentile", args, 2, [][]string{{object.ARRAY_OBJ}, {object.NUMBER_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	p := args[1].(*object.Number).Value
+	if p < 0 || p > 100 {
+		return newError(tok, "percentile(...) requires a percentile between 0 and 100, got %s", args[1].Inspect())
+	}
+
+	return arrayStat(tok, "percentile", args[0].(*object.Array), func(sorted []float64) float64 {
+		return percentile(sorted, p)
+	})
+}
@@ -1,8 +1,22 @@
 // Code generated for package evaluator by go-bindata DO NOT EDIT. (@generated)
 // sources:
+// stdlib/aws/index.abs
 // stdlib/cli/index.abs
+// stdlib/clipboard/index.abs
+// stdlib/docker/index.abs
+// stdlib/fs/index.abs
+// stdlib/gen/index.abs
+// stdlib/git/index.abs
+// stdlib/humanize/index.abs
+// stdlib/jwt/index.abs
+// stdlib/metrics/index.abs
+// stdlib/ps/index.abs
 // stdlib/runtime/index.abs
+// stdlib/secrets/index.abs
+// stdlib/semver/index.abs
+// stdlib/tty/index.abs
 // stdlib/util/index.abs
+// stdlib/webhook/index.abs
 package evaluator
 
 import (
@@ -79,6 +93,26 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
+var _stdlibAwsIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x51\x41\x8b\xdb\x3c\x10\xbd\xeb\x57\x0c\xf6\xc5\xe6\xcb\x2a\x1f\xa5\xa7\x42\x0e\x61\x59\x68\x68\x0b\xa5\x59\xda\x43\x29\x61\x2a\x4f\x6c\x35\xb6\xe4\x6a\xc6\x9b\x9a\x65\xff\x7b\x91\xbc\xce\x3a\xa5\x97\xe2\x83\xa5\x99\x37\xef\xbd\x79\xca\xe1\x2d\xb5\x3d\x05\x06\xf1\x20\xd8\x9e\xe2\x7f\xfb\x65\x0f\xdb\x8f\x3b\x56\x39\x9c\xad\x34\x7e\x10\x90\x86\x52\xf9\xf6\xfd\x4e\x2b\x3c\x33\x6c\xe0\xf1\x49\xa9\x1c\xf6\xb6\x76\x0c\x08\x81\x7e\x0e\xc4\x92\x06\x62\xf1\xf3\xeb\x15\x04\x92\x21\x38\xeb\x6a\x95\x27\x82\x86\xb0\x8a\x52\x56\x80\x1b\x3f\xb4\x15\x7c\x27\x60\x72\xd3\x54\xe2\xd5\x6c\x6b\x07\x1b\xc0\x33\x1f\xe2\x31\x4a\x7c\x22\xac\xa2\xc4\x03\xb6\x03\xc1\x31\xf8\x2e\xb1\xdd\xdd\xbe\x02\xeb\x58\xd0\x19\x52\x39\x74\x24\x58\xa1\x20\x30\x85\x07\x6b\x68\x05\x54\xeb\xc8\xa3\xe7\x4e\x91\xcd\xf0\x1b\x5b\x65\xa5\x5a\xf6\x9e\x25\xe7\xeb\x24\x5b\x5b\xef\x92\x94\x19\x42\x88\x36\xe7\x79\x08\x83\x63\xb0\x6e\xb2\x1c\x26\xe0\x06\x8e\x45\x09\x8f\x0a\x00\x9e\x37\xbf\xa2\x2c\xb2\xbe\x45\x43\x1d\x39\x59\x4f\x13\x59\xa9\x52\x84\xf7\xd4\xf5\x3e\x60\x18\xc1\x04\xaa\xc8\x89\xc5\x96\xc1\x1f\x93\xf4\x6e\xfb\x01\x82\x6f\xe3\x86\x28\x82\xa6\xa1\x2a\x3e\xd1\xdf\x5c\xc5\xc0\xb1\x1a\x63\xd8\x3e\x26\xdb\x23\xf3\x84\x9e\x83\x2d\xb4\xd6\xe5\x64\x7a\x29\x75\xe5\xdc\xb7\xf4\x47\x18\x45\x66\xb1\x5b\x33\x99\x21\x58\x19\x6f\x16\x93\xeb\xac\xd4\xad\x75\xc4\x45\xf9\xf5\xff\x6f\x69\xf3\xd8\xe5\x7f\x20\x80\xff\xd2\x7a\xa5\xfe\xc1\xde\x15\xa5\x5a\xc6\x37\x39\x8a\x5f\x86\xc6\x10\xf3\xe1\x44\x63\xf6\x26\xc5\xc4\x7a\x9b\x4a\xef\x68\xdc\x55\xab\x17\x20\x93\x09\x24\x57\xc0\x7d\x2a\x5d\xe0\x57\x60\x66\xeb\xdd\x41\xfc\x89\xdc\x05\x7f\x1f\x6f\x0b\x14\xfd\xea\x6d\x40\xb1\xfe\x05\x72\x77\x29\x25\xd8\x53\x7c\xc8\x40\x32\x04\x07\x78\x66\xf5\x7b\x00\xd9\xb8\xef\xc8\x56\x03\x00\x00")
+
+func stdlibAwsIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibAwsIndexAbs,
+		"stdlib/aws/index.abs",
+	)
+}
+
+func stdlibAwsIndexAbs() (*asset, error) {
+	bytes, err := stdlibAwsIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/aws/index.abs", size: 854, mode: os.FileMode(436), modTime: time.Unix(1792109429, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _stdlibCliIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x54\xd1\x6e\xda\x30\x14\x7d\xf7\x57\x9c\x25\x95\x48\x56\xc6\xc3\xfa\xd4\x4e\x68\xeb\x2a\x4d\xaa\x54\xed\x61\x7b\x64\x68\x32\xf1\x4d\xb0\x70\x9c\xc8\x76\x68\xbb\x8a\x7f\x9f\x9c\x38\x90\x40\x21\x4f\x90\xdc\x73\xae\xcf\xb9\xe7\x3a\xc6\xc3\xd3\x23\x78\x5d\xb3\x4c\x49\xcc\xf1\xb6\x63\x2c\xc6\x43\x55\x96\x5c\x0b\x0b\x43\x85\xb4\x8e\x0c\x09\x3c\x4b\xb7\x96\x1a\x6e\x2d\xed\x10\x33\xcb\xfa\xda\x1e\xfc\xa3\xd1\x99\x93\x95\x46\x63\x49\xc0\x55\x7b\x12\x70\x84\xe2\x0e\x58\x0a\xcc\x91\x27\x9a\x97\x34\x85\x20\x9b\x19\x59\x7b\xe0\x14\xb9\xe2\x45\x47\x98\xe2\x8d\x01\x80\x21\xd7\x18\x8d\x3c\xc9\x75\xff\xca\x3f\xc3\x03\x2c\x3c\xd1\xb2\x45\x7d\xb9\x50\xb0\xef\x3b\xe4\xf1\x4f\x8c\x07\x43\xdc\x51\xe8\xee\xf5\x42\x50\xce\x1b\xe5\xb0\xe5\xaa\x21\x3b\x2a\xcf\x2b\x83\xcd\x14\x7f\x21\x75\x40\x8c\xe9\xfc\xb3\xf5\x8d\x14\x2f\x92\x4d\xca\x4e\x3e\xca\x1c\xdb\x77\x30\x2d\xb7\xe7\x5b\x6c\xbc\x98\xed\x49\xc1\x8e\x9d\xff\x77\x2c\x88\x2b\x05\xb7\x26\x54\x46\x16\x52\x73\x85\xac\x14\xa3\x1a\x43\xd6\xeb\x9b\x23\xd7\xb3\x8c\x2b\x95\x2c\xb8\x29\x6c\x92\x2e\x6e\xee\x96\x61\x0e\xcb\xa3\xb3\xc7\x78\xcc\x3d\xa9\xa1\x89\x05\xef\x19\x9e\x09\xb5\x91\xda\x41\x3a\x54\x8d\x63\x47\x4a\x43\xd5\xa9\x5c\xca\xd6\x55\xd2\x7d\x4d\xcf\x08\xdb\xb1\x4b\xe3\x1c\x04\x07\xf3\x61\x8c\x58\x87\x6d\x33\xf9\xab\xd1\xad\x0f\xc3\xe0\x9a\x46\x8f\x72\x10\xe3\xfe\xfb\x6f\x58\x22\x8b\x88\xaf\x2c\x3a\xa2\x99\xff\xf9\xf2\xfa\x2f\x0a\x35\xb6\x6a\x89\xc2\x21\x20\x6d\xfb\xf7\xc6\x08\x70\x53\x34\x25\xe9\x4e\x7a\x17\x32\x6e\x8a\xe4\x73\xb0\x2f\xc6\xcf\xca\xa1\xe6\xd6\x4a\x5d\x1c\x56\xe1\x2b\x9e\xc8\x4d\x6c\x30\xcf\x73\xad\x49\xd5\x2c\xd8\xf6\xc1\xf3\x1c\x4c\x0b\x5b\x30\x72\x61\xe2\xeb\x27\x6d\xac\x93\x94\x0d\xfc\x6a\xe1\xc3\xca\xac\x14\xcb\x01\x19\xbd\x48\x97\xdc\xde\x4e\x11\xf5\x62\x26\x57\x6f\x59\x29\x76\x13\xe8\xca\x21\xaf\x1a\x2d\xa2\x11\xe3\x7b\xed\x3d\x69\xe8\xbd\x63\x9d\xaf\x54\xab\x91\xb1\xed\x88\xa3\xab\xc0\xe5\xf7\xc6\xab\x92\x1a\xd6\x09\xa9\x07\x27\x92\xf9\x29\xf7\x51\x64\xce\xf5\xde\x67\xa5\x5f\x82\x30\xf8\x7b\x21\xc0\xf7\x6b\xec\xad\xda\x8f\xce\xad\xb9\x43\xc6\x35\x56\xc4\x62\x54\x5b\x32\x46\x0a\x41\x1a\xab\xd7\x6e\xc4\x5c\x29\x32\xec\x5b\xb8\xab\x92\xc8\xa3\xa3\x29\xa2\x7e\x54\xd2\x76\x84\x25\x59\xcb\x0b\x8a\xa6\xfe\xbe\x62\x79\xfb\xf2\x48\xfc\xfd\x96\x4b\xc5\x57\x6a\x1f\x1c\x7b\xf7\x47\x47\x21\x19\x03\x47\x86\xf2\x66\x1b\x7a\xb5\x49\x3a\xb3\x95\x71\xa3\xcb\xca\xdf\x8c\x11\xf0\x11\xdd\xb8\x22\x76\xd1\xc0\xd1\x82\x8c\xcd\xb4\xb8\xf6\x4c\x9f\x10\xe1\xfa\x32\xf0\xbd\x5d\x6c\x95\xd9\xf4\xe0\xf6\x21\x1e\xec\x7f\x00\x00\x00\xff\xff\x5b\x6d\x77\x28\x57\x06\x00\x00")
 
 func stdlibCliIndexAbsBytes() ([]byte, error) {
@@ -99,6 +133,186 @@ func stdlibCliIndexAbs() (*asset, error) {
 	return a, nil
 }
 
+var _stdlibClipboardIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x8c\x31\x0b\xc2\x30\x10\x85\xf7\xfc\x8a\x47\xbb\x46\xdd\xbb\xb9\x0b\x0e\x0e\x8e\x92\x26\x07\x06\xea\x5d\xb8\x5c\x49\x45\xfc\xef\x52\x1d\x8a\xeb\xf7\x7d\xef\xf5\x38\xc6\x48\xb5\xc2\x04\x76\x27\xc4\x29\x97\x51\x82\xa6\x01\x65\x8c\x52\x9e\x38\xa0\x8c\x25\x54\x23\xd7\x43\x18\x8f\x10\xcf\x17\xff\x6d\xaf\x99\x93\xb4\xba\x6d\x10\x38\x61\xa9\x34\x79\xd7\x63\x59\x31\x44\xd1\xa6\xdd\x56\x08\xe3\x94\x79\x5e\xf6\x4e\xc9\x66\x65\xbc\x1c\x00\x74\x4a\x21\x75\xc3\x76\x75\x5b\x81\xff\xb9\xa6\xd9\xe8\x4f\x36\xcd\x46\xde\xbd\xdd\x07\x00\x00\xff\xff\x03\x00\xd9\x14\xe8\x1b\xbf\x00\x00\x00")
+
+func stdlibClipboardIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibClipboardIndexAbs,
+		"stdlib/clipboard/index.abs",
+	)
+}
+
+func stdlibClipboardIndexAbs() (*asset, error) {
+	bytes, err := stdlibClipboardIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/clipboard/index.abs", size: 191, mode: os.FileMode(436), modTime: time.Unix(1792125941, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _stdlibDockerIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\xcf\x41\x6a\xc3\x30\x10\x05\xd0\xbd\x4e\xf1\xb1\x16\x4d\xc0\xf8\x00\x85\x2c\xba\x6b\xa1\x8b\xd2\x0b\x14\x21\x4f\x2c\x11\x79\xc6\x8c\x24\xb2\x08\xb9\x7b\xb1\x12\x27\xed\xce\x63\x49\x6f\xfe\xb7\x78\xa7\xb4\x90\x66\x14\x81\xab\x45\x66\x57\x08\x5e\xb8\xb8\xc8\xed\x77\x50\xa9\x53\x30\x16\x25\x10\xde\xbe\x3e\x20\xc7\xf6\x39\x8a\x3f\x91\x82\x78\x8a\x4c\x83\xb9\x8f\x07\x5c\xae\xc6\x58\x7c\xc6\x5c\xd6\xc7\x04\xad\xcc\x91\xa7\x3f\x66\x0f\x51\xb8\x94\x8c\xbd\x5b\x33\xce\xb1\x04\xdc\x88\x61\xc9\xbb\xa2\x95\xf6\x9b\x39\x2c\x19\x87\xfb\xe1\xcf\x92\x57\xfd\xbb\x72\x86\x7b\x92\x3d\x68\x1a\x8c\xdd\x04\xad\xbc\xeb\x5c\x5a\x22\x53\xd7\xe3\xd2\xf9\x79\xec\x5e\xd1\x91\x0f\x82\x40\x29\x49\x77\xdd\x6f\xb8\x56\x7e\xea\x5a\xb9\xf1\x54\xaa\xf2\x2d\x7e\x92\x29\xaf\x31\xff\x6d\x13\x35\x16\x47\x49\x49\xce\xed\xd6\xdc\xc3\xbb\x94\xd6\x9a\x0e\xc7\xca\xbe\x44\xe1\x56\xca\x58\x90\xf3\x01\x29\x32\xc1\x65\xc4\xf2\x92\xb1\x68\xe4\x42\xe3\xa3\x60\xdb\xf1\x08\xb1\x4e\xc6\x68\xcb\x80\x51\xfc\x89\xd4\xfc\x02\x00\x00\xff\xff\x03\x00\x47\xa1\x22\x3f\xa7\x01\x00\x00")
+
+func stdlibDockerIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibDockerIndexAbs,
+		"stdlib/docker/index.abs",
+	)
+}
+
+func stdlibDockerIndexAbs() (*asset, error) {
+	bytes, err := stdlibDockerIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/docker/index.abs", size: 423, mode: os.FileMode(436), modTime: time.Unix(1792123555, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _stdlibFsIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x63\x00\x9c\xff\x23\x20\x48\x65\x6c\x70\x65\x72\x73\x20\x74\x6f\x20\x77\x6f\x72\x6b\x20\x77\x69\x74\x68\x20\x66\x69\x6c\x65\x73\x0a\x23\x20\x77\x69\x74\x68\x6f\x75\x74\x20\x72\x65\x61\x64\x69\x6e\x67\x20\x74\x68\x65\x6d\x20\x61\x6c\x6c\x20\x61\x74\x20\x6f\x6e\x63\x65\x2e\x0a\x72\x65\x74\x75\x72\x6e\x20\x7b\x0a\x20\x20\x20\x20\x22\x6c\x69\x6e\x65\x73\x22\x3a\x20\x66\x73\x5f\x6c\x69\x6e\x65\x73\x2c\x0a\x7d\x0a\x00\x00\x00\xff\xff\x03\x00\x2f\x14\x7d\xfb\x63\x00\x00\x00")
+
+func stdlibFsIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibFsIndexAbs,
+		"stdlib/fs/index.abs",
+	)
+}
+
+func stdlibFsIndexAbs() (*asset, error) {
+	bytes, err := stdlibFsIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/fs/index.abs", size: 99, mode: os.FileMode(436), modTime: time.Unix(1792114308, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _stdlibGenIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x8e\x51\x6a\xc3\x30\x10\x44\xff\x75\x8a\x21\xfe\x88\x04\x46\x07\x28\x18\xfa\xd7\x63\x18\xe1\xae\x2d\x91\x76\x95\xac\x56\xc1\x25\xe4\xee\x45\x8e\x43\x02\xfa\xd0\xcc\x9b\xd9\xdd\x0e\x5f\xc4\x24\x41\xb3\x14\xe4\x19\x12\xf8\x3b\xff\xe2\x1a\x7e\x2a\x95\x1e\x9a\x31\x45\x9a\x4e\x38\x4b\x3e\x93\x68\xa2\x62\xba\x96\x9b\x2b\x4f\x9a\x32\x17\x68\x94\x5c\x97\x88\x4b\x4d\xd3\x69\x0b\x5b\xef\xbd\xeb\x41\x8b\x37\x9d\xe9\x00\x2c\xc4\x18\x20\x74\xa9\x49\xc8\x1e\x3f\x17\xe2\xa3\xdb\xc8\x5b\x69\xb6\x6b\xc1\xd0\xb2\x3e\x88\x84\xbf\x31\xcf\xb6\x89\xc4\x6a\x9d\x73\xb8\x6d\x85\xf6\xd6\xe2\x85\xae\x24\x85\xac\x7b\xfd\x30\x0c\x58\xdb\x75\xc0\xdd\x19\x21\xad\xc2\xb8\x99\x56\x38\x24\xd6\xc3\x47\x1b\x3d\x26\xd6\xfe\xe1\x15\x95\xc4\xcb\x6e\x3f\xc4\x4e\x9e\xeb\x77\xf6\x94\x3b\x8d\xa1\xc4\x17\x8c\xa1\xc4\x31\xcf\xbd\xb9\x9b\x7f\x00\x00\x00\xff\xff\x03\x00\x70\xe2\xe6\x5e\x4b\x01\x00\x00")
+
+func stdlibGenIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibGenIndexAbs,
+		"stdlib/gen/index.abs",
+	)
+}
+
+func stdlibGenIndexAbs() (*asset, error) {
+	bytes, err := stdlibGenIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/gen/index.abs", size: 331, mode: os.FileMode(436), modTime: time.Unix(1792116643, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _stdlibGitIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x64\x92\xcf\x6a\xdc\x30\x10\xc6\xef\x7e\x8a\x0f\xf9\xb2\x05\xe3\x66\x7b\x5c\xc8\xa1\xb4\x85\x1c\x7a\xca\x0b\x94\x89\x3d\xb6\x44\x65\xc9\x8c\x46\x0d\x61\xc9\xbb\x17\xc9\x76\xb3\x4b\x6f\x1e\x79\xe6\xfb\x7d\xf3\xa7\xc5\x13\xfb\x95\x25\x41\x23\x28\x6b\x5c\x48\x19\xb3\x53\x08\xaf\x31\x39\x8d\xe2\x38\x75\x4d\x0b\x61\xcd\x12\x5c\x98\x61\x29\x59\x4e\xa0\x30\x82\x44\xe8\x2d\x41\x48\x2d\x0b\xd4\x52\x68\x5a\xa8\x65\xc4\xac\x6b\xd6\x2a\xb4\x8a\x0b\x9a\x30\x45\x81\xcd\x0b\x85\xd4\x37\xe5\xf9\x11\xd7\xf7\xa6\x69\xf1\x5c\x75\x53\xad\x7a\x11\x0a\x83\x45\x9c\x6a\xf4\xcf\xc1\x5b\xe1\xdb\xf8\x8a\x89\x04\x4e\xe1\x12\x26\x89\x0b\x9c\x26\xe4\x35\xa9\x30\x2d\xd5\x8e\x5a\x6e\x5a\x4c\xce\x73\xd1\x23\xc5\x60\x29\xcc\x3c\x56\x62\x9f\x94\x34\x27\x3c\x16\x57\xbf\xb6\xa0\x18\xf8\xe9\x92\x96\x74\xc6\x10\x97\xc5\x69\xea\xc0\x73\xdf\xb4\x25\xad\xf7\x71\x3e\x5d\x4d\x30\x17\x9c\x1f\x3a\x18\xe1\xc9\x5c\x60\xfe\x9c\xfb\x87\xbe\x7f\xfa\xf1\xf5\xbb\x79\xff\xd4\xec\x79\xbb\xb0\x8f\xf3\xbd\xaa\x8f\x03\xf9\xbd\x35\xde\x9a\xef\x8f\x68\xaf\x39\xc2\x52\xf8\x4d\x98\xb4\x8c\x77\x2f\x01\x29\x0a\xe9\xd6\xd5\xf6\xe7\x64\x84\x3d\x53\xe2\xcf\xe7\xfe\x8b\xe9\x70\x35\x83\xe5\xe1\x77\xcc\x6a\x2e\x50\xc9\xbc\x7b\xdb\x75\x6e\x51\x15\x74\x74\xfb\xb1\xd9\x62\xb7\x6c\xf7\xd8\xc0\x36\x90\x5b\xf2\xf6\x72\x32\xcf\x1b\x19\x07\x99\xbc\xbf\x87\x6e\x89\x7b\x7f\x5b\x50\xa1\x3e\x86\xda\xdc\xc7\x72\xe1\x42\xb9\x3d\x8c\x4e\x78\x28\x0f\x77\xbc\x92\x7f\xca\xe2\x3b\x18\x7a\x49\xb5\xcb\x91\x57\xb5\x65\x23\x07\xaa\xe4\x1c\xa4\xf2\x7d\x3f\xff\xff\xcf\xa1\xc3\xab\x53\xbb\x5f\xaa\x77\xd5\xd0\x38\xf2\x58\x6f\x68\x64\xcf\x7a\x9c\xcc\xe8\xa6\x69\x57\x2e\x9f\x4d\x23\xac\x59\x02\x66\xa7\xcd\x5f\x00\x00\x00\xff\xff\x03\x00\x6e\x15\xf7\x51\x3b\x03\x00\x00")
+
+func stdlibGitIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibGitIndexAbs,
+		"stdlib/git/index.abs",
+	)
+}
+
+func stdlibGitIndexAbs() (*asset, error) {
+	bytes, err := stdlibGitIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/git/index.abs", size: 827, mode: os.FileMode(436), modTime: time.Unix(1792125714, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _stdlibHumanizeIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x8e\x4d\xca\x83\x30\x14\x45\xe7\x59\xc5\x45\xa7\xe1\x5b\xc0\xb7\x8c\x6e\x40\x9e\xf5\x59\x03\x35\x91\xf7\x33\xa8\xa5\x7b\x2f\x56\x03\x99\x9e\x73\xb8\xdc\x1e\x37\xa6\x89\xc6\x27\x23\xfb\x3a\xb2\x68\x84\xa6\x9d\x35\x62\x72\x21\x4b\x25\x2b\x28\x4f\xa1\x87\xa5\xf5\xc0\x73\x11\xd8\xc2\x28\x6e\x9b\x1b\xca\x0c\xbd\x4b\xda\x4c\xff\x82\xb0\xb9\x64\xbc\x03\x00\x74\xe3\xcb\x58\xbb\x7f\x2c\xbe\x52\x4e\x3b\x0f\x3f\x10\x4f\x59\xc7\x5b\x5f\xd9\x95\x9c\x7f\xda\xe0\x24\x97\x3e\xee\x0c\xf4\x28\x6d\x50\x59\x0c\x9f\xf0\x05\x00\x00\xff\xff\x03\x00\x1f\x49\xc2\x35\xda\x00\x00\x00")
+
+func stdlibHumanizeIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibHumanizeIndexAbs,
+		"stdlib/humanize/index.abs",
+	)
+}
+
+func stdlibHumanizeIndexAbs() (*asset, error) {
+	bytes, err := stdlibHumanizeIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/humanize/index.abs", size: 218, mode: os.FileMode(436), modTime: time.Unix(1792114789, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _stdlibJwtIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x34\x8f\x41\x4b\x03\x31\x10\x85\xef\xf9\x15\x0f\xf6\xa2\x20\x3d\x08\x7a\xeb\x51\x28\x1e\x14\x5a\xc1\xa3\xc4\xcd\x6c\x3b\x6b\x3a\x59\x92\x17\xd7\x22\xfe\x77\xc9\xb6\xbd\xcd\x0c\x6f\xde\xf7\x5e\x87\x8d\xc4\x49\x72\x01\x13\x8e\x6a\x84\xb7\x00\xb5\x32\x49\x4f\x3c\xef\x5e\x5f\xf0\x2e\x9f\x78\x4b\x5f\x62\x65\xe5\xc6\x99\x58\xe3\xf7\xcf\xb9\x0e\x4f\xd6\xa7\x20\x05\x7d\xf4\x7a\x2c\x50\x63\x82\x07\x9b\x12\x45\xf7\x26\x01\xb3\xf2\xe0\x3a\x6c\x76\xf7\x0f\x8f\xb8\xe1\x41\x10\x64\xf0\x35\xf2\x16\x29\x63\xdb\xce\x8b\xe7\xaa\xe9\xb1\xc6\x38\xf3\xa3\x8d\xcd\x7e\x2b\xac\xd9\x0a\xda\xd7\x05\x91\x86\x2b\xe0\x0e\xdf\x92\x75\x38\xa9\xed\x5d\x07\x65\x59\x88\x9e\x35\xcb\x52\x40\x7e\x26\xcd\x27\x54\x8b\x52\x5a\xb5\x18\x60\x89\x60\x3a\xe3\x82\xb4\xe8\x17\xe0\x79\x71\x2e\x0b\x6b\x36\x8c\x33\xdd\x3f\x00\x00\x00\xff\xff\x03\x00\xec\xee\x05\x37\x16\x01\x00\x00")
+
+func stdlibJwtIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibJwtIndexAbs,
+		"stdlib/jwt/index.abs",
+	)
+}
+
+func stdlibJwtIndexAbs() (*asset, error) {
+	bytes, err := stdlibJwtIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/jwt/index.abs", size: 278, mode: os.FileMode(436), modTime: time.Unix(1792123400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _stdlibMetricsIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\xcc\x41\x0a\x02\x31\x0c\x85\xe1\x7d\x4f\x11\x66\xb6\xa3\x07\x70\xa7\x27\x10\x3c\x80\x0c\x35\x74\x0a\x36\x91\x97\x74\x36\xe2\xdd\x05\xa7\x4a\x5d\xe6\x7d\xe1\x1f\xe9\x0c\x2d\xec\x0b\x57\xa3\xc2\x8e\x1c\x6d\x22\x57\x2a\x2a\xd9\x15\x61\xa4\xbb\x4a\xda\xa1\x8a\x64\x49\x74\x3c\x5d\xc8\x22\xf2\xc3\x6d\x1f\xc0\x5e\x21\xf4\x0c\x44\x44\x43\xd4\x2a\xce\x18\x0e\xdf\xce\xb5\x2d\xd3\xe6\x69\xae\x89\x3b\xfd\xdc\xcd\x96\x6c\xae\x09\x73\xe9\xfc\xb7\xb5\x1f\xb0\xdc\xfe\xf2\xdb\xd0\xd4\x18\x6b\x5f\x37\xc6\xca\x53\x78\x85\xf7\x00\x77\x70\x81\x0f\xe2\x00\x00\x00")
+
+func stdlibMetricsIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibMetricsIndexAbs,
+		"stdlib/metrics/index.abs",
+	)
+}
+
+func stdlibMetricsIndexAbs() (*asset, error) {
+	bytes, err := stdlibMetricsIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/metrics/index.abs", size: 226, mode: os.FileMode(436), modTime: time.Unix(1792109519, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _stdlibPsIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x24\xc8\x41\x0a\xc2\x30\x10\x05\xd0\x7d\x4e\xf1\x69\xb7\xa1\x07\xf0\x14\xde\x40\x24\x7c\xda\xc1\x98\x94\xf9\x13\xa9\x88\x77\x17\xe9\xee\xf1\x66\x5c\xbd\x17\x4a\x14\x7c\xb4\x66\x6d\x45\x6f\x88\x8d\xd0\x5b\xc1\x67\x06\xd7\x25\xcd\x88\x8e\xb2\xb1\x3c\x70\x87\xe8\x2f\x2b\x84\x09\x0a\xab\x15\x63\x5f\x92\x33\x86\x37\x7c\x12\x00\x4c\xd5\x14\xd3\x05\xbb\x6e\x7f\xe5\x33\x79\x98\x42\x67\xf3\x30\x85\x72\xfa\xa6\x1f\x00\x00\x00\xff\xff\x03\x00\xc2\x1b\x67\xc2\x82\x00\x00\x00")
+
+func stdlibPsIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibPsIndexAbs,
+		"stdlib/ps/index.abs",
+	)
+}
+
+func stdlibPsIndexAbs() (*asset, error) {
+	bytes, err := stdlibPsIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/ps/index.abs", size: 130, mode: os.FileMode(436), modTime: time.Unix(1792126658, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _stdlibRuntimeIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2a\x4a\x2d\x29\x2d\xca\x53\xa8\xe6\x52\x50\x50\x50\x50\xca\x4b\xcc\x4d\x55\xb2\x52\x50\x4a\x4c\x2a\x56\xd2\x81\x08\x95\xa5\x16\x15\x67\xe6\xe7\x29\x59\x29\x38\x3a\x05\xc7\x87\xb9\x06\x05\x7b\xfa\xfb\x41\xe5\x32\xf3\x4a\x52\x8b\x12\x93\x4b\x32\xcb\x52\xa1\xf2\x9e\x7e\x21\xae\x41\x8e\xce\x21\x9e\x61\xae\x3a\x5c\xb5\x5c\x80\x00\x00\x00\xff\xff\x68\x41\xac\x26\x5e\x00\x00\x00")
 
 func stdlibRuntimeIndexAbsBytes() ([]byte, error) {
@@ -119,6 +333,66 @@ func stdlibRuntimeIndexAbs() (*asset, error) {
 	return a, nil
 }
 
+var _stdlibSecretsIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\xcc\xbd\xca\xc2\x30\x18\xc5\xf1\x3d\x57\x71\x68\x97\xf7\x85\xe2\x05\x74\x13\x57\xc5\xa1\x83\xa3\xc4\xe4\xd0\x06\xe3\x53\x48\x9e\xfa\x81\x78\xef\x42\x2a\xda\xf5\x77\x0e\xff\x1a\x6b\xe7\x98\x33\x74\x84\x0e\xc4\xbe\xc3\x99\x0f\x37\xd8\x20\x6d\x81\x8b\x75\x0b\x6b\x4c\x5d\xb4\xa3\x4b\x54\x74\x4c\xd7\xe0\x88\xbf\x18\x4e\xb9\xd0\x3f\x46\xc1\x36\xc8\x74\x87\x15\xff\x79\x1f\x82\xf8\xf1\x96\xb1\x49\xf4\x14\x0d\x36\x62\x67\xc5\xf6\x4c\x2b\x93\xa8\x53\x12\x3c\x0d\x00\x54\x3d\xb5\x6a\x31\xa7\x8e\x3d\xb5\x99\x39\x2f\x39\x7f\xd9\x33\x52\xf9\x5b\x3c\x23\x95\x8d\x79\x99\xf7\x00\xfe\x22\x11\x03\xd7\x00\x00\x00")
+
+func stdlibSecretsIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibSecretsIndexAbs,
+		"stdlib/secrets/index.abs",
+	)
+}
+
+func stdlibSecretsIndexAbs() (*asset, error) {
+	bytes, err := stdlibSecretsIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/secrets/index.abs", size: 215, mode: os.FileMode(436), modTime: time.Unix(1792109313, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _stdlibSemverIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\xcc\xc1\x0e\x82\x30\x10\x04\xd0\x7b\xbf\x62\x02\x17\x4d\x08\xdc\xf9\x0d\x3f\xc0\x34\x65\x84\x8d\xd0\x36\xbb\x95\x8b\xf1\xdf\x4d\x80\x88\xc7\x99\xb7\x3b\x35\x6e\x5c\x7c\x2c\x12\xb0\x52\x4d\x52\x34\x5c\xa6\x52\xb2\xf5\x5d\x67\x5c\x56\x6a\x9b\x74\xbc\x36\xae\x06\xc7\x16\x25\x21\x4c\x0c\x4f\x0c\xcc\x8c\x03\x63\x10\x1a\x24\x42\x39\xd3\x1b\x61\x41\x25\x17\x6b\x9d\xb2\xbc\x34\xe2\xed\x00\xa0\xca\x5e\x8d\x55\x8f\x7d\xf2\xbe\xc5\x66\xa7\xd5\xcf\x32\x9c\xb4\xc5\x83\x42\x5a\xb2\xd7\xbf\xbf\xa3\x38\xd8\x7c\x11\x7b\x08\xed\x3c\xf8\x55\x8d\xfb\xb8\x2f\x00\x00\x00\xff\xff\x03\x00\xa7\x5f\x9a\x67\xdd\x00\x00\x00")
+
+func stdlibSemverIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibSemverIndexAbs,
+		"stdlib/semver/index.abs",
+	)
+}
+
+func stdlibSemverIndexAbs() (*asset, error) {
+	bytes, err := stdlibSemverIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/semver/index.abs", size: 221, mode: os.FileMode(436), modTime: time.Unix(1792114645, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _stdlibTtyIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x8b\x3b\x0a\xc3\x30\x10\x44\x7b\x9d\x62\xb0\x5b\x93\x03\xe4\x22\x29\x85\x90\x05\x5e\x70\xb4\x62\x77\x54\x38\x21\x77\x0f\xfe\xa4\x48\x37\xf3\x1e\x6f\xc4\x63\x49\x04\x97\x02\x16\x7b\x4a\x4d\xeb\x71\x3c\x9b\x34\xc2\x7a\x75\x48\x45\x4e\x15\xb3\x4e\x61\x84\x2b\xb8\x17\x42\x87\x76\xb6\xce\x43\xa6\x39\x35\x82\x0a\xe1\x2d\x58\x61\xb7\x8a\x77\x00\x80\xc1\xe5\x55\x86\x3b\xc8\x2d\xee\x73\x3a\xa9\x78\x24\xb7\x8b\x9f\xe7\x32\xde\x5b\x53\xa3\xc7\xac\xab\xda\xaf\xfc\x83\x53\xf8\x84\x2f\x00\x00\x00\xff\xff\x03\x00\xf3\x20\xb6\x73\xba\x00\x00\x00")
+
+func stdlibTtyIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibTtyIndexAbs,
+		"stdlib/tty/index.abs",
+	)
+}
+
+func stdlibTtyIndexAbs() (*asset, error) {
+	bytes, err := stdlibTtyIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/tty/index.abs", size: 186, mode: os.FileMode(436), modTime: time.Unix(1792126276, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _stdlibUtilIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x92\x4d\xcb\xdb\x30\x10\x84\xef\xfa\x15\x83\x73\xb1\xdb\xb7\xc2\x6f\xa1\x97\x50\xf7\xd4\x63\x8f\x81\x1e\x4a\x09\xaa\xb3\x4a\x04\xb2\x14\xa4\x35\x29\x09\xfe\xef\x45\xfe\x08\xfe\x48\x7d\x30\x68\xf6\xd9\xd5\x8c\xa4\x1d\xbe\x53\xed\x83\x62\x1f\xc0\x1e\x0d\x35\xde\xdc\x49\xec\xc0\x17\x42\xa0\xd8\x5a\x86\xd7\x50\xd0\xad\xab\xd9\x78\x27\x85\x9e\xa8\x9c\xd9\x16\x78\x08\x00\xd8\xe1\x27\xe1\xa6\x1c\xa7\x29\x91\x7d\x20\xb0\x69\x28\xad\xd2\xa4\xc6\x58\x6b\x22\xd5\xde\x9d\xde\x46\x5e\x59\xeb\x6f\xc6\x9d\xa1\x7d\xc0\xe1\xf0\x23\x26\xf6\x0f\xe1\x2b\xde\x23\x72\x3a\xa3\x94\x9f\xbf\x94\x85\xec\x71\x66\x8b\xaa\xff\x7f\xc0\x7b\x59\x96\xbd\x98\x6c\x1c\x1b\x75\x45\x85\x47\x27\x7a\x29\x10\xb7\xc1\x41\xe7\xda\x4d\xce\x16\xf2\x5c\x4c\x5f\xa4\x60\x94\x35\x77\x3a\x1d\x55\x38\x47\x54\x90\x52\xca\xc8\x21\x2f\x16\x5c\xad\xea\x0b\x9d\x50\x3d\xf7\xfc\xb5\xea\xfc\xbd\xc0\x9d\xbf\xa1\x42\xeb\xcc\xdf\x63\x13\xf3\x42\x2c\x8a\x46\x4f\xe3\x96\x5e\x16\x35\xc9\x11\x1f\xfb\xc0\x9f\xfa\x69\xdf\x50\xbe\xc0\x67\xd9\xc6\xb6\xe1\xca\x36\x60\x27\x36\xd2\x94\x44\x5e\xfd\x35\x5f\xa5\x59\x86\x5f\x35\x07\x4a\xe7\xa4\x9d\xac\x95\xb5\xb9\x94\x72\x95\xef\xbf\x47\x94\xee\x69\x63\x23\xe3\x98\xed\x53\xc4\xb7\x6d\x69\x08\x93\xed\xd3\x96\x2b\x47\xf3\x95\x78\x71\x1c\xf3\x8e\x81\xee\x44\x27\xc4\x58\x1d\x6c\x64\xe3\x3b\xce\xf6\xcf\x77\xdf\x89\x7f\x01\x00\x00\xff\xff\x0a\xd8\xb2\x18\x11\x03\x00\x00")
 
 func stdlibUtilIndexAbsBytes() ([]byte, error) {
@@ -139,6 +413,26 @@ func stdlibUtilIndexAbs() (*asset, error) {
 	return a, nil
 }
 
+var _stdlibWebhookIndexAbs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x8f\xbd\x4e\xc4\x30\x10\x84\x7b\x3f\xc5\x48\x6e\xc9\x15\x48\xd0\x5d\x45\xc1\xd5\x84\x82\xee\x64\x27\x9b\xd8\x24\x67\x87\xf5\xfa\x4e\x11\xe2\xdd\x11\xf9\x39\x7e\xd2\xd0\xce\xce\xcc\x37\xab\x71\xa0\x7e\x20\x4e\x90\x08\x93\xc5\x51\x10\x5f\x19\x21\x88\x23\x5c\xc8\xba\x18\xbb\xa4\x34\x12\x05\x81\x1d\x21\xce\x73\x5d\x0c\x86\x65\x44\x22\x3e\xfb\x8a\xd2\x4e\x2d\x46\xec\xf1\xfe\xa1\x94\xc6\x83\xa3\xaa\x4b\x53\xc7\x4b\x71\xc8\xb6\x28\x7d\x1b\x8c\x64\xa6\xe2\xf6\xee\x1e\x8e\x4c\x4d\xac\x34\x62\x03\x83\x47\x2f\x87\x6c\x57\xd8\xb5\x6c\x77\x26\xf6\xcd\x78\x6c\xbd\xb8\x6c\xb1\x5f\x0d\xc7\x5f\xfa\x1f\x5a\x29\xec\x07\xfa\xc6\x2d\xa8\x09\xa4\xf4\x72\x5e\x9b\x6e\xc0\xf4\x4a\x95\xf8\xd0\x22\xf6\x35\x62\xa0\xb4\xc1\xa7\x39\xb2\xc1\xcf\xfa\xe6\xd9\xb2\x37\x55\xf7\x83\x6f\x42\xad\xf4\x55\x7f\xa2\xb7\x4c\x49\x8a\x67\x7f\xa2\x24\xe6\x34\x2c\xfb\x12\x62\xa3\x34\x0c\xa6\x38\x78\xb6\xfd\x6f\xdf\x94\xd8\xce\xfb\x92\x95\x62\x92\xcc\x01\x17\xb2\x2e\xc6\x4e\x7d\x02\x00\x00\xff\xff\x03\x00\x04\x0a\x0b\x71\xef\x01\x00\x00")
+
+func stdlibWebhookIndexAbsBytes() ([]byte, error) {
+	return bindataRead(
+		_stdlibWebhookIndexAbs,
+		"stdlib/webhook/index.abs",
+	)
+}
+
+func stdlibWebhookIndexAbs() (*asset, error) {
+	bytes, err := stdlibWebhookIndexAbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "stdlib/webhook/index.abs", size: 495, mode: os.FileMode(436), modTime: time.Unix(1792123252, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -191,9 +485,23 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"stdlib/cli/index.abs":     stdlibCliIndexAbs,
-	"stdlib/runtime/index.abs": stdlibRuntimeIndexAbs,
-	"stdlib/util/index.abs":    stdlibUtilIndexAbs,
+	"stdlib/aws/index.abs":       stdlibAwsIndexAbs,
+	"stdlib/cli/index.abs":       stdlibCliIndexAbs,
+	"stdlib/clipboard/index.abs": stdlibClipboardIndexAbs,
+	"stdlib/docker/index.abs":    stdlibDockerIndexAbs,
+	"stdlib/fs/index.abs":        stdlibFsIndexAbs,
+	"stdlib/gen/index.abs":       stdlibGenIndexAbs,
+	"stdlib/git/index.abs":       stdlibGitIndexAbs,
+	"stdlib/humanize/index.abs":  stdlibHumanizeIndexAbs,
+	"stdlib/jwt/index.abs":       stdlibJwtIndexAbs,
+	"stdlib/metrics/index.abs":   stdlibMetricsIndexAbs,
+	"stdlib/ps/index.abs":        stdlibPsIndexAbs,
+	"stdlib/runtime/index.abs":   stdlibRuntimeIndexAbs,
+	"stdlib/secrets/index.abs":   stdlibSecretsIndexAbs,
+	"stdlib/semver/index.abs":    stdlibSemverIndexAbs,
+	"stdlib/tty/index.abs":       stdlibTtyIndexAbs,
+	"stdlib/util/index.abs":      stdlibUtilIndexAbs,
+	"stdlib/webhook/index.abs":   stdlibWebhookIndexAbs,
 }
 
 // AssetDir returns the file names below a certain
@@ -238,15 +546,57 @@ type bintree struct {
 
 var _bintree = &bintree{nil, map[string]*bintree{
 	"stdlib": &bintree{nil, map[string]*bintree{
+		"aws": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibAwsIndexAbs, map[string]*bintree{}},
+		}},
 		"cli": &bintree{nil, map[string]*bintree{
 			"index.abs": &bintree{stdlibCliIndexAbs, map[string]*bintree{}},
 		}},
+		"clipboard": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibClipboardIndexAbs, map[string]*bintree{}},
+		}},
+		"docker": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibDockerIndexAbs, map[string]*bintree{}},
+		}},
+		"fs": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibFsIndexAbs, map[string]*bintree{}},
+		}},
+		"gen": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibGenIndexAbs, map[string]*bintree{}},
+		}},
+		"git": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibGitIndexAbs, map[string]*bintree{}},
+		}},
+		"humanize": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibHumanizeIndexAbs, map[string]*bintree{}},
+		}},
+		"jwt": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibJwtIndexAbs, map[string]*bintree{}},
+		}},
+		"metrics": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibMetricsIndexAbs, map[string]*bintree{}},
+		}},
+		"ps": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibPsIndexAbs, map[string]*bintree{}},
+		}},
 		"runtime": &bintree{nil, map[string]*bintree{
 			"index.abs": &bintree{stdlibRuntimeIndexAbs, map[string]*bintree{}},
 		}},
+		"secrets": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibSecretsIndexAbs, map[string]*bintree{}},
+		}},
+		"semver": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibSemverIndexAbs, map[string]*bintree{}},
+		}},
+		"tty": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibTtyIndexAbs, map[string]*bintree{}},
+		}},
 		"util": &bintree{nil, map[string]*bintree{
 			"index.abs": &bintree{stdlibUtilIndexAbs, map[string]*bintree{}},
 		}},
+		"webhook": &bintree{nil, map[string]*bintree{
+			"index.abs": &bintree{stdlibWebhookIndexAbs, map[string]*bintree{}},
+		}},
 	}},
 }}
 
@@ -1,9 +1,20 @@
 package evaluator
 
 import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/util"
 )
 
 type tests struct {
@@ -37,6 +48,392 @@ func TestUtil(t *testing.T) {
 	testStdLib(tests, t)
 }
 
+func TestSecrets(t *testing.T) {
+	tests := []tests{
+		{`"get" in require('@secrets').keys()`, true},
+		{`"set" in require('@secrets').keys()`, true},
+		{`"delete" in require('@secrets').keys()`, true},
+		{`require('@secrets').get("service")`, "wrong number of arguments to secret_get(...): got=1, want=2"},
+		{`require('@secrets').set("service", "account", 1)`, "argument 2 to secret_set(...) is not supported (got: 1, allowed: STRING)"},
+	}
+
+	testStdLib(tests, t)
+}
+
+func TestAws(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest/api/token":
+			w.Write([]byte("token"))
+		case "/latest/meta-data/placement/region":
+			if r.Header.Get("X-aws-ec2-metadata-token") != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("eu-west-1"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	os.Setenv("ABS_AWS_METADATA_ENDPOINT", server.URL)
+	defer os.Unsetenv("ABS_AWS_METADATA_ENDPOINT")
+
+	tests := []tests{
+		{`require('@aws').sign({"url": "https://example.amazonaws.com/", "region": "us-east-1", "service": "service", "date": "20150830T123600Z"}, {"access_key": "AKIDEXAMPLE", "secret_key": "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}).Authorization`, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{`require('@aws').sign({"url": "https://example.amazonaws.com/", "region": "us-east-1", "service": "service"}, {"AccessKeyId": "a", "SecretAccessKey": "b", "Token": "c"})["X-Amz-Security-Token"]`, "c"},
+		{`require('@aws').sign({"url": "/", "region": "us-east-1", "service": "service"}, {"access_key": "a", "secret_key": "b"})`, "the URL to sign must be absolute, got: /"},
+		{`require('@aws').region()`, "eu-west-1"},
+		{`require('@aws').metadata("instance-id")`, "unable to fetch metadata 'instance-id': 404 Not Found"},
+	}
+
+	testStdLib(tests, t)
+}
+
+func TestWebhook(t *testing.T) {
+	tests := []tests{
+		{`require('@webhook').verify_github("Hello, World!", "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", "It's a Secret to Everybody")`, true},
+		{`require('@webhook').verify_github("Hello, World?", "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", "It's a Secret to Everybody")`, false},
+		{`require('@webhook').verify_github("Hello, World!", "", "secret")`, false},
+		// signed long ago
+		{`require('@webhook').verify_stripe('{"id": 1}', "t=1700000000,v1=ff7d36d5bb309a173a3a6972f8281e91d1594106ac01df380439e545a03f2919", "whsec_test")`, false},
+		{`require('@webhook').verify_slack("token=xyz", "1531420618", "v0=7d1e505747e16b2e9a5cb99318cbd784d12290a7129487637e0b023164e4aafb", "8f742231b10e8888abcd99yyyzzz85a5", 5m)`, false},
+		{`require('@webhook').verify_stripe("", "", "", "")`, "Wrong arguments passed to 'webhook_verify_stripe'. Usage:\nwebhook_verify_stripe(STRING, STRING, STRING)\nwebhook_verify_stripe(STRING, STRING, STRING, NUMBER | DURATION)"},
+	}
+
+	testStdLib(tests, t)
+}
+
+func TestJwt(t *testing.T) {
+	tests := []tests{
+		{`jwt = require('@jwt'); t = jwt.sign({"sub": "me", "admin": true}, "secret"); jwt.decode(t, {"key": "secret"}).sub`, "me"},
+		{`jwt = require('@jwt'); t = jwt.sign({"sub": "me"}, "secret", "HS256"); jwt.decode(t, {"verify": false}).sub`, "me"},
+		{`jwt = require('@jwt'); t = jwt.sign({"sub": "me"}, "secret"); jwt.decode(t, {"key": "nope"})`, "signature mismatch"},
+		{`jwt = require('@jwt'); t = jwt.sign({"sub": "me"}, "secret"); jwt.decode(t)`, `a key is required to verify the token, or pass {"verify": false} to skip verification`},
+		{`jwt = require('@jwt'); t = jwt.sign({"exp": 1600000000}, "secret"); jwt.decode(t, {"key": "secret"})`, "the token expired at 2020-09-13T12:26:40Z"},
+		{`require('@jwt').sign({"f": f() {}}, "secret")`, "claims can only hold strings, numbers, booleans, arrays, hashes and null"},
+		{`require('@jwt').sign({}, "secret", "none")`, "unsupported algorithm 'none' (supported: HS256, RS256)"},
+	}
+
+	testStdLib(tests, t)
+}
+
+// Frames of a multiplexed stream, as sent by the docker engine
+func dockerFrames(frames ...string) []byte {
+	b := []byte{}
+	for i, f := range frames {
+		header := make([]byte, 8)
+		header[0] = byte(1 + i%2)
+		binary.BigEndian.PutUint32(header[4:], uint32(len(f)))
+		b = append(append(b, header...), f...)
+	}
+
+	return b
+}
+
+func TestDocker(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets aren't available: %s", err)
+	}
+
+	pulled, removed := false, false
+	created := map[string]interface{}{}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /containers/json":
+			containers := []map[string]interface{}{{"Id": "c0", "Names": []string{"/web"}, "Image": "nginx", "State": "running", "Status": "Up 2 minutes"}}
+			if r.URL.Query().Get("all") == "true" {
+				containers = append(containers, map[string]interface{}{"Id": "c9", "Names": []string{"/old"}, "Image": "alpine", "State": "exited"})
+			}
+			json.NewEncoder(w).Encode(containers)
+		case "POST /images/create":
+			pulled = r.URL.Query().Get("fromImage") == "alpine" && r.URL.Query().Get("tag") == "3.20"
+		case "POST /containers/create":
+			if !pulled {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message": "No such image: alpine:3.20"}`))
+				return
+			}
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Write([]byte(`{"Id": "c1"}`))
+		case "POST /containers/c1/start":
+			w.WriteHeader(http.StatusNoContent)
+		case "POST /containers/c1/wait":
+			w.Write([]byte(`{"StatusCode": 3}`))
+		case "GET /containers/c1/logs":
+			w.Header().Set("Content-Type", "application/vnd.docker.multiplexed-stream")
+			w.Write(dockerFrames("hello\nwor", "oops\n", "ld\n"))
+		case "DELETE /containers/c1":
+			removed = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "No such container: nope"}`))
+		}
+	}))
+	server.Listener = l
+	server.Start()
+	defer server.Close()
+
+	os.Setenv("DOCKER_HOST", "unix://"+socket)
+	defer os.Unsetenv("DOCKER_HOST")
+
+	tests := []tests{
+		{`require('@docker').ps().map(f(c) { c.name + ":" + c.state }).join(",")`, "web:running"},
+		{`require('@docker').ps(true).len()`, 2},
+		{`r = require('@docker').run("alpine:3.20", {"cmd": "echo hello", "env": {"A": "1"}, "rm": true}); [r.id, r.status, r.ok].join(" ")`, "c1 3 false"},
+		{`require('@docker').run("alpine:3.20", {"detach": true}).id`, "c1"},
+		{`require('@docker').logs("c1")`, "hello\nworoops\nld"},
+		{`lines = []; require('@docker').logs("c1", f(l) { lines.push(l) }); lines.join("|")`, "hello|woroops|ld"},
+		{`lines = []; require('@docker').logs("c1", f(l) { lines.push(l); false }); lines.join("|")`, "hello"},
+		{`require('@docker').logs("nope")`, "No such container: nope"},
+		{`require('@docker').run("alpine", {"nope": 1})`, "unknown docker_run(...) option 'nope' (available: cmd, detach, env, name, ports, rm, volumes, workdir)"},
+	}
+
+	testStdLib(tests, t)
+
+	if cmd, _ := json.Marshal(created["Cmd"]); string(cmd) != `["sh","-c","echo hello"]` {
+		t.Fatalf("wrong command: %s", cmd)
+	}
+
+	if !removed {
+		t.Fatalf("expected the container to be removed")
+	}
+}
+
+func TestClipboard(t *testing.T) {
+	if _, err := util.ReadClipboard(); err != util.ErrClipboardUnavailable {
+		t.Skip("the clipboard is available")
+	}
+
+	tests := []tests{
+		{`require('@clipboard').read()`, util.ErrClipboardUnavailable.Error()},
+		{`require('@clipboard').write("hello")`, util.ErrClipboardUnavailable.Error()},
+		{`require('@clipboard').write(1)`, "argument 0 to clipboard_write(...) is not supported (got: 1, allowed: STRING)"},
+	}
+
+	testStdLib(tests, t)
+}
+
+func TestTty(t *testing.T) {
+	// the output of tests isn't a terminal
+	if util.IsTTY(os.Stdout.Fd()) {
+		t.Skip("stdout is a terminal")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	t.Setenv("COLUMNS", "")
+	t.Setenv("LINES", "")
+
+	tests := []tests{
+		{`require('@tty').is_tty()`, false},
+		{`require('@tty').is_tty(1)`, false},
+		{`require('@tty').is_tty(1.5)`, "tty_is_tty(...) expects a file descriptor, got 1.5"},
+		{`require('@tty').supports_color()`, false},
+		{`FORCE_COLOR = "1"; require('@tty').supports_color()`, true},
+		{`FORCE_COLOR = "1"; NO_COLOR = "1"; require('@tty').supports_color(2)`, false},
+		{`COLUMNS = 100; LINES = 30; s = require('@tty').size(); [s.width, s.height].join("x")`, "100x30"},
+		{`require('@tty').size()`, "fd 1 is not a terminal, and COLUMNS / LINES aren't set"},
+	}
+
+	testStdLib(tests, t)
+}
+
+func TestPs(t *testing.T) {
+	pid := os.Getpid()
+
+	tests := []tests{
+		{fmt.Sprintf(`require('@ps').list().filter(f(p) { p.pid == %d }).len() == 1`, pid), true},
+		{`require('@ps').list()[0].keys().sort().join(",")`, "cmd,cpu,mem,pid"},
+		{fmt.Sprintf(`require('@ps').exists(%d)`, pid), true},
+		{`require('@ps').exists(0)`, false},
+		{`require('@ps').exists(1.5)`, "ps_exists(...) expects a pid, got 1.5"},
+	}
+
+	testStdLib(tests, t)
+}
+
+func TestGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Alex")
+	t.Setenv("GIT_AUTHOR_EMAIL", "alex@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Alex")
+	t.Setenv("GIT_COMMITTER_EMAIL", "alex@example.com")
+
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "-C", dir, "init", "--quiet", "--initial-branch=master").CombinedOutput(); err != nil {
+		t.Fatalf("%s", out)
+	}
+	os.WriteFile(filepath.Join(dir, "VERSION"), []byte("1.0\n"), 0644)
+
+	tests := []tests{
+		{`require('@git').log().len()`, 0},
+		{`require('@git').status().files.map(f(file) { file.staged + file.unstaged + " " + file.path }).join(",")`, "?? VERSION"},
+		{`require('@git').commit("Release 1.0", {"files": ["VERSION"]}).len()`, 40},
+		{`require('@git').status().clean`, true},
+		{`l = require('@git').log({"n": 1}); [l.len(), l[0].subject, l[0].author].join(" ")`, "1 Release 1.0 Alex"},
+		{`require('@git').branch("release", {"checkout": true}); require('@git').branches().filter(f(b) { b.current })[0].name`, "release"},
+		{`require('@git').status().branch`, "release"},
+		{`require('@git').diff().len()`, 0},
+		{`require('@git').log({"nope": 1})`, "unknown git_log(...) option 'nope'"},
+		{`require('@git').log({"n": "1"})`, "git_log(...) option 'n' must be a NUMBER, got STRING"},
+		{`require('@git').commit("Nothing")`, "git commit: On branch release\nnothing to commit, working tree clean"},
+	}
+
+	for i, tt := range tests {
+		tests[i].input = "with_cwd('" + dir + "', f() { " + tt.input + " })"
+	}
+
+	testStdLib(tests, t)
+}
+
+func TestGitCommandPolicy(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "-C", dir, "init", "--quiet").CombinedOutput(); err != nil {
+		t.Fatalf("%s", out)
+	}
+
+	records := []AuditRecord{}
+	AuditFunc = func(r AuditRecord) {
+		if strings.HasPrefix(r.Command, "git ") {
+			records = append(records, r)
+		}
+	}
+	defer func() {
+		AuditFunc = nil
+		deniedCommands = []string{}
+	}()
+
+	allowed := []tests{
+		{`require('@git').status().clean`, true},
+		{`ABS_DRY_RUN = true; require('@git').commit("Nothing", {"all": true})`, ""},
+	}
+	for i, tt := range allowed {
+		allowed[i].input = "with_cwd('" + dir + "', f() { " + tt.input + " })"
+	}
+	testStdLib(allowed, t)
+
+	if len(records) != 1 || records[0].Command != "git status --porcelain=v2 --branch --untracked-files=all -z" || records[0].Dir != dir {
+		t.Errorf("expected git status to be audited, got %+v", records)
+	}
+
+	deniedCommands = []string{"git"}
+	denied := []tests{
+		{`require('@git').status()`, "command not allowed: 'git' is in the list of denied commands"},
+		{`require('@git').clone("https://example.com/repo.git", "repo")`, "command not allowed: 'git' is in the list of denied commands"},
+	}
+	for i, tt := range denied {
+		denied[i].input = "with_cwd('" + dir + "', f() { " + tt.input + " })"
+	}
+	testStdLib(denied, t)
+}
+
+func TestMetrics(t *testing.T) {
+	tests := []tests{
+		{`m = require('@metrics'); c = m.counter("test_counter"); c.inc(); c.inc(2); c.value()`, 3},
+		{`m = require('@metrics'); c = m.counter("test_labels"); c.inc({"a": 1}); c.inc(); c.value({"a": 1})`, 1},
+		{`m = require('@metrics'); c = m.counter("test_twice"); c.inc(); m.counter("test_twice").value()`, 1},
+		{`m = require('@metrics'); c = m.counter("test_down"); c.inc(-1)`, "counters can only go up, got -1"},
+		{`m = require('@metrics'); m.counter("test_type"); m.gauge("test_type")`, "metric 'test_type' is already registered as a counter"},
+		{`m = require('@metrics'); m.counter("test-invalid")`, "invalid metric name 'test-invalid'"},
+		{`m = require('@metrics'); g = m.gauge("test_gauge"); g.set(5); g.dec(); g.inc(0.5); g.value()`, 4.5},
+		{`m = require('@metrics'); h = m.histogram("test_histogram", "help", [1]); h.observe(0.5); h.observe(2); "test_histogram_bucket{le=\"1\"} 1" in m.render()`, true},
+		{`m = require('@metrics'); m.counter("test_render", "Some help").inc(); "# HELP test_render Some help\n# TYPE test_render counter\ntest_render 1" in m.render()`, true},
+	}
+
+	testStdLib(tests, t)
+}
+
+func TestFs(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "lines.txt")
+	os.WriteFile(file, []byte("first\nsecond\r\nlast"), 0644)
+
+	tests := []tests{
+		{`fs = require('@fs'); lines = []; for i, l in fs.lines("` + file + `") { lines.push("$i:$l") }; lines.join(",")`, "0:first,1:second,2:last"},
+		{`fs = require('@fs'); next = fs.lines("` + file + `"); next(); next()`, "second"},
+		{`fs = require('@fs'); seen = []; for l in fs.lines("` + file + `") { seen.push(l); if l == "second" { break } }; seen.len()`, 2},
+		{`require('@fs').lines("/does/not/exist")`, "open /does/not/exist: no such file or directory"},
+	}
+
+	testStdLib(tests, t)
+}
+
+func TestSemver(t *testing.T) {
+	tests := []tests{
+		{`semver = require('@semver'); v = semver.parse("v1.2.3-beta.1+build"); [v.major, v.minor, v.patch, v.prerelease, v.build].str()`, `[1, 2, 3, "beta.1", "build"]`},
+		{`require('@semver').parse("1.2").version`, "1.2.0"},
+		{`require('@semver').parse("1.b")`, "invalid version '1.b': b is not a number"},
+		{`require('@semver').valid("1.2.3")`, true},
+		{`require('@semver').valid("latest")`, false},
+		{`require('@semver').compare("1.10.0", "1.9.0")`, 1},
+		{`require('@semver').compare("1.0.0-rc.1", "1.0.0")`, -1},
+		{`require('@semver').compare("1.0.0+a", "1.0.0")`, 0},
+		{`require('@semver').satisfies("1.5.0", "^1.2")`, true},
+		{`require('@semver').satisfies("2.0.0", "^1.2")`, false},
+		{`require('@semver').satisfies("3.2.0", ">=1.2 <2 || ~3.2")`, true},
+		{`require('@semver').satisfies("1.0.0", "^x.1")`, "invalid range '^x.1'"},
+	}
+
+	testStdLib(tests, t)
+}
+
+func TestHumanize(t *testing.T) {
+	tests := []tests{
+		{`require('@humanize').bytes(1500000)`, "1.5 MB"},
+		{`require('@humanize').bytes(1536, true)`, "1.5 KiB"},
+		{`require('@humanize').bytes(2GiB, true)`, "2 GiB"},
+		{`require('@humanize').duration(5400000)`, "1h 30m"},
+		{`require('@humanize').duration(90s)`, "1m 30s"},
+		{`require('@humanize').number(1234567.5)`, "1,234,567.5"},
+		{`require('@humanize').time_ago(unix_ms() - 300000)`, "5 minutes ago"},
+		{`require('@humanize').time_ago(unix_ms() + 7200500)`, "in 2 hours"},
+		{`require('@humanize').time_ago("2000-01-01T00:00:00Z").suffix("years ago")`, true},
+		{`require('@humanize').time_ago("yesterday")`, "humanize_time_ago(...) requires unix ms or an RFC 3339 time (eg. 2024-01-01T12:00:00Z), got yesterday"},
+	}
+
+	testStdLib(tests, t)
+}
+
+func TestGen(t *testing.T) {
+	tests := []tests{
+		{`gen = require('@gen'); quickcheck(f(xs = gen.array_of(gen.int())) { xs.reverse().reverse().str() == xs.str() })`, true},
+		{`gen = require('@gen'); quickcheck(f(h = gen.hash_of(gen.string(5), 3)) { h.keys().len() <= 3 }, {"runs": 50})`, true},
+		{`gen = require('@gen'); quickcheck(f(n = gen.int(5, 10)) { n >= 5 && n <= 10 && n.int() == n })`, true},
+		{`gen = require('@gen'); quickcheck(f(n = gen.int()) { n < 50 }, {"seed": 1})`, "quickcheck(...) failed after"},
+		{`gen = require('@gen'); quickcheck(f(n = gen.int()) { "yes" })`, "quickcheck(...) property must return a boolean, got yes"},
+		{`quickcheck(f(n = {"generate": f(size) { 7 }}) { n != 7 })`, "quickcheck(...) failed after 1 runs (0 shrinks, seed"},
+		{`quickcheck(f(n) { true })`, "quickcheck(...) requires every parameter of the property to default to a generator, eg. f(n = gen.int()), got n"},
+		{`quickcheck(f(n = 1) { true })`, "quickcheck(...) parameter n doesn't default to a generator (a hash with a generate(size) function), got 1"},
+		{`gen = require('@gen'); quickcheck(f(n = gen.int()) { true }, {"runs": 0})`, "quickcheck(...) option 'runs' must be a whole number greater than 0, got 0"},
+		{`gen = require('@gen'); gen.int(1, 3).shrink(3)`, []int{1, 2}},
+		{`gen = require('@gen'); gen.int(-8, 8).shrink(-8)`, []int{0, -4, -6, -7}},
+		{`gen = require('@gen'); gen.string().shrink("ab")`, []string{"", "a", "b", "b", "a", "aa"}},
+		{`gen = require('@gen'); gen.array_of(gen.int()).shrink([2]).str()`, "[[], [0], [1]]"},
+		{`gen = require('@gen'); gen.array_of(gen.int()).shrink([1, 2]).str()`, "[[], [1], [2], [2], [1], [0, 2], [1, 0], [1, 1]]"},
+		{`gen = require('@gen'); gen.string(3).generate(100).split("").len() <= 3`, true},
+		{`gen = require('@gen'); gen.array_of(gen.int(), 2).generate(100).len() <= 2`, true},
+		{`gen = require('@gen'); gen.int(3, 1)`, "gen_int(...) requires min to be lower than or equal to max, got 3 and 1"},
+		{`gen = require('@gen'); gen.array_of(1)`, "Wrong arguments passed to 'gen_array_of'"},
+		{`gen = require('@gen'); gen.array_of({})`, "gen_array_of(...) requires a generator (a hash with a generate(size) function), got {}"},
+	}
+
+	testStdLib(tests, t)
+}
+
 func testStdLib(tests []tests, t *testing.T) {
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
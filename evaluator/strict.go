@@ -0,0 +1,104 @@
+package evaluator
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/abs-lang/abs/lexer"
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// Strict, set through ABS_STRICT=1, turns implicit conversions
+// (such as "1" == 1, which is always false) into errors. When
+// not strict, they're reported as warnings on stderr instead.
+var Strict = os.Getenv("ABS_STRICT") == "1"
+
+// Locations we have already warned about, per source
+// being evaluated, so that an implicit conversion within
+// a loop is only reported once per run. Sources are
+// forgotten once they're done running, see BeginEval.
+var (
+	warnedMu sync.Mutex
+	warned   = map[*lexer.Lexer]map[int]bool{}
+)
+
+// Whether we have already warned about the given
+// location of a source, marking it as warned about
+func alreadyWarned(l *lexer.Lexer, pos int) bool {
+	warnedMu.Lock()
+	defer warnedMu.Unlock()
+
+	if warned[l] == nil {
+		warned[l] = map[int]bool{}
+	}
+
+	if warned[l][pos] {
+		return true
+	}
+
+	warned[l][pos] = true
+	return false
+}
+
+// Forgets the warnings about a source
+// once it's done running
+func forgetWarnings(l *lexer.Lexer) {
+	warnedMu.Lock()
+	defer warnedMu.Unlock()
+
+	delete(warned, l)
+}
+
+// implicitConversion reports an operation that mixes types:
+// in strict mode it returns an error, otherwise it prints a
+// warning and returns nil so that evaluation can continue.
+func implicitConversion(tok token.Token, env *object.Environment, format string, a ...interface{}) *object.Error {
+	if Strict {
+		return newError(tok, format, a...)
+	}
+
+	if !alreadyWarned(lex, tok.Position) {
+		lineNum, column, errorLine := lex.ErrorLine(tok.Position)
+		position := fmt.Sprintf("[%d:%d]\t%s", lineNum, column, errorLine)
+		fmt.Fprintf(env.Stdio.Stderr, "warning: %s\n\t%s\n", fmt.Sprintf(format, a...), position)
+	}
+
+	return nil
+}
+
+// isImplicitEquality tells whether an equality check
+// mixes types, and is therefore always false.
+// Checks against null are fine, as they're the
+// idiomatic way to test for a missing value.
+func isImplicitEquality(left, right object.Object) bool {
+	return left.Type() != right.Type() && left.Type() != object.NULL_OBJ && right.Type() != object.NULL_OBJ
+}
+
+// conversionHint suggests how to explicitly convert one
+// of the operands so that their types match, eg.
+// `, use int("1")` for "1" + 1.
+func conversionHint(left, right object.Object) string {
+	for _, pair := range [][2]object.Object{{left, right}, {right, left}} {
+		s, ok := pair[0].(*object.String)
+		if !ok || pair[1].Type() != object.NUMBER_OBJ {
+			continue
+		}
+
+		if n, err := strconv.ParseFloat(s.Value, 64); err == nil {
+			fn := "number"
+			if n == math.Trunc(n) {
+				fn = "int"
+			}
+
+			return fmt.Sprintf(", use %s(%q)", fn, s.Value)
+		}
+
+		return fmt.Sprintf(", use %s.str()", pair[1].Inspect())
+	}
+
+	return ""
+}
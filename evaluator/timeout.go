@@ -0,0 +1,71 @@
+package evaluator
+
+import (
+	"context"
+	"time"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/token"
+)
+
+// The error Eval returns once the deadline passed,
+// turned into a proper error by with_timeout(...)
+const deadlineExceeded = "deadline exceeded"
+
+// Whether the deadline set by the innermost with_timeout(...)
+// the code runs in, if any, has passed. Deadlines are kept in
+// the context of the code (see object.Environment.Context),
+// so that they only apply to the evaluation that set them:
+// once it passes, that evaluation bails out at the next
+// node it reaches, and its commands are killed.
+func deadlineExpired(env *object.Environment) bool {
+	d, ok := envContext(env).Deadline()
+	return ok && !time.Now().Before(d)
+}
+
+// A context that expires with the deadline of the code
+// run in env, or once the program is interrupted, so
+// that commands don't outlive either
+func deadlineContext(env *object.Environment) (context.Context, context.CancelFunc) {
+	d, ok := envContext(env).Deadline()
+	if !ok {
+		return context.WithCancel(interruptContext())
+	}
+
+	return context.WithDeadline(interruptContext(), d)
+}
+
+// with_timeout(500, f() {...}) or with_timeout(5s, f() {...})
+func withTimeoutFn(tok token.Token, env *object.Environment, args ...object.Object) object.Object {
+	err := validateArgs(tok, "with_timeout", args, 2, [][]string{{object.NUMBER_OBJ, object.DURATION_OBJ}, {object.FUNCTION_OBJ, object.BUILTIN_OBJ}})
+	if err != nil {
+		return err
+	}
+
+	var timeout time.Duration
+	switch arg := args[0].(type) {
+	case *object.Duration:
+		timeout = arg.Value
+	case *object.Number:
+		timeout = time.Duration(arg.Value) * time.Millisecond
+	}
+
+	// The deadline is never later than the ones
+	// of the calls we're nested in
+	own := time.Now().Add(timeout)
+	ctx, cancel := context.WithDeadline(envContext(env), own)
+	defer cancel()
+
+	scoped := object.NewEnclosedEnvironment(env, env.CurrentArgs)
+	scoped.Context = ctx
+	res := applyFunction(tok, args[1], scoped, []object.Object{})
+
+	// Only report the timeout if it was ours: the
+	// deadline of an outer call might have passed
+	// first, and that call should report it
+	if e, ok := res.(*object.Error); ok && e.Message == deadlineExceeded && !time.Now().Before(own) {
+		return newError(tok, "with_timeout(...) timed out after %s", (&object.Duration{Value: timeout}).Inspect())
+	}
+
+	return res
+}
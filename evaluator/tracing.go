@@ -0,0 +1,246 @@
+package evaluator
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tracing of function calls and commands.
+// Spans are collected only when ABS_OTEL_ENDPOINT
+// is set, and exported via OTLP/HTTP (JSON encoding)
+// when FlushTraces() is called, usually at the end
+// of a run.
+var tracer = &spanRecorder{}
+
+type spanRecorder struct {
+	sync.Mutex
+	traceID string
+	// Spans currently open, the last one
+	// being the parent of the next span
+	stack []*Span
+	// Spans that ended and are waiting
+	// to be exported
+	done []*Span
+}
+
+// Span represents a unit of work
+// (eg. a function call) being traced.
+type Span struct {
+	traceID  string
+	id       string
+	parentID string
+	name     string
+	start    time.Time
+	end      time.Time
+	attrs    map[string]interface{}
+	failed   bool
+	detached bool
+}
+
+// StartSpan (name, attrs)
+// Starts a span, child of the span currently open.
+// It returns nil if tracing isn't enabled, and all
+// methods of Span are nil-safe, so callers don't have to care.
+func StartSpan(name string, attrs map[string]interface{}) *Span {
+	return tracer.start(name, attrs, false)
+}
+
+// Starts a span that isn't going to be the parent
+// of any other span, eg. for background commands
+// that end at some point in the future.
+func startDetachedSpan(name string, attrs map[string]interface{}) *Span {
+	return tracer.start(name, attrs, true)
+}
+
+func (r *spanRecorder) start(name string, attrs map[string]interface{}, detached bool) *Span {
+	if os.Getenv("ABS_OTEL_ENDPOINT") == "" {
+		return nil
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if r.traceID == "" {
+		r.traceID = randomHex(16)
+	}
+
+	s := &Span{traceID: r.traceID, id: randomHex(8), name: name, start: time.Now(), attrs: attrs, detached: detached}
+
+	if len(r.stack) > 0 {
+		s.parentID = r.stack[len(r.stack)-1].id
+	}
+
+	if !detached {
+		r.stack = append(r.stack, s)
+	}
+
+	return s
+}
+
+// SetAttribute adds an attribute to the span
+func (s *Span) SetAttribute(k string, v interface{}) {
+	if s == nil {
+		return
+	}
+
+	tracer.Lock()
+	defer tracer.Unlock()
+
+	if s.attrs == nil {
+		s.attrs = map[string]interface{}{}
+	}
+	s.attrs[k] = v
+}
+
+// End (failed)
+// Ends the span, marking it as failed if needed
+func (s *Span) End(failed bool) {
+	if s == nil {
+		return
+	}
+
+	tracer.Lock()
+	defer tracer.Unlock()
+
+	s.end = time.Now()
+	s.failed = failed
+
+	if !s.detached {
+		for i := len(tracer.stack) - 1; i >= 0; i-- {
+			if tracer.stack[i] == s {
+				tracer.stack = append(tracer.stack[:i], tracer.stack[i+1:]...)
+				break
+			}
+		}
+	}
+
+	tracer.done = append(tracer.done, s)
+}
+
+// FlushTraces exports the spans that ended
+// to the OTLP endpoint. Once no span is open
+// anymore, the next span will start a new trace.
+func FlushTraces() error {
+	endpoint := os.Getenv("ABS_OTEL_ENDPOINT")
+
+	tracer.Lock()
+	spans := tracer.done
+	tracer.done = nil
+	if len(tracer.stack) == 0 {
+		tracer.traceID = ""
+	}
+	tracer.Unlock()
+
+	if endpoint == "" || len(spans) == 0 {
+		return nil
+	}
+
+	if !strings.HasSuffix(endpoint, "/v1/traces") {
+		endpoint = strings.TrimRight(endpoint, "/") + "/v1/traces"
+	}
+
+	body, err := json.Marshal(otlpPayload(spans))
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	res, err := client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("unable to export traces to %s: %s", endpoint, res.Status)
+	}
+
+	return nil
+}
+
+// See https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+func otlpPayload(spans []*Span) map[string]interface{} {
+	service := os.Getenv("OTEL_SERVICE_NAME")
+	if service == "" {
+		service = "abs"
+	}
+
+	otlpSpans := []map[string]interface{}{}
+	for _, s := range spans {
+		// 1 is OK, 2 is ERROR
+		status := 1
+		if s.failed {
+			status = 2
+		}
+
+		span := map[string]interface{}{
+			"traceId":           s.traceID,
+			"spanId":            s.id,
+			"name":              s.name,
+			"kind":              1,
+			"startTimeUnixNano": fmt.Sprintf("%d", s.start.UnixNano()),
+			"endTimeUnixNano":   fmt.Sprintf("%d", s.end.UnixNano()),
+			"attributes":        otlpAttributes(s.attrs),
+			"status":            map[string]interface{}{"code": status},
+		}
+
+		if s.parentID != "" {
+			span["parentSpanId"] = s.parentID
+		}
+
+		otlpSpans = append(otlpSpans, span)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": otlpAttributes(map[string]interface{}{"service.name": service}),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": "abs"},
+						"spans": otlpSpans,
+					},
+				},
+			},
+		},
+	}
+}
+
+func otlpAttributes(attrs map[string]interface{}) []interface{} {
+	res := []interface{}{}
+
+	for k, v := range attrs {
+		var value map[string]interface{}
+
+		switch v := v.(type) {
+		case bool:
+			value = map[string]interface{}{"boolValue": v}
+		case int:
+			value = map[string]interface{}{"intValue": fmt.Sprintf("%d", v)}
+		case float64:
+			value = map[string]interface{}{"doubleValue": v}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprintf("%v", v)}
+		}
+
+		res = append(res, map[string]interface{}{"key": k, "value": value})
+	}
+
+	return res
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
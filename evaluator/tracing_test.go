@@ -0,0 +1,63 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestTracing(t *testing.T) {
+	received := []map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("traces exported to the wrong path: %s", r.URL.Path)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		payload := map[string]interface{}{}
+		json.Unmarshal(body, &payload)
+
+		for _, rs := range payload["resourceSpans"].([]interface{}) {
+			for _, ss := range rs.(map[string]interface{})["scopeSpans"].([]interface{}) {
+				for _, s := range ss.(map[string]interface{})["spans"].([]interface{}) {
+					received = append(received, s.(map[string]interface{}))
+				}
+			}
+		}
+	}))
+	defer server.Close()
+
+	// no endpoint, no spans
+	testEval("f hello() { return 1 }; hello()")
+	if err := FlushTraces(); err != nil || len(received) != 0 {
+		t.Fatalf("spans should not be exported without ABS_OTEL_ENDPOINT, got %d (%v)", len(received), err)
+	}
+
+	os.Setenv("ABS_OTEL_ENDPOINT", server.URL)
+	defer os.Unsetenv("ABS_OTEL_ENDPOINT")
+
+	testEval("f hello() { return `echo hello` }; hello()")
+	if err := FlushTraces(); err != nil {
+		t.Fatalf("error exporting traces: %s", err)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(received))
+	}
+
+	command, function := received[0], received[1]
+	if command["name"] != "command" || function["name"] != "f hello" {
+		t.Fatalf("wrong span names: %s, %s", command["name"], function["name"])
+	}
+
+	if command["parentSpanId"] != function["spanId"] {
+		t.Fatalf("the command span should be a child of the function span")
+	}
+
+	if command["traceId"] != function["traceId"] {
+		t.Fatalf("spans should belong to the same trace")
+	}
+}
@@ -0,0 +1,117 @@
+// Package i18n translates the messages ABS shows its
+// users, such as the REPL's welcome and help output,
+// syntax errors and the most common runtime errors.
+//
+// Messages are identified by their english text (for
+// formatted messages, the format string), which is
+// also what's shown when there's no translation.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Translations embedded in ABS, by language
+var catalogs = map[string]map[string]string{
+	"it": italian,
+}
+
+var (
+	mu       sync.RWMutex
+	language = "en"
+	current  = map[string]string{}
+)
+
+// SetLanguage (lang)
+// Selects the language messages are translated to,
+// either an embedded one (eg. "it", "it_IT.UTF-8")
+// or a JSON file mapping english messages
+// to their translation. An empty lang,
+// or "en", switches back to english.
+func SetLanguage(lang string) error {
+	catalog, name, err := loadCatalog(lang)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	language = name
+	current = catalog
+
+	return nil
+}
+
+func loadCatalog(lang string) (map[string]string, string, error) {
+	if strings.HasSuffix(lang, ".json") {
+		b, err := os.ReadFile(lang)
+		if err != nil {
+			return nil, "", err
+		}
+
+		catalog := map[string]string{}
+		if err := json.Unmarshal(b, &catalog); err != nil {
+			return nil, "", fmt.Errorf("unable to parse %s: %s", lang, err.Error())
+		}
+
+		return catalog, lang, nil
+	}
+
+	name, _, _ := strings.Cut(lang, ".")
+	name, _, _ = strings.Cut(strings.ToLower(strings.ReplaceAll(name, "-", "_")), "_")
+
+	if name == "" || name == "en" {
+		return map[string]string{}, "en", nil
+	}
+
+	catalog, ok := catalogs[name]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported language '%s' (supported languages are %s)", lang, strings.Join(Languages(), ", "))
+	}
+
+	return catalog, name, nil
+}
+
+// Language returns the language currently in use
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return language
+}
+
+// Languages returns the embedded languages, english included
+func Languages() []string {
+	langs := []string{"en"}
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs[1:])
+
+	return langs
+}
+
+// T (msg)
+// Translates a message, returning
+// it as-is when there's no translation
+func T(msg string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if t, ok := current[msg]; ok {
+		return t
+	}
+
+	return msg
+}
+
+// Sprintf (format, a...)
+// Translates format and formats it with a
+func Sprintf(format string, a ...any) string {
+	return fmt.Sprintf(T(format), a...)
+}
@@ -0,0 +1,113 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestSetLanguage(t *testing.T) {
+	defer SetLanguage("")
+
+	tests := []struct {
+		lang     string
+		expected string
+		err      bool
+	}{
+		{"", "en", false},
+		{"en_US.UTF-8", "en", false},
+		{"it", "it", false},
+		{"it_IT.UTF-8", "it", false},
+		{"IT-it", "it", false},
+		{"xx", "", true},
+		{"/does/not/exist.json", "", true},
+	}
+
+	for _, tt := range tests {
+		SetLanguage("")
+		err := SetLanguage(tt.lang)
+
+		if tt.err {
+			if err == nil {
+				t.Fatalf("expected an error for '%s'", tt.lang)
+			}
+			if Language() != "en" {
+				t.Fatalf("expected a failed SetLanguage('%s') to keep english, got %s", tt.lang, Language())
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("unexpected error for '%s': %s", tt.lang, err)
+		}
+
+		if Language() != tt.expected {
+			t.Fatalf("expected '%s' to select %s, got %s", tt.lang, tt.expected, Language())
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	defer SetLanguage("")
+
+	if got := T("division by zero"); got != "division by zero" {
+		t.Fatalf("expected english by default, got %s", got)
+	}
+
+	SetLanguage("it")
+	if got := T("division by zero"); got != "divisione per zero" {
+		t.Fatalf("expected an italian translation, got %s", got)
+	}
+
+	if got := Sprintf("identifier not found: %s", "x"); got != "identificatore non trovato: x" {
+		t.Fatalf("expected an italian translation, got %s", got)
+	}
+
+	if got := T("no translation for this"); got != "no translation for this" {
+		t.Fatalf("expected untranslated messages to be returned as-is, got %s", got)
+	}
+}
+
+func TestCatalogFile(t *testing.T) {
+	defer SetLanguage("")
+
+	path := filepath.Join(t.TempDir(), "pirate.json")
+	os.WriteFile(path, []byte(`{"division by zero": "ye can't split by naught"}`), 0644)
+
+	if err := SetLanguage(path); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := T("division by zero"); got != "ye can't split by naught" {
+		t.Fatalf("expected the translation from the file, got %s", got)
+	}
+
+	os.WriteFile(path, []byte(`not json`), 0644)
+	if err := SetLanguage(path); err == nil {
+		t.Fatalf("expected an error for an invalid catalog")
+	}
+}
+
+// Translations must keep the verbs of the
+// english message, in the same order
+func TestCatalogVerbs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+	for lang, catalog := range catalogs {
+		for msg, translation := range catalog {
+			expected := verbs.FindAllString(msg, -1)
+			got := verbs.FindAllString(translation, -1)
+
+			if len(expected) != len(got) {
+				t.Fatalf("%s translation of '%s' has verbs %v, want %v", lang, msg, got, expected)
+			}
+
+			for i := range expected {
+				if expected[i] != got[i] {
+					t.Fatalf("%s translation of '%s' has verbs %v, want %v", lang, msg, got, expected)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package i18n
+
+// Italian translations
+var italian = map[string]string{
+	// REPL
+	"there": "straniero",
+	"Hello %s, welcome to the ABS (%s) programming language!":                                          "Ciao %s, benvenuto nel linguaggio di programmazione ABS (%s)!",
+	"Type 'quit' when you're done, 'help' if you get lost!":                                            "Scrivi 'quit' quando hai finito, 'help' se ti perdi!",
+	"*** Update available: %s (your version is %s) ***":                                                "*** Aggiornamento disponibile: %s (la tua versione è la %s) ***",
+	"encountered %d syntax errors:":                                                                    "trovati %d errori di sintassi:",
+	"parser errors:":                                                                                   "errori di sintassi:",
+	"Looking for a function? Search the docs with:":                                                    "Cerchi una funzione? Consulta la documentazione con:",
+	"Otherwise, try typing something along the lines of:":                                              "Altrimenti, prova a scrivere qualcosa come:",
+	"A command should be triggered in your system. Then try printing the result of that command with:": "Verrà eseguito un comando nel tuo sistema. Poi prova a stamparne il risultato con:",
+	"Here some other valid examples of ABS code:":                                                      "Ecco qualche altro esempio di codice ABS:",
+	"More examples are available through ':examples <topic>' (%s)":                                     "Altri esempi sono disponibili con ':examples <argomento>' (%s)",
+	"[%d] is still running: wait for it to be done, or bring it back with :fg":                         "[%d] è ancora in esecuzione: attendi che termini, o riportalo in primo piano con :fg",
+	"exec(...) isn't available to jobs in the background, see :fg":                                     "exec(...) non è disponibile per i job in background, vedi :fg",
+	"available topics: %s":                                                                             "argomenti disponibili: %s",
+	"no examples about '%s', %s":                                                                       "nessun esempio su '%s', %s",
+	"unknown command ':%s'":                                                                            "comando sconosciuto ':%s'",
+	"nothing found for '%s'":                                                                           "nessun risultato per '%s'",
+	"%3.f%% -- ↑/↓ to scroll, q to quit":                                                               "%3.f%% -- ↑/↓ per scorrere, q per uscire",
+	"usage: :ast expr":                                                                                 "uso: :ast espressione",
+	"usage: :watch expr [interval]":                                                                    "uso: :watch espressione [intervallo]",
+	"every %s: %s (ctrl+c to stop)":                                                                    "ogni %s: %s (ctrl+c per smettere)",
+	"watching %s (ctrl+c to stop)":                                                                     "osservo %s (ctrl+c per smettere)",
+	"just now":                                                                                         "poco fa",
+	"%dm ago":                                                                                          "%d min fa",
+	"%dh ago":                                                                                          "%d ore fa",
+	"%dd ago":                                                                                          "%d giorni fa",
+	"%d of %d matches":                                                                                 "%d di %d risultati",
+	"'%s' isn't a valid number of entries":                                                             "'%s' non è un numero di voci valido",
+	"no entries given":                                                                                 "nessuna voce indicata",
+	"'%s' isn't a valid entry (eg. 3, 5-7 or last 10)":                                                 "'%s' non è una voce valida (es. 3, 5-7 o last 10)",
+	"there's no entry %d in the history":                                                               "non c'è nessuna voce %d nella cronologia",
+	"the history is empty":                                                                             "la cronologia è vuota",
+	"run some of them with ':hist 3 5-7'":                                                              "eseguine alcune con ':hist 3 5-7'",
+	"↑/↓ to move, space to select, enter to run, q to quit":                                            "↑/↓ per muoverti, spazio per selezionare, invio per eseguire, q per uscire",
+	"usage: :export last 10 > file.abs (or >> to append)":                                              "uso: :export last 10 > file.abs (o >> per aggiungere in coda)",
+	"usage: :load file.abs":                                                                            "uso: :load file.abs",
+	"usage: :time on|off":                                                                              "uso: :time on|off",
+	"timing is on":                                                                                     "cronometro attivo",
+	"timing is off":                                                                                    "cronometro disattivato",
+	"evaluation":                                                                                       "valutazione",
+	"1 command":                                                                                        "1 comando",
+	"%d commands":                                                                                      "%d comandi",
+	"took %s (%s)":                                                                                     "%s (%s)",
+	"usage: :save file.abs":                                                                            "uso: :save file.abs",
+	"saved %d entries to %s":                                                                           "salvate %d voci in %s",
+	"the environment has been reset":                                                                   "l'ambiente è stato azzerato",
+	"works on: %s":                                                                                     "si applica a: %s",
+	"examples:":                                                                                        "esempi:",
+	"exported %d entries to %s":                                                                        "esportate %d voci in %s",
+	"stopped watching":                                                                                 "osservazione terminata",
+	"%d lines, enter to run, ctrl+c to discard":                                                        "%d righe, invio per eseguire, ctrl+c per scartare",
+	"%s to abort":                                                                                      "%s per interrompere",
+	"no jobs":                                                                                          "nessun job",
+	"no such job: %s":                                                                                  "job inesistente: %s",
+	"running for %s":                                                                                   "in esecuzione da %s",
+	"[%d] done: %s":                                                                                    "[%d] terminato: %s",
+	"running (%s)":                                                                                     "in esecuzione (%s)",
+
+	// Syntax errors
+	"Illegal token '%s'":                           "Token non valido '%s'",
+	"expected next token to be %s, got %s instead": "il token successivo doveva essere %s, trovato invece %s",
+	"no prefix parse function for '%s' found":      "'%s' non può iniziare un'espressione",
+	"could not parse %q as number":                 "impossibile interpretare %q come numero",
+	"a decorator should decorate a named function": "un decoratore deve decorare una funzione con nome",
+	"found mandatory parameter after optional one": "trovato un parametro obbligatorio dopo uno opzionale",
+	"invalid parameter format":                     "formato del parametro non valido",
+	"you can only defer a call: defer some.method() | defer `some command` | defer some_fn()": "puoi rimandare solo una chiamata: defer some.method() | defer `some command` | defer some_fn()",
+
+	// Runtime errors
+	"identifier not found: %s":                                       "identificatore non trovato: %s",
+	"type mismatch: %s %s %s%s":                                      "tipi incompatibili: %s %s %s%s",
+	"unknown operator: %s %s %s":                                     "operatore sconosciuto: %s %s %s",
+	"unknown operator: %s%s":                                         "operatore sconosciuto: %s%s",
+	"unknown operator: -%s":                                          "operatore sconosciuto: -%s",
+	"unknown operator: +%s":                                          "operatore sconosciuto: +%s",
+	"division by zero":                                               "divisione per zero",
+	"index out of range: %d":                                         "indice fuori dai limiti: %d",
+	"unusable as hash key: %s":                                       "non utilizzabile come chiave di un hash: %s",
+	"cannot assign to %s":                                            "impossibile assegnare a %s",
+	"command not allowed: %s":                                        "comando non consentito: %s",
+	"wrong number of arguments to %s(...): got=%d, want=%d":          "numero di argomenti errato per %s(...): ricevuti=%d, attesi=%d",
+	"wrong number of arguments to %s(...): got=%d, min=%d, max=%d":   "numero di argomenti errato per %s(...): ricevuti=%d, min=%d, max=%d",
+	"argument %d to %s(...) is not supported (got: %s, allowed: %s)": "l'argomento %d di %s(...) non è supportato (ricevuto: %s, ammessi: %s)",
+}
@@ -14,6 +14,9 @@ type Lexer struct {
 	input        []rune
 	// map of input line boundaries used by linePosition() for error location
 	lineMap [][2]int // array of [begin, end] pairs: [[0,12], [13,22], [23,33] ... ]
+	// source map of bundles, parsed by SourceMap() when first needed
+	sourceMap       *SourceMap
+	sourceMapParsed bool
 }
 
 func New(in string) *Lexer {
@@ -61,6 +64,29 @@ func (l *Lexer) linePosition(pos int) (int, int, int) {
 	return lineNum, begin, end
 }
 
+// CommentAbove (pos) returns the comment lines right above
+// the line holding pos, without their leading '#', which
+// is how ABS code documents functions:
+//
+// # Adds 2 numbers
+// f add(a, b) { a + b }
+func (l *Lexer) CommentAbove(pos int) string {
+	lineNum, _, _ := l.linePosition(pos)
+	comment := []string{}
+
+	for i := lineNum - 2; i >= 0; i-- {
+		line := strings.TrimSpace(string(l.input[l.lineMap[i][0]:l.lineMap[i][1]]))
+
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+
+		comment = append([]string{strings.TrimSpace(strings.TrimPrefix(line, "#"))}, comment...)
+	}
+
+	return strings.Join(comment, " ")
+}
+
 // ErrorLine (pos) returns lineNum, column, errorLine
 func (l *Lexer) ErrorLine(pos int) (int, int, string) {
 	lineNum, begin, end := l.linePosition(pos)
@@ -248,9 +274,23 @@ func (l *Lexer) NextToken() token.Token {
 			tok = l.newToken(token.DOT)
 		}
 	case '?':
-		tok = l.newToken(token.QUESTION)
+		if l.peekChars(2) == "?=" {
+			tok.Type = token.COMP_NULLISH
+			tok.Position = l.position
+			tok.Literal = "??="
+			l.readChar()
+			l.readChar()
+		} else {
+			tok = l.newToken(token.QUESTION)
+		}
 	case '|':
-		if l.peekChar() == '|' {
+		if l.peekChars(2) == "|=" {
+			tok.Type = token.COMP_OR
+			tok.Position = l.position
+			tok.Literal = "||="
+			l.readChar()
+			l.readChar()
+		} else if l.peekChar() == '|' {
 			tok.Type = token.OR
 			tok.Position = l.position
 			tok.Literal = l.readLogicalOperator()
@@ -394,6 +434,8 @@ func isCharAllowedInNumber(c rune) bool {
 // 12e-1
 // 12e+1
 // 12e1
+// 5s
+// 10MB
 func (l *Lexer) readNumber() (number string, kind token.TokenType) {
 	position := l.position
 	kind = token.NUMBER
@@ -414,6 +456,13 @@ func (l *Lexer) readNumber() (number string, kind token.TokenType) {
 			hasExponent = true
 		}
 
+		// Units are checked before abbreviations so that
+		// 10MB is a size rather than 10 million followed
+		// by a B
+		if unitKind := l.readUnit(); unitKind != "" {
+			return strings.ReplaceAll(string(l.input[position:l.position]), "_", ""), unitKind
+		}
+
 		// If this character is a number abbreviation
 		// (eg. the K in 12K), let's read it and complete
 		// the number
@@ -445,9 +494,40 @@ func (l *Lexer) readNumber() (number string, kind token.TokenType) {
 		return string(l.input[position:l.position]), token.ILLEGAL
 	}
 
+	if unitKind := l.readUnit(); unitKind != "" {
+		return strings.ReplaceAll(string(l.input[position:l.position]), "_", ""), unitKind
+	}
+
 	return strings.ReplaceAll(string(l.input[position:l.position]), "_", ""), kind
 }
 
+// readUnit consumes a duration or size unit (eg. the ms in 200ms)
+// if one starts at the current position, and returns the kind
+// of literal the unit makes. Nothing is consumed if the letters
+// ahead are not a known unit.
+func (l *Lexer) readUnit() token.TokenType {
+	end := l.position
+	for end < len(l.input) && unicode.IsLetter(l.input[end]) {
+		end++
+	}
+
+	var kind token.TokenType
+	unit := string(l.input[l.position:end])
+	if _, ok := token.DurationUnits[unit]; ok {
+		kind = token.DURATION
+	} else if _, ok := token.SizeUnits[unit]; ok {
+		kind = token.SIZE
+	} else {
+		return ""
+	}
+
+	for l.position < end {
+		l.readChar()
+	}
+
+	return kind
+}
+
 // A logical operator is 2 chars, so
 // we can simply read 2 chars and call
 // it a day.
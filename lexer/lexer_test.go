@@ -580,3 +580,42 @@ func TestUnicode(t *testing.T) {
 		}
 	}
 }
+
+func TestSourceMap(t *testing.T) {
+	m := &SourceMap{Version: 1}
+	m.Add("main.abs", 1)
+	m.Add("", 0)
+	m.Add("lib/util.abs", 3)
+	m.Add("main.abs", 2)
+
+	input := "#!/usr/bin/env abs\n# bundled\nx = 1\ny = 2\n" + m.Directive() + "\n"
+	parsed := New(input).SourceMap()
+	if parsed == nil {
+		t.Fatalf("no source map found in %q", input)
+	}
+
+	tests := []struct {
+		line int
+		file string
+		orig int
+		ok   bool
+	}{
+		{1, "main.abs", 1, true},
+		{2, "", 0, false},
+		{3, "lib/util.abs", 3, true},
+		{4, "main.abs", 2, true},
+		{5, "", 0, false},
+		{0, "", 0, false},
+	}
+
+	for _, tt := range tests {
+		file, orig, ok := parsed.Lookup(tt.line)
+		if file != tt.file || orig != tt.orig || ok != tt.ok {
+			t.Errorf("Lookup(%d) wrong. expected=%s:%d (%v), got=%s:%d (%v)", tt.line, tt.file, tt.orig, tt.ok, file, orig, ok)
+		}
+	}
+
+	if New("x = 1\n# abs-source-map: {").SourceMap() != nil || New("x = 1").SourceMap() != nil {
+		t.Errorf("expected no source map for scripts without a valid one")
+	}
+}
@@ -0,0 +1,92 @@
+package lexer
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// SourceMapDirective introduces the source map of a bundle
+// (see "abs bundle"), in a comment on its last line
+const SourceMapDirective = "# abs-source-map: "
+
+// SourceMap maps the lines of a bundle back to
+// the files, and lines, they were inlined from
+type SourceMap struct {
+	Version int      `json:"version"`
+	Files   []string `json:"files"`
+	// For every line of the bundle, the index of the
+	// file it comes from and its line there, or an
+	// empty list for lines added by the bundler
+	Lines [][]int `json:"lines"`
+}
+
+// Add (file, line) maps the next line of the bundle
+// to a line of a file, or to nothing if file is ""
+func (m *SourceMap) Add(file string, line int) {
+	if file == "" {
+		m.Lines = append(m.Lines, []int{})
+		return
+	}
+
+	idx := -1
+	for i, f := range m.Files {
+		if f == file {
+			idx = i
+			break
+		}
+	}
+
+	if idx < 0 {
+		m.Files = append(m.Files, file)
+		idx = len(m.Files) - 1
+	}
+
+	m.Lines = append(m.Lines, []int{idx, line})
+}
+
+// Lookup (line) returns the file, and line, a line
+// of the bundle comes from
+func (m *SourceMap) Lookup(line int) (string, int, bool) {
+	if m == nil || line < 1 || line > len(m.Lines) || len(m.Lines[line-1]) != 2 {
+		return "", 0, false
+	}
+
+	origin := m.Lines[line-1]
+	if origin[0] < 0 || origin[0] >= len(m.Files) {
+		return "", 0, false
+	}
+
+	return m.Files[origin[0]], origin[1], true
+}
+
+// Directive () returns the comment
+// embedding the map in a bundle
+func (m *SourceMap) Directive() string {
+	b, _ := json.Marshal(m)
+
+	return SourceMapDirective + string(b)
+}
+
+// SourceMap () returns the source map embedded
+// in the input, if it's a bundle, or nil
+func (l *Lexer) SourceMap() *SourceMap {
+	if l.sourceMapParsed {
+		return l.sourceMap
+	}
+	l.sourceMapParsed = true
+
+	input := strings.TrimRight(string(l.input), "\n")
+	last := input[strings.LastIndex(input, "\n")+1:]
+
+	if !strings.HasPrefix(last, SourceMapDirective) {
+		return nil
+	}
+
+	m := &SourceMap{}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(last, SourceMapDirective)), m); err != nil {
+		return nil
+	}
+	l.sourceMap = m
+
+	return m
+}
@@ -34,6 +34,81 @@ func main() {
 		return
 	}
 
+	if len(args) == 2 && args[1] == "tour" {
+		repl.BeginTour(Version)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "playground" {
+		repl.BeginPlayground(args, Version)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "ast" {
+		repl.BeginAst(args)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "tokens" {
+		repl.BeginTokens(args)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "cat" {
+		repl.BeginCat(args)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "bundle" {
+		repl.BeginBundle(args)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "deps" {
+		repl.BeginDeps(args)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "transpile" {
+		repl.BeginTranspile(args)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "new" {
+		repl.BeginNew(args)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "daemon" {
+		repl.BeginDaemon(args, Version)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "healthcheck" {
+		repl.BeginHealthcheck(args, Version)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "hook" {
+		repl.BeginHook(args, Version)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "test" {
+		repl.BeginTest(args, Version)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "repl" {
+		repl.BeginInteractive(args, Version)
+		return
+	}
+
+	if len(args) > 1 && args[1] == "run" {
+		repl.BeginRun(args, Version)
+		return
+	}
+
 	// begin the REPL
 	repl.BeginRepl(args, Version)
 }
@@ -1,9 +1,11 @@
 package object
 
 import (
+	"context"
 	"io"
 	"os"
 	"sort"
+	"sync"
 )
 
 // NewEnclosedEnvironment creates an environment
@@ -19,6 +21,8 @@ func NewEnclosedEnvironment(outer *Environment, args []Object) *Environment {
 	)
 	env.outer = outer
 	env.CurrentArgs = args
+	env.Context = outer.Context
+	env.CallDepth = outer.CallDepth
 	return env
 }
 
@@ -57,6 +61,10 @@ func NewEnvironment(stdio *Stdio, dir string, version string, interactive bool)
 // holds all variables etc.
 type Environment struct {
 	store map[string]Object
+	// the REPL can evaluate code in the background
+	// (see jobs) while evaluating more in the foreground,
+	// both reading and writing identifiers
+	mu sync.RWMutex
 	// Arguments this environment was created in.
 	// When we call function(1, 2, 3), a new environment
 	// for the function to execute is created, and 1/2/3
@@ -85,24 +93,48 @@ type Environment struct {
 	Version string
 	// is abs running in interactive mode?
 	Interactive bool
+	// Context the code runs in, nil for none. Unlike
+	// identifiers it follows calls rather than scopes:
+	// functions run in the context of their caller, so
+	// that eg. with_cwd(dir, fn) applies to everything
+	// fn does, and not to code that runs next to it.
+	Context context.Context
+	// How many function calls deep the code runs,
+	// so that runaway recursion can be stopped
+	// before it overflows the stack
+	CallDepth int
 }
 
 // Get returns an identifier stored within the environment
 func (e *Environment) Get(name string) (Object, bool) {
+	e.mu.RLock()
 	obj, ok := e.store[name]
+	e.mu.RUnlock()
 	if !ok && e.outer != nil {
 		obj, ok = e.outer.Get(name)
 	}
 	return obj, ok
 }
 
+// Root returns the outermost environment,
+// the one holding global identifiers
+func (e *Environment) Root() *Environment {
+	if e.outer == nil {
+		return e
+	}
+
+	return e.outer.Root()
+}
+
 // GetKeys returns the list of all identifiers
 // stored in this environment
 func (e *Environment) GetKeys() []string {
+	e.mu.RLock()
 	keys := make([]string, 0, len(e.store))
 	for k := range e.store {
 		keys = append(keys, k)
 	}
+	e.mu.RUnlock()
 
 	sort.Strings(keys)
 
@@ -111,13 +143,17 @@ func (e *Environment) GetKeys() []string {
 
 // Set sets an identifier in the environment
 func (e *Environment) Set(name string, val Object) Object {
+	e.mu.Lock()
 	e.store[name] = val
+	e.mu.Unlock()
 	return val
 }
 
 // Delete deletes an identifier from the environment
 func (e *Environment) Delete(name string) {
+	e.mu.Lock()
 	delete(e.store, name)
+	e.mu.Unlock()
 }
 
 type Stdio struct {
@@ -2,12 +2,15 @@ package object
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
+	"math"
 	"os/exec"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/abs-lang/abs/ast"
 	"github.com/abs-lang/abs/token"
@@ -21,9 +24,12 @@ const (
 	NULL_OBJ  = "NULL"
 	ERROR_OBJ = "ERROR"
 
-	NUMBER_OBJ  = "NUMBER"
-	BOOLEAN_OBJ = "BOOLEAN"
-	STRING_OBJ  = "STRING"
+	NUMBER_OBJ   = "NUMBER"
+	BOOLEAN_OBJ  = "BOOLEAN"
+	STRING_OBJ   = "STRING"
+	BYTES_OBJ    = "BYTES"
+	DURATION_OBJ = "DURATION"
+	SIZE_OBJ     = "SIZE"
 
 	RETURN_VALUE_OBJ = "RETURN_VALUE"
 
@@ -304,6 +310,82 @@ func (s *String) SetCmdResult(Ok *Boolean) {
 	s.Done = TRUE
 }
 
+// Bytes hold raw, binary data (eg. the output
+// of `tar -c` or the content of an image), which
+// would otherwise get mangled when converted
+// to a string.
+type Bytes struct {
+	Token token.Token
+	Value []byte
+}
+
+func (b *Bytes) Type() ObjectType { return BYTES_OBJ }
+func (b *Bytes) Inspect() string  { return string(b.Value) }
+func (b *Bytes) Json() string {
+	return `"` + base64.StdEncoding.EncodeToString(b.Value) + `"`
+}
+
+// Duration is a span of time, created by
+// literals such as 5s or 200ms
+type Duration struct {
+	Token token.Token
+	Value time.Duration
+}
+
+func (d *Duration) Type() ObjectType { return DURATION_OBJ }
+func (d *Duration) Inspect() string {
+	if d.Value == 0 {
+		return "0s"
+	}
+
+	return formatUnit(float64(d.Value), durationUnits, token.DurationUnits)
+}
+
+// Durations are serialized in milliseconds
+func (d *Duration) Json() string {
+	return strconv.FormatFloat(float64(d.Value)/float64(time.Millisecond), 'f', -1, 64)
+}
+
+// Size is an amount of bytes, created by
+// literals such as 10MB or 4KiB
+type Size struct {
+	Token token.Token
+	Value int64
+}
+
+func (s *Size) Type() ObjectType { return SIZE_OBJ }
+func (s *Size) Inspect() string {
+	if s.Value == 0 {
+		return "0KB"
+	}
+
+	return formatUnit(float64(s.Value), sizeUnits, token.SizeUnits)
+}
+
+// Sizes are serialized in bytes
+func (s *Size) Json() string { return strconv.FormatInt(s.Value, 10) }
+
+// Units used to print durations and sizes,
+// from the largest to the smallest
+var durationUnits = []string{"d", "h", "min", "s", "ms", "us", "ns"}
+var sizeUnits = []string{"TiB", "TB", "GiB", "GB", "MiB", "MB", "KiB", "KB"}
+
+// formatUnit prints value with the largest unit that
+// divides it exactly, so that the output can be parsed
+// back into the same value (eg. 90s rather than 1.5min).
+// Values that no unit divides are printed as a fraction
+// of the smallest unit.
+func formatUnit(value float64, units []string, scale map[string]float64) string {
+	for _, unit := range units {
+		if math.Mod(value, scale[unit]) == 0 {
+			return strconv.FormatFloat(value/scale[unit], 'f', -1, 64) + unit
+		}
+	}
+
+	smallest := units[len(units)-1]
+	return strconv.FormatFloat(value/scale[smallest], 'f', -1, 64) + smallest
+}
+
 type Builtin struct {
 	Token    token.Token
 	Fn       BuiltinFunction
@@ -344,6 +426,9 @@ type Array struct {
 	// as opposd to the unpacked arguments.
 	IsCurrentArgs bool
 	position      int
+	// Frozen arrays can't be modified,
+	// see freeze(...)
+	Frozen bool
 }
 
 func (ao *Array) Type() ObjectType { return ARRAY_OBJ }
@@ -408,6 +493,9 @@ type Hash struct {
 	Token    token.Token
 	Pairs    map[HashKey]HashPair
 	Position int
+	// Frozen hashes can't be modified,
+	// see freeze(...)
+	Frozen bool
 }
 
 func (h *Hash) Type() ObjectType { return HASH_OBJ }
@@ -429,7 +517,22 @@ func (h *Hash) GetKeyType(k string) ObjectType {
 	return pair.Value.Type()
 }
 
+// HashStringer, when set, lets hashes customize their
+// string representation (see the __str__ magic method):
+// it returns false for hashes that don't.
+var HashStringer func(h *Hash) (string, bool)
+
 func (h *Hash) Inspect() string {
+	if HashStringer != nil {
+		if s, ok := HashStringer(h); ok {
+			return s
+		}
+	}
+
+	return h.Json()
+}
+
+func (h *Hash) Json() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
@@ -445,7 +548,6 @@ func (h *Hash) Inspect() string {
 
 	return out.String()
 }
-func (h *Hash) Json() string { return h.Inspect() }
 
 // Pretty convoluted logic here we could
 // refactor.
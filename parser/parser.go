@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/abs-lang/abs/ast"
+	"github.com/abs-lang/abs/i18n"
 	"github.com/abs-lang/abs/lexer"
 	"github.com/abs-lang/abs/token"
 )
@@ -58,6 +61,8 @@ var precedences = map[token.TokenType]int{
 	token.COMP_ASTERISK: EQUALS,
 	token.COMP_EXPONENT: EQUALS,
 	token.COMP_MODULO:   EQUALS,
+	token.COMP_OR:       EQUALS,
+	token.COMP_NULLISH:  EQUALS,
 	token.RANGE:         RANGE,
 	token.LPAREN:        CALL,
 	token.LBRACKET:      INDEX,
@@ -83,6 +88,10 @@ type Parser struct {
 	// support assignment to hash property h.a = 1
 	prevPropertyExpression *ast.PropertyExpression
 
+	// comparisons wrapped in parenthesis, which
+	// are not chained: (a < b) < c
+	groupedComparisons map[ast.Expression]bool
+
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
 	// Autocomplete subject is the latest node that
@@ -96,13 +105,16 @@ type Parser struct {
 
 func New(l *lexer.Lexer) *Parser {
 	p := &Parser{
-		l:      l,
-		errors: []string{},
+		l:                  l,
+		errors:             []string{},
+		groupedComparisons: map[ast.Expression]bool{},
 	}
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.NUMBER, p.ParseNumberLiteral)
+	p.registerPrefix(token.DURATION, p.ParseDurationLiteral)
+	p.registerPrefix(token.SIZE, p.ParseSizeLiteral)
 	p.registerPrefix(token.STRING, p.ParseStringLiteral)
 	p.registerPrefix(token.NULL, p.ParseNullLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
@@ -139,6 +151,8 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.COMP_SLASH, p.parseCompoundAssignment)
 	p.registerInfix(token.COMP_EXPONENT, p.parseCompoundAssignment)
 	p.registerInfix(token.COMP_MODULO, p.parseCompoundAssignment)
+	p.registerInfix(token.COMP_OR, p.parseCompoundAssignment)
+	p.registerInfix(token.COMP_NULLISH, p.parseCompoundAssignment)
 	p.registerInfix(token.COMP_ASTERISK, p.parseCompoundAssignment)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
@@ -173,7 +187,7 @@ func (p *Parser) nextToken() {
 	p.peekToken = p.l.NextToken()
 
 	if p.curTokenIs(token.ILLEGAL) {
-		msg := fmt.Sprintf(`Illegal token '%s'`, p.curToken.Literal)
+		msg := i18n.Sprintf("Illegal token '%s'", p.curToken.Literal)
 		p.reportError(msg, p.curToken)
 	}
 }
@@ -207,12 +221,12 @@ func (p *Parser) reportError(err string, tok token.Token) {
 }
 
 func (p *Parser) peekError(tok token.Token) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead", tok.Type, p.peekToken.Type)
+	msg := i18n.Sprintf("expected next token to be %s, got %s instead", tok.Type, p.peekToken.Type)
 	p.reportError(msg, tok)
 }
 
 func (p *Parser) noPrefixParseFnError(tok token.Token) {
-	msg := fmt.Sprintf("no prefix parse function for '%s' found", tok.Literal)
+	msg := i18n.Sprintf("no prefix parse function for '%s' found", tok.Literal)
 	p.reportError(msg, tok)
 }
 
@@ -453,7 +467,7 @@ func (p *Parser) ParseNumberLiteral() ast.Expression {
 
 	value, err := strconv.ParseFloat(number, 64)
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as number", number)
+		msg := i18n.Sprintf("could not parse %q as number", number)
 		p.reportError(msg, p.curToken)
 		return nil
 	}
@@ -467,6 +481,44 @@ func (p *Parser) ParseNumberLiteral() ast.Expression {
 	return lit
 }
 
+// 5s or 1.5h
+func (p *Parser) ParseDurationLiteral() ast.Expression {
+	value, ok := p.parseUnitLiteral(token.DurationUnits)
+	if !ok {
+		return nil
+	}
+
+	return &ast.DurationLiteral{Token: p.curToken, Value: time.Duration(value)}
+}
+
+// 10MB or 4KiB
+func (p *Parser) ParseSizeLiteral() ast.Expression {
+	value, ok := p.parseUnitLiteral(token.SizeUnits)
+	if !ok {
+		return nil
+	}
+
+	return &ast.SizeLiteral{Token: p.curToken, Value: int64(value)}
+}
+
+// parseUnitLiteral splits the current token into its number and
+// unit (eg. 200 and ms) and returns the number scaled by the unit.
+func (p *Parser) parseUnitLiteral(units map[string]float64) (float64, bool) {
+	literal := p.curToken.Literal
+	i := len(literal)
+	for i > 0 && unicode.IsLetter(rune(literal[i-1])) {
+		i--
+	}
+
+	value, err := strconv.ParseFloat(literal[:i], 64)
+	if err != nil {
+		p.reportError(i18n.Sprintf("could not parse %q as number", literal[:i]), p.curToken)
+		return 0, false
+	}
+
+	return value * units[literal[i:]], true
+}
+
 // "some"
 func (p *Parser) ParseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
@@ -500,7 +552,38 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 }
 
 // x * x
+// Comparisons that can be chained, as in 0 <= x < 10
+var chainableComparisons = map[token.TokenType]bool{
+	token.LT:    true,
+	token.LT_EQ: true,
+	token.GT:    true,
+	token.GT_EQ: true,
+}
+
 func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
+	if chainableComparisons[p.curToken.Type] && !p.groupedComparisons[left] {
+		switch l := left.(type) {
+		// a < b < c < d
+		case *ast.ComparisonChain:
+			l.Operators = append(l.Operators, p.curToken.Literal)
+			p.nextToken()
+			l.Operands = append(l.Operands, p.parseExpression(LESSGREATER))
+			return l
+		// a < b < c
+		case *ast.InfixExpression:
+			if chainableComparisons[l.Token.Type] {
+				chain := &ast.ComparisonChain{
+					Token:     l.Token,
+					Operands:  []ast.Expression{l.Left, l.Right},
+					Operators: []string{l.Operator, p.curToken.Literal},
+				}
+				p.nextToken()
+				chain.Operands = append(chain.Operands, p.parseExpression(LESSGREATER))
+				return chain
+			}
+		}
+	}
+
 	expression := &ast.InfixExpression{
 		Token:    p.curToken,
 		Operator: p.curToken.Literal,
@@ -604,6 +687,8 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
 		return nil
 	}
 
+	p.groupedComparisons[exp] = true
+
 	return exp
 }
 
@@ -806,7 +891,7 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 //	  return 1
 //	}
 func (p *Parser) parseFunctionLiteral() ast.Expression {
-	lit := &ast.FunctionLiteral{Token: p.curToken}
+	lit := &ast.FunctionLiteral{Token: p.curToken, Doc: p.l.CommentAbove(p.curToken.Position)}
 
 	if p.peekTokenIs(token.IDENT) {
 		p.nextToken()
@@ -846,14 +931,14 @@ func (p *Parser) parseDecorator() ast.Expression {
 		switch fn := exp.Expression.(type) {
 		case *ast.FunctionLiteral:
 			if fn.Name == "" {
-				p.reportError("a decorator should decorate a named function", dc.Token)
+				p.reportError(i18n.T("a decorator should decorate a named function"), dc.Token)
 			}
 
 			dc.Decorated = fn
 		case *ast.Decorator:
 			dc.Decorated = fn
 		default:
-			p.reportError("a decorator should decorate a named function", dc.Token)
+			p.reportError(i18n.T("a decorator should decorate a named function"), dc.Token)
 		}
 	})()
 
@@ -872,7 +957,7 @@ func (p *Parser) parseDefer() ast.Expression {
 	if d, ok := exp.(ast.Deferrable); ok {
 		d.SetDeferred(true)
 	} else {
-		p.reportError("you can only defer a call: defer some.method() | defer `some command` | defer some_fn()", p.curToken)
+		p.reportError(i18n.T("you can only defer a call: defer some.method() | defer `some command` | defer some_fn()"), p.curToken)
 	}
 
 	return exp
@@ -904,7 +989,7 @@ func (p *Parser) parseFunctionParameters() []*ast.Parameter {
 		param, optional := p.parseFunctionParameter()
 
 		if foundOptionalParameter && !optional {
-			p.reportError("found mandatory parameter after optional one", p.curToken)
+			p.reportError(i18n.T("found mandatory parameter after optional one"), p.curToken)
 		}
 
 		if optional {
@@ -939,7 +1024,7 @@ func (p *Parser) parseFunctionParameter() (param *ast.Parameter, optional bool)
 	// if the next token is not an assignment, though, there's
 	// a major problem
 	if !p.peekTokenIs(token.ASSIGN) {
-		p.reportError("invalid parameter format", p.curToken)
+		p.reportError(i18n.T("invalid parameter format"), p.curToken)
 		return &ast.Parameter{Identifier: ident, Default: nil}, false
 	}
 
@@ -1003,8 +1088,9 @@ func (p *Parser) ParseArrayLiteral() ast.Expression {
 // some["thing"] or some[1:10]
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	noStart := p.peekTokenIs(token.COLON)
 
-	if p.peekTokenIs(token.COLON) {
+	if noStart {
 		exp.Index = &ast.NumberLiteral{Value: 0, Token: token.Token{Type: token.NUMBER, Position: 0, Literal: "0"}}
 		exp.IsRange = true
 	} else {
@@ -1016,12 +1102,25 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 		exp.IsRange = true
 		p.nextToken()
 
-		if p.peekTokenIs(token.RBRACKET) {
+		if p.peekTokenIs(token.RBRACKET) || p.peekTokenIs(token.COLON) {
 			exp.End = nil
 		} else {
 			p.nextToken()
 			exp.End = p.parseExpression(LOWEST)
 		}
+
+		// x[start:end:step]
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			p.nextToken()
+			exp.Step = p.parseExpression(LOWEST)
+
+			// The default start of a stepped range
+			// depends on the step's direction
+			if noStart {
+				exp.Index = nil
+			}
+		}
 	}
 
 	if !p.expectPeek(token.RBRACKET) {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/abs-lang/abs/ast"
 	"github.com/abs-lang/abs/lexer"
@@ -164,6 +165,43 @@ func TestNumberLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestUnitLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		value interface{}
+	}{
+		{"5s", 5 * time.Second},
+		{"200ms", 200 * time.Millisecond},
+		{"1.5h", 90 * time.Minute},
+		{"2min", 2 * time.Minute},
+		{"1_000us", time.Millisecond},
+		{"10MB", int64(10000000)},
+		{"4KiB", int64(4096)},
+		{"1.5GB", int64(1500000000)},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		switch literal := stmt.Expression.(type) {
+		case *ast.DurationLiteral:
+			if literal.Value != tt.value {
+				t.Errorf("%s: literal.Value not %v. got=%v", tt.input, tt.value, literal.Value)
+			}
+		case *ast.SizeLiteral:
+			if literal.Value != tt.value {
+				t.Errorf("%s: literal.Value not %v. got=%v", tt.input, tt.value, literal.Value)
+			}
+		default:
+			t.Errorf("%s: exp not a unit literal. got=%T", tt.input, stmt.Expression)
+		}
+	}
+}
+
 func TestParsingPrefixExpressions(t *testing.T) {
 	prefixTests := []struct {
 		input    string
@@ -284,6 +322,30 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"!-a",
 			"(!(-a))",
 		},
+		{
+			"a[::-1]",
+			"(a[::(-1)])",
+		},
+		{
+			"a[1:5:2]",
+			"(a[1:5:2])",
+		},
+		{
+			"0 <= x < 10",
+			"(0 <= x < 10)",
+		},
+		{
+			"a < b + 1 <= c > d",
+			"(a < (b + 1) <= c > d)",
+		},
+		{
+			"(a < b) < c",
+			"((a < b) < c)",
+		},
+		{
+			"a < b == c < d",
+			"((a < b) == (c < d))",
+		},
 		{
 			"a + b + c",
 			"((a + b) + c)",
@@ -1037,6 +1099,41 @@ func TestFunctionLiteralParsing(t *testing.T) {
 	testInfixExpression(t, secondExpr.Expression, "x", "+", "y")
 }
 
+func TestFunctionLiteralDoc(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectedDoc string
+	}{
+		{"f add(a, b) { a + b }", ""},
+		{"# Adds 2 numbers\nf add(a, b) { a + b }", "Adds 2 numbers"},
+		{"# Adds 2\n  # numbers\nf add(a, b) { a + b }", "Adds 2 numbers"},
+		{"# Unrelated\n\nf add(a, b) { a + b }", ""},
+		{"x = 1\n# Adds 2 numbers\nadd = f(a, b) { a + b }", "Adds 2 numbers"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		var function *ast.FunctionLiteral
+
+		switch stmt := program.Statements[len(program.Statements)-1].(type) {
+		case *ast.ExpressionStatement:
+			function = stmt.Expression.(*ast.FunctionLiteral)
+		case *ast.AssignStatement:
+			function = stmt.Value.(*ast.FunctionLiteral)
+		default:
+			t.Fatalf("unexpected statement %T", stmt)
+		}
+
+		if function.Doc != tt.expectedDoc {
+			t.Fatalf("wrong doc for %q. expected=%q, got=%q", tt.input, tt.expectedDoc, function.Doc)
+		}
+	}
+}
+
 func TestCommandParsing(t *testing.T) {
 	input := `$(curl icanhazip.com -X POST)`
 
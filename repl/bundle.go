@@ -0,0 +1,211 @@
+package repl
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abs-lang/abs/evaluator"
+	"github.com/abs-lang/abs/lexer"
+)
+
+const bundleUsage = "usage: abs bundle [--strip-comments] [--no-source-map] entry.abs [-o out.abs]"
+
+// BeginBundle (args) -- inlines the imports of a script through "abs bundle [--strip-comments] [--no-source-map] entry.abs [-o out.abs]"
+//
+// Every file the entry script requires or sources
+// (transitively) is inlined in a single script, so
+// that it can be shipped on its own. Stdlib modules
+// (eg. @runtime) ship with ABS, so they aren't.
+//
+// Unless --no-source-map is given, the bundle embeds a
+// source map, so that errors point to the original files.
+func BeginBundle(args []string) {
+	entry, out, strip, sourceMap := "", "", false, true
+
+	for i := 2; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--strip-comments":
+			strip = true
+		case arg == "--no-source-map":
+			sourceMap = false
+		case arg == "-o" || arg == "--output":
+			if i+1 >= len(args) {
+				exitWithMessage(fmt.Sprintf("missing value for option %s\n%s", arg, bundleUsage))
+			}
+			out = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "-"):
+			exitWithMessage(fmt.Sprintf("unknown option %s\n%s", arg, bundleUsage))
+		case entry == "":
+			entry = arg
+		default:
+			exitWithMessage(fmt.Sprintf("unexpected argument %s\n%s", arg, bundleUsage))
+		}
+	}
+
+	if entry == "" {
+		exitWithMessage("no script given\n" + bundleUsage)
+	}
+
+	g := loadDeps(entry)
+
+	for _, w := range g.Warnings {
+		fmt.Fprintln(os.Stderr, "warning: "+w)
+	}
+
+	if len(g.Problems) > 0 {
+		for _, p := range g.Problems {
+			fmt.Fprintln(os.Stderr, "error: "+p)
+		}
+		os.Exit(1)
+	}
+
+	bundle := g.bundle(strip, sourceMap)
+
+	if out == "" {
+		fmt.Print(bundle)
+		return
+	}
+
+	// Bundles are meant to be run, so they're
+	// executable (as long as they have a shebang)
+	if err := os.WriteFile(out, []byte(bundle), 0755); err != nil {
+		exitWithMessage(err.Error())
+	}
+}
+
+// Lays out the bundle: each inlined file is registered
+// through bundle_module(...), then the entry script runs.
+// The source map, unless disabled, maps every line of
+// the bundle to the file (and line) it comes from.
+func (g *depsGraph) bundle(strip bool, sourceMap bool) string {
+	lines := []string{}
+	m := &lexer.SourceMap{Version: 1, Files: []string{}, Lines: [][]int{}}
+	add := func(line string, file string, orig int) {
+		lines = append(lines, line)
+		m.Add(file, orig)
+	}
+
+	root := g.byPath[g.Root]
+	code := root.code
+	offset := 0
+
+	// The shebang, if any, has to stay on top
+	if strings.HasPrefix(code, "#!") {
+		shebang, rest, _ := strings.Cut(code, "\n")
+		add(shebang, root.Path, 1)
+		code, offset = rest, 1
+	}
+
+	add(fmt.Sprintf("# Bundled from %s by abs bundle: edit the original files rather than this one", g.Root), "", 0)
+
+	for _, f := range g.Files {
+		if f == root || strings.HasPrefix(f.Path, "@") {
+			continue
+		}
+
+		add(fmt.Sprintf("bundle_module(\"%s%s\", f() {", evaluator.BundlePrefix, f.Path), "", 0)
+		inlined, origins := g.rewrite(f, f.code, strip)
+		for i, line := range inlined {
+			add(line, f.Path, origins[i])
+		}
+		add("})", "", 0)
+	}
+
+	entry, origins := g.rewrite(root, code, strip)
+	for i, line := range entry {
+		add(line, root.Path, origins[i]+offset)
+	}
+
+	if sourceMap {
+		lines = append(lines, m.Directive())
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Points the imports of a file to the files inlined in the
+// bundle and, optionally, strips its comments. Code is only
+// changed in between tokens, so that strings and commands
+// holding '#' are left alone. Returns the lines of code,
+// along with the lines they were on in the file.
+func (g *depsGraph) rewrite(f *depsFile, code string, strip bool) ([]string, []int) {
+	// The shebang might have been cut from the code,
+	// shifting the positions (in runes) of tokens
+	runes := []rune(code)
+	offset := len([]rune(f.code)) - len(runes)
+	paths := map[int]string{}
+
+	for _, imp := range f.Imports {
+		if !strings.HasPrefix(imp.Path, "@") {
+			paths[imp.pos-offset] = fmt.Sprintf("\"%s%s\"", evaluator.BundlePrefix, imp.Path)
+		}
+	}
+
+	var b strings.Builder
+	l := lexer.New(code)
+	end := 0
+
+	gap := func(s string) {
+		if strip {
+			s = stripComments(s)
+		}
+		b.WriteString(s)
+	}
+
+	for tok := l.NextToken(); tok.Type != "EOF"; tok = l.NextToken() {
+		start := max(tok.Position, end)
+		gap(string(runes[end:start]))
+		end = max(l.CurrentPosition(), start)
+
+		if path, ok := paths[tok.Position]; ok {
+			b.WriteString(path)
+			continue
+		}
+
+		b.WriteString(string(runes[start:min(end, len(runes))]))
+	}
+
+	gap(string(runes[min(end, len(runes)):]))
+
+	// Stripping comments never adds or removes
+	// line breaks, so lines are still in place
+	lines, origins := []string{}, []int{}
+	for i, line := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+		if strip {
+			// Drop the lines that held nothing but comments
+			if line = strings.TrimRight(line, " \t\r"); line == "" {
+				continue
+			}
+		}
+
+		lines = append(lines, line)
+		origins = append(origins, i+1)
+	}
+
+	return lines, origins
+}
+
+// Strips the comments (# or //) found in
+// between tokens, up to the end of the line
+func stripComments(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '#' || (s[i] == '/' && i+1 < len(s) && s[i+1] == '/') {
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+
+			if i < len(s) {
+				b.WriteByte('\n')
+			}
+			continue
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
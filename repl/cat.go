@@ -0,0 +1,55 @@
+package repl
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/abs-lang/abs/terminal"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const catUsage = "usage: abs cat [--no-numbers] script.abs..."
+
+// BeginCat (args) -- prints scripts with syntax highlighting through "abs cat [--no-numbers] script.abs..."
+//
+// Handy to review scripts over SSH: highlighting is
+// dropped when the output is piped (or NO_COLOR is set).
+func BeginCat(args []string) {
+	files, numbers := []string{}, true
+
+	for _, arg := range args[2:] {
+		switch {
+		case arg == "--no-numbers":
+			numbers = false
+		case len(arg) > 1 && arg[0] == '-':
+			exitWithMessage(fmt.Sprintf("unknown option %s\n%s", arg, catUsage))
+		default:
+			files = append(files, arg)
+		}
+	}
+
+	if len(files) == 0 {
+		exitWithMessage("no script given\n" + catUsage)
+	}
+
+	faint := lipgloss.NewStyle().Faint(true)
+
+	for i, file := range files {
+		if len(files) > 1 {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(faint.Render("==> " + file + " <=="))
+		}
+
+		lines := terminal.Highlight(readDumpSource(file))
+		width := len(strconv.Itoa(len(lines)))
+
+		for n, line := range lines {
+			if numbers {
+				line = faint.Render(fmt.Sprintf("%*d │ ", width, n+1)) + line
+			}
+			fmt.Println(line)
+		}
+	}
+}
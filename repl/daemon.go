@@ -0,0 +1,555 @@
+package repl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode"
+
+	"github.com/abs-lang/abs/evaluator"
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/runner"
+	"github.com/abs-lang/abs/token"
+)
+
+const daemonUsage = "usage: abs daemon [--pidfile file] [--log-file file] [--log-max-size 10MB] [--log-keep 5] [--reload] [--reload-grace 30s] [--foreground] script.abs [args...]"
+
+// How often "abs daemon --reload" checks whether the script changed
+const DAEMON_RELOAD_INTERVAL = time.Second
+
+// Set in the environment of the process
+// "abs daemon" detaches, which runs the script
+const daemonChildEnv = "ABS_DAEMON_CHILD"
+
+// Options of "abs daemon"
+type daemonOptions struct {
+	pidfile string
+	logFile string
+	// size after which the log is rotated (0
+	// to never rotate it) and how many of the
+	// rotated logs we keep
+	logMaxSize int64
+	logKeep    int
+	foreground bool
+	// whether the script is reloaded once it
+	// (or its imports) change, and how long we
+	// wait for it to be done with what it's doing
+	reload      bool
+	reloadGrace time.Duration
+}
+
+// BeginDaemon (args, version) -- runs a script in the background through "abs daemon [options] script.abs [args]"
+//
+// Options:
+//
+//	--pidfile file        writes the PID of the daemon to the file, refusing to start if it's running already
+//	--log-file file       writes the output of the script to the file (default: discarded)
+//	--log-max-size size   rotates the log once it reaches the size (default: 10MB, 0 to never rotate it)
+//	--log-keep n          how many rotated logs to keep, as file.1, file.2... (default: 5)
+//	--reload              reloads the script once it, or its imports, change
+//	--reload-grace d      how long a reload waits for the script to be done with what it's doing (default: 30s)
+//	--foreground          doesn't detach, eg. under systemd or in containers
+//
+// SIGTERM (or ctrl+c, in the foreground) stops the script gracefully:
+// it's interrupted, along with the commands it's running, and the
+// daemon exits with 0. SIGHUP re-opens the log file, for logrotate.
+func BeginDaemon(args []string, version string) {
+	opts, script := parseDaemonArgs(args)
+
+	if opts.pidfile != "" {
+		if pid, ok := runningPid(opts.pidfile); ok {
+			exitWithMessage(fmt.Sprintf("%s is already running (pid %d, from %s)", script[0], pid, opts.pidfile))
+		}
+	}
+
+	if opts.foreground || os.Getenv(daemonChildEnv) == "1" {
+		os.Unsetenv(daemonChildEnv)
+		os.Exit(runDaemon(opts, script, version))
+	}
+
+	pid, err := detachDaemon(opts)
+	if err != nil {
+		exitWithMessage(fmt.Sprintf("unable to start the daemon: %s", err.Error()))
+	}
+
+	fmt.Printf("started %s (pid %d)\n", script[0], pid)
+}
+
+func parseDaemonArgs(args []string) (daemonOptions, []string) {
+	opts := daemonOptions{logMaxSize: 10e6, logKeep: 5, reloadGrace: 30 * time.Second}
+	i := 2
+
+	for ; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			i++
+			break
+		}
+
+		if len(arg) == 0 || arg[0] != '-' {
+			break
+		}
+
+		if arg == "--foreground" {
+			opts.foreground = true
+			continue
+		}
+
+		if arg == "--reload" {
+			opts.reload = true
+			continue
+		}
+
+		if i+1 >= len(args) {
+			exitWithMessage(fmt.Sprintf("missing value for option %s\n%s", arg, daemonUsage))
+		}
+
+		value := args[i+1]
+		i++
+
+		switch arg {
+		case "--pidfile":
+			opts.pidfile = absPath(value)
+		case "--log-file":
+			opts.logFile = absPath(value)
+		case "--log-max-size":
+			size, ok := parseSize(value)
+			if !ok {
+				exitWithMessage(fmt.Sprintf("invalid size '%s' (eg. 10MB or 512KiB)\n%s", value, daemonUsage))
+			}
+			opts.logMaxSize = size
+		case "--log-keep":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				exitWithMessage(fmt.Sprintf("invalid number of logs '%s'\n%s", value, daemonUsage))
+			}
+			opts.logKeep = n
+		case "--reload-grace":
+			grace, err := time.ParseDuration(value)
+			if err != nil || grace < 0 {
+				exitWithMessage(fmt.Sprintf("invalid grace period '%s' (eg. 30s)\n%s", value, daemonUsage))
+			}
+			opts.reloadGrace = grace
+		default:
+			exitWithMessage(fmt.Sprintf("unknown option %s\n%s", arg, daemonUsage))
+		}
+	}
+
+	if i >= len(args) {
+		exitWithMessage("no script to run\n" + daemonUsage)
+	}
+
+	return opts, args[i:]
+}
+
+// Re-runs "abs daemon" in a new session, detached from
+// the terminal, returning the PID of the new process
+func detachDaemon(opts daemonOptions) (int, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+
+	devnull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer devnull.Close()
+
+	// Until the script starts writing the log itself,
+	// whatever the process prints (eg. a panic) should
+	// make it there as well
+	out := devnull
+	if opts.logFile != "" {
+		out, err = os.OpenFile(opts.logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return 0, err
+		}
+		defer out.Close()
+	}
+
+	cmd := exec.Command(self, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), daemonChildEnv+"=1")
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = devnull, out, out
+	detach(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+
+	pid := cmd.Process.Pid
+	return pid, cmd.Process.Release()
+}
+
+// Runs the script until it's done, or until we're asked
+// to stop, returning the exit code of the daemon
+func runDaemon(opts daemonOptions, args []string, version string) int {
+	var log io.ReadWriter = os.Stdout
+	var rotating *rotatingLog
+
+	if opts.logFile != "" {
+		var err error
+		rotating, err = openRotatingLog(opts.logFile, opts.logMaxSize, opts.logKeep)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to open the log: %s\n", err.Error())
+			return 99
+		}
+		defer rotating.Close()
+		log = rotating
+	}
+
+	logf := func(format string, a ...any) {
+		fmt.Fprintf(log, "%s abs daemon: %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, a...))
+	}
+
+	if opts.pidfile != "" {
+		if err := os.WriteFile(opts.pidfile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+			logf("unable to write the pidfile: %s", err.Error())
+			return 99
+		}
+		defer removePidfile(opts.pidfile)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGHUP, os.Interrupt)
+	defer signal.Stop(signals)
+
+	go func() {
+		for sig := range signals {
+			if sig == syscall.SIGHUP {
+				if rotating != nil {
+					if err := rotating.Reopen(); err != nil {
+						fmt.Fprintf(os.Stderr, "unable to re-open the log: %s\n", err.Error())
+					}
+				}
+				continue
+			}
+
+			if ctx.Err() != nil {
+				continue
+			}
+
+			name := "SIGTERM"
+			if sig == os.Interrupt {
+				name = "SIGINT"
+			}
+
+			logf("received %s, stopping", name)
+			cancel()
+		}
+	}()
+
+	// Scripts see the same arguments they would
+	// see with "abs script.abs ..."
+	os.Args = append([]string{os.Args[0]}, args...)
+	script := args[0]
+
+	stdio := &object.Stdio{Stdin: os.Stdin, Stdout: log, Stderr: log}
+	run := &daemonRun{}
+
+	logf("started %s (pid %d)", script, os.Getpid())
+
+	if opts.reload {
+		go watchDaemon(ctx, script, run, opts.reloadGrace, logf)
+	}
+
+	// Every reload runs the new code in a
+	// brand new environment
+	for {
+		env := object.NewEnvironment(stdio, filepath.Dir(script), version, false)
+		getAbsInitFile(env)
+
+		code, err := os.ReadFile(script)
+		if err != nil {
+			logf("%s", err.Error())
+			return 99
+		}
+
+		out, ok, parseErrors := runner.RunWithOptions(string(code), env, runner.Options{Context: run.start(ctx)})
+		reloading := run.stop()
+
+		switch {
+		case len(parseErrors) != 0:
+			printParserErrors(parseErrors, env)
+			return 99
+		case ctx.Err() != nil:
+			logf("stopped %s", script)
+			return 0
+		case reloading:
+			evaluator.ResetRequireCache()
+			logf("reloaded %s", script)
+			continue
+		case !ok:
+			fmt.Fprintln(log, out.Inspect())
+			logf("%s failed", script)
+			return 99
+		}
+
+		logf("%s is done", script)
+		return 0
+	}
+}
+
+// The run of the script going on, if any,
+// which a reload drains
+type daemonRun struct {
+	mu        sync.Mutex
+	cancel    context.CancelFunc
+	reloading bool
+}
+
+func (r *daemonRun) start(ctx context.Context) context.Context {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	evaluator.ResetInterrupt()
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.reloading = false
+
+	return ctx
+}
+
+// Ends the run, telling whether it
+// ended because of a reload
+func (r *daemonRun) stop() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cancel()
+	r.cancel = nil
+
+	return r.reloading
+}
+
+// Drains the run: the script stops at its next sleep(...),
+// once it's done with the work at hand, or once the grace
+// period is over, whichever comes first
+func (r *daemonRun) reload(grace time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel == nil || r.reloading {
+		return
+	}
+
+	r.reloading = true
+	evaluator.Drain()
+	time.AfterFunc(grace, r.cancel)
+}
+
+// Watches the script and its imports, reloading the
+// script once they change -- unless the new code has
+// problems (eg. syntax errors), which we log instead
+func watchDaemon(ctx context.Context, script string, run *daemonRun, grace time.Duration, logf func(string, ...any)) {
+	mtimes := depsMtimes(loadDeps(script))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(DAEMON_RELOAD_INTERVAL):
+		}
+
+		changed := false
+		for path, mtime := range mtimes {
+			if info, err := os.Stat(path); err != nil || !info.ModTime().Equal(mtime) {
+				changed = true
+			}
+		}
+
+		if !changed {
+			continue
+		}
+
+		// imports might have changed as well
+		g := loadDeps(script)
+		mtimes = depsMtimes(g)
+
+		if len(g.Problems) > 0 {
+			logf("not reloading %s:\n%s", script, strings.Join(g.Problems, "\n"))
+			continue
+		}
+
+		logf("%s changed, reloading", script)
+		run.reload(grace)
+	}
+}
+
+// Modification times of the files in the import graph,
+// but stdlib modules, which can't change
+func depsMtimes(g *depsGraph) map[string]time.Time {
+	mtimes := map[string]time.Time{}
+
+	for _, f := range g.Files {
+		if strings.HasPrefix(f.Path, "@") {
+			continue
+		}
+
+		mtime := time.Time{}
+		if info, err := os.Stat(f.Path); err == nil {
+			mtime = info.ModTime()
+		}
+
+		mtimes[f.Path] = mtime
+	}
+
+	return mtimes
+}
+
+// The PID in the pidfile, if that process is running
+func runningPid(pidfile string) (int, bool) {
+	b, err := os.ReadFile(pidfile)
+	if err != nil {
+		return 0, false
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil || pid == os.Getpid() {
+		return 0, false
+	}
+
+	return pid, processRunning(pid)
+}
+
+// Removes the pidfile, unless another
+// daemon took it over in the meantime
+func removePidfile(pidfile string) {
+	b, err := os.ReadFile(pidfile)
+	if err == nil && strings.TrimSpace(string(b)) == strconv.Itoa(os.Getpid()) {
+		os.Remove(pidfile)
+	}
+}
+
+// Paths are resolved before detaching,
+// so that they don't depend on where the
+// daemon runs from
+func absPath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+
+	return p
+}
+
+// 10MB, 512KiB or a number of bytes
+func parseSize(s string) (int64, bool) {
+	i := len(s)
+	for i > 0 && unicode.IsLetter(rune(s[i-1])) {
+		i--
+	}
+
+	value, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+
+	if s[i:] == "" || s[i:] == "B" {
+		return int64(value), true
+	}
+
+	unit, ok := token.SizeUnits[s[i:]]
+	return int64(value * unit), ok
+}
+
+// A log file that's rotated once it reaches a size: file
+// becomes file.1, file.1 becomes file.2 and so on, up to
+// the number of rotated logs we keep
+type rotatingLog struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	keep    int
+	f       *os.File
+	size    int64
+}
+
+func openRotatingLog(path string, maxSize int64, keep int) (*rotatingLog, error) {
+	l := &rotatingLog{path: path, maxSize: maxSize, keep: keep}
+	return l, l.open()
+}
+
+func (l *rotatingLog) open() error {
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	l.f, l.size = f, info.Size()
+	return nil
+}
+
+func (l *rotatingLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(p)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := l.f.Write(p)
+	l.size += int64(n)
+
+	return n, err
+}
+
+// Nothing to read from a log
+func (l *rotatingLog) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (l *rotatingLog) rotate() error {
+	l.f.Close()
+
+	if l.keep == 0 {
+		os.Remove(l.path)
+		return l.open()
+	}
+
+	for i := l.keep - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+	}
+
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return err
+	}
+
+	return l.open()
+}
+
+// Re-opens the log, once it's been moved
+// by someone else (eg. logrotate)
+func (l *rotatingLog) Reopen() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.f.Close()
+	return l.open()
+}
+
+func (l *rotatingLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.f.Close()
+}
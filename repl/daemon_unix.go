@@ -0,0 +1,19 @@
+//go:build !windows
+
+package repl
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// The daemon gets its own session, so that it
+// doesn't go away along with the terminal
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+func processRunning(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
@@ -0,0 +1,317 @@
+package repl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/abs-lang/abs/ast"
+	"github.com/abs-lang/abs/evaluator"
+	"github.com/abs-lang/abs/lexer"
+	"github.com/abs-lang/abs/parser"
+	"github.com/abs-lang/abs/util"
+)
+
+// A script (or stdlib module) in the import
+// graph, as emitted by "abs deps --format json"
+type depsFile struct {
+	Path    string       `json:"path"`
+	Imports []depsImport `json:"imports"`
+	Missing bool         `json:"missing"`
+
+	// source code, see "abs bundle"
+	code string
+}
+
+// An import, ie. a require(...) or
+// source(...) with a literal path
+type depsImport struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	Line int    `json:"line"`
+
+	// where the path is, in the importing file
+	pos int
+}
+
+// The transitive import graph of a script
+type depsGraph struct {
+	Root  string      `json:"root"`
+	Files []*depsFile `json:"files"`
+	// Import cycles, eg. [a.abs, b.abs, a.abs]
+	Cycles [][]string `json:"cycles"`
+	// Missing files, cycles and parser errors
+	Problems []string `json:"problems"`
+	// Imports that can't be followed, eg.
+	// require(path) with a variable path
+	Warnings []string `json:"warnings"`
+
+	byPath  map[string]*depsFile
+	aliases map[string]string
+}
+
+// BeginDeps (args) -- prints the import graph of a script through "abs deps [--format text|json|dot] script.abs"
+//
+// Exits with 1 when imports are missing, cyclic
+// or can't be parsed, so that it can run in CI.
+func BeginDeps(args []string) {
+	file, format := dumpArgs(args, "deps")
+	if file == "-" {
+		exitWithMessage("abs deps requires a script, not stdin")
+	}
+
+	g := loadDeps(file)
+
+	switch format {
+	case "json":
+		printJson(g)
+	case "dot":
+		fmt.Print(g.dot())
+	default:
+		fmt.Print(g.text())
+	}
+
+	for _, w := range g.Warnings {
+		fmt.Fprintln(os.Stderr, "warning: "+w)
+	}
+
+	for _, p := range g.Problems {
+		fmt.Fprintln(os.Stderr, "error: "+p)
+	}
+
+	if len(g.Problems) > 0 {
+		os.Exit(1)
+	}
+}
+
+func loadDeps(root string) *depsGraph {
+	g := &depsGraph{
+		Root:     displayPath(root),
+		Files:    []*depsFile{},
+		Cycles:   [][]string{},
+		Problems: []string{},
+		Warnings: []string{},
+		byPath:   map[string]*depsFile{},
+		aliases:  map[string]string{},
+	}
+
+	// Aliases are resolved the same way require(...)
+	// does, from the current directory
+	if a, err := os.ReadFile("./packages.abs.json"); err == nil {
+		json.Unmarshal(a, &g.aliases)
+	}
+
+	g.visit(root, []string{})
+
+	if g.byPath[g.Root].Missing {
+		g.Problems = append(g.Problems, fmt.Sprintf("cannot read %s", root))
+	}
+
+	return g
+}
+
+// Visits a file and, depth-first, its imports:
+// stack holds the files being visited, so that
+// an import of any of them is a cycle
+func (g *depsGraph) visit(path string, stack []string) {
+	name := displayPath(path)
+	stack = append(stack, name)
+
+	f := &depsFile{Path: name, Imports: []depsImport{}}
+	g.Files = append(g.Files, f)
+	g.byPath[name] = f
+
+	code, err := readImport(path)
+	if err != nil {
+		f.Missing = true
+		return
+	}
+
+	f.code = string(code)
+	l := lexer.New(f.code)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		g.Problems = append(g.Problems, fmt.Sprintf("%s: parser errors:\n\t%s", name, strings.Join(p.Errors(), "\n\t")))
+		return
+	}
+
+	targets := []string{}
+	ast.Walk(program, func(node ast.Node) {
+		call, ok := node.(*ast.CallExpression)
+		if !ok {
+			return
+		}
+
+		fn, ok := call.Function.(*ast.Identifier)
+		if !ok || (fn.Value != "require" && fn.Value != "source") || len(call.Arguments) == 0 {
+			return
+		}
+
+		line, _, _ := l.ErrorLine(call.Token.Position)
+		arg, ok := call.Arguments[0].(*ast.StringLiteral)
+		if !ok {
+			g.Warnings = append(g.Warnings, fmt.Sprintf("%s:%d: %s(%s) can't be followed, as its path isn't a string literal", name, line, fn.Value, call.Arguments[0].String()))
+			return
+		}
+
+		target := g.resolve(fn.Value, arg.Value, path)
+		f.Imports = append(f.Imports, depsImport{displayPath(target), fn.Value, line, arg.Token.Position})
+		targets = append(targets, target)
+	})
+
+	for i, target := range targets {
+		imp := f.Imports[i]
+
+		if at := indexOf(stack, imp.Path); at >= 0 {
+			cycle := append(append([]string{}, stack[at:]...), imp.Path)
+			g.Cycles = append(g.Cycles, cycle)
+			g.Problems = append(g.Problems, fmt.Sprintf("%s:%d: import cycle %s", name, imp.Line, strings.Join(cycle, " -> ")))
+			continue
+		}
+
+		if _, ok := g.byPath[imp.Path]; !ok {
+			g.visit(target, stack)
+		}
+
+		if g.byPath[imp.Path].Missing {
+			g.Problems = append(g.Problems, fmt.Sprintf("%s:%d: missing import %s", name, imp.Line, imp.Path))
+		}
+	}
+}
+
+// Resolves an import the way the evaluator does: required
+// files are relative to the importing script (and can be
+// aliased in packages.abs.json), sourced ones to the
+// current directory
+func (g *depsGraph) resolve(kind string, arg string, importer string) string {
+	if kind == "source" {
+		path, _ := util.ExpandPath(arg)
+		return path
+	}
+
+	path := util.UnaliasPath(arg, g.aliases)
+	if strings.HasPrefix(path, "@") {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(importer), path)
+}
+
+func readImport(path string) ([]byte, error) {
+	if strings.HasPrefix(path, "@") {
+		return evaluator.Asset("stdlib/" + path[1:])
+	}
+
+	return os.ReadFile(path)
+}
+
+// Paths are shown relative to the current directory,
+// stdlib modules by their name (eg. @runtime)
+func displayPath(path string) string {
+	if strings.HasPrefix(path, "@") {
+		return strings.TrimSuffix(path, "/index.abs")
+	}
+
+	path = filepath.Clean(path)
+	if abs, err := filepath.Abs(path); err == nil {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				return rel
+			}
+		}
+	}
+
+	return path
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Renders the graph as an indented tree, eg.
+//
+//	main.abs
+//	  lib/util.abs
+//	    @runtime
+//	  lib/missing.abs (missing)
+//
+// files already shown are marked rather than repeated
+func (g *depsGraph) text() string {
+	lines := []string{}
+	shown := map[string]bool{}
+
+	var write func(path string, depth int, stack []string)
+	write = func(path string, depth int, stack []string) {
+		f := g.byPath[path]
+		line := strings.Repeat("  ", depth) + path
+
+		switch {
+		case indexOf(stack, path) >= 0:
+			lines = append(lines, line+" (cycle)")
+			return
+		case f == nil:
+			lines = append(lines, line)
+			return
+		case f.Missing:
+			lines = append(lines, line+" (missing)")
+			return
+		case shown[path] && len(f.Imports) > 0:
+			lines = append(lines, line+" (see above)")
+			return
+		}
+
+		lines = append(lines, line)
+		shown[path] = true
+
+		for _, imp := range f.Imports {
+			write(imp.Path, depth+1, append(stack, path))
+		}
+	}
+
+	write(g.Root, 0, []string{})
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Renders the graph in Graphviz's dot format:
+// missing files are dashed, cycles red
+func (g *depsGraph) dot() string {
+	inCycle := map[[2]string]bool{}
+	for _, c := range g.Cycles {
+		for i := 0; i < len(c)-1; i++ {
+			inCycle[[2]string{c[i], c[i+1]}] = true
+		}
+	}
+
+	lines := []string{"digraph deps {"}
+	for _, f := range g.Files {
+		if f.Missing {
+			lines = append(lines, fmt.Sprintf("  %q [style=dashed, color=red];", f.Path))
+		} else {
+			lines = append(lines, fmt.Sprintf("  %q;", f.Path))
+		}
+	}
+
+	for _, f := range g.Files {
+		for _, imp := range f.Imports {
+			attrs := fmt.Sprintf("label=%q", imp.Kind)
+			if inCycle[[2]string{f.Path, imp.Path}] {
+				attrs += ", color=red"
+			}
+
+			lines = append(lines, fmt.Sprintf("  %q -> %q [%s];", f.Path, imp.Path, attrs))
+		}
+	}
+
+	return strings.Join(append(lines, "}"), "\n") + "\n"
+}
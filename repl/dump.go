@@ -0,0 +1,132 @@
+package repl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/abs-lang/abs/ast"
+	"github.com/abs-lang/abs/lexer"
+	"github.com/abs-lang/abs/parser"
+	"github.com/abs-lang/abs/token"
+)
+
+// A token, as emitted by "abs tokens --format json"
+type tokenRecord struct {
+	Type     string `json:"type"`
+	Literal  string `json:"literal"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Position int    `json:"position"`
+}
+
+// BeginAst (args) -- prints the AST of a script through "abs ast [--format tree|json] script.abs"
+func BeginAst(args []string) {
+	file, format := dumpArgs(args, "ast")
+	code := readDumpSource(file)
+
+	p := parser.New(lexer.New(code))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		fmt.Fprintln(os.Stderr, "parser errors:")
+		for _, e := range p.Errors() {
+			fmt.Fprintln(os.Stderr, "\t"+e)
+		}
+		os.Exit(99)
+	}
+
+	if format == "json" {
+		printJson(ast.Dump(program))
+		return
+	}
+
+	fmt.Println(ast.Tree(program))
+}
+
+// BeginTokens (args) -- prints the tokens of a script through "abs tokens [--format text|json] script.abs"
+func BeginTokens(args []string) {
+	file, format := dumpArgs(args, "tokens")
+	code := readDumpSource(file)
+	l := lexer.New(code)
+	records := []tokenRecord{}
+
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		line, column, _ := l.ErrorLine(tok.Position)
+		records = append(records, tokenRecord{string(tok.Type), tok.Literal, line, column, tok.Position})
+	}
+
+	if format == "json" {
+		printJson(records)
+		return
+	}
+
+	for _, r := range records {
+		fmt.Printf("%d:%d\t%s\t%s\n", r.Line, r.Column, r.Type, r.Literal)
+	}
+}
+
+// Parses "[--format F] script.abs", where the
+// script can be '-' to read from stdin
+func dumpArgs(args []string, command string) (file string, format string) {
+	formats := map[string][]string{"ast": {"tree", "json"}, "tokens": {"text", "json"}, "deps": {"text", "json", "dot"}}[command]
+	format = formats[0]
+	usage := fmt.Sprintf("usage: abs %s [--format %s] script.abs", command, strings.Join(formats, "|"))
+
+	for i := 2; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--format":
+			if i+1 >= len(args) {
+				exitWithMessage("missing value for option --format\n" + usage)
+			}
+			format = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+		case file == "":
+			file = arg
+		default:
+			exitWithMessage(fmt.Sprintf("unexpected argument %s\n%s", arg, usage))
+		}
+	}
+
+	if file == "" {
+		exitWithMessage("no script given\n" + usage)
+	}
+
+	if !slices.Contains(formats, format) {
+		exitWithMessage(fmt.Sprintf("unsupported format '%s'\n%s", format, usage))
+	}
+
+	return file, format
+}
+
+func readDumpSource(file string) string {
+	var code []byte
+	var err error
+
+	if file == "-" {
+		code, err = io.ReadAll(os.Stdin)
+	} else {
+		code, err = os.ReadFile(file)
+	}
+
+	if err != nil {
+		exitWithMessage(err.Error())
+	}
+
+	return string(code)
+}
+
+func printJson(v any) {
+	b, _ := json.MarshalIndent(v, "", "  ")
+	fmt.Println(string(b))
+}
+
+func exitWithMessage(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(99)
+}
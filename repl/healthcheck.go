@@ -0,0 +1,128 @@
+package repl
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/abs-lang/abs/evaluator"
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/runner"
+)
+
+const healthcheckUsage = "usage: abs healthcheck [--timeout 5s] 'expr'"
+
+// How long "abs healthcheck" waits for the expression by default,
+// well within the 30s Docker gives health checks by default
+const HEALTHCHECK_TIMEOUT = 5 * time.Second
+
+// How much of the result makes it to the output, as
+// Docker keeps only the first 4KB of it anyway
+const healthcheckMaxOutput = 200
+
+// BeginHealthcheck (args, version) -- checks an expression through "abs healthcheck [--timeout 5s] 'expr'"
+//
+// Meant for Docker's HEALTHCHECK (or Kubernetes probes), it exits with
+// 0 if the expression is truthy and 1 otherwise, or if it errors or
+// doesn't complete within the timeout:
+//
+//	HEALTHCHECK CMD abs healthcheck '`curl -sf localhost:8080/health`.ok'
+//
+// Health checks don't run in a terminal, so the ABS init file isn't
+// loaded, nothing is read from stdin and the output is a single line.
+func BeginHealthcheck(args []string, version string) {
+	timeout, expr := HEALTHCHECK_TIMEOUT, ""
+
+	for i := 2; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--timeout" || arg == "-t":
+			if i+1 >= len(args) {
+				exitUnhealthy(fmt.Sprintf("missing value for option %s\n%s", arg, healthcheckUsage))
+			}
+			timeout = parseHealthcheckTimeout(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--timeout="):
+			timeout = parseHealthcheckTimeout(strings.TrimPrefix(arg, "--timeout="))
+		case strings.HasPrefix(arg, "-") && expr == "":
+			exitUnhealthy(fmt.Sprintf("unknown option %s\n%s", arg, healthcheckUsage))
+		case expr == "":
+			expr = arg
+		default:
+			exitUnhealthy(fmt.Sprintf("unexpected argument %s\n%s", arg, healthcheckUsage))
+		}
+	}
+
+	if strings.TrimSpace(expr) == "" {
+		exitUnhealthy("no expression given\n" + healthcheckUsage)
+	}
+
+	os.Stdin.Close()
+	d, _ := os.Getwd()
+	env := object.NewEnvironment(object.SystemStdio, d, version, false)
+
+	type result struct {
+		out         object.Object
+		ok          bool
+		parseErrors []string
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		out, ok, parseErrors := runner.RunWithOptions(expr, env, runner.Options{Timeout: timeout})
+		done <- result{out, ok, parseErrors}
+	}()
+
+	// The runner interrupts the expression once the timeout
+	// expires, but a command might not return in time: the
+	// budget is strict, so we don't wait for it
+	select {
+	case r := <-done:
+		switch {
+		case len(r.parseErrors) != 0:
+			exitUnhealthy("parser errors: " + strings.Join(strings.Fields(strings.Join(r.parseErrors, " ")), " "))
+		case !r.ok:
+			exitUnhealthy(healthcheckOutput(r.out))
+		case !evaluator.IsTruthy(r.out):
+			exitUnhealthy("unhealthy: " + healthcheckOutput(r.out))
+		}
+
+		fmt.Println("healthy: " + healthcheckOutput(r.out))
+	case <-time.After(timeout + 100*time.Millisecond):
+		exitUnhealthy(fmt.Sprintf("timed out after %s", timeout))
+	}
+}
+
+func parseHealthcheckTimeout(s string) time.Duration {
+	timeout, err := time.ParseDuration(s)
+	if err != nil || timeout <= 0 {
+		exitUnhealthy(fmt.Sprintf("invalid timeout '%s' (eg. 500ms or 5s)", s))
+	}
+
+	return timeout
+}
+
+// The result on a single, short line
+func healthcheckOutput(out object.Object) string {
+	s := strings.Join(strings.Fields(out.Inspect()), " ")
+
+	if len(s) > healthcheckMaxOutput {
+		// don't cut a character in half
+		n := healthcheckMaxOutput
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+
+		return s[:n] + "..."
+	}
+
+	return s
+}
+
+// Docker reserves the exit code 2, so anything
+// that isn't healthy exits with 1
+func exitUnhealthy(msg string) {
+	fmt.Println(msg)
+	os.Exit(1)
+}
@@ -0,0 +1,30 @@
+package repl
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/abs-lang/abs/object"
+)
+
+func TestHealthcheckOutput(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"ok", "ok"},
+		{"  all\n  good ", "all good"},
+		{strings.Repeat("a", 250), strings.Repeat("a", 200) + "..."},
+		// 'é' takes 2 bytes: the 200th byte is half of one
+		{"a" + strings.Repeat("é", 150), "a" + strings.Repeat("é", 99) + "..."},
+	}
+
+	for _, tt := range tests {
+		res := healthcheckOutput(&object.String{Value: tt.value})
+
+		if res != tt.expected || !utf8.ValidString(res) {
+			t.Fatalf("expected '%s', got '%s'", tt.expected, res)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package repl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/abs-lang/abs/lexer"
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/parser"
+)
+
+const hookUsage = `usage:
+  abs hook install hook [script.abs] [--force]
+  abs hook run hook [script.abs] [args...]
+  abs hook check`
+
+// Marks hooks installed by "abs hook install", which
+// can be replaced without --force
+const hookMarker = "# installed by abs hook install"
+
+var hookName = regexp.MustCompile(`^[a-z][a-z-]*$`)
+
+// BeginHook (args, version) -- wires ABS scripts as git hooks through "abs hook"
+//
+// Commands:
+//
+//	install hook [script.abs] [--force]    writes a git hook running the script through "abs hook run"
+//	run hook [script.abs] [args...]        runs the script of a hook, after checking staged files if it's a pre-commit
+//	check                                  parses the .abs files staged for commit, reporting errors
+//
+// The exit code of the script is the one of the hook, so that
+// exit(1) (or an error) in a pre-commit script aborts the commit.
+func BeginHook(args []string, version string) {
+	if len(args) < 3 {
+		exitWithMessage(hookUsage)
+	}
+
+	switch args[2] {
+	case "install":
+		path, err := installHook(args[3:])
+		if err != nil {
+			exitWithMessage(err.Error())
+		}
+
+		fmt.Printf("installed %s\n", path)
+	case "run":
+		runHook(args[3:], version)
+	case "check":
+		if !checkStaged() {
+			os.Exit(1)
+		}
+	default:
+		exitWithMessage(fmt.Sprintf("unknown command '%s'\n%s", args[2], hookUsage))
+	}
+}
+
+// abs hook install pre-commit scripts/pre-commit.abs
+func installHook(args []string) (string, error) {
+	hook, script, force := "", "", false
+
+	for _, arg := range args {
+		switch {
+		case arg == "--force" || arg == "-f":
+			force = true
+		case strings.HasPrefix(arg, "-"):
+			return "", fmt.Errorf("unknown option %s\n%s", arg, hookUsage)
+		case hook == "":
+			hook = arg
+		case script == "":
+			script = arg
+		default:
+			return "", fmt.Errorf("unexpected argument %s\n%s", arg, hookUsage)
+		}
+	}
+
+	if hook == "" {
+		return "", errors.New("no hook given\n" + hookUsage)
+	}
+
+	if !hookName.MatchString(hook) {
+		return "", fmt.Errorf("invalid hook '%s' (eg. pre-commit or pre-push)", hook)
+	}
+
+	// Without a script, a pre-commit hook still
+	// checks the staged files, other hooks would
+	// do nothing at all
+	if script == "" && hook != "pre-commit" {
+		return "", fmt.Errorf("no script given for the %s hook\n%s", hook, hookUsage)
+	}
+
+	hooksDir, err := git("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+
+	if script != "" {
+		script, err = hookScriptPath(script)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	abs, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(hooksDir, hook)
+	if existing, err := os.ReadFile(path); err == nil && !force && !bytes.Contains(existing, []byte(hookMarker)) {
+		return "", fmt.Errorf("%s already exists and wasn't installed by abs, use --force to replace it", path)
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(path, []byte(hookWrapper(abs, hook, script)), 0755); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// Script of a hook, relative to the root of the work tree,
+// where git runs hooks from, if it's in there
+func hookScriptPath(script string) (string, error) {
+	abs, err := filepath.Abs(script)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(abs); err != nil {
+		return "", err
+	}
+
+	root, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return abs, nil
+	}
+
+	if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel), nil
+	}
+
+	return abs, nil
+}
+
+// The shell script git runs, which hands over to "abs hook run"
+// so that its exit code is the one of the ABS script. abs is
+// looked up on the PATH when the hook runs, so that upgrades
+// (or a repository shared across machines) don't break it,
+// falling back to the abs that installed the hook.
+func hookWrapper(abs string, hook string, script string) string {
+	command := fmt.Sprintf("exec \"$abs\" hook run %s", hook)
+	if script != "" {
+		command += " " + shellQuote(script)
+	}
+
+	return fmt.Sprintf("#!/bin/sh\n%s, edits are lost when reinstalling\nabs=$(command -v abs) || abs=%s\n%s \"$@\"\n", hookMarker, shellQuote(abs), command)
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// abs hook run pre-commit scripts/pre-commit.abs "$@"
+func runHook(args []string, version string) {
+	if len(args) == 0 {
+		exitWithMessage("no hook given\n" + hookUsage)
+	}
+
+	// Scripts with syntax errors shouldn't make it
+	// to the repository, whatever the script says
+	if args[0] == "pre-commit" && !checkStaged() {
+		os.Exit(1)
+	}
+
+	if len(args) < 2 {
+		return
+	}
+
+	// Scripts see the arguments git passed to
+	// the hook as if they were run through
+	// "abs script.abs ..."
+	os.Args = append([]string{os.Args[0]}, args[1:]...)
+	script := os.Args[1]
+
+	env := object.NewEnvironment(object.SystemStdio, filepath.Dir(script), version, false)
+	getAbsInitFile(env)
+
+	code, err := os.ReadFile(script)
+	if err != nil {
+		exitWithMessage(err.Error())
+	}
+
+	if _, ok, _ := evalAndPrint(string(code), env); !ok {
+		os.Exit(99)
+	}
+}
+
+// Parses the .abs files staged for commit, as staged
+// rather than as they are in the work tree, printing
+// errors on stderr: returns whether all of them parse
+func checkStaged() bool {
+	out, err := git("diff", "--cached", "--name-only", "-z", "--diff-filter=ACMR", "--", "*.abs")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return false
+	}
+
+	ok := true
+	for _, file := range strings.Split(out, "\x00") {
+		if file == "" {
+			continue
+		}
+
+		code, err := git("show", ":"+file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", file, err.Error())
+			ok = false
+			continue
+		}
+
+		p := parser.New(lexer.New(code))
+		p.ParseProgram()
+
+		if len(p.Errors()) != 0 {
+			fmt.Fprintf(os.Stderr, "%s: parser errors:\n", file)
+			for _, e := range p.Errors() {
+				fmt.Fprintln(os.Stderr, "\t"+strings.ReplaceAll(e, "\n", "\n\t"))
+			}
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// Runs git, returning its output without
+// the trailing newline
+func git(args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+
+		return "", fmt.Errorf("git %s: %s", args[0], err.Error())
+	}
+
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
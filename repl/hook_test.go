@@ -0,0 +1,143 @@
+package repl
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Moves the test into an empty repository
+func newTestHookRepo(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	if _, err := git("init", "--quiet"); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestInstallHook(t *testing.T) {
+	dir := newTestHookRepo(t)
+	os.MkdirAll(filepath.Join(dir, "hooks"), 0755)
+	os.WriteFile(filepath.Join(dir, "hooks", "pre-push.abs"), []byte("exit(0)"), 0644)
+
+	path, err := installHook([]string{"pre-commit"})
+	if err != nil || path != filepath.Join(".git", "hooks", "pre-commit") {
+		t.Fatalf("expected the hook to be installed, got %s (%v)", path, err)
+	}
+
+	// hooks installed by abs can be replaced
+	if _, err := installHook([]string{"pre-commit", "hooks/pre-push.abs"}); err != nil {
+		t.Fatalf("expected the hook to be replaced, got %v", err)
+	}
+
+	content, _ := os.ReadFile(path)
+	if !strings.Contains(string(content), hookMarker) || !strings.Contains(string(content), `hook run pre-commit 'hooks/pre-push.abs' "$@"`) {
+		t.Fatalf("wrong hook: %s", content)
+	}
+
+	// the ones installed by others need --force
+	os.WriteFile(filepath.Join(dir, ".git", "hooks", "pre-push"), []byte("#!/bin/sh\nmake test\n"), 0755)
+	if _, err := installHook([]string{"pre-push", "hooks/pre-push.abs"}); err == nil || !strings.Contains(err.Error(), "use --force to replace it") {
+		t.Fatalf("expected the hook not to be replaced, got %v", err)
+	}
+
+	content, _ = os.ReadFile(filepath.Join(dir, ".git", "hooks", "pre-push"))
+	if string(content) != "#!/bin/sh\nmake test\n" {
+		t.Fatalf("expected the hook to be untouched, got %s", content)
+	}
+
+	if _, err := installHook([]string{"pre-push", "hooks/pre-push.abs", "--force"}); err != nil {
+		t.Fatalf("expected the hook to be replaced, got %v", err)
+	}
+
+	invalid := map[string][]string{
+		"no hook given":                         {},
+		"invalid hook 'Pre_Commit'":             {"Pre_Commit"},
+		"no script given for the pre-push hook": {"pre-push"},
+		"unknown option --nope":                 {"pre-commit", "--nope"},
+	}
+	for expected, args := range invalid {
+		if _, err := installHook(args); err == nil || !strings.HasPrefix(err.Error(), expected) {
+			t.Fatalf("expected '%s', got %v", expected, err)
+		}
+	}
+}
+
+func TestHookWrapper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks run through sh")
+	}
+
+	dir := t.TempDir()
+	fake := func(path string, name string) {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("#!/bin/sh\necho "+name+" \"$@\"\n"), 0755)
+	}
+	fake(filepath.Join(dir, "bin", "abs"), "path")
+	fake(filepath.Join(dir, "recorded", "abs"), "recorded")
+	os.MkdirAll(filepath.Join(dir, "empty"), 0755)
+
+	hook := filepath.Join(dir, "pre-commit")
+	os.WriteFile(hook, []byte(hookWrapper(filepath.Join(dir, "recorded", "abs"), "pre-commit", "it's.abs")), 0755)
+
+	tests := map[string]string{
+		// abs is looked up on the PATH...
+		filepath.Join(dir, "bin"): "path hook run pre-commit it's.abs x",
+		// ...falling back to the one that installed the hook
+		filepath.Join(dir, "empty"): "recorded hook run pre-commit it's.abs x",
+	}
+	for path, expected := range tests {
+		cmd := exec.Command("/bin/sh", hook, "x")
+		cmd.Env = append(os.Environ(), "PATH="+path)
+		out, err := cmd.Output()
+
+		if err != nil || strings.TrimSpace(string(out)) != expected {
+			t.Fatalf("expected '%s', got '%s' (%v)", expected, out, err)
+		}
+	}
+}
+
+func TestCheckStaged(t *testing.T) {
+	dir := newTestHookRepo(t)
+	write := func(file string, code string) {
+		os.WriteFile(filepath.Join(dir, file), []byte(code), 0644)
+	}
+
+	if !checkStaged() {
+		t.Fatalf("expected nothing staged to be fine")
+	}
+
+	write("good.abs", "a = 1")
+	write("notes.txt", "a = (")
+	git("add", "good.abs", "notes.txt")
+	if !checkStaged() {
+		t.Fatalf("expected good.abs to parse")
+	}
+
+	// files are checked as staged, not
+	// as they are in the work tree
+	write("bad.abs", "a = (")
+	git("add", "bad.abs")
+	write("bad.abs", "a = 1")
+	if checkStaged() {
+		t.Fatalf("expected the staged bad.abs not to parse")
+	}
+
+	git("add", "bad.abs")
+	if !checkStaged() {
+		t.Fatalf("expected the fixed bad.abs to parse")
+	}
+}
@@ -0,0 +1,157 @@
+package repl
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+const newUsage = "usage: abs new name [--template cli|daemon|test]"
+
+// Skeletons of projects created through "abs new": the
+// files of the common directory go in every project,
+// along with the ones of the template picked. In paths,
+// __name__ stands for the name of the project; contents
+// are Go templates, given the Name and Template.
+//
+//go:embed all:templates
+var templatesFS embed.FS
+
+var projectTemplates = []string{"cli", "daemon", "test"}
+
+var projectName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// BeginNew (args) -- scaffolds a project through "abs new name [--template cli|daemon|test]"
+//
+// The project is created in a new directory, named after it,
+// with a script (and its shebang), logging helpers, tests, a
+// Makefile to run, test, bundle and install it and, for
+// daemons, a systemd unit.
+func BeginNew(args []string) {
+	name, tpl := "", projectTemplates[0]
+
+	for i := 2; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--template" || arg == "-t":
+			if i+1 >= len(args) {
+				exitWithMessage(fmt.Sprintf("missing value for option %s\n%s", arg, newUsage))
+			}
+			tpl = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--template="):
+			tpl = strings.TrimPrefix(arg, "--template=")
+		case strings.HasPrefix(arg, "-"):
+			exitWithMessage(fmt.Sprintf("unknown option %s\n%s", arg, newUsage))
+		case name == "":
+			name = arg
+		default:
+			exitWithMessage(fmt.Sprintf("unexpected argument %s\n%s", arg, newUsage))
+		}
+	}
+
+	if name == "" {
+		exitWithMessage("no name given\n" + newUsage)
+	}
+
+	if !slices.Contains(projectTemplates, tpl) {
+		exitWithMessage(fmt.Sprintf("unknown template '%s'\n%s", tpl, newUsage))
+	}
+
+	files, err := scaffold(name, tpl, name)
+	if err != nil {
+		exitWithMessage(err.Error())
+	}
+
+	fmt.Printf("created %s from the %s template:\n", name, tpl)
+	for _, f := range files {
+		fmt.Println("  " + f)
+	}
+	fmt.Printf("\nnext: cd %s && make test\n", name)
+}
+
+// Creates a project in dir, returning the files created
+func scaffold(name string, tpl string, dir string) ([]string, error) {
+	if !projectName.MatchString(filepath.Base(name)) {
+		return nil, fmt.Errorf("invalid name '%s': use letters, digits, '.', '_' and '-'", name)
+	}
+
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("%s already exists", dir)
+	}
+
+	data := map[string]string{"Name": filepath.Base(name), "Template": tpl}
+	files := map[string][]byte{}
+
+	for _, root := range []string{"templates/common", "templates/" + tpl} {
+		err := fs.WalkDir(templatesFS, root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			content, err := renderTemplate(p, data)
+			if err != nil {
+				return err
+			}
+
+			rel := strings.ReplaceAll(strings.TrimPrefix(p, root+"/"), "__name__", data["Name"])
+			files[rel] = content
+
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	created := []string{}
+	for _, rel := range slices.Sorted(maps.Keys(files)) {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+
+		// Scripts with a shebang can be executed
+		mode := os.FileMode(0644)
+		if bytes.HasPrefix(files[rel], []byte("#!")) {
+			mode = 0755
+		}
+
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(p, files[rel], mode); err != nil {
+			return nil, err
+		}
+
+		created = append(created, p)
+	}
+
+	return created, nil
+}
+
+func renderTemplate(p string, data map[string]string) ([]byte, error) {
+	content, err := templatesFS.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := template.New(path.Base(p)).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,159 @@
+package repl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/runner"
+)
+
+// Largest snippet the playground accepts
+const playgroundMaxCodeSize = 64 * 1024
+
+// The playground evaluates one snippet at a time,
+// as the evaluator's state (eg. sandboxing) is global
+var playgroundMux = &sync.Mutex{}
+
+// What the playground returns for every snippet
+type playgroundResult struct {
+	Output      string   `json:"output"`
+	Result      string   `json:"result"`
+	Ok          bool     `json:"ok"`
+	Duration    int64    `json:"duration"`
+	ParseErrors []string `json:"parse_errors"`
+}
+
+// BeginPlayground (args, version) -- serves a web UI to try ABS out, "abs playground [--listen :8080] [--timeout 5s]"
+//
+// Snippets are evaluated in a sandbox: they can't run commands
+// nor call builtins that reach outside of the program (see
+// runner.Options), and they're interrupted when they run for
+// longer than the timeout.
+func BeginPlayground(args []string, version string) {
+	flags := flag.NewFlagSet("playground", flag.ExitOnError)
+	listen := flags.String("listen", ":8080", "address to listen on")
+	timeout := flags.Duration("timeout", 5*time.Second, "maximum time a snippet can run for")
+	flags.Parse(args[2:])
+
+	fmt.Printf("ABS playground listening on %s\n", *listen)
+	log.Fatal(http.ListenAndServe(*listen, newPlaygroundHandler(version, *timeout)))
+}
+
+func newPlaygroundHandler(version string, timeout time.Duration) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, strings.ReplaceAll(playgroundHTML, "{{version}}", version))
+	})
+
+	mux.HandleFunc("POST /eval", func(w http.ResponseWriter, r *http.Request) {
+		code, err := io.ReadAll(io.LimitReader(r.Body, playgroundMaxCodeSize+1))
+
+		if err != nil || len(code) > playgroundMaxCodeSize {
+			http.Error(w, "snippet too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(evalSnippet(string(code), version, timeout))
+	})
+
+	return mux
+}
+
+// Evaluates a snippet in a fresh, sandboxed environment
+func evalSnippet(code string, version string, timeout time.Duration) playgroundResult {
+	playgroundMux.Lock()
+	defer playgroundMux.Unlock()
+
+	var out bytes.Buffer
+	stdout := bufio.NewReadWriter(bufio.NewReader(&out), bufio.NewWriter(&out))
+	stdin := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	stdio := &object.Stdio{Stdin: stdin, Stdout: stdout, Stderr: stdout}
+	env := object.NewEnvironment(stdio, os.TempDir(), version, false)
+
+	start := time.Now()
+	res, ok, parseErrors := runner.RunWithOptions(code, env, runner.Options{Sandboxed: true, Timeout: timeout})
+	stdout.Flush()
+
+	result := ""
+	if res != nil && res != object.NULL {
+		result = res.Inspect()
+	}
+
+	if parseErrors == nil {
+		parseErrors = []string{}
+	}
+
+	return playgroundResult{
+		Output:      out.String(),
+		Result:      result,
+		Ok:          ok,
+		Duration:    time.Since(start).Milliseconds(),
+		ParseErrors: parseErrors,
+	}
+}
+
+const playgroundHTML = `<!doctype html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>ABS playground</title>
+  <style>
+    body { font-family: sans-serif; max-width: 50em; margin: 2em auto; padding: 0 1em; }
+    textarea, pre { box-sizing: border-box; width: 100%; font-family: monospace; font-size: 14px; }
+    textarea { height: 15em; }
+    pre { background: #f4f4f4; padding: 1em; min-height: 5em; white-space: pre-wrap; }
+    .error { color: #ed4747; }
+    small { color: #888; }
+  </style>
+</head>
+<body>
+  <h1>ABS playground <small>{{version}}</small></h1>
+  <textarea id="code">echo("hello %s", "world")
+[1, 2, 3].map(f(x) { x * 2 })</textarea>
+  <p><button id="run">Run</button> <small>(ctrl+enter) &mdash; commands are disabled in the playground</small></p>
+  <pre id="output"></pre>
+  <script>
+    const code = document.getElementById("code")
+    const output = document.getElementById("output")
+
+    async function run() {
+      output.textContent = "running..."
+      output.className = ""
+
+      const res = await fetch("eval", {method: "POST", body: code.value}).then(r => r.json())
+      const lines = [res.output.trimEnd()]
+
+      if (res.parse_errors.length > 0) {
+        lines.push(res.parse_errors.join("\n"))
+      } else if (res.result !== "") {
+        lines.push(res.result)
+      }
+
+      output.textContent = lines.filter(l => l !== "").join("\n") + "\n\n(" + res.duration + "ms)"
+      output.className = res.ok ? "" : "error"
+    }
+
+    document.getElementById("run").onclick = run
+    code.onkeydown = (e) => {
+      if (e.ctrlKey && e.key === "Enter") {
+        run()
+      }
+    }
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,38 @@
+package repl
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlaygroundRecursion(t *testing.T) {
+	server := httptest.NewServer(newPlaygroundHandler("test", 5*time.Second))
+	defer server.Close()
+
+	eval := func(code string) playgroundResult {
+		res, err := http.Post(server.URL+"/eval", "text/plain", strings.NewReader(code))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		var r playgroundResult
+		json.NewDecoder(res.Body).Decode(&r)
+
+		return r
+	}
+
+	// runaway recursion fails the snippet,
+	// rather than crashing the server
+	if r := eval("g = f() { g() }; g()"); r.Ok || !strings.Contains(r.Result, "maximum call depth exceeded") {
+		t.Fatalf("expected the snippet to fail, got %+v", r)
+	}
+
+	if r := eval("1 + 1"); !r.Ok || r.Result != "2" {
+		t.Fatalf("expected the server to still be up, got %+v", r)
+	}
+}
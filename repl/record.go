@@ -0,0 +1,78 @@
+package repl
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/abs-lang/abs/terminal"
+	"github.com/charmbracelet/x/term"
+)
+
+// BeginInteractive (args, version) -- the REPL, recording or replaying sessions through
+// "abs repl [--record session.cast] [--replay session.cast [--speed 2] [--idle 2s]]"
+//
+// Sessions are recorded in the asciicast format, so they can be played by
+// asciinema as well: inputs, outputs and their timing end up in the file.
+func BeginInteractive(args []string, version string) {
+	flags := flag.NewFlagSet("repl", flag.ExitOnError)
+	record := flags.String("record", "", "file the session is recorded to")
+	replay := flags.String("replay", "", "recorded session to replay")
+	speed := flags.Float64("speed", 1, "how fast the session is replayed")
+	idle := flags.Duration("idle", 2*time.Second, "longest pause when replaying, unless the recording sets one")
+	flags.Parse(args[2:])
+
+	if *replay != "" {
+		replaySession(*replay, *speed, *idle)
+		return
+	}
+
+	d, _ := os.Getwd()
+	env := newReplEnvironment(d, version, true)
+
+	if *record == "" {
+		beginTerminal(env)
+		return
+	}
+
+	f, err := os.Create(*record)
+	if err != nil {
+		exitWithMessage(err.Error())
+	}
+	defer f.Close()
+
+	width, height, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || width == 0 {
+		width, height = 80, 24
+	}
+
+	rec, err := terminal.NewCastRecorder(f, width, height)
+	if err != nil {
+		exitWithMessage(err.Error())
+	}
+
+	beginTerminal(env, rec.Options()...)
+
+	if err := rec.Close(); err != nil {
+		exitWithMessage(fmt.Sprintf("couldn't record the session: %s", err.Error()))
+	}
+
+	fmt.Printf("session recorded to %s\n", *record)
+}
+
+func replaySession(file string, speed float64, idle time.Duration) {
+	if speed <= 0 {
+		exitWithMessage(fmt.Sprintf("invalid speed %v, it must be greater than 0", speed))
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		exitWithMessage(err.Error())
+	}
+	defer f.Close()
+
+	if err := terminal.ReplayCast(f, os.Stdout, speed, idle); err != nil {
+		exitWithMessage(fmt.Sprintf("%s: %s", file, err.Error()))
+	}
+}
@@ -1,17 +1,18 @@
 package repl
 
 import (
-	"bytes"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/abs-lang/abs/i18n"
 	"github.com/abs-lang/abs/object"
 	"github.com/abs-lang/abs/runner"
 	"github.com/abs-lang/abs/terminal"
 	"github.com/abs-lang/abs/util"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // support for ABS init file
@@ -44,45 +45,77 @@ func getAbsInitFile(env *object.Environment) {
 // This function takes code and evaluates
 // it, spitting out the result.
 func Run(code string, env *object.Environment) {
-	out, ok, parseErrors := runner.Run(code, env)
+	_, ok, _ := evalAndPrint(code, env)
 
-	// let's check if this REPL is interactive
-	v, _ := env.Get("ABS_INTERACTIVE")
-	interactive := v == object.TRUE
+	if !ok && !isInteractive(env) {
+		os.Exit(99)
+	}
+}
+
+// Evaluates the code, printing errors or,
+// when interactive, the result of the evaluation.
+func evalAndPrint(code string, env *object.Environment) (out object.Object, ok bool, parseErrors []string) {
+	out, ok, parseErrors = runner.Run(code, env)
 
 	if len(parseErrors) != 0 {
 		printParserErrors(parseErrors, env)
-
-		if !interactive {
-			os.Exit(99)
-		}
-
-		return
+		return out, false, parseErrors
 	}
 
 	if !ok {
 		fmt.Fprintf(env.Stdio.Stdout, "%s", out)
 		fmt.Fprintln(env.Stdio.Stdout)
-
-		if !interactive {
-			os.Exit(99)
-		}
-		return
+		return out, ok, parseErrors
 	}
 
-	if interactive && out.Type() != object.NULL_OBJ {
+	if isInteractive(env) && out.Type() != object.NULL_OBJ {
 		env.Stdio.Stdout.Write([]byte(out.Inspect()))
-		return
 	}
+
+	return out, ok, parseErrors
+}
+
+// let's check if this REPL is interactive
+func isInteractive(env *object.Environment) bool {
+	v, _ := env.Get("ABS_INTERACTIVE")
+	return v == object.TRUE
 }
 
 func printParserErrors(errors []string, env *object.Environment) {
-	fmt.Fprintf(env.Stdio.Stdout, "%s", " parser errors:\n")
+	fmt.Fprintf(env.Stdio.Stdout, " %s\n", i18n.T("parser errors:"))
 	for _, msg := range errors {
 		fmt.Fprint(env.Stdio.Stdout, " \t"+msg+"\n")
 	}
 }
 
+// Sets up the environment of the REPL, or of
+// the script it runs, through the ABS init file
+func newReplEnvironment(dir string, version string, interactive bool) *object.Environment {
+	if err := i18n.SetLanguage(os.Getenv("ABS_LANG")); err != nil {
+		fmt.Fprintf(os.Stderr, "ABS_LANG: %s; using english\n", err.Error())
+	}
+
+	env := object.NewEnvironment(object.SystemStdio, dir, version, interactive)
+
+	// get abs init file
+	// user may test ABS_INTERACTIVE to decide what code to run
+	getAbsInitFile(env)
+
+	return env
+}
+
+// Launches the interactive terminal
+func beginTerminal(env *object.Environment, opts ...tea.ProgramOption) {
+	term := terminal.NewTerminal(
+		env,
+		opts...,
+	)
+
+	if _, err := term.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
 // BeginRepl (args) -- the REPL, both interactive and script modes begin here
 // This allows us to prime the global env with ABS_INTERACTIVE = true/false,
 // load the builtin Fns names for the use of command completion, and
@@ -96,30 +129,11 @@ func BeginRepl(args []string, version string) {
 		d = filepath.Dir(args[1])
 	}
 
-	env := object.NewEnvironment(object.SystemStdio, d, version, interactive)
-
-	// get abs init file
-	// user may test ABS_INTERACTIVE to decide what code to run
-	getAbsInitFile(env)
+	env := newReplEnvironment(d, version, interactive)
 
 	// This is a terminal / actual REPL
 	if interactive {
-		// launch the interactive terminal
-		stdio := bytes.NewBufferString("")
-		env.Stdio.Stdout = stdio
-		env.Stdio.Stderr = stdio
-		r, w, _ := os.Pipe()
-		env.Stdio.Stdin = r
-
-		term := terminal.NewTerminal(
-			env,
-			w,
-		)
-
-		if _, err := term.Run(); err != nil {
-			log.Fatal(err)
-		}
-
+		beginTerminal(env)
 		return
 	}
 
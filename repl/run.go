@@ -0,0 +1,178 @@
+package repl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/abs-lang/abs/evaluator"
+	"github.com/abs-lang/abs/object"
+)
+
+// Options of "abs run"
+type runOptions struct {
+	// Format of the record emitted once
+	// the script is done, eg. "json"
+	output string
+	// File descriptor the record is written to
+	outputFd int
+}
+
+// The record emitted by "abs run --output json"
+type runRecord struct {
+	Result      interface{} `json:"result"`
+	Ok          bool        `json:"ok"`
+	Duration    int64       `json:"duration"`
+	ParseErrors []string    `json:"parse_errors"`
+}
+
+// BeginRun (args, version) -- runs a script through "abs run [options] script.abs [args]"
+//
+// Options:
+//
+//	--output json    emits a final record {result, ok, duration, parse_errors},
+//	                 also when the script calls exit(n): result is then n
+//	                 and ok whether n is 0
+//	--output-fd N    writes the record on the file descriptor N (default: 1, stdout)
+//	--dry-run        prints commands instead of executing them
+func BeginRun(args []string, version string) {
+	opts := runOptions{outputFd: 1}
+	i := 2
+
+	for ; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			i++
+			break
+		}
+
+		if len(arg) == 0 || arg[0] != '-' {
+			break
+		}
+
+		if arg == "--dry-run" {
+			os.Setenv("ABS_DRY_RUN", "true")
+			continue
+		}
+
+		if i+1 >= len(args) {
+			exitWithUsage(fmt.Sprintf("missing value for option %s", arg))
+		}
+
+		switch arg {
+		case "--output":
+			opts.output = args[i+1]
+
+			if opts.output != "json" {
+				exitWithUsage(fmt.Sprintf("unsupported output format '%s'", opts.output))
+			}
+		case "--output-fd":
+			fd, err := strconv.Atoi(args[i+1])
+
+			if err != nil || fd < 1 {
+				exitWithUsage(fmt.Sprintf("invalid file descriptor '%s'", args[i+1]))
+			}
+
+			opts.outputFd = fd
+		default:
+			exitWithUsage(fmt.Sprintf("unknown option %s", arg))
+		}
+
+		i++
+	}
+
+	if i >= len(args) {
+		exitWithUsage("no script to run")
+	}
+
+	// Scripts see the same arguments they would
+	// see with "abs script.abs ...", so that arg(n)
+	// and flag(...) keep working
+	os.Args = append([]string{args[0]}, args[i:]...)
+	script := os.Args[1]
+
+	env := object.NewEnvironment(object.SystemStdio, filepath.Dir(script), version, false)
+	getAbsInitFile(env)
+
+	code, err := os.ReadFile(script)
+	if err != nil {
+		fmt.Fprintln(env.Stdio.Stdout, err.Error())
+		os.Exit(99)
+	}
+
+	start := time.Now()
+
+	// exit(n) terminates the process without
+	// returning here, so the record is written
+	// right before it does, with the exit code
+	// as the result
+	if opts.output == "json" {
+		evaluator.ExitHook = func(code int) {
+			writeRunRecord(opts.outputFd, runRecord{
+				Result:   code,
+				Ok:       code == 0,
+				Duration: time.Since(start).Milliseconds(),
+			})
+		}
+	}
+
+	out, ok, parseErrors := evalAndPrint(string(code), env)
+
+	if opts.output == "json" {
+		writeRunRecord(opts.outputFd, runRecord{
+			Result:      recordResult(out),
+			Ok:          ok,
+			Duration:    time.Since(start).Milliseconds(),
+			ParseErrors: parseErrors,
+		})
+	}
+
+	if !ok {
+		os.Exit(99)
+	}
+}
+
+// Converts the result of the script into something
+// we can serialize: its JSON representation, if valid,
+// or its string representation otherwise (eg. errors).
+func recordResult(out object.Object) interface{} {
+	if out == nil || out.Type() == object.NULL_OBJ {
+		return nil
+	}
+
+	if err, ok := out.(*object.Error); ok {
+		return err.Message
+	}
+
+	if json.Valid([]byte(out.Json())) {
+		return json.RawMessage(out.Json())
+	}
+
+	return out.Inspect()
+}
+
+func writeRunRecord(fd int, record runRecord) {
+	if record.ParseErrors == nil {
+		record.ParseErrors = []string{}
+	}
+
+	f := os.NewFile(uintptr(fd), "abs-output")
+	if f == nil {
+		fmt.Fprintf(os.Stderr, "unable to write the result on fd %d\n", fd)
+		return
+	}
+
+	b, _ := json.Marshal(record)
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to write the result on fd %d: %s\n", fd, err.Error())
+	}
+}
+
+func exitWithUsage(msg string) {
+	fmt.Fprintf(os.Stderr, "%s\nusage: abs run [--dry-run] [--output json] [--output-fd N] script.abs [args...]\n", msg)
+	os.Exit(99)
+}
@@ -0,0 +1,76 @@
+package repl
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Runs "abs run --output json" on the script in
+// ABS_TEST_RUN_SCRIPT: it's executed in a child
+// process by TestRunRecord, since exit(n) terminates
+// the process.
+func TestRunHelper(t *testing.T) {
+	script := os.Getenv("ABS_TEST_RUN_SCRIPT")
+	if script == "" {
+		t.Skip("only runs as a child of TestRunRecord")
+	}
+
+	BeginRun([]string{"abs", "run", "--output", "json", script}, "test")
+	os.Exit(0)
+}
+
+func TestRunRecord(t *testing.T) {
+	tests := []struct {
+		code     string
+		exitCode int
+		result   string
+		ok       bool
+	}{
+		{"1 + 1", 0, "2", true},
+		{"exit(0)", 0, "0", true},
+		{"exit(3)", 3, "3", false},
+		{"echo(1); exit(3, 'bye')", 3, "3", false},
+	}
+
+	for _, tt := range tests {
+		script := filepath.Join(t.TempDir(), "script.abs")
+		if err := os.WriteFile(script, []byte(tt.code), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cmd := exec.Command(os.Args[0], "-test.run=^TestRunHelper$")
+		cmd.Env = append(os.Environ(), "ABS_TEST_RUN_SCRIPT="+script)
+		out, err := cmd.Output()
+
+		exitCode := 0
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		if exitCode != tt.exitCode {
+			t.Fatalf("'%s' should exit with %d, got %d", tt.code, tt.exitCode, exitCode)
+		}
+
+		var record struct {
+			Result   json.RawMessage `json:"result"`
+			Ok       bool            `json:"ok"`
+			Duration *int64          `json:"duration"`
+		}
+		// the record follows whatever the script printed
+		i := strings.LastIndex(string(out), `{"result"`)
+
+		if i < 0 || json.Unmarshal(out[i:], &record) != nil {
+			t.Fatalf("'%s' didn't emit a record, output: %s", tt.code, out)
+		}
+
+		if string(record.Result) != tt.result || record.Ok != tt.ok || record.Duration == nil {
+			t.Fatalf("wrong record for '%s': %s", tt.code, out)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package repl
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abs-lang/abs/evaluator"
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/runner"
+)
+
+const testUsage = "usage: abs test [-update] [file_test.abs | dir ...]"
+
+// BeginTest (args, version) -- runs tests through "abs test [-update] [paths...]"
+//
+// Tests are files ending in _test.abs, found in the given
+// paths (directories are walked recursively, the current
+// one by default): each file runs in its own environment,
+// from its own directory, and fails on its first error.
+// Mocks are reset between files.
+//
+// Options:
+//
+//	-update    (re)writes golden files rather than comparing against them
+func BeginTest(args []string, version string) {
+	paths := []string{}
+
+	for _, arg := range args[2:] {
+		switch {
+		case arg == "-update" || arg == "--update":
+			os.Setenv("ABS_UPDATE_GOLDEN", "true")
+		case strings.HasPrefix(arg, "-"):
+			exitWithMessage(fmt.Sprintf("unknown option %s\n%s", arg, testUsage))
+		default:
+			paths = append(paths, arg)
+		}
+	}
+
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	files, err := findTestFiles(paths)
+	if err != nil {
+		exitWithMessage(err.Error())
+	}
+
+	if len(files) == 0 {
+		exitWithMessage("no test files (*_test.abs) found\n" + testUsage)
+	}
+
+	failed := 0
+	start := time.Now()
+
+	for _, file := range files {
+		d, msg := runTestFile(file, version)
+
+		if msg != "" {
+			failed++
+			fmt.Printf("FAIL %s (%s)\n%s\n", file, d.Round(time.Millisecond), indent(msg))
+			continue
+		}
+
+		fmt.Printf("ok   %s (%s)\n", file, d.Round(time.Millisecond))
+	}
+
+	fmt.Printf("\n%d passed, %d failed in %s\n", len(files)-failed, failed, time.Since(start).Round(time.Millisecond))
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// Expands the given paths into a
+// sorted list of test files
+func findTestFiles(paths []string) ([]string, error) {
+	files := []string{}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() && p != path && (strings.HasPrefix(d.Name(), ".") || d.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+
+			if !d.IsDir() && strings.HasSuffix(d.Name(), "_test.abs") {
+				files = append(files, p)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// Runs a test file, returning how long it took
+// and, if it failed, the reason why
+func runTestFile(file string, version string) (time.Duration, string) {
+	evaluator.ResetMocks()
+	defer evaluator.ResetMocks()
+
+	code, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err.Error()
+	}
+
+	// The script sees itself as the one being run,
+	// eg. to locate its checkpoint
+	os.Args = []string{os.Args[0], file}
+	env := object.NewEnvironment(object.SystemStdio, filepath.Dir(file), version, false)
+
+	start := time.Now()
+	out, ok, parseErrors := runner.Run(string(code), env)
+	d := time.Since(start)
+
+	if len(parseErrors) != 0 {
+		return d, "parser errors:\n" + strings.Join(parseErrors, "\n")
+	}
+
+	if !ok {
+		return d, out.Inspect()
+	}
+
+	return d, ""
+}
+
+func indent(s string) string {
+	return "    " + strings.ReplaceAll(s, "\n", "\n    ")
+}
@@ -0,0 +1,23 @@
+package repl
+
+import (
+	"bytes"
+	"log"
+	"os"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/terminal"
+)
+
+// BeginTour (version) -- an interactive tutorial of the language, "abs tour"
+func BeginTour(version string) {
+	d, _ := os.Getwd()
+	env := object.NewEnvironment(object.SystemStdio, d, version, true)
+	stdio := bytes.NewBufferString("")
+	env.Stdio.Stdout = stdio
+	env.Stdio.Stderr = stdio
+
+	if _, err := terminal.NewTour(env).Run(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,70 @@
+package repl
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abs-lang/abs/transpiler"
+)
+
+const transpileUsage = "usage: abs transpile --target bash script.abs [-o out.sh]"
+
+// BeginTranspile (args) -- converts a script to another language through "abs transpile --target bash script.abs [-o out.sh]"
+//
+// This is experimental, and meant for the environments
+// that can't run the abs binary: only the subset of ABS
+// that maps cleanly to the target (variables, commands,
+// conditionals) is supported, anything else is an error.
+func BeginTranspile(args []string) {
+	file, out, target := "", "", ""
+
+	for i := 2; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--target" || arg == "-o" || arg == "--output":
+			if i+1 >= len(args) {
+				exitWithMessage(fmt.Sprintf("missing value for option %s\n%s", arg, transpileUsage))
+			}
+			if arg == "--target" {
+				target = args[i+1]
+			} else {
+				out = args[i+1]
+			}
+			i++
+		case strings.HasPrefix(arg, "--target="):
+			target = strings.TrimPrefix(arg, "--target=")
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			exitWithMessage(fmt.Sprintf("unknown option %s\n%s", arg, transpileUsage))
+		case file == "":
+			file = arg
+		default:
+			exitWithMessage(fmt.Sprintf("unexpected argument %s\n%s", arg, transpileUsage))
+		}
+	}
+
+	if file == "" {
+		exitWithMessage("no script given\n" + transpileUsage)
+	}
+
+	if target == "" {
+		exitWithMessage("no target given\n" + transpileUsage)
+	}
+
+	if target != "bash" {
+		exitWithMessage(fmt.Sprintf("unsupported target '%s' (only bash is supported)\n%s", target, transpileUsage))
+	}
+
+	code, err := transpiler.Bash(readDumpSource(file), file)
+	if err != nil {
+		exitWithMessage(fmt.Sprintf("%s: %s", file, err.Error()))
+	}
+
+	if out == "" {
+		fmt.Print(code)
+		return
+	}
+
+	if err := os.WriteFile(out, []byte(code), 0755); err != nil {
+		exitWithMessage(err.Error())
+	}
+}
@@ -1,12 +1,42 @@
 package runner
 
 import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
 	"github.com/abs-lang/abs/evaluator"
 	"github.com/abs-lang/abs/lexer"
 	"github.com/abs-lang/abs/object"
 	"github.com/abs-lang/abs/parser"
 )
 
+// Options customize how a program is run,
+// mostly for those embedding ABS.
+type Options struct {
+	// CommandFilter is called before executing any
+	// command (eg. `ls -la`): if it returns an error
+	// the command won't run, and the error is returned
+	// to the program.
+	CommandFilter func(cmd string) error
+	// Audit is called after every command
+	// has been executed, eg. to keep track
+	// of what a script did.
+	Audit func(record evaluator.AuditRecord)
+	// Sandboxed programs cannot run commands nor
+	// call builtins that reach outside of the
+	// program, such as exit() or require().
+	Sandboxed bool
+	// Timeout, when set, limits how long a
+	// program can run before being interrupted.
+	Timeout time.Duration
+	// Context, when set, interrupts the program
+	// once it's done, eg. when canceled on SIGTERM,
+	// killing the commands it's running.
+	Context context.Context
+}
+
 // Run, well, runs an abs program.
 // It returns output of the program,
 // whether it encountered an error
@@ -14,6 +44,40 @@ import (
 // can print helpful error locations
 // for you to fix he code.
 func Run(code string, env *object.Environment) (out object.Object, ok bool, parseErrors []string) {
+	return RunWithOptions(code, env, Options{})
+}
+
+// RunWithOptions runs an abs program
+// the same way Run does, with the given options.
+func RunWithOptions(code string, env *object.Environment, opts Options) (out object.Object, ok bool, parseErrors []string) {
+	previousFilter := evaluator.CommandFilter
+	evaluator.CommandFilter = opts.CommandFilter
+	defer func() { evaluator.CommandFilter = previousFilter }()
+
+	previousAudit := evaluator.AuditFunc
+	evaluator.AuditFunc = opts.Audit
+	defer func() { evaluator.AuditFunc = previousAudit }()
+
+	previousSandboxed := evaluator.Sandboxed
+	evaluator.Sandboxed = opts.Sandboxed
+	defer func() { evaluator.Sandboxed = previousSandboxed }()
+
+	var timedOut atomic.Bool
+	if opts.Timeout > 0 {
+		defer evaluator.ResetInterrupt()
+		timer := time.AfterFunc(opts.Timeout, func() {
+			timedOut.Store(true)
+			evaluator.Interrupt()
+		})
+		defer timer.Stop()
+	}
+
+	if opts.Context != nil {
+		defer evaluator.ResetInterrupt()
+		stop := context.AfterFunc(opts.Context, evaluator.Interrupt)
+		defer stop()
+	}
+
 	lex := lexer.New(code)
 	p := parser.New(lex)
 
@@ -26,11 +90,18 @@ func Run(code string, env *object.Environment) (out object.Object, ok bool, pars
 
 	// invoke BeginEval() passing in the program, env, and lexer for error position
 	// NB. Eval(node, env) is recursive so we can't call it directly
+	span := evaluator.StartSpan("abs.run", nil)
 	evaluated := evaluator.BeginEval(program, env, lex)
+	span.End(evaluated != nil && evaluated.Type() == object.ERROR_OBJ)
+	evaluator.FlushTraces()
 
 	if evaluated == nil {
 		return object.NULL, false, []string{}
 	}
 
+	if timedOut.Load() && evaluated.Type() == object.ERROR_OBJ {
+		evaluated = &object.Error{Message: fmt.Sprintf("program timed out after %s", opts.Timeout)}
+	}
+
 	return evaluated, evaluated.Type() != object.ERROR_OBJ, parseErrors
 }
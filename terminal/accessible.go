@@ -0,0 +1,59 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/util"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Accessibility mode (ABS_ACCESSIBLE=1) is meant for
+// screen-reader users: the cursor doesn't blink and
+// nothing gets redrawn in place -- suggestions are
+// announced as plain text, long outputs aren't paged
+// and ctrl+l doesn't clear the screen -- so that
+// the output of the REPL is append-only.
+func isAccessible(env *object.Environment) bool {
+	v := util.GetEnvVar(env, "ABS_ACCESSIBLE", "")
+
+	return v == "1" || v == "true"
+}
+
+// Describes the suggestions being shown, either
+// listing them all (when they first come up) or
+// reading out the one that's been selected
+func (m Model) describeSuggestions() string {
+	if !m.IsSuggesting() {
+		return ""
+	}
+
+	if m.suggestionsIndex < 0 {
+		values := []string{}
+		for _, s := range m.suggestions {
+			values = append(values, s.Value)
+		}
+
+		return fmt.Sprintf("%d suggestions: %s (tab to cycle, enter to pick)", len(m.suggestions), strings.Join(values, ", "))
+	}
+
+	s := m.suggestions[m.suggestionsIndex]
+	msg := fmt.Sprintf("suggestion %d of %d: %s", m.suggestionsIndex+1, len(m.suggestions), s.Value)
+
+	if s.Comment != "" {
+		msg += " # " + s.Comment
+	}
+
+	return msg
+}
+
+// In accessibility mode, suggestions are printed
+// rather than rendered below the input
+func (m Model) announceSuggestions() tea.Cmd {
+	if !m.accessible || !m.IsSuggesting() {
+		return nil
+	}
+
+	return tea.Println(m.describeSuggestions())
+}
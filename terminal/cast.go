@@ -0,0 +1,213 @@
+package terminal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Sessions are recorded in the asciicast v2 format, so that
+// they can be played by asciinema (and uploaded, or embedded
+// in docs) as well as by "abs repl --replay": a header followed
+// by one event per line, [seconds, kind, data], where the kind
+// is "o" for output, "i" for input and "r" for a resize.
+
+// Header of an asciicast
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	IdleLimit float64           `json:"idle_time_limit,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// CastRecorder records a REPL session
+// to an asciicast, see NewCastRecorder
+type CastRecorder struct {
+	mu    sync.Mutex
+	w     *bufio.Writer
+	start time.Time
+	err   error
+}
+
+// NewCastRecorder starts recording a session of
+// the given size, writing its header to w
+func NewCastRecorder(w io.Writer, width int, height int) (*CastRecorder, error) {
+	r := &CastRecorder{w: bufio.NewWriter(w), start: time.Now()}
+	header, _ := json.Marshal(castHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.start.Unix(),
+		Env:       map[string]string{"SHELL": os.Getenv("SHELL"), "TERM": os.Getenv("TERM")},
+	})
+
+	if _, err := fmt.Fprintf(r.w, "%s\n", header); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *CastRecorder) event(kind string, data string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+
+	event, _ := json.Marshal([]any{time.Since(r.start).Seconds(), kind, data})
+	_, r.err = fmt.Fprintf(r.w, "%s\n", event)
+}
+
+// Close flushes the recording, returning
+// the error we might have run into
+func (r *CastRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	return r.w.Flush()
+}
+
+// Options for the terminal to be recorded: what it
+// writes to stdout, the keys typed and the resizes
+func (r *CastRecorder) Options() []tea.ProgramOption {
+	filter := func(_ tea.Model, msg tea.Msg) tea.Msg {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			if input := keyInput(msg); input != "" {
+				r.event("i", input)
+			}
+		case tea.WindowSizeMsg:
+			if msg.Width == 0 {
+				break
+			}
+
+			r.event("r", fmt.Sprintf("%dx%d", msg.Width, msg.Height))
+		}
+
+		return msg
+	}
+
+	return []tea.ProgramOption{
+		tea.WithOutput(castOutput{File: os.Stdout, rec: r}),
+		tea.WithFilter(filter),
+	}
+}
+
+// Stdout, recording what's written to it -- it's
+// still a file so that the terminal can tell its size
+type castOutput struct {
+	*os.File
+	rec *CastRecorder
+}
+
+func (o castOutput) Write(p []byte) (int, error) {
+	o.rec.event("o", string(p))
+
+	return o.File.Write(p)
+}
+
+// Escape sequences of the keys that
+// aren't runes nor control characters
+var keySequences = map[tea.KeyType]string{
+	tea.KeyUp:       "\x1b[A",
+	tea.KeyDown:     "\x1b[B",
+	tea.KeyRight:    "\x1b[C",
+	tea.KeyLeft:     "\x1b[D",
+	tea.KeyHome:     "\x1b[H",
+	tea.KeyEnd:      "\x1b[F",
+	tea.KeyDelete:   "\x1b[3~",
+	tea.KeyPgUp:     "\x1b[5~",
+	tea.KeyPgDown:   "\x1b[6~",
+	tea.KeyShiftTab: "\x1b[Z",
+}
+
+// What the terminal received for a key, as
+// far as we can tell from the key itself
+func keyInput(msg tea.KeyMsg) string {
+	input := ""
+
+	switch {
+	case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+		input = string(msg.Runes)
+	case msg.Type >= tea.KeyNull && msg.Type <= tea.KeyBackspace:
+		// control characters are their own code
+		input = string(rune(msg.Type))
+	default:
+		input = keySequences[msg.Type]
+	}
+
+	if msg.Alt && input != "" {
+		input = "\x1b" + input
+	}
+
+	return input
+}
+
+// ReplayCast plays the output of a recorded session at the given
+// speed, with pauses shortened to the idle time limit of the
+// recording, if it has one, or else to maxIdle (if not zero)
+func ReplayCast(r io.Reader, out io.Writer, speed float64, maxIdle time.Duration) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	if !scanner.Scan() {
+		return fmt.Errorf("the recording is empty")
+	}
+
+	header := castHeader{}
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Version != 2 {
+		return fmt.Errorf("not an asciicast v2 recording")
+	}
+
+	if header.IdleLimit > 0 {
+		maxIdle = time.Duration(header.IdleLimit * float64(time.Second))
+	}
+
+	last := 0.0
+	for n := 2; scanner.Scan(); n++ {
+		var event []any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || len(event) != 3 {
+			return fmt.Errorf("line %d: invalid event", n)
+		}
+
+		at, okAt := event[0].(float64)
+		kind, okKind := event[1].(string)
+		data, okData := event[2].(string)
+
+		if !okAt || !okKind || !okData {
+			return fmt.Errorf("line %d: invalid event", n)
+		}
+
+		if kind != "o" {
+			continue
+		}
+
+		pause := time.Duration((at - last) * float64(time.Second))
+		if maxIdle > 0 {
+			pause = min(pause, maxIdle)
+		}
+
+		time.Sleep(time.Duration(float64(pause) / speed))
+		last = at
+
+		if _, err := io.WriteString(out, data); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
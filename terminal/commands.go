@@ -0,0 +1,96 @@
+package terminal
+
+import (
+	"strings"
+
+	"github.com/abs-lang/abs/ast"
+	"github.com/abs-lang/abs/i18n"
+	"github.com/abs-lang/abs/lexer"
+	"github.com/abs-lang/abs/parser"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Meta-commands are colon-prefixed lines
+// (eg. ':watch x') that are handled by the
+// terminal itself rather than evaluated
+// as ABS code.
+type metaCommand func(m Model, args string) (Model, tea.Cmd)
+
+var metaCommands = map[string]metaCommand{
+	"watch":    Model.watch,
+	"examples": Model.showExamples,
+	"ast":      Model.showAst,
+	"hist":     Model.hist,
+	"export":   Model.export,
+	"load":     Model.load,
+	"save":     Model.save,
+	"env":      Model.showEnv,
+	"reset":    Model.reset,
+	"time":     Model.setTiming,
+	"jobs":     Model.showJobs,
+	"fg":       Model.foreground,
+}
+
+// :examples strings
+func (m Model) showExamples(topic string) (Model, tea.Cmd) {
+	line := m.currentLine()
+	m = m.clearInput()
+	statements, ok := examples[topic]
+
+	if !ok {
+		msg := i18n.Sprintf("available topics: %s", strings.Join(exampleTopics(), ", "))
+
+		if topic != "" {
+			msg = i18n.Sprintf("no examples about '%s', %s", topic, msg)
+		}
+
+		return m, tea.Println(line + "\n" + styleFaint.Render(msg))
+	}
+
+	lines := Lines{}
+	prompt := m.prompt()
+
+	for _, s := range statements {
+		lines.Add("  " + prompt + styleCode.Render(s))
+	}
+
+	return m, tea.Println(line + styleNestedContainer.Render(lines.Join()))
+}
+
+// :ast 1 + 2
+func (m Model) showAst(code string) (Model, tea.Cmd) {
+	line := m.currentLine()
+	m = m.clearInput()
+
+	if code == "" {
+		return m, tea.Println(line + "\n" + styleErr.Render(i18n.T("usage: :ast expr")))
+	}
+
+	p := parser.New(lexer.New(code))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		errors := renderParseErrors(p.Errors())
+		return m, tea.Println(line + "\n" + errors.Join())
+	}
+
+	return m, tea.Println(line + styleNestedContainer.Render(ast.Tree(program)))
+}
+
+func isMetaCommand(line string) bool {
+	return strings.HasPrefix(line, ":")
+}
+
+func (m Model) runMetaCommand(line string) (Model, tea.Cmd) {
+	name, args, _ := strings.Cut(strings.TrimPrefix(line, ":"), " ")
+	command, ok := metaCommands[name]
+
+	if !ok {
+		msg := m.currentLine() + "\n" + styleErr.Render(i18n.Sprintf("unknown command ':%s'", name))
+		m = m.clearInput()
+
+		return m, tea.Println(msg)
+	}
+
+	return command(m, strings.TrimSpace(args))
+}
@@ -0,0 +1,141 @@
+package terminal
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/abs-lang/abs/ast"
+	"github.com/abs-lang/abs/object"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The debug panel sits to the right of the prompt
+// (F12 toggles it, DEBUG=1 opens it at startup) and
+// shows what's in the environment, the AST of the
+// last evaluation and how long it took. It scrolls
+// with pgup / pgdown, independently from the prompt.
+func (m Model) toggleDebugPanel() Model {
+	m.debugOpen = !m.debugOpen
+
+	if m.debugOpen {
+		m.debugPane = viewport.New(0, 0)
+		m = m.refreshDebugPanel()
+	}
+
+	return m
+}
+
+func (m Model) scrollDebugPanel(msg tea.KeyMsg) (Model, bool) {
+	switch msg.Type {
+	case tea.KeyPgUp:
+		m.debugPane.HalfViewUp()
+	case tea.KeyPgDown:
+		m.debugPane.HalfViewDown()
+	default:
+		return m, false
+	}
+
+	return m, true
+}
+
+// Resizes the panel and updates its content,
+// eg. after an evaluation
+func (m Model) refreshDebugPanel() Model {
+	if !m.debugOpen {
+		return m
+	}
+
+	// on narrow terminals it spans the whole width
+	// (minus its border), under the prompt
+	m.debugPane.Width = m.terminalWidth()/2 - 2
+	if m.isNarrow() {
+		m.debugPane.Width = m.terminalWidth() - styleDebugPanel.GetHorizontalFrameSize()
+	}
+	m.debugPane.Height = m.terminalHeight()/2 - 2
+	m.debugPane.SetContent(m.debugContent())
+
+	return m
+}
+
+func (m Model) renderDebugPanel() string {
+	footer := styleFaint.Render(fmt.Sprintf("%3.f%% -- pgup/pgdown to scroll, F12 to close", m.debugPane.ScrollPercent()*100))
+
+	return styleDebugPanel.Render(m.debugPane.View() + "\n" + footer)
+}
+
+func (m Model) debugContent() string {
+	lines := Lines{}
+
+	lines.Add(styleDebugTitle.Render("ENVIRONMENT"))
+	keys := m.env.GetKeys()
+	width := 0
+	for _, k := range keys {
+		width = max(width, len(k))
+	}
+
+	for _, k := range keys {
+		v, _ := m.env.Get(k)
+		lines.Add(fmt.Sprintf("%-*s  %-9s %s", width, k, v.Type(), describeSize(v)))
+	}
+
+	if len(keys) == 0 {
+		lines.Add(styleFaint.Render("(empty)"))
+	}
+
+	lines.Add("")
+	lines.Add(styleDebugTitle.Render("LAST EVAL"))
+	if m.lastAST == nil {
+		lines.Add(styleFaint.Render("(nothing evaluated yet)"))
+	} else {
+		lines.Add(fmt.Sprintf("took %s", m.lastEvalTime.Round(time.Microsecond)))
+		lines.Add("")
+		lines.Add(ast.Tree(m.lastAST))
+	}
+
+	lines.Add("")
+	lines.Add(styleDebugTitle.Render("STATE"))
+	state := m.asMap()
+	for _, k := range slices.Sorted(maps.Keys(state)) {
+		lines.Add(fmt.Sprintf("%s: %v", k, state[k]))
+	}
+
+	return lines.Join()
+}
+
+// Sizes shown in the debug panel: the length of
+// strings and collections, the value of the rest
+func describeSize(o object.Object) string {
+	switch o := o.(type) {
+	case *object.String:
+		return fmt.Sprintf("%d chars", len(o.Value))
+	case *object.Array:
+		return fmt.Sprintf("%d items", len(o.Elements))
+	case *object.Hash:
+		return fmt.Sprintf("%d keys", len(o.Pairs))
+	case *object.Function, *object.Builtin:
+		return ""
+	}
+
+	s := o.Inspect()
+	if len(s) > 30 {
+		s = s[:27] + "..."
+	}
+
+	return s
+}
+
+// Lays the panel out next to what
+// the terminal would otherwise show
+func (m Model) withDebugPanel(view string) string {
+	if m.isNarrow() {
+		return lipgloss.JoinVertical(lipgloss.Left, view, m.renderDebugPanel())
+	}
+
+	left := lipgloss.NewStyle().Width(m.terminalWidth() - lipgloss.Width(m.renderDebugPanel())).Render(view)
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, m.renderDebugPanel())
+}
@@ -0,0 +1,124 @@
+package terminal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/abs-lang/abs/i18n"
+	"github.com/abs-lang/abs/object"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// How wide the docs panel gets at most
+const DOCS_PANEL_WIDTH = 72
+
+// How many examples the docs panel shows at most
+const DOCS_MAX_EXAMPLES = 3
+
+// The docs panel (F1 toggles it) sits below the suggestions,
+// showing the full docs of the one that's selected, which
+// the suggestions themselves truncate: what it works on, and
+// examples of how it's used. In accessibility mode the docs
+// are printed instead.
+func (m Model) toggleDocs() (Model, tea.Cmd) {
+	m.docsOpen = !m.docsOpen
+
+	if m.accessible && m.docsOpen {
+		if s, ok := m.selectedSuggestion(); ok {
+			docs := suggestionDocs(s)
+			return m, tea.Println(docs.Join())
+		}
+	}
+
+	return m, nil
+}
+
+func (m Model) selectedSuggestion() (Suggestion, bool) {
+	if !m.IsSuggesting() || m.suggestionsIndex < 0 {
+		return Suggestion{}, false
+	}
+
+	return m.suggestions[m.suggestionsIndex], true
+}
+
+func (m Model) renderDocsPanel(s Suggestion) string {
+	width := min(m.contentWidth()-2, DOCS_PANEL_WIDTH)
+
+	docs := suggestionDocs(s)
+
+	return styleDocsPanel.Width(width).Render(docs.Join())
+}
+
+// The docs of a suggestion: its name (or signature),
+// description, the types it works on and examples
+func suggestionDocs(s Suggestion) Lines {
+	lines := Lines{}
+	doc := s.Comment
+
+	switch o := s.Object.(type) {
+	case *object.Builtin:
+		lines.Add(styleDocsTitle.Render(s.Value))
+		doc = o.Doc
+
+		types := []string{}
+		for _, t := range o.Types {
+			types = append(types, strings.ToLower(t))
+		}
+
+		if len(types) > 0 {
+			doc += "\n" + styleFaint.Render(i18n.Sprintf("works on: %s", strings.Join(types, ", ")))
+		}
+	case *object.Function:
+		params := []string{}
+		for _, p := range o.Parameters {
+			params = append(params, p.String())
+		}
+
+		lines.Add(styleDocsTitle.Render(fmt.Sprintf("%s(%s)", s.Value, strings.Join(params, ", "))))
+		doc = ""
+		if o.Node != nil {
+			doc = o.Node.Doc
+		}
+	case nil:
+		lines.Add(styleDocsTitle.Render(s.Value))
+	default:
+		lines.Add(styleDocsTitle.Render(s.Value))
+		doc = o.Inspect()
+	}
+
+	if doc != "" {
+		lines.Add(doc)
+	}
+
+	if examples := examplesOf(s.Value); len(examples) > 0 {
+		lines.Add(styleFaint.Render(i18n.T("examples:")))
+
+		for _, e := range examples {
+			lines.Add("  " + styleCode.Render(e))
+		}
+	}
+
+	return lines
+}
+
+// Examples (see ':examples') calling
+// the function with the given name
+func examplesOf(name string) []string {
+	call := regexp.MustCompile(`(^|[^\w])` + regexp.QuoteMeta(name) + `\(`)
+	found := []string{}
+
+	for _, topic := range exampleTopics() {
+		for _, e := range examples[topic] {
+			if call.MatchString(e) {
+				found = append(found, e)
+			}
+
+			if len(found) == DOCS_MAX_EXAMPLES {
+				return found
+			}
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,136 @@
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The programs found in $PATH, cached as listing
+// them all takes a while: we look for them again
+// once $PATH, or one of its directories, changes
+var executables struct {
+	mu     sync.Mutex
+	path   string
+	mtimes map[string]time.Time
+	// names of the programs, sorted, along
+	// with where they're found
+	names []string
+	paths map[string]string
+}
+
+// Modification times of the directories in $PATH,
+// which change as programs are (un)installed
+func pathMtimes(path string) map[string]time.Time {
+	mtimes := map[string]time.Time{}
+
+	for _, dir := range filepath.SplitList(path) {
+		if info, err := os.Stat(dir); err == nil {
+			mtimes[dir] = info.ModTime()
+		}
+	}
+
+	return mtimes
+}
+
+// Whether a file can be run as a program: on windows
+// it depends on its extension (see PATHEXT), elsewhere
+// on its permissions
+func isExecutable(info os.FileInfo) bool {
+	if info.IsDir() {
+		return false
+	}
+
+	if runtime.GOOS != "windows" {
+		return info.Mode()&0111 != 0
+	}
+
+	exts := strings.ToLower(os.Getenv("PATHEXT"))
+	if exts == "" {
+		exts = ".com;.exe;.bat;.cmd"
+	}
+
+	return slices.Contains(strings.Split(exts, ";"), strings.ToLower(filepath.Ext(info.Name())))
+}
+
+// Lists the programs in $PATH, the first
+// directory a name is found in winning
+func findExecutables(path string) ([]string, map[string]string) {
+	names := []string{}
+	paths := map[string]string{}
+
+	for _, dir := range filepath.SplitList(path) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, e := range entries {
+			if _, ok := paths[e.Name()]; ok {
+				continue
+			}
+
+			// stat follows symlinks, which is
+			// how many programs are installed
+			info, err := os.Stat(filepath.Join(dir, e.Name()))
+			if err != nil || !isExecutable(info) {
+				continue
+			}
+
+			names = append(names, e.Name())
+			paths[e.Name()] = filepath.Join(dir, e.Name())
+		}
+	}
+
+	slices.Sort(names)
+
+	return names, paths
+}
+
+// Suggests the programs in $PATH
+// whose name starts with prefix
+func executableSuggestions(prefix string) []Suggestion {
+	executables.mu.Lock()
+	defer executables.mu.Unlock()
+
+	path := os.Getenv("PATH")
+	mtimes := pathMtimes(path)
+
+	if executables.names == nil || path != executables.path || !mapsEqual(mtimes, executables.mtimes) {
+		executables.names, executables.paths = findExecutables(path)
+		executables.path, executables.mtimes = path, mtimes
+	}
+
+	matches := []Suggestion{}
+	for _, name := range executables.names {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		matches = append(matches, NewSuggestion(name, SUGGESTION_EXECUTABLE, executables.paths[name]))
+
+		if len(matches) == PATH_MAX_SUGGESTIONS {
+			break
+		}
+	}
+
+	return matches
+}
+
+func mapsEqual(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		if !b[k].Equal(v) {
+			return false
+		}
+	}
+
+	return true
+}
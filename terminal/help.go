@@ -0,0 +1,148 @@
+package terminal
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/abs-lang/abs/evaluator"
+	"github.com/abs-lang/abs/i18n"
+	"github.com/abs-lang/abs/lexer"
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/parser"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Size we assume the terminal has
+// before we're told its actual one
+const defaultTerminalWidth = 80
+const defaultTerminalHeight = 24
+
+// A documented function, either a builtin
+// (eg. 'split') or a member of a stdlib
+// module (eg. 'util.memoize')
+type helpEntry struct {
+	name string
+	doc  string
+}
+
+// help split
+// help "http"
+func (m Model) helpSearch(query string) (Model, tea.Cmd) {
+	line := m.currentLine()
+	m = m.clearInput()
+
+	query = strings.Trim(strings.TrimSpace(query), `"'`)
+	matches := searchHelp(m.env, query)
+
+	if len(matches) == 0 {
+		return m, tea.Println(line + "\n" + styleFaint.Render(i18n.Sprintf("nothing found for '%s'", query)))
+	}
+
+	lines := Lines{}
+
+	for _, e := range matches {
+		lines.Add(styleSuggestions[SUGGESTION_FUNCTION].Render(e.name) + "  " + styleFaint.Render(e.doc))
+	}
+
+	// short results can simply be printed,
+	// long ones go in a pager (unless we're
+	// in accessibility mode)
+	if len(lines) < m.terminalHeight()-2 || m.accessible {
+		return m, tea.Println(line + styleNestedContainer.Render(lines.Join()))
+	}
+
+	pager := viewport.New(m.terminalWidth(), m.terminalHeight()-2)
+	pager.SetContent(lines.Join())
+	m.pager = &pager
+	m.in.Blur()
+
+	return m, tea.Println(line)
+}
+
+func (m Model) closePager() (Model, tea.Cmd) {
+	m.pager = nil
+	m.in.Focus()
+
+	return m, nil
+}
+
+func (m Model) renderPager() string {
+	footer := styleFaint.Render(i18n.Sprintf("%3.f%% -- ↑/↓ to scroll, q to quit", m.pager.ScrollPercent()*100))
+
+	return m.pager.View() + "\n" + footer
+}
+
+func (m Model) terminalWidth() int {
+	if m.width == 0 {
+		return defaultTerminalWidth
+	}
+
+	return m.width
+}
+
+func (m Model) terminalHeight() int {
+	if m.height == 0 {
+		return defaultTerminalHeight
+	}
+
+	return m.height
+}
+
+// Searches the docs of builtin functions and
+// stdlib modules for the given query: functions
+// named after it come first, then the ones whose
+// name contains it and, last, the ones whose docs
+// mention it.
+func searchHelp(env *object.Environment, query string) []helpEntry {
+	query = strings.ToLower(query)
+	exact := []helpEntry{}
+	byName := []helpEntry{}
+	byDoc := []helpEntry{}
+
+	for _, e := range helpEntries(env) {
+		switch {
+		case strings.ToLower(e.name) == query:
+			exact = append(exact, e)
+		case strings.Contains(strings.ToLower(e.name), query):
+			byName = append(byName, e)
+		case strings.Contains(strings.ToLower(e.doc), query):
+			byDoc = append(byDoc, e)
+		}
+	}
+
+	return slices.Concat(exact, byName, byDoc)
+}
+
+func helpEntries(env *object.Environment) []helpEntry {
+	entries := []helpEntry{}
+	fns := evaluator.GetFns()
+
+	for _, name := range slices.Sorted(maps.Keys(fns)) {
+		entries = append(entries, helpEntry{name, fns[name].Doc})
+	}
+
+	modules := stdlibModules()
+	slices.Sort(modules)
+
+	for _, module := range modules {
+		program := parser.New(lexer.New(fmt.Sprintf("require('@%s')", module))).ParseProgram()
+		hash, ok := evaluator.BeginEval(program, env, lexer.New("")).(*object.Hash)
+
+		if !ok {
+			continue
+		}
+
+		keys := slices.SortedFunc(maps.Keys(hash.Pairs), func(a, b object.HashKey) int {
+			return strings.Compare(a.Value, b.Value)
+		})
+
+		for _, k := range keys {
+			entries = append(entries, helpEntry{module + "." + k.Value, describe(hash.Pairs[k].Value)})
+		}
+	}
+
+	return entries
+}
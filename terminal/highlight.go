@@ -0,0 +1,121 @@
+package terminal
+
+import (
+	"strings"
+
+	"github.com/abs-lang/abs/lexer"
+	"github.com/abs-lang/abs/token"
+)
+
+// What a bit of code is, as far as
+// syntax highlighting is concerned
+type highlightClass int
+
+const (
+	HIGHLIGHT_PLAIN highlightClass = iota
+	HIGHLIGHT_KEYWORD
+	HIGHLIGHT_CONSTANT
+	HIGHLIGHT_NUMBER
+	HIGHLIGHT_STRING
+	HIGHLIGHT_COMMAND
+	HIGHLIGHT_FUNCTION
+	HIGHLIGHT_COMMENT
+)
+
+// Classifies every rune of the code through the lexer:
+// whatever sits in between tokens is either whitespace
+// or a comment.
+func highlightClasses(code []rune) []highlightClass {
+	classes := make([]highlightClass, len(code))
+	l := lexer.New(string(code))
+
+	tokens := []token.Token{}
+	ends := []int{}
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		tokens = append(tokens, tok)
+		ends = append(ends, l.CurrentPosition())
+	}
+
+	end := 0
+	for i, tok := range tokens {
+		start := max(tok.Position, end)
+		classifyGap(code, classes, end, min(start, len(code)))
+		end = min(max(ends[i], start), len(code))
+
+		class := HIGHLIGHT_PLAIN
+		switch tok.Type {
+		case token.IF, token.ELSE, token.RETURN, token.WHILE, token.FOR, token.IN, token.NOT_IN,
+			token.BREAK, token.CONTINUE, token.DEFER, token.FUNCTION:
+			class = HIGHLIGHT_KEYWORD
+		case token.TRUE, token.FALSE, token.NULL:
+			class = HIGHLIGHT_CONSTANT
+		case token.NUMBER, token.DURATION, token.SIZE:
+			class = HIGHLIGHT_NUMBER
+		case token.STRING:
+			class = HIGHLIGHT_STRING
+		case token.COMMAND:
+			class = HIGHLIGHT_COMMAND
+		case token.IDENT:
+			if i+1 < len(tokens) && tokens[i+1].Type == token.LPAREN {
+				class = HIGHLIGHT_FUNCTION
+			}
+		}
+
+		for j := start; j < end; j++ {
+			classes[j] = class
+		}
+	}
+
+	classifyGap(code, classes, end, len(code))
+
+	return classes
+}
+
+// Marks the comments (# or //) found in
+// between tokens, up to the end of the line
+func classifyGap(code []rune, classes []highlightClass, start int, end int) {
+	for i := start; i < end; i++ {
+		if code[i] != '#' && !(code[i] == '/' && i+1 < end && code[i+1] == '/') {
+			continue
+		}
+
+		for ; i < end && code[i] != '\n'; i++ {
+			classes[i] = HIGHLIGHT_COMMENT
+		}
+	}
+}
+
+// Highlight (code) returns the lines of the code, styled
+// for the terminal. Styles are dropped when the output
+// doesn't support colors (eg. it's piped or NO_COLOR is set).
+func Highlight(code string) []string {
+	runes := []rune(strings.TrimSuffix(code, "\n"))
+	classes := highlightClasses(runes)
+	lines := []string{}
+
+	var line strings.Builder
+	for i := 0; i < len(runes); {
+		if runes[i] == '\n' {
+			lines = append(lines, line.String())
+			line.Reset()
+			i++
+			continue
+		}
+
+		// Style runs of runes of the same class at once,
+		// never across lines so that each can stand alone
+		j := i
+		for j < len(runes) && runes[j] != '\n' && classes[j] == classes[i] {
+			j++
+		}
+
+		text := string(runes[i:j])
+		if style, ok := styleHighlight[classes[i]]; ok {
+			text = style.Render(text)
+		}
+		line.WriteString(text)
+		i = j
+	}
+
+	return append(lines, line.String())
+}
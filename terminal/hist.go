@@ -0,0 +1,277 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/abs-lang/abs/i18n"
+	"github.com/abs-lang/abs/util"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// How many entries ':hist' shows at once
+const HIST_MAX_ENTRIES = 15
+
+// State of a ':hist' session: entries of the history
+// are listed so that some can be selected, and re-run
+// as a single block.
+type historyBrowser struct {
+	// entries that can be picked, as indexes
+	// in the history, from the oldest one
+	entries  []int
+	cursor   int
+	selected map[int]bool
+}
+
+// Whether an entry of the history is ABS code, rather
+// than something for the REPL itself (eg. ':ast x' or
+// 'help split'), so that it can be re-run or exported
+func isScriptable(entry string) bool {
+	entry = strings.TrimSpace(entry)
+
+	return entry != "" && !isMetaCommand(entry) && entry != "help" && entry != "quit" && !strings.HasPrefix(entry, "help ")
+}
+
+// The entries of the history that are ABS code,
+// as indexes in the history
+func scriptableHistory(history []string) []int {
+	entries := []int{}
+
+	for i, entry := range history {
+		if isScriptable(entry) {
+			entries = append(entries, i)
+		}
+	}
+
+	return entries
+}
+
+// Parses a selection of entries of the history, either
+// "last N" or their numbers (eg. "3 5-7", or "3,5-7"),
+// as shown by ':hist', into indexes in the history
+func parseHistorySelection(history []string, selection string) ([]int, error) {
+	entries := scriptableHistory(history)
+	fields := strings.Fields(strings.ReplaceAll(selection, ",", " "))
+
+	if len(fields) == 2 && fields[0] == "last" {
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf(i18n.T("'%s' isn't a valid number of entries"), fields[1])
+		}
+
+		return entries[max(0, len(entries)-n):], nil
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%s", i18n.T("no entries given"))
+	}
+
+	indexes := []int{}
+	for _, field := range fields {
+		from, to, isRange := strings.Cut(field, "-")
+		start, err := strconv.Atoi(from)
+		end := start
+
+		if err == nil && isRange {
+			end, err = strconv.Atoi(to)
+		}
+
+		if err != nil || start > end {
+			return nil, fmt.Errorf(i18n.T("'%s' isn't a valid entry (eg. 3, 5-7 or last 10)"), field)
+		}
+
+		for n := start; n <= end; n++ {
+			if n < 1 || n > len(history) || !isScriptable(history[n-1]) {
+				return nil, fmt.Errorf(i18n.T("there's no entry %d in the history"), n)
+			}
+
+			indexes = append(indexes, n-1)
+		}
+	}
+
+	return indexes, nil
+}
+
+// Joins entries of the history into a block of code
+func historyBlock(history []string, indexes []int) string {
+	code := []string{}
+
+	for _, i := range indexes {
+		code = append(code, history[i])
+	}
+
+	return strings.Join(code, "\n")
+}
+
+// :hist or :hist 3 5-7
+func (m Model) hist(selection string) (Model, tea.Cmd) {
+	line := m.currentLine()
+	m = m.clearInput()
+
+	if selection != "" {
+		indexes, err := parseHistorySelection(m.history, selection)
+		if err != nil {
+			return m, tea.Println(line + "\n" + styleErr.Render(err.Error()))
+		}
+
+		return m.runHistory(indexes, tea.Println(line))
+	}
+
+	entries := scriptableHistory(m.history)
+
+	if len(entries) == 0 {
+		return m, tea.Println(line + "\n" + styleFaint.Render(i18n.T("the history is empty")))
+	}
+
+	// screen readers can't follow a list that's
+	// redrawn in place, so we print the entries
+	if m.accessible {
+		lines := Lines{}
+		for _, i := range entries[max(0, len(entries)-HIST_MAX_ENTRIES):] {
+			lines.Add(fmt.Sprintf("%d: %s", i+1, m.history[i]))
+		}
+		lines.Add(i18n.T("run some of them with ':hist 3 5-7'"))
+
+		return m, tea.Println(line + "\n" + lines.Join())
+	}
+
+	m.browsing = &historyBrowser{entries: entries, cursor: len(entries) - 1, selected: map[int]bool{}}
+	m.in.Blur()
+
+	return m, tea.Println(line)
+}
+
+// Re-runs entries of the history as a single block,
+// which makes it to the history itself
+func (m Model) runHistory(indexes []int, print tea.Cmd) (Model, tea.Cmd) {
+	block := historyBlock(m.history, indexes)
+	m = m.appendHistory(block)
+	m = m.resetInput()
+	m = m.setInput(block)
+
+	m, eval := m.eval()
+
+	if print == nil {
+		return m, eval
+	}
+
+	return m, tea.Sequence(print, eval)
+}
+
+func (m Model) onBrowseHistory(msg tea.KeyMsg) (Model, tea.Cmd) {
+	b := m.browsing
+
+	switch msg.String() {
+	case "up", "k":
+		b.cursor = max(0, b.cursor-1)
+	case "down", "j":
+		b.cursor = min(len(b.entries)-1, b.cursor+1)
+	case " ":
+		i := b.entries[b.cursor]
+		b.selected[i] = !b.selected[i]
+	case "enter":
+		// Without a selection, we run the
+		// entry under the cursor
+		indexes := []int{}
+		for _, i := range b.entries {
+			if b.selected[i] {
+				indexes = append(indexes, i)
+			}
+		}
+
+		if len(indexes) == 0 {
+			indexes = append(indexes, b.entries[b.cursor])
+		}
+
+		m.browsing = nil
+		return m.runHistory(indexes, nil)
+	case "q", "esc", "ctrl+c":
+		m.browsing = nil
+		m.in.Focus()
+	}
+
+	return m, nil
+}
+
+// Renders the entries around the cursor, with multi-line
+// ones shown on a single line
+func (m Model) renderHistoryBrowser() string {
+	b := m.browsing
+	lines := Lines{}
+	height := min(HIST_MAX_ENTRIES, max(1, m.terminalHeight()-3))
+	offset := min(max(0, b.cursor-height/2), max(0, len(b.entries)-height))
+	width := len(strconv.Itoa(b.entries[len(b.entries)-1] + 1))
+
+	for c := offset; c < len(b.entries) && c < offset+height; c++ {
+		i := b.entries[c]
+		mark := "[ ]"
+		if b.selected[i] {
+			mark = "[x]"
+		}
+
+		entry := fmt.Sprintf("%*d %s %s", width, i+1, mark, strings.ReplaceAll(m.history[i], "\n", " ↵ "))
+
+		if c == b.cursor {
+			lines.Add(styleSelectedPrefix.Render(" → ") + styleSelectedSuggestion.Render(entry))
+			continue
+		}
+
+		lines.Add("   " + entry)
+	}
+
+	lines.Add(styleFaint.Render("   " + i18n.T("↑/↓ to move, space to select, enter to run, q to quit")))
+
+	return styleSuggestion.Render(lines.Join())
+}
+
+// :export last 10 > setup.abs
+func (m Model) export(args string) (Model, tea.Cmd) {
+	line := m.currentLine()
+	m = m.clearInput()
+	usage := styleErr.Render(i18n.T("usage: :export last 10 > file.abs (or >> to append)"))
+
+	selection, file, found := strings.Cut(args, ">")
+	appending := strings.HasPrefix(file, ">")
+	file = strings.TrimSpace(strings.TrimPrefix(file, ">"))
+
+	if !found || file == "" {
+		return m, tea.Println(line + "\n" + usage)
+	}
+
+	// the ':export' itself is in the history already,
+	// but it isn't ABS code so it won't be exported
+	indexes, err := parseHistorySelection(m.history, selection)
+	if err != nil {
+		return m, tea.Println(line + "\n" + styleErr.Render(err.Error()))
+	}
+
+	path, err := util.ExpandPath(file)
+	if err == nil {
+		err = writeScript(path, historyBlock(m.history, indexes), appending)
+	}
+
+	if err != nil {
+		return m, tea.Println(line + "\n" + styleErr.Render(err.Error()))
+	}
+
+	return m, tea.Println(line + "\n" + styleFaint.Render(i18n.Sprintf("exported %d entries to %s", len(indexes), file)))
+}
+
+// Writes code to a script, which gets a shebang
+// (and can be executed) unless we append to it
+func writeScript(path string, code string, appending bool) error {
+	if appending {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = f.WriteString(code + "\n")
+		return err
+	}
+
+	return os.WriteFile(path, []byte("#!/usr/bin/env abs\n"+code+"\n"), 0755)
+}
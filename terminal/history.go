@@ -3,9 +3,13 @@ package terminal
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/abs-lang/abs/i18n"
 	"github.com/abs-lang/abs/object"
 	"github.com/abs-lang/abs/util"
 )
@@ -18,15 +22,36 @@ Support for abs history file in the interactive REPL:
    Default ABS_HISTORY_FILE is "~/.abs_history".
 2) Append each non-null, unique next line passed from prompt to the executor() to the local history.
    NB. the live prompt history shows duplicate next lines, but they are not saved to the local history.
-3) Save the local history whenever the prompt.Run() loop exits (^D) or the executor() exits (on quit).
-   Write the local history to the ABS_HISTORY_FILE up to ABS_MAX_HISTORY_LINES (default 1000 lines).
+3) Append each entry to the ABS_HISTORY_FILE as soon as it's run, rather than saving the
+   whole local history on quit, so that REPLs running side by side don't clobber each
+   other's entries: the file holds the entries of every session, in the order they ran.
+4) Trim the ABS_HISTORY_FILE to ABS_MAX_HISTORY_LINES (default 1000 lines) on quit.
 
 Note that ABS_HISTORY_FILE and ABS_MAX_HISTORY_LINES variables may come from the OS environment.
+
+With ABS_HISTORY_SCOPE=project, a .abs_history file found in the current working directory
+is used instead of ABS_HISTORY_FILE, so that the history of different projects isn't mixed up.
+
+Entries spanning multiple lines are saved with each line, but the last one, terminated
+by a backslash, so that they can be told apart from separate entries:
+
+	if x {\
+	  echo(x)\
+	}
+
+Entries are saved along with the time they were run at, in zsh's extended history
+format (": <unix time>:0;<entry>"), while entries from history files written before
+timestamps were introduced, or by hand, are read as they are and have no time:
+
+	: 1717171717:0;echo("hello")
+	ls -la
 */
 
 const (
-	ABS_HISTORY_FILE      = "~/.abs_history"
-	ABS_MAX_HISTORY_LINES = "1000"
+	ABS_HISTORY_FILE         = "~/.abs_history"
+	ABS_MAX_HISTORY_LINES    = "1000"
+	ABS_HISTORY_SCOPE        = "global"
+	ABS_PROJECT_HISTORY_FILE = ".abs_history"
 )
 
 // Expand full path to ABS_HISTORY_FILE for current user and get ABS_MAX_HISTORY_LINES
@@ -53,15 +78,42 @@ func getHistoryConfiguration(env *object.Environment) (string, int) {
 		}
 		historyFile = filePath
 	}
+	// ABS_HISTORY_SCOPE
+	scope := util.GetEnvVar(env, "ABS_HISTORY_SCOPE", ABS_HISTORY_SCOPE)
+	switch scope {
+	case "global":
+	case "project":
+		historyFile = projectHistoryFile(historyFile)
+	default:
+		fmt.Printf("ABS_HISTORY_SCOPE must be either global or project: %s; using default: %s\n", scope, ABS_HISTORY_SCOPE)
+	}
 	return historyFile, maxLines
 }
 
-// getHistory - read the history file and split it into the local history[...] slice
-func getHistory(historyFile string, maxLines int) []string {
+// projectHistoryFile - the .abs_history file of the current working directory,
+// if there's one, or the global history file
+func projectHistoryFile(globalFile string) string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return globalFile
+	}
+
+	projectFile := filepath.Join(dir, ABS_PROJECT_HISTORY_FILE)
+	if info, err := os.Stat(projectFile); err != nil || info.IsDir() {
+		return globalFile
+	}
+
+	return projectFile
+}
+
+// getHistory - read the history file and split it into the local history[...] slice,
+// along with the time each entry was run at (zero if unknown)
+func getHistory(historyFile string, maxLines int) ([]string, []time.Time) {
 	var history []string
+	var times []time.Time
 	if maxLines == 0 {
 		// do not open a history file for zero max lines
-		return history
+		return history, times
 	}
 	// verify the expanded historyFile exists, if not create it now
 	fd, ok := os.OpenFile(historyFile, os.O_RDONLY|os.O_CREATE, 0666)
@@ -73,13 +125,94 @@ func getHistory(historyFile string, maxLines int) []string {
 	// read the file and split the lines into history[...]
 	bytes, err := os.ReadFile(historyFile)
 	if err != nil {
-		return history
+		return history, times
 	}
 	// fill the local history from the file
 	if len(bytes) > 0 {
-		history = strings.Split(string(bytes), "\n")
+		history, times = decodeHistory(string(bytes))
 	}
-	return history
+	return history, times
+}
+
+// The timestamp of an entry, in zsh's extended history format
+var historyTimestamp = regexp.MustCompile(`^: (\d+):\d+;`)
+
+// Backslashes lines end with are doubled in the history
+// file, so that a line ending with an odd number of them
+// is continued on the next one, see encodeHistory
+func escapeTrailingBackslashes(line string) string {
+	trimmed := strings.TrimRight(line, "\\")
+	return line + line[len(trimmed):]
+}
+
+// Reads a line of the history file, telling whether
+// the entry it's part of continues on the next one
+func unescapeTrailingBackslashes(line string) (string, bool) {
+	trimmed := strings.TrimRight(line, "\\")
+	n := len(line) - len(trimmed)
+
+	return trimmed + strings.Repeat("\\", n/2), n%2 == 1
+}
+
+// decodeHistory - split the content of the history file into entries,
+// joining lines terminated by a backslash with the following ones,
+// and strip the timestamps entries start with, if any
+func decodeHistory(content string) ([]string, []time.Time) {
+	var history []string
+	var times []time.Time
+	entry := []string{}
+
+	add := func(lines []string) {
+		at := time.Time{}
+		if match := historyTimestamp.FindStringSubmatch(lines[0]); match != nil {
+			seconds, _ := strconv.ParseInt(match[1], 10, 64)
+			at = time.Unix(seconds, 0)
+			lines[0] = lines[0][len(match[0]):]
+		}
+
+		history = append(history, strings.Join(lines, "\n"))
+		times = append(times, at)
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		line, continued := unescapeTrailingBackslashes(line)
+		if continued {
+			entry = append(entry, line)
+			continue
+		}
+
+		add(append(entry, line))
+		entry = []string{}
+	}
+
+	// the file ended with a continuation
+	if len(entry) > 0 {
+		add(entry)
+	}
+
+	return history, times
+}
+
+// encodeHistory - join history entries into the content of the history file,
+// terminating every line but the last one of multi-line entries with a backslash,
+// and prefixing entries with the time they were run at, when known. Backslashes
+// lines really end with are doubled, so that they don't read as continuations.
+func encodeHistory(history []string, times []time.Time) string {
+	entries := make([]string, len(history))
+
+	for i, entry := range history {
+		lines := strings.Split(entry, "\n")
+		for j, line := range lines {
+			lines[j] = escapeTrailingBackslashes(line)
+		}
+		entries[i] = strings.Join(lines, "\\\n")
+
+		if i < len(times) && !times[i].IsZero() {
+			entries[i] = fmt.Sprintf(": %d:0;%s", times[i].Unix(), entries[i])
+		}
+	}
+
+	return strings.Join(entries, "\n")
 }
 
 // addToHistory - append unique next line to local history[...]
@@ -100,17 +233,121 @@ func addToHistory(history []string, maxLines int, line string) []string {
 	return history
 }
 
+// appendToHistoryFile - append an entry, run at the given time, to historyFile
+func appendToHistoryFile(historyFile string, entry string, at time.Time) error {
+	f, err := os.OpenFile(historyFile, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0664)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	content := encodeHistory([]string{entry}, []time.Time{at})
+
+	// entries are separated, rather than terminated,
+	// by newlines, unless the file was edited by hand
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			content = "\n" + content
+		}
+	}
+
+	_, err = f.WriteString(content)
+	return err
+}
+
+// trimHistory - drop the oldest entries of historyFile beyond maxLines,
+// including the ones appended by other sessions in the meantime
+func trimHistory(historyFile string, maxLines int) error {
+	if maxLines == 0 {
+		return nil
+	}
+
+	bytes, err := os.ReadFile(historyFile)
+	if err != nil {
+		return err
+	}
+
+	history, times := decodeHistory(string(bytes))
+	if len(history) <= maxLines {
+		return nil
+	}
+
+	// write and rename, so that a session appending
+	// to the file meanwhile doesn't find it half-written
+	tmp := fmt.Sprintf("%s.%d.tmp", historyFile, os.Getpid())
+	if err := saveHistory(tmp, maxLines, history, times); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, historyFile)
+}
+
 // saveHistory - save the local history containing maxLines to historyFile
-func saveHistory(historyFile string, maxLines int, history []string) error {
+func saveHistory(historyFile string, maxLines int, history []string, times []time.Time) error {
 	if maxLines == 0 {
 		// do not save a history file for zero max lines
 		return nil
 	}
 	if len(history) > maxLines {
 		// remove the excess lines from the front of the history slice
-		history = history[len(history)-maxLines:]
+		excess := len(history) - maxLines
+		history = history[excess:]
+		times = times[min(excess, len(times)):]
 	}
 	// write the augmented local history back out to the file
-	historyStr := strings.Join(history, "\n")
+	historyStr := encodeHistory(history, times)
 	return os.WriteFile(historyFile, []byte(historyStr), 0664)
 }
+
+// appendHistory - append an entry to the history, and to the history file,
+// along with the time it was run at, unless it's the same as the previous entry
+func (m Model) appendHistory(entry string) Model {
+	if len(m.history) > 0 && m.history[len(m.history)-1] == entry {
+		return m
+	}
+
+	at := time.Now()
+	if m.historyFile != "" && m.historyMaxLInes != 0 {
+		if err := appendToHistoryFile(m.historyFile, entry, at); err != nil {
+			m.historyErr = err
+		}
+	}
+
+	// entries the history was loaded with
+	// might not have a time, so we line up
+	// times with entries before appending
+	for len(m.historyTimes) < len(m.history) {
+		m.historyTimes = append(m.historyTimes, time.Time{})
+	}
+
+	m.history = append(m.history, entry)
+	m.historyTimes = append(m.historyTimes[:len(m.history)-1], at)
+
+	return m
+}
+
+// historyTime - when an entry of the history was run, if known
+func (m Model) historyTime(i int) time.Time {
+	if i < 0 || i >= len(m.historyTimes) {
+		return time.Time{}
+	}
+
+	return m.historyTimes[i]
+}
+
+// relativeTime - how long ago something happened, eg. "5m ago"
+func relativeTime(at time.Time, now time.Time) string {
+	elapsed := now.Sub(at)
+
+	switch {
+	case elapsed < time.Minute:
+		return i18n.T("just now")
+	case elapsed < time.Hour:
+		return i18n.Sprintf("%dm ago", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return i18n.Sprintf("%dh ago", int(elapsed.Hours()))
+	default:
+		return i18n.Sprintf("%dd ago", int(elapsed.Hours()/24))
+	}
+}
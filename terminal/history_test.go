@@ -0,0 +1,398 @@
+package terminal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHistoryRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history")
+	history := []string{
+		"1 + 1",
+		"if true {\n  echo(1)\n}",
+		"echo(\"a\\nb\")",
+		"x = 1",
+	}
+
+	if err := saveHistory(file, 10, history, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := getHistory(file, 10)
+
+	if !slices.Equal(got, history) {
+		t.Fatalf("history didn't survive a round trip: got %q exp %q", got, history)
+	}
+
+	content, _ := os.ReadFile(file)
+	exp := "1 + 1\nif true {\\\n  echo(1)\\\n}\necho(\"a\\nb\")\nx = 1"
+
+	if string(content) != exp {
+		t.Fatalf("wrong history file: got %q exp %q", content, exp)
+	}
+}
+
+func TestHistoryTrailingBackslashRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history")
+	history := []string{
+		`echo "a\\"`,
+		`cd C:\`,
+		"x = 1\\\ny = 2\\",
+		"x = 1",
+	}
+
+	if err := saveHistory(file, 10, history, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := getHistory(file, 10); !slices.Equal(got, history) {
+		t.Fatalf("history didn't survive a round trip: got %q exp %q", got, history)
+	}
+}
+
+func TestDecodeHistory(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected []string
+	}{
+		{"a\nb", []string{"a", "b"}},
+		{"a\\\nb\nc", []string{"a\nb", "c"}},
+		{"a\\", []string{"a"}},
+		{"a\\\\\nb", []string{"a\\", "b"}},
+		{"a\\\\\\\nb", []string{"a\\\nb"}},
+		{"", []string{""}},
+	}
+
+	for _, tt := range tests {
+		if got, _ := decodeHistory(tt.content); !slices.Equal(got, tt.expected) {
+			t.Fatalf("decoding %q: got %q exp %q", tt.content, got, tt.expected)
+		}
+	}
+}
+
+func TestHistoryTimestamps(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history")
+	at := time.Unix(1717171717, 0)
+	history := []string{"ls", "if x {\n  1\n}", "x = 1"}
+
+	if err := saveHistory(file, 10, history, []time.Time{{}, at, at}); err != nil {
+		t.Fatal(err)
+	}
+
+	content, _ := os.ReadFile(file)
+	exp := "ls\n: 1717171717:0;if x {\\\n  1\\\n}\n: 1717171717:0;x = 1"
+
+	if string(content) != exp {
+		t.Fatalf("wrong history file: got %q exp %q", content, exp)
+	}
+
+	got, times := getHistory(file, 10)
+
+	if !slices.Equal(got, history) {
+		t.Fatalf("history didn't survive a round trip: got %q exp %q", got, history)
+	}
+
+	if !times[0].IsZero() || !times[1].Equal(at) || !times[2].Equal(at) {
+		t.Fatalf("wrong times: %v", times)
+	}
+
+	// duplicates of the previous entry
+	// aren't added to the history
+	m := Model{history: got, historyTimes: times}
+	m = m.appendHistory("x = 1")
+	m = m.appendHistory("y = 2")
+	m = m.appendHistory("y = 2")
+
+	if !slices.Equal(m.history, append(history, "y = 2")) || len(m.historyTimes) != 4 || m.historyTime(3).IsZero() {
+		t.Fatalf("wrong history: %q %v", m.history, m.historyTimes)
+	}
+}
+
+func TestConcurrentHistory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history")
+	os.WriteFile(file, []byte("old\n"), 0664)
+
+	// two sessions running side by side
+	history, times := getHistory(file, 3)
+	a := Model{history: history, historyTimes: times, historyFile: file, historyMaxLInes: 3}
+	b := a
+
+	a = a.appendHistory("a = 1")
+	b = b.appendHistory("b = 1")
+	a = a.appendHistory("if a {\n  a\n}")
+
+	got, _ := getHistory(file, 3)
+	exp := []string{"old", "a = 1", "b = 1", "if a {\n  a\n}"}
+
+	if !slices.Equal(got, exp) {
+		t.Fatalf("sessions clobbered each other: got %q exp %q", got, exp)
+	}
+
+	// quitting trims the file, keeping the
+	// most recent entries of both sessions
+	a.quit()
+	b.quit()
+
+	got, _ = getHistory(file, 3)
+	if !slices.Equal(got, exp[1:]) {
+		t.Fatalf("wrong trimmed history: got %q exp %q", got, exp[1:])
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Now()
+	tests := map[time.Duration]string{
+		10 * time.Second: "just now",
+		5 * time.Minute:  "5m ago",
+		3 * time.Hour:    "3h ago",
+		50 * time.Hour:   "2d ago",
+	}
+
+	for elapsed, expected := range tests {
+		if got := relativeTime(now.Add(-elapsed), now); got != expected {
+			t.Fatalf("%s ago: got %q exp %q", elapsed, got, expected)
+		}
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query     string
+		candidate string
+		ok        bool
+		positions []int
+	}{
+		{"", "anything", true, []int{}},
+		{"gst", "git status", true, []int{0, 4, 5}},
+		{"ls", "a lot; ls -la", true, []int{7, 8}},
+		{"LS", "ls", false, nil},
+		{"Ls", "echo(Ls)", true, []int{5, 6}},
+		{"xyz", "git status", false, nil},
+		{"stg", "git status", false, nil},
+	}
+
+	for _, tt := range tests {
+		_, positions, ok := fuzzyMatch(tt.query, tt.candidate)
+		if ok != tt.ok || (ok && !slices.Equal(positions, tt.positions)) {
+			t.Fatalf("fuzzyMatch(%q, %q): expected %v %v, got %v %v", tt.query, tt.candidate, tt.ok, tt.positions, ok, positions)
+		}
+	}
+}
+
+func TestSearchHistory(t *testing.T) {
+	history := []string{
+		"git status",
+		"echo(\"good stuff\")",
+		"x = `git stash`",
+		"git status",
+		"1 + 1",
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		// exact runs rank first, recent entries win ties, no duplicates
+		{"git st", []string{"git status", "x = `git stash`"}},
+		{"gst", []string{"git status", "x = `git stash`", "echo(\"good stuff\")"}},
+		{"", []string{"1 + 1", "git status", "x = `git stash`", "echo(\"good stuff\")"}},
+		{"nothing", []string{}},
+	}
+
+	for _, tt := range tests {
+		got := []string{}
+		for _, match := range searchHistory(history, tt.query) {
+			got = append(got, history[match.index])
+		}
+
+		if !slices.Equal(got, tt.expected) {
+			t.Fatalf("searchHistory(%q): expected %q, got %q", tt.query, tt.expected, got)
+		}
+	}
+
+	m := Model{history: history, searchText: textinput.New(), in: textinput.New()}
+	m = m.search()
+	m.searchText.SetValue("gst")
+	m = m.search()
+
+	if !m.isSearching || m.in.Value() != "git status" {
+		t.Fatalf("expected the best match to be selected, got %q", m.in.Value())
+	}
+
+	if m = m.advanceSearch(+1); m.in.Value() != "x = `git stash`" {
+		t.Fatalf("expected ctrl+r to select the next match, got %q", m.in.Value())
+	}
+
+	if m = m.advanceSearch(-1).advanceSearch(-1); m.in.Value() != "echo(\"good stuff\")" {
+		t.Fatalf("expected up to wrap around to the last match, got %q", m.in.Value())
+	}
+
+	if !strings.Contains(m.View(), "good stuff") || !strings.Contains(m.View(), "git stash") {
+		t.Fatalf("expected the matches to be listed, got:\n%s", m.View())
+	}
+
+	if m = m.selectSearch(); m.isSearching || m.in.Value() != "echo(\"good stuff\")" {
+		t.Fatalf("expected the selected match to be kept in the input, got %q", m.in.Value())
+	}
+}
+
+func TestHistorySelection(t *testing.T) {
+	history := []string{"x = 1", ":ast x", "y = x + 1", "help split", "echo(y)", ":export last 2 > a.abs"}
+
+	tests := []struct {
+		selection string
+		expected  []int
+		err       string
+	}{
+		{"last 2", []int{2, 4}, ""},
+		{"last 10", []int{0, 2, 4}, ""},
+		{"1 3", []int{0, 2}, ""},
+		{"3-5", nil, "there's no entry 4 in the history"},
+		{"1,5", []int{0, 4}, ""},
+		{"2", nil, "there's no entry 2 in the history"},
+		{"last x", nil, "'x' isn't a valid number of entries"},
+		{"5-3", nil, "'5-3' isn't a valid entry (eg. 3, 5-7 or last 10)"},
+		{"", nil, "no entries given"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseHistorySelection(history, tt.selection)
+
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("parseHistorySelection(%q): expected error %q, got %v", tt.selection, tt.err, err)
+			}
+			continue
+		}
+
+		if err != nil || !slices.Equal(got, tt.expected) {
+			t.Fatalf("parseHistorySelection(%q): expected %v, got %v (%v)", tt.selection, tt.expected, got, err)
+		}
+	}
+
+	file := filepath.Join(t.TempDir(), "setup.abs")
+	m := Model{history: history, prompt: func() string { return "> " }, in: textinput.New(), searchText: textinput.New(), stdinInput: textinput.New()}
+
+	m.export("last 2 > " + file)
+	m.export("1 >> " + file)
+
+	content, _ := os.ReadFile(file)
+	if string(content) != "#!/usr/bin/env abs\ny = x + 1\necho(y)\nx = 1\n" {
+		t.Fatalf("unexpected exported script: %q", content)
+	}
+
+	m.history = append(m.history, ":hist")
+	m, _ = m.hist("")
+
+	if m.browsing == nil || m.browsing.cursor != 2 {
+		t.Fatalf("expected :hist to browse the history from its last entry")
+	}
+
+	for _, key := range []tea.KeyType{tea.KeySpace, tea.KeyUp, tea.KeyUp, tea.KeySpace} {
+		m, _ = m.onBrowseHistory(tea.KeyMsg{Type: key, Runes: []rune{' '}})
+	}
+
+	if view := m.View(); !strings.Contains(view, "5 [x] echo(y)") || !strings.Contains(view, "1 [x] x = 1") {
+		t.Fatalf("expected the selected entries to be marked, got:\n%s", view)
+	}
+
+	m.env = object.NewEnvironment(&object.Stdio{Stdout: bufio.NewReadWriter(nil, bufio.NewWriter(io.Discard))}, ".", "test", false)
+	m, _ = m.onBrowseHistory(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.browsing != nil || !m.isEvaluating || m.history[len(m.history)-1] != "x = 1\necho(y)" {
+		t.Fatalf("expected the selected entries to run as a block, got %q", m.history[len(m.history)-1])
+	}
+}
+
+func TestHistoryScope(t *testing.T) {
+	env := object.NewEnvironment(&object.Stdio{}, ".", "test", false)
+	global := filepath.Join(t.TempDir(), "global_history")
+	project := t.TempDir()
+	t.Setenv("ABS_HISTORY_FILE", global)
+	t.Chdir(project)
+
+	tests := []struct {
+		scope    string
+		local    bool
+		expected string
+	}{
+		{"", true, global},
+		{"global", true, global},
+		{"project", false, global},
+		{"project", true, filepath.Join(project, ".abs_history")},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("ABS_HISTORY_SCOPE", tt.scope)
+		os.Remove(filepath.Join(project, ".abs_history"))
+
+		if tt.local {
+			os.WriteFile(filepath.Join(project, ".abs_history"), []byte("x = 1"), 0664)
+		}
+
+		if file, _ := getHistoryConfiguration(env); file != tt.expected {
+			t.Fatalf("scope '%s' (local file: %v): expected %s, got %s", tt.scope, tt.local, tt.expected, file)
+		}
+	}
+}
+
+func TestSessionCommands(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	env := object.NewEnvironment(&object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}, ".", "test", false)
+	dir := t.TempDir()
+	lib := filepath.Join(dir, "lib.abs")
+	os.WriteFile(lib, []byte("double = f(x) { x * 2 }\n"), 0644)
+
+	// the first entry comes from a previous session
+	m := Model{env: env, history: []string{"old = 1"}, sessionStart: 1, baseline: snapshotEnv(env), prompt: func() string { return "> " }, keys: newKeymap(nil), in: textinput.New(), searchText: textinput.New(), stdinInput: textinput.New()}
+	m.in.Focus()
+
+	run := func(code string) {
+		m = m.setInput(code)
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+
+		if m.isEvaluating {
+			updated, _ = m.Update(evalResult(cmd))
+			m = updated.(Model)
+		}
+	}
+
+	run(":load " + lib)
+	run("y = double(21)")
+	run(":ast y")
+
+	if y, _ := env.Get("y"); y == nil || y.Inspect() != "42" {
+		t.Fatalf("expected :load to evaluate the file in the environment, got %v", y)
+	}
+
+	session := filepath.Join(dir, "session.abs")
+	run(":save " + session)
+
+	content, _ := os.ReadFile(session)
+	expected := fmt.Sprintf("#!/usr/bin/env abs\nsource(%q)\ny = double(21)\n", lib)
+	if string(content) != expected {
+		t.Fatalf("expected :save to write the session, got %q", content)
+	}
+
+	run(":reset")
+
+	if _, ok := env.Get("y"); ok {
+		t.Fatalf("expected :reset to clear the environment")
+	}
+
+	if v, _ := env.Get("ABS_VERSION"); v == nil || v.Inspect() != "test" {
+		t.Fatalf("expected :reset to keep the bindings the REPL started with, got %v", v)
+	}
+}
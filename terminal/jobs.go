@@ -0,0 +1,186 @@
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abs-lang/abs/i18n"
+	"github.com/abs-lang/abs/object"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// An evaluation sent to the background (ctrl+z),
+// so that we can keep using the REPL while it runs.
+// Jobs are numbered as in shells ([1], [2]...),
+// listed through ':jobs' and brought back to the
+// foreground through ':fg'.
+type job struct {
+	id int
+	// the evaluation that's running, see doneEval
+	eval   int
+	code   string
+	start  time.Time
+	cancel context.CancelFunc
+}
+
+// Evaluations share the state of the evaluator (the code
+// errors point to, whether they were interrupted, the
+// sandbox...), so only one runs at a time: while a job runs
+// in the background we can edit code, list jobs and so on,
+// but code is only evaluated once the job is done or back
+// in the foreground. The code typed is left in the input.
+func (m Model) jobRunning() (Model, tea.Cmd) {
+	j := m.jobs[len(m.jobs)-1]
+	msg := i18n.Sprintf("[%d] is still running: wait for it to be done, or bring it back with :fg", j.id)
+
+	return m, tea.Println(styleErr.Render(msg))
+}
+
+// The job running an evaluation, if it's in the background
+func (m Model) job(eval int) *job {
+	for _, j := range m.jobs {
+		if j.eval == eval {
+			return j
+		}
+	}
+
+	return nil
+}
+
+// What the code prints is streamed as long
+// as code runs, in the foreground or not
+func (m Model) streamOutput() {
+	if m.output != nil {
+		m.output.stream(m.isEvaluating || len(m.jobs) > 0)
+	}
+}
+
+// Sends the code being evaluated to the background
+// and gives the prompt back, eg. "[1] `sleep 10`"
+func (m Model) background() (Model, tea.Cmd) {
+	id := 1
+	for _, j := range m.jobs {
+		id = max(id, j.id+1)
+	}
+
+	j := &job{id, m.evalID, m.input(), m.evalStart, m.cancelEval}
+	m.jobs = append(slices.Clone(m.jobs), j)
+
+	lines := Lines{}
+	if !m.echoed {
+		lines.Add(m.currentLine())
+	}
+	lines = append(lines, m.stdinLines...)
+	m.stdinLines = Lines{}
+	lines.Add(styleFaint.Render(fmt.Sprintf("[%d] %s", j.id, j.code)))
+
+	m.isEvaluating = false
+	m.cancelEval = nil
+	m.stdinInput.Blur()
+	m.stdinInput.Reset()
+	m.in.Focus()
+	m = m.clearInput()
+
+	return m, lines.Dump()
+}
+
+// Tells we're done with a job, along with its result
+func (m Model) onJobDone(j *job, res doneEval) (Model, tea.Cmd) {
+	m.jobs = slices.DeleteFunc(slices.Clone(m.jobs), func(other *job) bool {
+		return other == j
+	})
+	m.streamOutput()
+
+	lines := Lines{}
+	lines.Add(styleFaint.Render(i18n.Sprintf("[%d] done: %s", j.id, j.code)))
+
+	if len(res.parseErrors) > 0 {
+		lines = append(lines, renderParseErrors(res.parseErrors)...)
+	}
+
+	// whatever's left to print is the job's, unless
+	// the foreground is printing something as well
+	if !m.isEvaluating {
+		if b, _ := io.ReadAll(m.env.Stdio.Stdout); len(b) > 0 {
+			lines.Add(strings.TrimSuffix(string(b), "\n"))
+		}
+	}
+
+	if res.out != object.NULL {
+		lines.Add(m.renderResult(res.out, res.ok))
+	}
+
+	if m.timing {
+		lines.Add(renderTiming(res))
+	}
+
+	return m, lines.Dump()
+}
+
+// :jobs
+func (m Model) showJobs(string) (Model, tea.Cmd) {
+	line := m.currentLine()
+	m = m.clearInput()
+
+	if len(m.jobs) == 0 {
+		return m, tea.Println(line + "\n" + styleFaint.Render(i18n.T("no jobs")))
+	}
+
+	lines := Lines{}
+	for _, j := range m.jobs {
+		running := i18n.Sprintf("running for %s", time.Since(j.start).Round(time.Second))
+		lines.Add(fmt.Sprintf("[%d] %s  %s", j.id, j.code, styleFaint.Render(running)))
+	}
+
+	return m, tea.Println(line + styleNestedContainer.Render(lines.Join()))
+}
+
+// :fg 1 (or the last job sent to the background)
+func (m Model) foreground(args string) (Model, tea.Cmd) {
+	line := m.currentLine()
+	m = m.clearInput()
+
+	var j *job
+	if len(m.jobs) == 0 {
+		return m, tea.Println(line + "\n" + styleErr.Render(i18n.T("no jobs")))
+	}
+
+	if args == "" {
+		j = m.jobs[len(m.jobs)-1]
+	} else if id, err := strconv.Atoi(strings.TrimPrefix(args, "%")); err == nil {
+		i := slices.IndexFunc(m.jobs, func(j *job) bool { return j.id == id })
+		if i >= 0 {
+			j = m.jobs[i]
+		}
+	}
+
+	if j == nil {
+		return m, tea.Println(line + "\n" + styleErr.Render(i18n.Sprintf("no such job: %s", args)))
+	}
+
+	m.jobs = slices.DeleteFunc(slices.Clone(m.jobs), func(other *job) bool {
+		return other == j
+	})
+
+	// the evaluation is still waited on, and it's
+	// the one in the foreground again once it's done
+	m.isEvaluating = true
+	m.evalID = j.eval
+	m.cancelEval = j.cancel
+	m.echoed = false
+	m = m.setInput(j.code)
+	m.in.Blur()
+	m.stdinInput.Reset()
+	m.stdinInput.Focus()
+	m.streamOutput()
+
+	m, progress := m.startProgress()
+	m.evalStart = j.start
+
+	return m, tea.Batch(tea.Println(line), progress)
+}
@@ -0,0 +1,143 @@
+package terminal
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/abs-lang/abs/object"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Actions of the REPL that can be bound to keys
+const (
+	ACTION_SUBMIT       = "submit"
+	ACTION_SUGGEST      = "suggest"
+	ACTION_SEARCH       = "search"
+	ACTION_HISTORY_PREV = "history-prev"
+	ACTION_HISTORY_NEXT = "history-next"
+	ACTION_CLEAR        = "clear"
+	ACTION_INTERRUPT    = "interrupt"
+	ACTION_BACKGROUND   = "background"
+	ACTION_QUIT         = "quit"
+	ACTION_DEBUG        = "debug"
+	ACTION_DOCS         = "docs"
+)
+
+// Keys bound to actions by default, as named
+// by bubbletea (eg. "ctrl+r", "up" or "f12")
+var defaultKeymap = map[string][]string{
+	ACTION_SUBMIT:       {"enter"},
+	ACTION_SUGGEST:      {"tab"},
+	ACTION_SEARCH:       {"ctrl+r"},
+	ACTION_HISTORY_PREV: {"up"},
+	ACTION_HISTORY_NEXT: {"down"},
+	ACTION_CLEAR:        {"ctrl+l"},
+	ACTION_INTERRUPT:    {"ctrl+c"},
+	ACTION_BACKGROUND:   {"ctrl+z"},
+	ACTION_QUIT:         {"esc", "ctrl+d"},
+	ACTION_DEBUG:        {"f12"},
+	ACTION_DOCS:         {"f1"},
+}
+
+// Maps keys to the actions they trigger
+type keymap map[string]string
+
+// The action a key triggers, if any
+func (k keymap) action(msg tea.KeyMsg) string {
+	if k == nil {
+		k = newKeymap(nil)
+	}
+
+	return k[msg.String()]
+}
+
+// The keys bound to an action, sorted
+func (k keymap) keysFor(action string) []string {
+	if k == nil {
+		k = newKeymap(nil)
+	}
+
+	keys := []string{}
+	for key, a := range k {
+		if a == action {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// Builds a keymap from the default one, overridden by
+// the given bindings: an action bound to keys loses
+// its default ones, and a key bound to an action no
+// longer triggers the one it did by default
+func newKeymap(bindings map[string][]string) keymap {
+	k := keymap{}
+
+	for action, keys := range defaultKeymap {
+		if _, ok := bindings[action]; ok {
+			continue
+		}
+
+		for _, key := range keys {
+			k[key] = action
+		}
+	}
+
+	for action, keys := range bindings {
+		for _, key := range keys {
+			k[key] = action
+		}
+	}
+
+	return k
+}
+
+// Reads the keymap from ABS_KEYMAP, either a hash set in
+// the ABS init file (eg. {"search": "ctrl+f", "quit": ["ctrl+q"]})
+// or an OS environment variable (eg. "search=ctrl+f,quit=ctrl+q")
+func getKeymap(env *object.Environment) (keymap, error) {
+	bindings := map[string][]string{}
+
+	if v, ok := env.Get("ABS_KEYMAP"); ok {
+		hash, ok := v.(*object.Hash)
+		if !ok {
+			return newKeymap(nil), fmt.Errorf("ABS_KEYMAP must be a hash, got %s", v.Type())
+		}
+
+		for _, pair := range hash.Pairs {
+			switch keys := pair.Value.(type) {
+			case *object.String:
+				bindings[pair.Key.Inspect()] = []string{keys.Value}
+			case *object.Array:
+				for _, key := range keys.Elements {
+					bindings[pair.Key.Inspect()] = append(bindings[pair.Key.Inspect()], key.Inspect())
+				}
+			default:
+				return newKeymap(nil), fmt.Errorf("ABS_KEYMAP: keys of '%s' must be a string or an array, got %s", pair.Key.Inspect(), pair.Value.Type())
+			}
+		}
+	} else if v := os.Getenv("ABS_KEYMAP"); v != "" {
+		for _, binding := range strings.Split(v, ",") {
+			action, key, ok := strings.Cut(binding, "=")
+			if !ok {
+				return newKeymap(nil), fmt.Errorf("ABS_KEYMAP: '%s' should be in the form action=key", binding)
+			}
+
+			action = strings.TrimSpace(action)
+			bindings[action] = append(bindings[action], strings.TrimSpace(key))
+		}
+	}
+
+	for action := range bindings {
+		if _, ok := defaultKeymap[action]; !ok {
+			return newKeymap(nil), fmt.Errorf("ABS_KEYMAP: unknown action '%s' (available: %s)", action, strings.Join(slices.Sorted(maps.Keys(defaultKeymap)), ", "))
+		}
+	}
+
+	return newKeymap(bindings), nil
+}
@@ -0,0 +1,45 @@
+package terminal
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Below this width the debug panel doesn't fit
+// next to the prompt, and goes under it instead
+const DEBUG_PANEL_MIN_WIDTH = 100
+
+// Resizes whatever depends on the size of the
+// terminal, as soon as it changes
+func (m Model) resize(width int, height int) Model {
+	m.width, m.height = width, height
+	m = m.refreshDebugPanel()
+	m.searchText.Width = max(0, m.contentWidth()-styleSearch.GetHorizontalFrameSize()-lipgloss.Width(m.searchText.Prompt)-1)
+
+	return m
+}
+
+// Whether the debug panel is stacked under the prompt
+func (m Model) isNarrow() bool {
+	return m.terminalWidth() < DEBUG_PANEL_MIN_WIDTH
+}
+
+// The width the prompt, suggestions and search
+// results can take, which is all of the terminal
+// unless the debug panel sits next to them
+func (m Model) contentWidth() int {
+	if !m.debugOpen || m.isNarrow() {
+		return m.terminalWidth()
+	}
+
+	return m.terminalWidth() - lipgloss.Width(m.renderDebugPanel())
+}
+
+// Cuts a (styled) line so that it fits in width
+func fitWidth(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	return ansi.Truncate(s, width, "...")
+}
@@ -0,0 +1,116 @@
+package terminal
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The input is a single line, so code spanning multiple
+// lines -- pasted, or recalled from the history -- is kept
+// in a block shown in its place, and evaluated as a whole
+// on enter. Terminals tell pastes apart from typing through
+// bracketed paste, so newlines within them aren't enters.
+
+// The code the user is about to run
+func (m Model) input() string {
+	if m.block != "" {
+		return m.block
+	}
+
+	return m.in.Value()
+}
+
+// Puts code in the input, or in a block if
+// it spans multiple lines
+func (m Model) setInput(code string) Model {
+	if strings.Contains(code, "\n") {
+		m.block = code
+		m.in.SetValue("")
+
+		return m
+	}
+
+	m.block = ""
+	m.in.SetValue(code)
+	m.in.CursorEnd()
+
+	return m
+}
+
+func (m Model) clearInput() Model {
+	m.block = ""
+	m.in.Reset()
+
+	return m
+}
+
+func isMultilinePaste(msg tea.KeyMsg) bool {
+	return msg.Paste && strings.ContainsAny(string(msg.Runes), "\r\n")
+}
+
+// Pasted code, with the newlines of
+// all platforms and no trailing ones
+func pastedCode(msg tea.KeyMsg) string {
+	code := strings.ReplaceAll(string(msg.Runes), "\r\n", "\n")
+	code = strings.ReplaceAll(code, "\r", "\n")
+
+	return strings.TrimRight(code, "\n")
+}
+
+// Whether keys should be kept away from the input,
+// as they go to the block instead
+func (m Model) isMultiline(msg tea.KeyMsg) bool {
+	return m.block != "" || isMultilinePaste(msg)
+}
+
+// Handles multi-line pastes, and what's typed while a block is
+// shown, returning whether the key was handled: enter, ctrl+c
+// and the other keys bound to actions are left to the keymap
+func (m Model) onMultilineKey(msg tea.KeyMsg) (Model, bool) {
+	if m.block == "" {
+		if !isMultilinePaste(msg) {
+			return m, false
+		}
+
+		// the paste goes where the cursor is
+		value := []rune(m.in.Value())
+		pos := m.in.Position()
+
+		return m.setInput(string(value[:pos]) + pastedCode(msg) + string(value[pos:])), true
+	}
+
+	switch {
+	case msg.Paste:
+		return m.setInput(m.block + pastedCode(msg)), true
+	case msg.Type == tea.KeyBackspace:
+		block := []rune(m.block)
+		return m.setInput(string(block[:len(block)-1])), true
+	case m.keys.action(msg) != "":
+		return m, false
+	case msg.Type == tea.KeyRunes, msg.Type == tea.KeySpace:
+		return m.setInput(m.block + string(msg.Runes)), true
+	}
+
+	return m, true
+}
+
+// Renders the lines of the block, the first one after
+// the prompt and the others aligned with it
+func (m Model) renderBlock() string {
+	prompt := m.prompt()
+	indent := strings.Repeat(" ", lipgloss.Width(prompt))
+	lines := Lines{}
+
+	for i, line := range strings.Split(m.block, "\n") {
+		if i == 0 {
+			lines.Add(prompt + line)
+			continue
+		}
+
+		lines.Add(indent + line)
+	}
+
+	return lines.Join()
+}
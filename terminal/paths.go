@@ -0,0 +1,159 @@
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/abs-lang/abs/util"
+)
+
+// How many paths are suggested at most,
+// eg. when completing in /usr/bin
+const PATH_MAX_SUGGESTIONS = 50
+
+// Characters after which a command runs
+// another program, eg. `ls | grep x
+const commandSeparators = "|;&("
+
+// The word being typed within a string or a command
+type literal struct {
+	word string
+	// whether we're within a command and, if so,
+	// whether the word is the program it runs (eg.
+	// `gr or `ls | gr) rather than an argument
+	command    bool
+	executable bool
+}
+
+// When the input ends within a string or a command (eg.
+// `ls ./sr), returns the word being typed in it, the way
+// bash does for the arguments of a command
+func literalWord(input string) (literal, bool) {
+	runes := []rune(input)
+	quote := rune(0)
+	start := 0
+
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+
+		switch {
+		case quote == 0 && c == '#':
+			// the rest of the line is a comment
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case quote == 0 && (c == '"' || c == '\'' || c == '`'):
+			quote, start = c, i+1
+		case quote == 0 && c == '$' && i+1 < len(runes) && runes[i+1] == '(':
+			// $(command) runs till the end of the line
+			quote, start = '$', i+2
+			i++
+		case quote == '$':
+			if c == '\n' {
+				quote = 0
+			}
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		}
+	}
+
+	if quote == 0 {
+		return literal{}, false
+	}
+
+	content := runes[start:]
+	command := quote == '`' || quote == '$'
+	from := 0
+
+	// spaces within commands can be escaped,
+	// eg. `ls my\ folder, while pipes and the
+	// like separate words as well, eg. `ls|gr
+	for i := 0; i < len(content); i++ {
+		if command && content[i] == '\\' {
+			i++
+			continue
+		}
+
+		if unicode.IsSpace(content[i]) || (command && strings.ContainsRune(commandSeparators, content[i])) {
+			from = i + 1
+		}
+	}
+
+	l := literal{word: string(content[from:]), command: command}
+
+	if command {
+		before := strings.TrimSpace(string(content[:from]))
+		l.executable = before == "" || strings.ContainsRune(commandSeparators, rune(before[len(before)-1]))
+	}
+
+	return l, true
+}
+
+// Suggests what the word being typed in a string or
+// command could be completed with: the programs in
+// $PATH when it's the name of the program to run,
+// paths otherwise
+func literalSuggestions(l literal) []Suggestion {
+	if l.executable && !strings.ContainsAny(l.word, `/\`) {
+		return executableSuggestions(l.word)
+	}
+
+	return pathSuggestions(l.word, l.command)
+}
+
+// Suggests the files and directories a path
+// could be completed with, relative to the
+// current directory unless it's absolute
+func pathSuggestions(word string, command bool) []Suggestion {
+	path := word
+	if command {
+		path = strings.ReplaceAll(path, `\ `, " ")
+	}
+
+	dir, prefix := filepath.Split(path)
+	expanded, err := util.ExpandPath(dir)
+	if err != nil {
+		return nil
+	}
+
+	if expanded == "" {
+		expanded = "."
+	}
+
+	entries, err := os.ReadDir(expanded)
+	if err != nil {
+		return nil
+	}
+
+	matches := []Suggestion{}
+	for _, e := range entries {
+		name := e.Name()
+
+		// hidden files only come up
+		// when asked for, as in bash
+		if !strings.HasPrefix(name, prefix) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".")) {
+			continue
+		}
+
+		value := dir + name
+		if info, err := os.Stat(filepath.Join(expanded, name)); err == nil && info.IsDir() {
+			value += "/"
+		}
+
+		if command {
+			value = strings.ReplaceAll(value, " ", `\ `)
+		}
+
+		matches = append(matches, NewSuggestion(value, SUGGESTION_PATH, ""))
+
+		if len(matches) == PATH_MAX_SUGGESTIONS {
+			break
+		}
+	}
+
+	return matches
+}
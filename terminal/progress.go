@@ -0,0 +1,65 @@
+package terminal
+
+import (
+	"strings"
+	"time"
+
+	"github.com/abs-lang/abs/i18n"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Evaluations that take longer than this get
+// a spinner next to the prompt, so that quick
+// ones don't make it flicker
+const PROGRESS_DELAY = 300 * time.Millisecond
+
+// time to show how the evaluation is going
+type progressDue struct{}
+
+// Starts the spinner shown while evaluating
+func (m Model) startProgress() (Model, tea.Cmd) {
+	m.evalStart = time.Now()
+
+	// screen readers would read the spinner out
+	// at every frame: the hint is enough, we only
+	// need to redraw once it's due
+	if m.accessible {
+		return m, tea.Tick(PROGRESS_DELAY, func(time.Time) tea.Msg {
+			return progressDue{}
+		})
+	}
+
+	m.spinner = spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(styleFaint))
+
+	return m, m.spinner.Tick
+}
+
+// Moves the spinner on, till the evaluation is done
+func (m Model) onSpinnerTick(msg spinner.TickMsg) (Model, tea.Cmd) {
+	if !m.isEvaluating {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+
+	return m, cmd
+}
+
+// The spinner, how long the evaluation has been
+// running and how to stop it, eg. "⣾ 3.2s (ctrl+c to abort)"
+func (m Model) renderProgress(now time.Time) string {
+	elapsed := now.Sub(m.evalStart)
+	if elapsed < PROGRESS_DELAY {
+		return ""
+	}
+
+	hint := i18n.Sprintf("%s to abort", strings.Join(m.keys.keysFor(ACTION_INTERRUPT), "/"))
+
+	if m.accessible {
+		return styleFaint.Render(i18n.Sprintf("running (%s)", hint))
+	}
+
+	return m.spinner.View() + styleFaint.Render(" "+elapsed.Round(100*time.Millisecond).String()+" ("+hint+")")
+}
@@ -0,0 +1,187 @@
+package terminal
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/abs-lang/abs/i18n"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// How many history entries are shown
+// below the reverse search input
+const SEARCH_MAX_RESULTS = 8
+
+// A history entry matching the reverse search
+type searchMatch struct {
+	// index of the entry in the history
+	index int
+	score int
+	// runes of the entry matching the query,
+	// to highlight them
+	positions []int
+}
+
+// Matches the query against a candidate (fzf-style): every
+// rune of the query has to appear in the candidate, in order,
+// though not necessarily next to each other. Runes matched
+// right after the previous one, or at the start of a word,
+// score higher, while gaps in between matches score lower.
+// Matching ignores case, unless the query has uppercase runes.
+func fuzzyMatch(query string, candidate string) (int, []int, bool) {
+	q := []rune(query)
+	c := []rune(candidate)
+
+	if len(q) == 0 {
+		return 0, []int{}, true
+	}
+
+	if !strings.ContainsFunc(query, unicode.IsUpper) {
+		for i := range q {
+			q[i] = unicode.ToLower(q[i])
+		}
+		for i := range c {
+			c[i] = unicode.ToLower(c[i])
+		}
+	}
+
+	best, bestPositions, found := 0, []int{}, false
+
+	// Try every place the first rune matches at, as
+	// matching greedily from the first one could miss
+	// a tighter match later on (eg. "ls" in "a lot; ls")
+	for start := range c {
+		if c[start] != q[0] {
+			continue
+		}
+
+		score, positions, ok := fuzzyMatchFrom(q, c, start)
+		if ok && (!found || score > best) {
+			best, bestPositions, found = score, positions, true
+		}
+	}
+
+	return best, bestPositions, found
+}
+
+func fuzzyMatchFrom(q []rune, c []rune, start int) (int, []int, bool) {
+	positions := []int{}
+	score := 0
+	qi := 0
+
+	for ci := start; ci < len(c) && qi < len(q); ci++ {
+		if c[ci] != q[qi] {
+			continue
+		}
+
+		score += 1
+
+		if ci == 0 || !isWordRune(c[ci-1]) {
+			score += 3
+		}
+
+		if len(positions) > 0 {
+			prev := positions[len(positions)-1]
+
+			if prev == ci-1 {
+				score += 5
+			} else {
+				score -= min(ci-prev-1, 5)
+			}
+		}
+
+		positions = append(positions, ci)
+		qi++
+	}
+
+	return score, positions, qi == len(q)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// Ranks the history entries matching the query, best
+// first: entries scoring the same are sorted from the
+// most recent one, and duplicates only show up once.
+// With no query, the most recent entries are returned.
+func searchHistory(history []string, query string) []searchMatch {
+	matches := []searchMatch{}
+	seen := map[string]bool{}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		if seen[history[i]] || history[i] == "" {
+			continue
+		}
+		seen[history[i]] = true
+
+		if score, positions, ok := fuzzyMatch(query, history[i]); ok {
+			matches = append(matches, searchMatch{i, score, positions})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b searchMatch) int {
+		return b.score - a.score
+	})
+
+	return matches
+}
+
+// Renders the results of the reverse search, with the
+// selected one pointed to and matching runes highlighted.
+// Only a window of results, around the selected one, is
+// shown; multi-line entries are shown on a single line,
+// followed by how long ago they were run, when known.
+func (m Model) renderSearchResults() string {
+	lines := Lines{}
+	offset := max(0, m.searchPosition-SEARCH_MAX_RESULTS+1)
+	width := m.contentWidth() - styleSuggestion.GetHorizontalFrameSize()
+
+	for i := offset; i < len(m.searchMatches) && i < offset+SEARCH_MAX_RESULTS; i++ {
+		match := m.searchMatches[i]
+		entry := []rune(m.history[match.index])
+		highlighted := map[int]bool{}
+		for _, p := range match.positions {
+			highlighted[p] = true
+		}
+
+		style, prefix := styleSearchResult, "   "
+		if i == m.searchPosition {
+			style, prefix = styleSelectedSuggestion, styleSelectedPrefix.Render(" → ")
+		}
+
+		// the time goes after the entry, which
+		// is cut to fit the terminal
+		suffix := ""
+		if at := m.historyTime(match.index); !at.IsZero() {
+			suffix = styleFaint.Render("  " + relativeTime(at, time.Now()))
+		}
+
+		// Style runs of (un)matched runes at once
+		var line strings.Builder
+		for j := 0; j < len(entry); {
+			k := j
+			for k < len(entry) && highlighted[k] == highlighted[j] {
+				k++
+			}
+
+			s := strings.ReplaceAll(string(entry[j:k]), "\n", " ↵ ")
+			if highlighted[j] {
+				line.WriteString(styleSearchMatch.Render(s))
+			} else {
+				line.WriteString(style.Render(s))
+			}
+			j = k
+		}
+
+		lines.Add(fitWidth(prefix+line.String(), width-lipgloss.Width(suffix)) + suffix)
+	}
+
+	if len(m.searchMatches) > SEARCH_MAX_RESULTS {
+		lines.Add(styleFaint.Render("   " + i18n.Sprintf("%d of %d matches", m.searchPosition+1, len(m.searchMatches))))
+	}
+
+	return styleSuggestion.Render(lines.Join())
+}
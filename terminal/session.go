@@ -0,0 +1,134 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/abs-lang/abs/i18n"
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/util"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// How long values listed by ':env' can be
+const ENV_MAX_VALUE_LENGTH = 60
+
+// :load file.abs
+func (m Model) load(file string) (Model, tea.Cmd) {
+	if file == "" {
+		line := m.currentLine()
+		m = m.clearInput()
+
+		return m, tea.Println(line + "\n" + styleErr.Render(i18n.T("usage: :load file.abs")))
+	}
+
+	path, err := util.ExpandPath(file)
+	code := []byte{}
+	if err == nil {
+		code, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		line := m.currentLine()
+		m = m.clearInput()
+
+		return m, tea.Println(line + "\n" + styleErr.Render(err.Error()))
+	}
+
+	// the ':load' line stays in the input, so
+	// that it's printed along with the output
+	return m.evalCode(string(code))
+}
+
+// The code typed in this session, where the
+// files loaded through ':load' are sourced
+func (m Model) sessionCode() []string {
+	code := []string{}
+
+	for _, entry := range m.history[m.sessionStart:] {
+		if file, ok := strings.CutPrefix(entry, ":load "); ok {
+			code = append(code, fmt.Sprintf("source(%s)", strconv.Quote(strings.TrimSpace(file))))
+			continue
+		}
+
+		if isScriptable(entry) {
+			code = append(code, entry)
+		}
+	}
+
+	return code
+}
+
+// :save session.abs
+func (m Model) save(file string) (Model, tea.Cmd) {
+	line := m.currentLine()
+	m = m.clearInput()
+
+	if file == "" {
+		return m, tea.Println(line + "\n" + styleErr.Render(i18n.T("usage: :save file.abs")))
+	}
+
+	code := m.sessionCode()
+	path, err := util.ExpandPath(file)
+	if err == nil {
+		err = writeScript(path, strings.Join(code, "\n"), false)
+	}
+
+	if err != nil {
+		return m, tea.Println(line + "\n" + styleErr.Render(err.Error()))
+	}
+
+	return m, tea.Println(line + "\n" + styleFaint.Render(i18n.Sprintf("saved %d entries to %s", len(code), file)))
+}
+
+// :env
+func (m Model) showEnv(string) (Model, tea.Cmd) {
+	line := m.currentLine()
+	m = m.clearInput()
+	lines := Lines{}
+
+	for _, name := range m.env.GetKeys() {
+		value, _ := m.env.Get(name)
+		desc, _, _ := strings.Cut(describe(value), "\n")
+
+		if len([]rune(desc)) > ENV_MAX_VALUE_LENGTH {
+			desc = string([]rune(desc)[:ENV_MAX_VALUE_LENGTH]) + "..."
+		}
+
+		lines.Add(fmt.Sprintf("%s %s", styleCode.Render(name), styleFaint.Render(desc)))
+	}
+
+	return m, tea.Println(line + styleNestedContainer.Render(lines.Join()))
+}
+
+// :reset
+func (m Model) reset(string) (Model, tea.Cmd) {
+	line := m.currentLine()
+	m = m.clearInput()
+
+	// we go back to the environment the
+	// REPL started with, eg. after the
+	// ABS init file was evaluated
+	for _, name := range m.env.GetKeys() {
+		m.env.Delete(name)
+	}
+
+	for name, value := range m.baseline {
+		m.env.Set(name, value)
+	}
+
+	return m, tea.Println(line + "\n" + styleFaint.Render(i18n.T("the environment has been reset")))
+}
+
+// The bindings of the environment
+func snapshotEnv(env *object.Environment) map[string]object.Object {
+	bindings := map[string]object.Object{}
+
+	for _, name := range env.GetKeys() {
+		bindings[name], _ = env.Get(name)
+	}
+
+	return bindings
+}
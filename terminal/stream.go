@@ -0,0 +1,163 @@
+package terminal
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Stdout (and stderr) of the code we evaluate. While
+// streaming, the terminal is told as soon as a line is
+// complete, so that code that runs for a while (eg.
+// following logs) shows what it prints as it goes,
+// rather than once it's done. Whatever isn't streamed
+// is read as a whole, eg. once the evaluation is done.
+type outputStream struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+	// how we tell the terminal lines are ready
+	send      func(tea.Msg)
+	streaming bool
+	// whether the terminal has been told
+	// already, and not read the lines yet
+	notified bool
+}
+
+// lines written to the output are ready to be printed
+type outputReady struct{}
+
+func (s *outputStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	n, err := s.buf.Write(p)
+	notify := s.streaming && !s.notified && s.send != nil && bytes.IndexByte(p, '\n') >= 0
+	if notify {
+		s.notified = true
+	}
+	s.mu.Unlock()
+
+	// the terminal reads the lines while handling
+	// the message, so we can't hold the lock
+	if notify {
+		s.send(outputReady{})
+	}
+
+	return n, err
+}
+
+func (s *outputStream) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buf.Read(p)
+}
+
+func (s *outputStream) stream(on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.streaming = on
+	s.notified = false
+}
+
+// Takes the lines that are complete, leaving
+// the last one in the buffer if it's not
+func (s *outputStream) lines() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.notified = false
+	i := bytes.LastIndexByte(s.buf.Bytes(), '\n')
+	if i < 0 {
+		return ""
+	}
+
+	return strings.TrimSuffix(string(s.buf.Next(i+1)), "\n")
+}
+
+// Prints what the code being evaluated wrote so far,
+// after the code itself (and what was typed on stdin)
+// or, for jobs, as it comes
+func (m Model) onOutputReady() (Model, tea.Cmd) {
+	if (!m.isEvaluating && len(m.jobs) == 0) || m.output == nil {
+		return m, nil
+	}
+
+	text := m.output.lines()
+	if text == "" {
+		return m, nil
+	}
+
+	// jobs in the background print above the prompt
+	lines := Lines{}
+	if m.isEvaluating {
+		if !m.echoed {
+			m.echoed = true
+			lines.Add(m.currentLine())
+		}
+		lines = append(lines, m.stdinLines...)
+		m.stdinLines = Lines{}
+	}
+	lines.Add(text)
+
+	return m, lines.Dump()
+}
+
+// Stdin of the code we evaluate: the lines typed while
+// it runs are written to it as they're submitted, and
+// ctrl+d on an empty line ends the input (reading it
+// returns io.EOF), as in a shell. Reading then carries
+// on with the lines typed afterwards, eg. by the next
+// evaluation.
+type inputStream struct {
+	mu    sync.Mutex
+	ready *sync.Cond
+	buf   bytes.Buffer
+	// EOFs sent that haven't been read yet
+	eofs int
+}
+
+func newInputStream() *inputStream {
+	s := &inputStream{}
+	s.ready = sync.NewCond(&s.mu)
+
+	return s
+}
+
+func (s *inputStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, err := s.buf.Write(p)
+	s.ready.Broadcast()
+
+	return n, err
+}
+
+// Blocks till something's typed
+func (s *inputStream) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.buf.Len() == 0 && s.eofs == 0 {
+		s.ready.Wait()
+	}
+
+	if s.buf.Len() > 0 {
+		return s.buf.Read(p)
+	}
+
+	s.eofs--
+	return 0, io.EOF
+}
+
+// Ends the input, once what was typed so far is read
+func (s *inputStream) sendEOF() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.eofs++
+	s.ready.Broadcast()
+}
@@ -5,9 +5,20 @@ import "github.com/charmbracelet/lipgloss"
 // ANSI color codes
 // https://raw.githubusercontent.com/fidian/ansi/master/images/color-codes.png
 
-var styleDebug = lipgloss.NewStyle().
-	Faint(true).
-	Foreground(lipgloss.Color("11"))
+var styleDebugPanel = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("11")).
+	PaddingLeft(1)
+var styleDebugTitle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11"))
+
+var stylePrompt = lipgloss.NewStyle().Foreground(lipgloss.Color("#4287f5"))
+
+var styleDocsPanel = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("39")).
+	PaddingLeft(1).
+	PaddingRight(1)
+var styleDocsTitle = lipgloss.NewStyle().Bold(true)
 
 var styleFaint = lipgloss.NewStyle().Faint(true)
 
@@ -22,6 +33,8 @@ var styleSuggestions = map[suggestionType]lipgloss.Style{
 	SUGGESTION_FUNCTION:   lipgloss.NewStyle().Foreground(lipgloss.Color("39")),
 	SUGGESTION_IDENTIFIER: lipgloss.NewStyle().Foreground(lipgloss.Color("220")),
 	SUGGESTION_PROPERTY:   lipgloss.NewStyle().Foreground(lipgloss.Color("14")),
+	SUGGESTION_PATH:       lipgloss.NewStyle().Foreground(lipgloss.Color("75")),
+	SUGGESTION_EXECUTABLE: styleCode,
 }
 var styleSelectedSuggestion = lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Underline(true)
 var styleSelectedPrefix = styleSelectedSuggestion.Underline(false)
@@ -29,3 +42,18 @@ var styleSelectedPrefix = styleSelectedSuggestion.Underline(false)
 var styleSearch = styleSuggestion
 var styleSearchPrompt = lipgloss.NewStyle().Foreground(lipgloss.Color("178")).Faint(true)
 var styleSearchText = styleCode
+var styleSearchResult = lipgloss.NewStyle()
+var styleSearchMatch = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+
+var styleDiffAdded = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+var styleDiffRemoved = styleErr
+
+var styleHighlight = map[highlightClass]lipgloss.Style{
+	HIGHLIGHT_KEYWORD:  lipgloss.NewStyle().Foreground(lipgloss.Color("205")),
+	HIGHLIGHT_CONSTANT: lipgloss.NewStyle().Foreground(lipgloss.Color("141")),
+	HIGHLIGHT_NUMBER:   lipgloss.NewStyle().Foreground(lipgloss.Color("141")),
+	HIGHLIGHT_STRING:   lipgloss.NewStyle().Foreground(lipgloss.Color("10")),
+	HIGHLIGHT_COMMAND:  styleCode,
+	HIGHLIGHT_FUNCTION: styleSuggestions[SUGGESTION_FUNCTION],
+	HIGHLIGHT_COMMENT:  styleFaint,
+}
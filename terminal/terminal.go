@@ -3,65 +3,125 @@ package terminal
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
 	"math/big"
-	mrand "math/rand"
 	"os"
+	"os/exec"
 	"os/user"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/abs-lang/abs/ast"
 	"github.com/abs-lang/abs/evaluator"
+	"github.com/abs-lang/abs/i18n"
 	"github.com/abs-lang/abs/lexer"
 	"github.com/abs-lang/abs/object"
 	"github.com/abs-lang/abs/parser"
 	"github.com/abs-lang/abs/runner"
 	"github.com/abs-lang/abs/util"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 var debug = os.Getenv("DEBUG") == "1"
 
-func NewTerminal(env *object.Environment, stdinRelay io.Writer) *tea.Program {
+func NewTerminal(env *object.Environment, opts ...tea.ProgramOption) *tea.Program {
 	historyFile, maxLines := getHistoryConfiguration(env)
-	history := getHistory(historyFile, maxLines)
+	history, historyTimes := getHistory(historyFile, maxLines)
 
 	// Setup the input line of our terminal
 	prompt := func() string {
 		return getPrompt(env)
 	}
+	accessible := isAccessible(env)
+	keys, err := getKeymap(env)
+	if err != nil {
+		fmt.Printf("%s; using the default keymap\n", err.Error())
+	}
+	theme, err := getTheme(env)
+	if err != nil {
+		fmt.Printf("%s; using the default theme\n", err.Error())
+	}
+	applyTheme(env, theme)
 	in := textinput.New()
 	in.Prompt = prompt()
-	in.Placeholder = exampleStatements[mrand.Intn(len(exampleStatements))] + " # just something you can run... (tab + enter)"
+	in.Placeholder = randomExample() + " # just something you can run... (tab + enter)"
 	in.Focus()
 
+	if accessible {
+		in.Placeholder = ""
+		in.Cursor.SetMode(cursor.CursorStatic)
+	}
+
 	search := textinput.New()
 	search.Prompt = " search: "
 	search.PromptStyle = styleSearchPrompt
 	search.TextStyle = styleSearchText
 
+	// line editor for whatever the user types
+	// while a script is reading from stdin
+	stdinInput := textinput.New()
+	stdinInput.Prompt = ""
+
+	// what the code we run prints is relayed
+	// to the terminal as it's printed, and
+	// what's typed while it runs to its stdin
+	output := &outputStream{}
+	env.Stdio.Stdout, env.Stdio.Stderr = output, output
+	stdinRelay := newInputStream()
+	env.Stdio.Stdin = stdinRelay
+
 	m := Model{
 		in:               in,
 		env:              env,
+		output:           output,
 		stdinRelay:       stdinRelay,
+		stdinInput:       stdinInput,
 		prompt:           prompt,
 		history:          history,
+		historyTimes:     historyTimes,
+		usage:            loadUsage(historyFile, maxLines),
 		historyIndex:     len(history) - 1,
+		sessionStart:     len(history),
+		baseline:         snapshotEnv(env),
 		historyFile:      historyFile,
 		historyMaxLInes:  maxLines,
 		suggestionsIndex: -1,
 		searchText:       search,
+		accessible:       accessible,
+		keys:             keys,
+		vi:               getEditingMode(env),
+		timing:           isTiming(env),
+	}
+
+	if debug {
+		m = m.toggleDebugPanel()
 	}
 
-	p := tea.NewProgram(m)
+	p := tea.NewProgram(m, opts...)
+	output.send = p.Send
+
+	// interactive commands (vim, ssh, top...) launched
+	// through exec(...) get the real TTY while they run
+	evaluator.InteractiveExec = func(c *exec.Cmd) error {
+		c.Stdin, c.Stdout, c.Stderr = nil, nil, nil
+		done := make(chan error, 1)
+		p.Send(execRequest{cmd: c, done: done})
+
+		return <-done
+	}
 
 	return p
 }
@@ -77,13 +137,33 @@ type Model struct {
 	// We instead create a relay used to
 	// forward stdin events from terminal
 	// to abs' stdin.
-	stdinRelay io.Writer
+	stdinRelay *inputStream
+	// lines are edited locally (echo, backspace,
+	// arrows, paste, ctrl+u) and only relayed to
+	// ABS once the user hits enter
+	stdinInput textinput.Model
+	stdinLines Lines
 	// flag to know whether ABS is executing
 	// code or not -- for example, this is used
 	// to determine that while ABS is executing,
 	// we should relay stdin from the terminal
 	isEvaluating bool
 	cancelEval   context.CancelFunc
+	// evaluations are numbered, so that we know
+	// which one is done: the one in the foreground
+	// or one of the jobs in the background
+	evals  int
+	evalID int
+	jobs   []*job
+	// spinner shown next to the prompt while
+	// evaluating, and when the evaluation started
+	spinner   spinner.Model
+	evalStart time.Time
+	// what the code being evaluated prints, and
+	// whether the code itself has been printed
+	// already, before the first lines it printed
+	output *outputStream
+	echoed bool
 	// function to print the prompt 'prefix'
 	prompt func() string
 	// dirty input -- input I may have typed on
@@ -93,23 +173,67 @@ type Model struct {
 	// were about to type
 	dirtyInput string
 	// input field to type all of ABS' goodness!
-	in              textinput.Model
+	in textinput.Model
+	// code spanning multiple lines, shown in
+	// place of the input, see paste.go
+	block           string
 	history         []string
+	historyTimes    []time.Time
 	historyIndex    int
 	historyFile     string
 	historyMaxLInes int
+	// the last error writing to the history file
+	historyErr error
+	// how often suggestions are picked, see usage.go
+	usage *suggestionUsage
+	// where the entries typed in this
+	// session start in the history, see ':save'
+	sessionStart int
+	// bindings the REPL started with, see ':reset'
+	baseline map[string]object.Object
 	// autocomplete
 	suggestionsIndex int
 	suggestions      []Suggestion
 	textToReplace    string
 	// search
 	isSearching bool
-	// reverse search input
+	// reverse search input, the history
+	// entries matching it (best first)
+	// and the one that's selected
 	searchText     textinput.Model
+	searchMatches  []searchMatch
 	searchPosition int
+	// expression being watched through ':watch'
+	watching *watcher
+	// history being browsed through ':hist'
+	browsing *historyBrowser
+	// pager showing long outputs, eg. 'help split'
+	pager *viewport.Model
+	// size of the terminal
+	width  int
+	height int
+	// accessibility mode, see isAccessible()
+	accessible bool
+	// actions bound to keys, see getKeymap()
+	keys keymap
+	// vi editing mode, see getEditingMode()
+	vi *viEditor
+	// docs panel, see toggleDocs()
+	docsOpen bool
+	// debug panel, see toggleDebugPanel()
+	debugOpen    bool
+	debugPane    viewport.Model
+	lastAST      ast.Node
+	lastEvalTime time.Duration
+	// whether evaluations are timed, see ':time'
+	timing bool
 }
 
 func (m Model) Init() tea.Cmd {
+	if m.accessible {
+		return tea.Batch(tea.SetWindowTitle("abs-repl"), m.welcome())
+	}
+
 	return tea.Batch(
 		tea.SetWindowTitle("abs-repl"),
 		textarea.Blink,
@@ -120,25 +244,54 @@ func (m Model) Init() tea.Cmd {
 func (m Model) View() string {
 	components := []string{m.in.View()}
 
+	if m.block != "" {
+		hint := i18n.Sprintf("%d lines, enter to run, ctrl+c to discard", strings.Count(m.block, "\n")+1)
+		components = []string{m.renderBlock(), styleFaint.Render(hint)}
+	}
+
+	if m.pager != nil {
+		components = []string{m.renderPager()}
+	}
+
+	if m.watching != nil {
+		components = []string{m.renderWatching()}
+	}
+
+	if m.browsing != nil {
+		components = []string{m.renderHistoryBrowser()}
+	}
+
+	if m.isEvaluating {
+		if progress := m.renderProgress(time.Now()); progress != "" {
+			components[0] += "  " + progress
+		}
+		components = append(components, m.stdinLines...)
+		components = append(components, m.stdinInput.View())
+	}
+
 	if m.isSearching {
 		components = append(components, styleSearch.Render(m.searchText.View()))
+
+		if !m.accessible && len(m.searchMatches) > 0 {
+			components = append(components, m.renderSearchResults())
+		}
 	}
 
-	if m.IsSuggesting() {
+	if m.IsSuggesting() && !m.accessible {
 		components = append(components, m.renderSuggestions())
-	}
 
-	if debug {
-		m := m.asMap()
-		wrapper := ""
-		for _, k := range slices.Sorted(maps.Keys(m)) {
-			wrapper += fmt.Sprintf(("\n%s: %v"), k, m[k])
+		if s, ok := m.selectedSuggestion(); ok && m.docsOpen {
+			components = append(components, m.renderDocsPanel(s))
 		}
+	}
 
-		components = append(components, styleNestedContainer.Render(styleDebug.Render(wrapper)))
+	view := lipgloss.JoinVertical(0, components...)
+
+	if m.debugOpen {
+		return m.withDebugPanel(view)
 	}
 
-	return lipgloss.JoinVertical(0, components...)
+	return view
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -146,33 +299,96 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		tiCmd tea.Cmd
 	)
 
-	m.in, _ = m.in.Update(msg)
+	// in vi's normal mode keys are commands, and
+	// multi-line code doesn't fit in the input,
+	// so neither goes there
+	if key, ok := msg.(tea.KeyMsg); !ok || (!m.isViNormal() && !m.isMultiline(key)) {
+		m.in, _ = m.in.Update(msg)
+	}
 	m.searchText, _ = m.searchText.Update(msg)
 
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m = m.resize(msg.Width, msg.Height)
 	case doneEval:
+		if j := m.job(msg.eval); j != nil {
+			return m.onJobDone(j, msg)
+		}
+
 		return m.onDoneEval(msg)
+	case spinner.TickMsg:
+		return m.onSpinnerTick(msg)
+	case outputReady:
+		return m.onOutputReady()
+	case watchResult:
+		return m.onWatchResult(msg)
+	case watchTick:
+		return m.onWatchTick(msg)
+	case execRequest:
+		return m.onExecRequest(msg)
 	case tea.KeyMsg:
+		if m.keys.action(msg) == ACTION_DEBUG {
+			return m.toggleDebugPanel(), nil
+		}
+
+		if m.debugOpen {
+			if scrolled, ok := m.scrollDebugPanel(msg); ok {
+				return scrolled, nil
+			}
+		}
+
 		// the REPL is evaluating ABS code,
 		// so if we type during this time,
 		// we should forward this to ABS' stdin
 		if m.isEvaluating {
-			switch msg.Type {
-			case tea.KeyCtrlC:
+			switch m.keys.action(msg) {
+			case ACTION_INTERRUPT:
 				return m.abortEval()
+			case ACTION_BACKGROUND:
+				return m.background()
 			default:
 				return m.interceptStdin(msg)
 			}
 		}
 
-		if m.IsSuggesting() {
+		if m.pager != nil {
+			switch msg.String() {
+			case "q", "esc", "ctrl+c":
+				return m.closePager()
+			default:
+				pager, cmd := m.pager.Update(msg)
+				m.pager = &pager
+				return m, cmd
+			}
+		}
+
+		if m.browsing != nil {
+			return m.onBrowseHistory(msg)
+		}
+
+		// while watching an expression the only
+		// thing we can do is to stop watching
+		if m.watching != nil {
 			switch msg.Type {
-			case tea.KeyEnter:
+			case tea.KeyCtrlC, tea.KeyEsc:
+				return m.stopWatching()
+			default:
+				return m, nil
+			}
+		}
+
+		if m.IsSuggesting() {
+			switch m.keys.action(msg) {
+			case ACTION_SUBMIT:
 				return m.selectSuggestion(), nil
-			case tea.KeyTab, tea.KeyDown:
-				return m.suggest(+1), nil
-			case tea.KeyUp:
-				return m.suggest(-1), nil
+			case ACTION_SUGGEST, ACTION_HISTORY_NEXT:
+				m = m.suggest(+1)
+				return m, m.announceSuggestions()
+			case ACTION_HISTORY_PREV:
+				m = m.suggest(-1)
+				return m, m.announceSuggestions()
+			case ACTION_DOCS:
+				return m.toggleDocs()
 			default:
 				return m.exitSuggestions(), nil
 			}
@@ -180,54 +396,72 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if m.isSearching {
 			// for every keyboard input let's restart
-			// our search -- but for ctrl+R, which should
-			// continue our search backwards
-			if msg.Type != tea.KeyCtrlR {
-				m.searchPosition = len(m.history) - 1
-			}
-
-			switch msg.Type {
-			case tea.KeyEnter:
+			// our search -- but for ctrl+R and the
+			// arrows, which move through the results
+			switch m.keys.action(msg) {
+			case ACTION_SUBMIT:
 				return m.selectSearch(), nil
-			case tea.KeyCtrlR:
-				return m.advanceSearch(), nil
-			case tea.KeyCtrlC, tea.KeyCtrlD:
+			case ACTION_SEARCH, ACTION_HISTORY_NEXT:
+				return m.advanceSearch(+1), nil
+			case ACTION_HISTORY_PREV:
+				return m.advanceSearch(-1), nil
+			case ACTION_QUIT:
+				// leave the search, not the REPL
+				m.in.SetValue("")
+				return m.resetInput(), nil
+			case ACTION_INTERRUPT:
 				break
 			default:
 				return m.search(), nil
 			}
 		}
 
-		switch msg.Type {
-		case tea.KeyEsc, tea.KeyCtrlD:
+		if m, ok := m.onMultilineKey(msg); ok {
+			return m, nil
+		}
+
+		if m.vi != nil {
+			if m, ok := m.onViKey(msg); ok {
+				return m, nil
+			}
+		}
+
+		switch m.keys.action(msg) {
+		case ACTION_QUIT:
 			return m.quit()
-		case tea.KeyCtrlC:
+		case ACTION_INTERRUPT:
 			m = m.resetInput()
 			return m.interrupt()
-		case tea.KeyCtrlR:
+		case ACTION_SEARCH:
 			return m.search(), nil
-		case tea.KeyEnter:
+		case ACTION_SUBMIT:
 			// Let's get rid of the placeholder
 			// first time user submits something
 			m.in.Placeholder = ""
+			code := m.input()
 
 			// The user submitted empty code.
 			// Just print a new line and continue...
-			if m.in.Value() == "" {
+			if code == "" {
 				return m, tea.Println(m.prompt())
 			}
 
-			// TODO this is breakig new installs (found via tapes)
 			// We have something submitted, let's add
 			// it to the history, only if it's not a duplicate
 			// of the last entry
-			// if m.maxHistoryIndex() > 0 || m.history[m.historyIndex] != m.in.Value() {
-			m.history = append(m.history, m.in.Value())
-			// }
+			m = m.appendHistory(code)
 
 			m = m.resetInput()
 
-			switch m.in.Value() {
+			if isMetaCommand(code) {
+				return m.runMetaCommand(code)
+			}
+
+			if query, ok := strings.CutPrefix(code, "help "); ok {
+				return m.helpSearch(query)
+			}
+
+			switch code {
 			case "quit":
 				return m.quit()
 			case "help":
@@ -235,10 +469,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			default:
 				return m.eval()
 			}
-		case tea.KeyTab:
+		case ACTION_SUGGEST:
 			// If the placeholder code is shown,
 			// allow the user to run it by tabbing
-			if m.in.Value() == "" {
+			if m.input() == "" {
 				if m.in.Placeholder != "" {
 					return m.engagePlaceholder()
 				}
@@ -246,12 +480,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-			return m.suggest(0), nil
-		case tea.KeyCtrlL:
+			// there's nothing to complete
+			// in a block of code
+			if m.block != "" {
+				return m, nil
+			}
+
+			m = m.suggest(0)
+			return m, m.announceSuggestions()
+		case ACTION_CLEAR:
 			return m.clear()
-		case tea.KeyUp:
+		case ACTION_DOCS:
+			return m.toggleDocs()
+		case ACTION_HISTORY_PREV:
 			m = m.prevHistory()
-		case tea.KeyDown:
+		case ACTION_HISTORY_NEXT:
 			m = m.nextHistory()
 		}
 
@@ -270,8 +513,9 @@ func (m Model) abortEval() (tea.Model, tea.Cmd) {
 
 		// 'fake' a command being done
 		return doneEval{
-			out: object.NULL,
-			ok:  false,
+			out:  object.NULL,
+			ok:   false,
+			eval: m.evalID,
 		}
 	}
 }
@@ -282,42 +526,40 @@ func (m Model) search() Model {
 		m.searchText.SetValue("")
 		m.searchText.Focus()
 		m.in.Blur()
-
-		return m
 	}
 
-	if m.searchText.Value() == "" {
-		m.in.SetValue("")
+	m.searchMatches = searchHistory(m.history, m.searchText.Value())
+	m.searchPosition = 0
+
+	return m.showSearchMatch()
+}
+
+// Moves through the results of the search: ctrl+R
+// (or down) goes to the next one, up to the previous
+func (m Model) advanceSearch(direction int) Model {
+	if !m.isSearching || len(m.searchMatches) == 0 {
 		return m
 	}
 
-	for i := m.searchPosition; i >= 0; i-- {
-		line := m.history[i]
+	m.searchPosition += direction
+	m.searchPosition %= len(m.searchMatches)
 
-		if strings.Contains(line, m.searchText.Value()) {
-			m.in.SetValue(line)
-			m.searchPosition = i
-			return m
-		}
+	if m.searchPosition < 0 {
+		m.searchPosition += len(m.searchMatches)
 	}
 
-	m.searchPosition = len(m.history) - 1
-
-	return m
+	return m.showSearchMatch()
 }
 
-func (m Model) advanceSearch() Model {
-	if !m.isSearching {
-		return m
+// Shows the selected result of the search in the input
+func (m Model) showSearchMatch() Model {
+	if len(m.searchMatches) == 0 {
+		return m.setInput("")
 	}
 
-	if len(m.history) == 0 {
-		return m
-	}
-
-	m.searchPosition -= 1
+	m = m.setInput(m.history[m.searchMatches[m.searchPosition].index])
 
-	return m.search()
+	return m
 }
 
 func (m Model) selectSearch() Model {
@@ -325,9 +567,9 @@ func (m Model) selectSearch() Model {
 		return m
 	}
 
-	txt := m.in.Value()
+	txt := m.input()
 	m = m.resetInput()
-	m.in.SetValue(txt)
+	m = m.setInput(txt)
 
 	return m
 }
@@ -338,6 +580,10 @@ func (m Model) exitSuggestions() Model {
 }
 
 func (m Model) selectSuggestion() Model {
+	if s, ok := m.selectedSuggestion(); ok {
+		m.usage.pick(s)
+	}
+
 	return m.resetInput()
 }
 
@@ -365,22 +611,31 @@ func (m Model) suggest(direction int) Model {
 	}
 
 	if !m.IsSuggesting() {
-		l := lexer.New(m.in.Value())
-		p := parser.New(l)
-		p.ParseProgram()
+		// within strings and commands we complete
+		// paths, eg. `ls ./sr[TAB], and programs
+		// to run, eg. `gr[TAB]
+		if l, ok := literalWord(m.in.Value()); ok {
+			m.dirtyInput = m.in.Value()
+			m.suggestions, m.textToReplace = literalSuggestions(l), l.word
+		} else {
+			l := lexer.New(m.in.Value())
+			p := parser.New(l)
+			p.ParseProgram()
+
+			if len(p.Errors()) != 0 {
+				return m
+			}
 
-		if len(p.Errors()) != 0 {
-			return m
-		}
+			if p.AutocompleteSubject == nil {
+				return m
+			}
 
-		if p.AutocompleteSubject == nil {
-			return m
+			m.dirtyInput = m.in.Value()
+			m.suggestions, m.textToReplace = m.getSuggestions(p.AutocompleteSubject)
 		}
 
-		m.dirtyInput = m.in.Value()
-		m.suggestions, m.textToReplace = m.getSuggestions(p.AutocompleteSubject)
-
 		if len(m.suggestions) == 1 {
+			m.usage.pick(m.suggestions[0])
 			m.in.SetValue(applySuggestion(m.dirtyInput, m.textToReplace, m.suggestions[0].Value))
 			return m.resetInput()
 		}
@@ -391,6 +646,8 @@ func (m Model) suggest(direction int) Model {
 
 func (m Model) renderSuggestions() string {
 	lines := Lines{}
+	// long comments are cut to fit the terminal
+	width := m.contentWidth() - styleSuggestion.GetHorizontalFrameSize()
 
 	for i, sugg := range m.suggestions {
 		s := styleSuggestions[sugg.Type].Render(sugg.Value)
@@ -405,7 +662,7 @@ func (m Model) renderSuggestions() string {
 			}
 		}
 
-		lines.Add(prefix + s)
+		lines.Add(fitWidth(prefix+s, width))
 	}
 
 	return styleSuggestion.Render(lines.Join())
@@ -423,10 +680,10 @@ func (m Model) prevHistory() Model {
 	// Only save dirty state on the first
 	// up press
 	if m.historyIndex == m.maxHistoryIndex() {
-		m.dirtyInput = m.in.Value()
+		m.dirtyInput = m.input()
 	}
 
-	m.in.SetValue(m.history[m.historyIndex])
+	m = m.setInput(m.history[m.historyIndex])
 
 	ix := m.historyIndex - 1
 	m.historyIndex = ix
@@ -439,15 +696,14 @@ func (m Model) nextHistory() Model {
 
 	if newPoint <= m.maxHistoryIndex() {
 		m.historyIndex = newPoint
-		m.in.SetValue(m.history[m.historyIndex])
+		m = m.setInput(m.history[m.historyIndex])
 
 		return m
 	}
 
 	// We reached the end of history,
 	// if we had a dirty value, let's use it
-	m.in.SetValue(m.dirtyInput)
-	return m
+	return m.setInput(m.dirtyInput)
 }
 
 func (m Model) resetInput() Model {
@@ -459,6 +715,11 @@ func (m Model) resetInput() Model {
 	m.in.Focus()
 	m.isSearching = false
 	m.searchText.Blur()
+	m.searchMatches = nil
+
+	if m.vi != nil {
+		m.vi.reset()
+	}
 
 	return m
 }
@@ -469,6 +730,11 @@ func (m Model) asMap() map[string]any {
 		"max_history_index": m.maxHistoryIndex(),
 		"dirty_input":       m.dirtyInput,
 		"is_evaluating":     m.isEvaluating,
+		"jobs":              len(m.jobs),
+		"is_watching":       m.watching != nil,
+		"is_browsing":       m.browsing != nil,
+		"is_vi_normal":      m.isViNormal(),
+		"stdin_input":       m.stdinInput.Value(),
 		"suggestions_index": m.suggestionsIndex,
 		"search_position":   m.searchPosition,
 	}
@@ -479,19 +745,19 @@ func (m Model) welcome() tea.Cmd {
 	username := u.Username
 
 	if err != nil {
-		username = "there"
+		username = i18n.T("there")
 	}
 
 	lines := Lines{}
-	lines.Add(fmt.Sprintf("Hello %s, welcome to the ABS (%s) programming language!", username, m.env.Version))
-	lines.Add("Type 'quit' when you're done, 'help' if you get lost!")
+	lines.Add(i18n.Sprintf("Hello %s, welcome to the ABS (%s) programming language!", username, m.env.Version))
+	lines.Add(i18n.T("Type 'quit' when you're done, 'help' if you get lost!"))
 
 	// check for new version about 10% of the time,
 	// to avoid too many hangups
 	if r, e := rand.Int(rand.Reader, big.NewInt(100)); e == nil && r.Int64() < 10 {
 		if newver, update := util.UpdateAvailable(m.env.Version); update {
-			lines.Add(styleFaint.Render(fmt.Sprintf(
-				"\n*** Update available: %s (your version is %s) ***",
+			lines.Add(styleFaint.Render("\n" + i18n.Sprintf(
+				"*** Update available: %s (your version is %s) ***",
 				newver,
 				m.env.Version,
 			)))
@@ -503,28 +769,24 @@ func (m Model) welcome() tea.Cmd {
 
 func (m Model) onDoneEval(res doneEval) (Model, tea.Cmd) {
 	m.isEvaluating = false
+	m.stdinInput.Blur()
+	m.stdinInput.Reset()
+	m.in.Focus()
+	m.streamOutput()
 
 	lines := Lines{}
-	lines.Add(m.prompt() + m.in.Value())
+	if !m.echoed {
+		lines.Add(m.currentLine())
+	}
+	lines = append(lines, m.stdinLines...)
+	m.stdinLines = Lines{}
 
 	if len(res.parseErrors) > 0 {
-		lines.Add(styleErr.Render(fmt.Sprintf(
-			"encountered %d syntax errors:\n",
+		lines.Add(styleErr.Render(i18n.Sprintf(
+			"encountered %d syntax errors:",
 			len(res.parseErrors),
-		)))
-
-		for _, e := range res.parseErrors {
-			ls := strings.Split(e, "\n")
-
-			for i, l := range ls {
-				prefix := ""
-
-				if i == 0 {
-					prefix = fmt.Sprintf("%d) ", i+1)
-				}
-				lines.Add(styleErr.Render("  " + prefix + l))
-			}
-		}
+		) + "\n"))
+		lines = append(lines, renderParseErrors(res.parseErrors)...)
 	}
 
 	b, _ := io.ReadAll(m.env.Stdio.Stdout)
@@ -534,39 +796,99 @@ func (m Model) onDoneEval(res doneEval) (Model, tea.Cmd) {
 	}
 
 	if res.out != object.NULL {
-		out := res.out.Inspect()
-
-		if !res.ok {
-			out = styleErr.Render(out)
-		}
+		lines.Add(m.renderResult(res.out, res.ok))
+	}
 
-		lines.Add(out)
+	// evaluations that were interrupted
+	// aren't timed, see abortEval()
+	if m.timing && res.elapsed > 0 {
+		lines.Add(renderTiming(res))
 	}
 
-	m.in.Reset()
+	m = m.clearInput()
+	m.lastEvalTime = res.elapsed
+	m = m.refreshDebugPanel()
 
 	return m, lines.Dump()
 }
 
+// Renders the result of an evaluation: errors
+// are shown in red and, with ABS_HIGHLIGHT_STDERR,
+// so is whatever a command wrote to its stderr.
+func (m Model) renderResult(out object.Object, ok bool) string {
+	if !ok {
+		return styleErr.Render(out.Inspect())
+	}
+
+	cmd, isCmd := out.(*object.String)
+
+	if !isCmd || cmd.Stderr == nil || util.GetEnvVar(m.env, "ABS_HIGHLIGHT_STDERR", "false") != "true" {
+		return out.Inspect()
+	}
+
+	// a failed command holds its stderr already
+	if cmd.Ok != nil && !cmd.Ok.Value {
+		return styleErr.Render(cmd.Inspect())
+	}
+
+	lines := Lines{}
+
+	if cmd.Inspect() != "" {
+		lines.Add(cmd.Inspect())
+	}
+
+	if stderr := strings.TrimSpace(cmd.Stderr.String()); stderr != "" {
+		lines.Add(styleErr.Render(stderr))
+	}
+
+	return lines.Join()
+}
+
 func (m Model) interceptStdin(msg tea.KeyMsg) (Model, tea.Cmd) {
-	if msg.String() == "enter" {
-		m.stdinRelay.Write([]byte{'\n'})
+	// ctrl+d on an empty line ends the input,
+	// otherwise it deletes the next character
+	if msg.Type == tea.KeyCtrlD && m.stdinInput.Value() == "" {
+		m.stdinRelay.sendEOF()
 		return m, nil
 	}
 
-	m.stdinRelay.Write([]byte(string(msg.Runes)))
-	return m, nil
+	if msg.Type == tea.KeyEnter {
+		line := m.stdinInput.Value()
+		m.stdinLines.Add(line)
+		m.stdinInput.Reset()
+		m.stdinRelay.Write([]byte(line + "\n"))
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.stdinInput, cmd = m.stdinInput.Update(msg)
+	return m, cmd
 }
 
 func (m Model) clear() (Model, tea.Cmd) {
 	m.in.Placeholder = ""
 
+	// clearing the screen would wipe out
+	// what a screen reader has been reading
+	if m.accessible {
+		return m, nil
+	}
+
 	return m, tea.ClearScreen
 }
 
 func (m Model) quit() (Model, tea.Cmd) {
 	cmds := []tea.Cmd{}
-	err := saveHistory(m.historyFile, m.historyMaxLInes, m.history)
+	// entries are written to the history file as
+	// they're run, so we're only left with trimming it
+	err := m.historyErr
+	if err == nil {
+		err = trimHistory(m.historyFile, m.historyMaxLInes)
+	}
+
+	if err == nil {
+		err = m.usage.save()
+	}
 
 	if err != nil {
 		cmds = append(cmds, tea.Println(fmt.Sprintf(
@@ -582,6 +904,10 @@ func (m Model) quit() (Model, tea.Cmd) {
 }
 
 func (m Model) currentLine() string {
+	if m.block != "" {
+		return m.renderBlock()
+	}
+
 	return m.prompt() + m.in.Value()
 }
 
@@ -589,19 +915,22 @@ func (m Model) help() (Model, tea.Cmd) {
 	lines := Lines{}
 	prompt := m.prompt()
 
-	lines.Add(styleFaint.Render("Try typing something along the lines of:\n"))
+	lines.Add(styleFaint.Render(i18n.T("Looking for a function? Search the docs with:") + "\n"))
+	lines.Add("  " + prompt + styleCode.Render("help split\n"))
+	lines.Add(styleFaint.Render(i18n.T("Otherwise, try typing something along the lines of:") + "\n"))
 	lines.Add("  " + prompt + styleCode.Render("current_date = `date`\n"))
-	lines.Add(styleFaint.Render("A command should be triggered in your system. Then try printing the result of that command with:\n"))
+	lines.Add(styleFaint.Render(i18n.T("A command should be triggered in your system. Then try printing the result of that command with:") + "\n"))
 	lines.Add("  " + prompt + styleCode.Render("current_date\n"))
-	lines.Add(styleFaint.Render("Here some other valid examples of ABS code:\n"))
+	lines.Add(styleFaint.Render(i18n.T("Here some other valid examples of ABS code:") + "\n"))
 
 	for i := 0; i < 5; i++ {
-		ix := mrand.Intn(len(exampleStatements))
-		lines.Add("  " + prompt + styleCode.Render(exampleStatements[ix]+"\n"))
+		lines.Add("  " + prompt + styleCode.Render(randomExample()+"\n"))
 	}
 
+	lines.Add(styleFaint.Render(i18n.Sprintf("More examples are available through ':examples <topic>' (%s)", strings.Join(exampleTopics(), ", "))))
+
 	msg := m.currentLine() + styleNestedContainer.Render(lines.Join())
-	m.in.Reset()
+	m = m.clearInput()
 
 	return m, tea.Println(msg)
 }
@@ -612,18 +941,64 @@ func (m Model) engagePlaceholder() (Model, tea.Cmd) {
 	return m, nil
 }
 
+// sent by exec(...) when a command
+// needs to take over the terminal
+type execRequest struct {
+	cmd  *exec.Cmd
+	done chan error
+}
+
+// Hands the terminal over to the command exec(...) runs, till it
+// exits. Only the evaluation in the foreground gets it: a job in
+// the background would take it from under the prompt, so its
+// command fails instead, as it would in a shell.
+func (m Model) onExecRequest(msg execRequest) (Model, tea.Cmd) {
+	if !m.isEvaluating {
+		msg.done <- errors.New(i18n.T("exec(...) isn't available to jobs in the background, see :fg"))
+		return m, nil
+	}
+
+	return m, tea.ExecProcess(msg.cmd, func(err error) tea.Msg {
+		msg.done <- err
+		return nil
+	})
+}
+
 type doneEval struct {
 	out         object.Object
 	ok          bool
 	parseErrors []string
+	elapsed     time.Duration
+	// how many commands ran
+	commands int
+	// which evaluation this is
+	eval int
 }
 
 func (m Model) eval() (Model, tea.Cmd) {
+	return m.evalCode(m.input())
+}
+
+func (m Model) evalCode(code string) (Model, tea.Cmd) {
+	if len(m.jobs) > 0 {
+		return m.jobRunning()
+	}
+
 	m.isEvaluating = true
+	m.echoed = false
+	m.evals++
+	m.evalID = m.evals
+	m.streamOutput()
+	m.in.Blur()
+	m.stdinInput.Reset()
+	m.stdinInput.Focus()
 	ctx, cancel := context.WithCancel(context.Background())
 	m.cancelEval = cancel
+	m.lastAST = parser.New(lexer.New(code)).ParseProgram()
 
+	m, progress := m.startProgress()
 	done := make(chan doneEval)
+	eval := m.evalID
 
 	go func() {
 		defer m.cancelEval()
@@ -634,24 +1009,26 @@ func (m Model) eval() (Model, tea.Cmd) {
 		// terminated (which is bad). Again, I think the real solution
 		// over time is to introduce a CancelContext to the runner
 		// that gets passed down all the way to running the commands.
-		out, ok, parseErrors := runner.Run(m.in.Value(), m.env)
+		start := time.Now()
+		commands := evaluator.CommandsRun()
+		out, ok, parseErrors := runner.Run(code, m.env)
 
 		// someone cancelled the eval operation
 		if err := ctx.Err(); err != nil {
 			return
 		}
 
-		done <- doneEval{out, ok, parseErrors}
+		done <- doneEval{out, ok, parseErrors, time.Since(start), evaluator.CommandsRun() - commands, eval}
 	}()
 
-	return m, func() tea.Msg {
+	return m, tea.Batch(progress, func() tea.Msg {
 		return <-done
-	}
+	})
 }
 
 func (m Model) interrupt() (Model, tea.Cmd) {
 	l := m.currentLine()
-	m.in.Reset()
+	m = m.clearInput()
 
 	return m, tea.Println(l)
 }
@@ -661,11 +1038,16 @@ type suggestionType int
 const SUGGESTION_FUNCTION suggestionType = 0
 const SUGGESTION_IDENTIFIER suggestionType = 1
 const SUGGESTION_PROPERTY suggestionType = 2
+const SUGGESTION_PATH suggestionType = 3
+const SUGGESTION_EXECUTABLE suggestionType = 4
 
 type Suggestion struct {
 	Value   string
 	Comment string
 	Type    suggestionType
+	// what's suggested, eg. the
+	// builtin function, if known
+	Object object.Object
 }
 
 func NewSuggestion(v string, t suggestionType, c string) Suggestion {
@@ -692,13 +1074,17 @@ func (m Model) getSuggestions(n ast.Node) ([]Suggestion, string) {
 		for _, v := range vars {
 			if strings.HasPrefix(strings.ToLower(v), strings.ToLower(input)) {
 				vv, _ := m.env.Get(v)
-				matches = append(matches, NewSuggestion(v, SUGGESTION_IDENTIFIER, vv.Inspect()))
+				s := NewSuggestion(v, SUGGESTION_IDENTIFIER, describe(vv))
+				s.Object = vv
+				matches = append(matches, s)
 			}
 		}
 
 		for _, f := range slices.Sorted(maps.Keys(functions)) {
 			if strings.HasPrefix(strings.ToLower(f), strings.ToLower(input)) {
-				matches = append(matches, NewSuggestion(f, SUGGESTION_FUNCTION, functions[f].Doc))
+				s := NewSuggestion(f, SUGGESTION_FUNCTION, functions[f].Doc)
+				s.Object = functions[f]
+				matches = append(matches, s)
 			}
 		}
 	case *ast.PropertyExpression:
@@ -707,9 +1093,13 @@ func (m Model) getSuggestions(n ast.Node) ([]Suggestion, string) {
 		// can be called on it.
 		//
 		// "string".hell[TAB]
-		evaluated := evaluator.BeginEval(node.Object, m.env, lexer.New(node.Object.String()))
+		evaluated := m.evalSubject(node.Object)
 		toReplace = node.Property.String()
 
+		if evaluated == nil {
+			break
+		}
+
 		// native functions that can be called on the subject
 		for _, f := range slices.Sorted(maps.Keys(functions)) {
 			if functions[f].Standalone || !evaluator.CanCallMethod(functions[f], evaluated) {
@@ -717,7 +1107,9 @@ func (m Model) getSuggestions(n ast.Node) ([]Suggestion, string) {
 			}
 
 			if strings.HasPrefix(strings.ToLower(f), strings.ToLower(toReplace)) {
-				matches = append(matches, NewSuggestion(f, SUGGESTION_FUNCTION, functions[f].Doc))
+				s := NewSuggestion(f, SUGGESTION_FUNCTION, functions[f].Doc)
+				s.Object = functions[f]
+				matches = append(matches, s)
 			}
 		}
 
@@ -728,20 +1120,174 @@ func (m Model) getSuggestions(n ast.Node) ([]Suggestion, string) {
 			break
 		}
 
-		for p := range hash.Pairs {
-			matches = append(matches, NewSuggestion(p.Value, SUGGESTION_PROPERTY, hash.Pairs[p].Value.Inspect()))
+		keys := slices.SortedFunc(maps.Keys(hash.Pairs), func(a, b object.HashKey) int {
+			return strings.Compare(a.Value, b.Value)
+		})
+
+		for _, p := range keys {
+			if !strings.HasPrefix(strings.ToLower(p.Value), strings.ToLower(toReplace)) {
+				continue
+			}
+
+			// members of modules are usually functions,
+			// let's show them with their docs
+			v := hash.Pairs[p].Value
+			t := SUGGESTION_PROPERTY
+
+			if v.Type() == object.FUNCTION_OBJ || v.Type() == object.BUILTIN_OBJ {
+				t = SUGGESTION_FUNCTION
+			}
+
+			s := NewSuggestion(p.Value, t, describe(v))
+			s.Object = v
+			matches = append(matches, s)
 		}
 	}
 
-	sort.Slice(matches, func(i, j int) bool {
+	sort.SliceStable(matches, func(i, j int) bool {
 		return matches[i].Type > matches[j].Type
 	})
 
-	for k, v := range matches {
-		if len(v.Comment) > 50 {
-			matches[k].Comment = v.Comment[:50] + "..."
+	// the ones picked the most come first
+	m.usage.rank(matches)
+
+	return matches, toReplace
+}
+
+// Resolves the subject of a property expression (eg. 'util'
+// in 'util.mem[TAB]', or 'config.server' in 'config.server.po[TAB]'),
+// so we can suggest its members. Nothing that could have side
+// effects is evaluated: variables, properties and indexes are
+// looked up, literals evaluated, and anything else (eg. function
+// calls or commands) resolves to nil. Names of stdlib modules that
+// aren't bound to anything resolve to the module itself, so
+// that you can explore them without requiring them first.
+func (m Model) evalSubject(n ast.Node) object.Object {
+	switch n := n.(type) {
+	case *ast.Identifier:
+		if v, ok := m.env.Get(n.Value); ok {
+			return v
+		}
+
+		if slices.Contains(stdlibModules(), n.Value) {
+			module := parser.New(lexer.New(fmt.Sprintf("require('@%s')", n.Value))).ParseProgram()
+			return m.evalInert(module)
+		}
+	case *ast.PropertyExpression:
+		return member(m.evalSubject(n.Object), n.Property.String())
+	case *ast.IndexExpression:
+		if n.IsRange || !isInert(n.Index) {
+			return nil
+		}
+
+		if index := m.evalInert(n.Index); index != nil {
+			return member(m.evalSubject(n.Left), index.Inspect())
+		}
+	default:
+		if isInert(n) {
+			return m.evalInert(n)
 		}
 	}
 
-	return matches, toReplace
+	return nil
+}
+
+// Evaluates code that has no side effects, for
+// suggestions, unless a job is running: only one
+// evaluation runs at a time (see jobRunning)
+func (m Model) evalInert(n ast.Node) object.Object {
+	if len(m.jobs) > 0 {
+		return nil
+	}
+
+	return evaluator.BeginEval(n, m.env, lexer.New(n.String()))
+}
+
+// Whether a node is made of literals only, so
+// evaluating it can't have any side effect
+func isInert(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.StringLiteral, *ast.NumberLiteral, *ast.Boolean, *ast.NullLiteral, *ast.DurationLiteral, *ast.SizeLiteral:
+		return true
+	case *ast.PrefixExpression:
+		// eg. -1
+		return isInert(n.Right)
+	case *ast.ArrayLiteral:
+		for _, e := range n.Elements {
+			if !isInert(e) {
+				return false
+			}
+		}
+
+		return true
+	case *ast.HashLiteral:
+		for k, v := range n.Pairs {
+			if !isInert(k) || !isInert(v) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// The member of a hash (by key), or of an array (by index)
+func member(o object.Object, key string) object.Object {
+	switch o := o.(type) {
+	case *object.Hash:
+		if pair, ok := o.GetPair(key); ok {
+			return pair.Value
+		}
+	case *object.Array:
+		if i, err := strconv.Atoi(key); err == nil {
+			if i < 0 {
+				i += len(o.Elements)
+			}
+
+			if i >= 0 && i < len(o.Elements) {
+				return o.Elements[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// Names of the modules in the standard library,
+// eg. 'util' for require('@util')
+func stdlibModules() []string {
+	modules := []string{}
+
+	for _, name := range evaluator.AssetNames() {
+		name = strings.TrimPrefix(name, "stdlib/")
+		name = strings.TrimSuffix(name, "/index.abs")
+		modules = append(modules, name)
+	}
+
+	return modules
+}
+
+// Short description of a value to be shown next
+// to a suggestion: functions are described by their
+// docs (or signature), anything else by its value.
+func describe(o object.Object) string {
+	switch o := o.(type) {
+	case *object.Function:
+		if o.Node != nil && o.Node.Doc != "" {
+			return o.Node.Doc
+		}
+
+		params := []string{}
+		for _, p := range o.Parameters {
+			params = append(params, p.String())
+		}
+
+		return fmt.Sprintf("f(%s)", strings.Join(params, ", "))
+	case *object.Builtin:
+		return o.Doc
+	}
+
+	return o.Inspect()
 }
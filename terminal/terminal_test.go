@@ -0,0 +1,1216 @@
+package terminal
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abs-lang/abs/evaluator"
+	"github.com/abs-lang/abs/lexer"
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/parser"
+	"github.com/abs-lang/abs/runner"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestModuleSuggestions(t *testing.T) {
+	tests := []struct {
+		setup    string
+		input    string
+		expected string
+		comment  string
+	}{
+		{"", "util.mem", "memoize", "Decorator to memoize the result of a function."},
+		{"u = require('@util')", "u.mem", "memoize", "Decorator to memoize the result of a function."},
+		{"# Says hi\nf hi(name) { 'hi ' + name }; h = {'hi': hi, 'x': 1}", "h.h", "hi", "Says hi"},
+		{"h = {'add': f(a, b) { a + b }}", "h.a", "add", "f(a, b)"},
+	}
+
+	for _, tt := range tests {
+		discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+		stdio := &object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}
+		env := object.NewEnvironment(stdio, ".", "test", false)
+
+		if tt.setup != "" {
+			if _, ok, errs := runner.Run(tt.setup, env); !ok {
+				t.Fatalf("%v (code evaluated: %s)", errs, tt.setup)
+			}
+		}
+
+		p := parser.New(lexer.New(tt.input))
+		p.ParseProgram()
+		m := Model{env: env}
+		suggestions, _ := m.getSuggestions(p.AutocompleteSubject)
+
+		if len(suggestions) != 1 {
+			t.Fatalf("expected 1 suggestion for %s, got %v", tt.input, suggestions)
+		}
+
+		if suggestions[0].Value != tt.expected || suggestions[0].Comment != tt.comment {
+			t.Fatalf("wrong suggestion for %s: %+v", tt.input, suggestions[0])
+		}
+	}
+}
+
+func TestNestedSuggestions(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	stdio := &object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}
+	env := object.NewEnvironment(stdio, ".", "test", false)
+	setup := `config = {"server": {"port": 80, "tls": {"cert": "a.pem"}}, "hosts": [{"name": "web-1"}]}; calls = 0; f = f() { calls += 1; config }`
+
+	if _, ok, errs := runner.Run(setup, env); !ok {
+		t.Fatalf("%v (code evaluated: %s)", errs, setup)
+	}
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"config.server.po", []string{"port"}},
+		{"config.server.tls.ce", []string{"cert"}},
+		{"config['server'].tls.ce", []string{"cert"}},
+		{"config.hosts[0].na", []string{"name"}},
+		{"config.hosts[-1].na", []string{"name"}},
+		{`{"a": {"bb": 1}}.a.b`, []string{"bb"}},
+		{"config.nope.po", []string{}},
+		{"config.hosts[5].na", []string{}},
+		{"f().server.po", []string{}},
+		{"`echo x`.server.po", []string{}},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		p.ParseProgram()
+		m := Model{env: env}
+		suggestions, _ := m.getSuggestions(p.AutocompleteSubject)
+
+		got := []string{}
+		for _, s := range suggestions {
+			if s.Type == SUGGESTION_PROPERTY {
+				got = append(got, s.Value)
+			}
+		}
+
+		if !slices.Equal(got, tt.expected) {
+			t.Fatalf("%s: expected %v, got %v", tt.input, tt.expected, got)
+		}
+	}
+
+	if calls, _ := env.Get("calls"); calls.Inspect() != "0" {
+		t.Fatalf("expected suggestions not to call functions, got %s calls", calls.Inspect())
+	}
+}
+
+func TestSearchHelp(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	stdio := &object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}
+	env := object.NewEnvironment(stdio, ".", "test", false)
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"split", "split"},
+		{"SPLIT", "split"},
+		{"memoize", "util.memoize"},
+		{"sleep", "sleep"},
+	}
+
+	for _, tt := range tests {
+		matches := searchHelp(env, tt.query)
+
+		if len(matches) == 0 || matches[0].name != tt.expected {
+			t.Fatalf("wrong first match for %s: %v", tt.query, matches)
+		}
+	}
+
+	if matches := searchHelp(env, "this matches nothing"); len(matches) != 0 {
+		t.Fatalf("expected no matches, got %v", matches)
+	}
+}
+
+func TestTourSolutions(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	stdio := &object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}
+	env := object.NewEnvironment(stdio, ".", "test", false)
+
+	for _, l := range lessons {
+		if l.check == nil {
+			continue
+		}
+
+		if _, passed := runLesson(l, "null", object.NewEnvironment(stdio, ".", "test", false)); passed {
+			t.Fatalf("lesson '%s' passes with the wrong answer", l.title)
+		}
+
+		if out, passed := runLesson(l, l.solution, env); !passed {
+			t.Fatalf("solution of lesson '%s' doesn't pass: %s", l.title, out)
+		}
+	}
+}
+
+func TestAccessibleSuggestions(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	stdio := &object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}
+	env := object.NewEnvironment(stdio, ".", "test", false)
+
+	t.Setenv("ABS_ACCESSIBLE", "")
+	if isAccessible(env) {
+		t.Fatalf("expected accessibility mode to be off by default")
+	}
+
+	t.Setenv("ABS_ACCESSIBLE", "1")
+	if !isAccessible(env) {
+		t.Fatalf("expected ABS_ACCESSIBLE=1 to turn on accessibility mode")
+	}
+
+	m := Model{env: env, accessible: true, suggestionsIndex: -1, suggestions: []Suggestion{
+		NewSuggestion("split", SUGGESTION_FUNCTION, "splits a string"),
+		NewSuggestion("splice", SUGGESTION_FUNCTION, ""),
+	}}
+
+	tests := []struct {
+		index    int
+		expected string
+	}{
+		{-1, "2 suggestions: split, splice (tab to cycle, enter to pick)"},
+		{0, "suggestion 1 of 2: split # splits a string"},
+		{1, "suggestion 2 of 2: splice"},
+	}
+
+	for _, tt := range tests {
+		m.suggestionsIndex = tt.index
+
+		if got := m.describeSuggestions(); got != tt.expected {
+			t.Fatalf("expected '%s', got '%s'", tt.expected, got)
+		}
+
+		if m.announceSuggestions() == nil {
+			t.Fatalf("expected suggestions to be announced")
+		}
+	}
+
+	if strings.Contains(m.View(), "splice") {
+		t.Fatalf("expected suggestions not to be rendered below the input")
+	}
+
+	m.accessible = false
+	if m.announceSuggestions() != nil {
+		t.Fatalf("expected suggestions not to be announced outside of accessibility mode")
+	}
+}
+
+func TestDebugPanel(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	stdio := &object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}
+	env := object.NewEnvironment(stdio, ".", "test", false)
+
+	if _, ok, errs := runner.Run(`name = "abs"; tags = [1, 2, 3]`, env); !ok {
+		t.Fatalf("%v", errs)
+	}
+
+	m := Model{env: env, width: 120, height: 60}
+	m.lastAST = parser.New(lexer.New("1 + 2")).ParseProgram()
+	m = m.toggleDebugPanel()
+
+	content := m.debugContent()
+	for _, expected := range []string{
+		"name             STRING    3 chars",
+		"tags             ARRAY     3 items",
+		"InfixExpression Operator=\"+\"",
+		"history_index: 0",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Fatalf("expected the debug panel to contain '%s', got:\n%s", expected, content)
+		}
+	}
+
+	if !strings.Contains(m.View(), "F12 to close") {
+		t.Fatalf("expected the debug panel to be shown")
+	}
+
+	if strings.Contains(m.toggleDebugPanel().View(), "F12 to close") {
+		t.Fatalf("expected the debug panel to be hidden")
+	}
+}
+
+func TestHighlightClasses(t *testing.T) {
+	code := "# hi\nif x { echo(\"a#b\", 1, `ls`) } // bye"
+	classes := highlightClasses([]rune(code))
+
+	tests := []struct {
+		text     string
+		expected highlightClass
+	}{
+		{"# hi", HIGHLIGHT_COMMENT},
+		{"if", HIGHLIGHT_KEYWORD},
+		{"x", HIGHLIGHT_PLAIN},
+		{"echo", HIGHLIGHT_FUNCTION},
+		{"\"a#b\"", HIGHLIGHT_STRING},
+		{"1", HIGHLIGHT_NUMBER},
+		{"`ls`", HIGHLIGHT_COMMAND},
+		{"// bye", HIGHLIGHT_COMMENT},
+	}
+
+	runes := []rune(code)
+	for _, tt := range tests {
+		start := strings.Index(code, tt.text)
+		for i := start; i < start+len(tt.text); i++ {
+			if classes[i] != tt.expected {
+				t.Fatalf("wrong class for %q at %d (%q): expected %d, got %d", tt.text, i, string(runes[i]), tt.expected, classes[i])
+			}
+		}
+	}
+
+	if lines := Highlight("a = 1\nb = 2\n"); len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %v", lines)
+	}
+}
+
+func TestKeymap(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	stdio := &object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}
+
+	tests := []struct {
+		absrc    string
+		osEnv    string
+		key      tea.KeyMsg
+		expected string
+		err      string
+	}{
+		{"", "", tea.KeyMsg{Type: tea.KeyCtrlR}, ACTION_SEARCH, ""},
+		{"", "", tea.KeyMsg{Type: tea.KeyEsc}, ACTION_QUIT, ""},
+		{`ABS_KEYMAP = {"search": "ctrl+f"}`, "", tea.KeyMsg{Type: tea.KeyCtrlF}, ACTION_SEARCH, ""},
+		{`ABS_KEYMAP = {"search": "ctrl+f"}`, "", tea.KeyMsg{Type: tea.KeyCtrlR}, "", ""},
+		{`ABS_KEYMAP = {"quit": ["ctrl+q", "ctrl+d"]}`, "", tea.KeyMsg{Type: tea.KeyEsc}, "", ""},
+		{`ABS_KEYMAP = {"quit": ["ctrl+q", "ctrl+d"]}`, "", tea.KeyMsg{Type: tea.KeyCtrlQ}, ACTION_QUIT, ""},
+		{`ABS_KEYMAP = {"history-prev": "ctrl+p", "clear": "up"}`, "", tea.KeyMsg{Type: tea.KeyUp}, ACTION_CLEAR, ""},
+		{"", "search=ctrl+f, quit=ctrl+q", tea.KeyMsg{Type: tea.KeyCtrlF}, ACTION_SEARCH, ""},
+		{"", "search=ctrl+f, quit=ctrl+q", tea.KeyMsg{Type: tea.KeyEsc}, "", ""},
+		{`ABS_KEYMAP = {"fly": "ctrl+f"}`, "", tea.KeyMsg{Type: tea.KeyCtrlR}, ACTION_SEARCH, "ABS_KEYMAP: unknown action 'fly'"},
+		{`ABS_KEYMAP = {"search": 1}`, "", tea.KeyMsg{Type: tea.KeyCtrlR}, ACTION_SEARCH, "ABS_KEYMAP: keys of 'search' must be a string or an array, got NUMBER"},
+		{`ABS_KEYMAP = "search"`, "", tea.KeyMsg{Type: tea.KeyCtrlR}, ACTION_SEARCH, "ABS_KEYMAP must be a hash, got STRING"},
+		{"", "search", tea.KeyMsg{Type: tea.KeyCtrlR}, ACTION_SEARCH, "ABS_KEYMAP: 'search' should be in the form action=key"},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment(stdio, ".", "test", false)
+		t.Setenv("ABS_KEYMAP", tt.osEnv)
+
+		if tt.absrc != "" {
+			if _, ok, errs := runner.Run(tt.absrc, env); !ok {
+				t.Fatalf("%v (code evaluated: %s)", errs, tt.absrc)
+			}
+		}
+
+		keys, err := getKeymap(env)
+
+		if (err == nil && tt.err != "") || (err != nil && !strings.HasPrefix(err.Error(), tt.err)) || (err != nil && tt.err == "") {
+			t.Fatalf("%s%s: expected error '%s', got %v", tt.absrc, tt.osEnv, tt.err, err)
+		}
+
+		if got := keys.action(tt.key); got != tt.expected {
+			t.Fatalf("%s%s: expected %s to trigger '%s', got '%s'", tt.absrc, tt.osEnv, tt.key, tt.expected, got)
+		}
+	}
+
+	// the REPL follows the keymap
+	m := Model{history: []string{"x = 1"}, keys: newKeymap(map[string][]string{"search": {"ctrl+f"}}), in: textinput.New(), searchText: textinput.New()}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+
+	if !updated.(Model).isSearching {
+		t.Fatalf("expected ctrl+f to start searching")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if updated.(Model).isSearching {
+		t.Fatalf("expected ctrl+r not to start searching once search is bound to ctrl+f")
+	}
+}
+
+func TestViMode(t *testing.T) {
+	tests := []struct {
+		keys     string
+		expected string
+		cursor   int
+	}{
+		{"echo hello world<esc>", "echo hello world", 15},
+		{"echo hello world<esc>0dw", "hello world", 0},
+		{"echo hello world<esc>0cwprint<esc>", "print hello world", 4},
+		{"echo hello world<esc>bD", "echo hello ", 10},
+		{"echo hello world<esc>02x", "ho hello world", 0},
+		{"echo hello world<esc>dd", "", 0},
+		{"echo(x, y)<esc>0f(ldt)", "echo()", 5},
+		{"a b c<esc>0wylP", "a bb c", 2},
+		{"a b c<esc>0wyeP", "a b cb c", 4},
+		{"echo hello<esc>0dwu", "echo hello", 0},
+		{"echo hello<esc>0dwdwuu", "echo hello", 0},
+		{"abc<esc>0~~", "ABc", 2},
+		{"abc<esc>0rx", "xbc", 0},
+		{"x = 1<esc>Iz<esc>", "zx = 1", 0},
+		{"one two three<esc>02w", "one two three", 8},
+		{"one two<esc>0wd0", "two", 0},
+		{"one two three<esc>0d2w", "three", 0},
+		{"one two<esc>0dwwp", "twoone ", 6},
+		{"one<esc>0d<esc>x", "ne", 0},
+	}
+
+	for _, tt := range tests {
+		m := Model{keys: newKeymap(nil), vi: newViEditor(), in: textinput.New(), searchText: textinput.New()}
+		m.in.Focus()
+
+		for keys := tt.keys; keys != ""; {
+			msg := tea.KeyMsg{Type: tea.KeyEsc}
+
+			if rest, ok := strings.CutPrefix(keys, "<esc>"); ok {
+				keys = rest
+			} else {
+				r := []rune(keys)
+				msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: r[:1]}
+				keys = string(r[1:])
+			}
+
+			updated, _ := m.Update(msg)
+			m = updated.(Model)
+		}
+
+		if m.in.Value() != tt.expected || m.in.Position() != tt.cursor {
+			t.Fatalf("%s: expected '%s' (cursor at %d), got '%s' (cursor at %d)", tt.keys, tt.expected, tt.cursor, m.in.Value(), m.in.Position())
+		}
+	}
+
+	// keys bound to actions work in normal mode too
+	m := Model{history: []string{"x = 1"}, keys: newKeymap(nil), vi: newViEditor(), in: textinput.New(), searchText: textinput.New(), stdinInput: textinput.New()}
+	m.in.Focus()
+	m.vi.normal = true
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+
+	if !updated.(Model).isSearching {
+		t.Fatalf("expected ctrl+r to search in normal mode")
+	}
+}
+
+func TestMultilinePaste(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	stdio := &object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}
+	env := object.NewEnvironment(stdio, ".", "test", false)
+
+	m := Model{env: env, prompt: func() string { return "⧐  " }, keys: newKeymap(nil), in: textinput.New(), searchText: textinput.New(), stdinInput: textinput.New()}
+	m.in.Focus()
+
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("x = ")},
+		{Type: tea.KeyRunes, Runes: []rune("1\r\n# a comment\r\ny = x + 1\n"), Paste: true},
+		{Type: tea.KeyRunes, Runes: []rune("0")},
+		{Type: tea.KeyBackspace},
+	}
+
+	for _, k := range keys {
+		updated, _ := m.Update(k)
+		m = updated.(Model)
+	}
+
+	expected := "x = 1\n# a comment\ny = x + 1"
+	if m.input() != expected || m.in.Value() != "" {
+		t.Fatalf("expected the paste to make a block '%s', got '%s' (input: '%s')", expected, m.input(), m.in.Value())
+	}
+
+	if got := m.currentLine(); got != "⧐  x = 1\n   # a comment\n   y = x + 1" {
+		t.Fatalf("expected the block to be aligned with the prompt, got:\n%s", got)
+	}
+
+	// the block runs as a whole
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	if m.history[len(m.history)-1] != expected {
+		t.Fatalf("expected the block to make it to the history, got %q", m.history[len(m.history)-1])
+	}
+
+	updated, _ = m.Update(evalResult(cmd))
+	m = updated.(Model)
+
+	if y, _ := env.Get("y"); y == nil || y.Inspect() != "2" {
+		t.Fatalf("expected y to be 2, got %v", y)
+	}
+
+	if m.input() != "" {
+		t.Fatalf("expected the input to be cleared, got '%s'", m.input())
+	}
+
+	// recalling it from the history, and discarding it
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = updated.(Model)
+
+	if m.block != expected {
+		t.Fatalf("expected the block to be recalled from the history, got '%s'", m.block)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if updated.(Model).input() != "" {
+		t.Fatalf("expected ctrl+c to discard the block, got '%s'", updated.(Model).input())
+	}
+}
+
+func TestCastRecording(t *testing.T) {
+	cast := &bytes.Buffer{}
+	rec, _ := NewCastRecorder(cast, 80, 24)
+
+	rec.event("o", "⧐  ")
+	for _, k := range []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("x")}, {Type: tea.KeyUp}, {Type: tea.KeyCtrlC}, {Type: tea.KeyEnter}} {
+		rec.event("i", keyInput(k))
+	}
+	rec.event("o", "x\r\n1")
+	rec.Close()
+
+	lines := strings.Split(strings.TrimSpace(cast.String()), "\n")
+	if len(lines) != 7 || !strings.HasPrefix(lines[0], `{"version":2,"width":80,"height":24,`) {
+		t.Fatalf("unexpected recording:\n%s", cast.String())
+	}
+
+	for i, expected := range []string{`"i","x"]`, `"i","\u001b[A"]`, `"i","\u0003"]`, `"i","\r"]`} {
+		if !strings.HasSuffix(lines[i+2], expected) {
+			t.Fatalf("expected event %d to end with %s, got %s", i+2, expected, lines[i+2])
+		}
+	}
+
+	out := &bytes.Buffer{}
+	if err := ReplayCast(cast, out, 10, 0); err != nil || out.String() != "⧐  x\r\n1" {
+		t.Fatalf("expected the output to be replayed, got %q (%v)", out.String(), err)
+	}
+
+	if err := ReplayCast(strings.NewReader("x = 1\n"), out, 1, 0); err == nil {
+		t.Fatalf("expected replaying something that isn't a recording to fail")
+	}
+}
+
+func TestTheme(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	stdio := &object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}
+	file := filepath.Join(t.TempDir(), "theme.json")
+	os.WriteFile(file, []byte(`{"prompt": "bright-blue", "faint": "#888"}`), 0644)
+
+	tests := []struct {
+		absrc    string
+		osEnv    string
+		expected map[string]lipgloss.Color
+		err      string
+	}{
+		{"", "", map[string]lipgloss.Color{}, ""},
+		{`ABS_THEME = {"error": "red", "prompt": "#4287f5"}`, "", map[string]lipgloss.Color{"error": "1", "prompt": "#4287f5"}, ""},
+		{"", "error=205, suggestion=Cyan", map[string]lipgloss.Color{"error": "205", "suggestion": "6"}, ""},
+		{"", file, map[string]lipgloss.Color{"prompt": "12", "faint": "#888"}, ""},
+		{`ABS_THEME = {"title": "red"}`, "", nil, "ABS_THEME: unknown color 'title'"},
+		{`ABS_THEME = {"error": "reddish"}`, "", nil, "ABS_THEME: error: invalid color 'reddish'"},
+		{"", "error=256", nil, "ABS_THEME: error: invalid color '256'"},
+		{`ABS_THEME = "dark"`, "", nil, "ABS_THEME must be a hash, got STRING"},
+		{"", "/nonexistent/theme.json", nil, "ABS_THEME: open /nonexistent/theme.json"},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment(stdio, ".", "test", false)
+		t.Setenv("ABS_THEME", tt.osEnv)
+
+		if tt.absrc != "" {
+			if _, ok, errs := runner.Run(tt.absrc, env); !ok {
+				t.Fatalf("%v (code evaluated: %s)", errs, tt.absrc)
+			}
+		}
+
+		theme, err := getTheme(env)
+
+		if (err == nil && tt.err != "") || (err != nil && !strings.HasPrefix(err.Error(), tt.err)) || (err != nil && tt.err == "") {
+			t.Fatalf("%s%s: expected error '%s', got %v", tt.absrc, tt.osEnv, tt.err, err)
+		}
+
+		if err == nil && !maps.Equal(theme, tt.expected) {
+			t.Fatalf("%s%s: expected theme %v, got %v", tt.absrc, tt.osEnv, tt.expected, theme)
+		}
+	}
+}
+
+func TestPathSuggestions(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "src", "lib"), 0755)
+	os.WriteFile(filepath.Join(dir, "src", "main.abs"), nil, 0644)
+	os.WriteFile(filepath.Join(dir, "setup.abs"), nil, 0644)
+	os.WriteFile(filepath.Join(dir, "my notes.txt"), nil, 0644)
+	os.WriteFile(filepath.Join(dir, ".env"), nil, 0644)
+	t.Chdir(dir)
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"cat(\"s", []string{"setup.abs", "src/"}},
+		{"`ls src/", []string{"src/lib/", "src/main.abs"}},
+		{"$(wc -l src/m", []string{"src/main.abs"}},
+		{"`cat my", []string{`my\ notes.txt`}},
+		{"'my", []string{"my notes.txt"}},
+		{"`cat .", []string{".env"}},
+		{"`cat ./s", []string{"./setup.abs", "./src/"}},
+		{`"say \" s`, []string{"setup.abs", "src/"}},
+		{"`ls x", []string{}},
+		{"x = \"src\"; s", nil},
+		{"# \"s", nil},
+	}
+
+	for _, tt := range tests {
+		l, ok := literalWord(tt.input)
+
+		if tt.expected == nil {
+			if ok {
+				t.Fatalf("%s: expected no path to complete, got '%s'", tt.input, l.word)
+			}
+			continue
+		}
+
+		got := []string{}
+		for _, s := range literalSuggestions(l) {
+			got = append(got, s.Value)
+		}
+
+		if !ok || !slices.Equal(got, tt.expected) {
+			t.Fatalf("%s: expected %v, got %v", tt.input, tt.expected, got)
+		}
+	}
+
+	// a single match is picked right away
+	m := Model{keys: newKeymap(nil), in: textinput.New(), searchText: textinput.New()}
+	m.in.SetValue("`ls sr")
+	m = m.suggest(0)
+
+	if m.in.Value() != "`ls src/" {
+		t.Fatalf("expected the path to be completed, got '%s'", m.in.Value())
+	}
+}
+
+func TestExecutableSuggestions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	bin, other := t.TempDir(), t.TempDir()
+	os.WriteFile(filepath.Join(bin, "greet"), nil, 0755)
+	os.WriteFile(filepath.Join(bin, "grep-logs"), nil, 0755)
+	os.WriteFile(filepath.Join(bin, "grumpy.txt"), nil, 0644)
+	os.WriteFile(filepath.Join(other, "greet"), nil, 0755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+other)
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"`gr", []string{"greet", "grep-logs"}},
+		{"$(ls | gre", []string{"greet", "grep-logs"}},
+		{"`ls;grep-", []string{"grep-logs"}},
+		{"`ls gr", []string{}},
+		{"'gr", []string{}},
+	}
+
+	for _, tt := range tests {
+		l, _ := literalWord(tt.input)
+
+		got := []string{}
+		for _, s := range literalSuggestions(l) {
+			got = append(got, s.Value)
+		}
+
+		if !slices.Equal(got, tt.expected) {
+			t.Fatalf("%s: expected %v, got %v", tt.input, tt.expected, got)
+		}
+	}
+
+	if s := executableSuggestions("greet"); len(s) != 1 || s[0].Comment != filepath.Join(bin, "greet") {
+		t.Fatalf("expected the first greet in $PATH to win, got %v", s)
+	}
+
+	// programs installed later on come up as well
+	os.WriteFile(filepath.Join(other, "grunt"), nil, 0755)
+	os.Chtimes(other, time.Now(), time.Now().Add(time.Minute))
+
+	if s := executableSuggestions("gru"); len(s) != 1 || s[0].Value != "grunt" {
+		t.Fatalf("expected the cache to be refreshed, got %v", s)
+	}
+}
+
+func TestDocsPanel(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	stdio := &object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}
+	env := object.NewEnvironment(stdio, ".", "test", false)
+	setup := "# Deploys the given service to all the hosts of the cluster, one by one, waiting for each to be healthy\ndeploy_service = f(name, hosts) { name }\ndeploy_all = 1"
+
+	if _, ok, errs := runner.Run(setup, env); !ok {
+		t.Fatalf("%v (code evaluated: %s)", errs, setup)
+	}
+
+	m := Model{env: env, keys: newKeymap(nil), in: textinput.New(), searchText: textinput.New()}
+	m.in.Focus()
+
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			updated, _ := m.Update(k)
+			m = updated.(Model)
+		}
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("deploy_")}, tea.KeyMsg{Type: tea.KeyTab})
+
+	if s, _ := m.selectedSuggestion(); s.Value != "deploy_all" {
+		t.Fatalf("expected deploy_all to be selected, got %v", s)
+	}
+
+	if strings.Contains(m.View(), "healthy") {
+		t.Fatalf("expected the docs panel to be closed by default")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyF1}, tea.KeyMsg{Type: tea.KeyTab})
+
+	if !m.IsSuggesting() || !strings.Contains(m.View(), "deploy_service(name, hosts)") || !strings.Contains(m.View(), "waiting for each to be healthy") {
+		t.Fatalf("expected the docs panel to show the full docs, got:\n%s", m.View())
+	}
+
+	// builtins come with the types they
+	// work on, and examples of their use
+	docs := suggestionDocs(NewSuggestion("undocumented", SUGGESTION_IDENTIFIER, ""))
+	if len(docs) != 1 {
+		t.Fatalf("expected a suggestion without docs to only have a title, got %v", docs)
+	}
+
+	s := NewSuggestion("split", SUGGESTION_FUNCTION, "")
+	s.Object = evaluator.GetFns()["split"]
+	docs = suggestionDocs(s)
+
+	if joined := docs.Join(); !strings.Contains(joined, "works on: string") || !strings.Contains(joined, ".split(") {
+		t.Fatalf("expected the docs of split to mention types and examples, got:\n%s", joined)
+	}
+}
+
+func TestTiming(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	env := object.NewEnvironment(&object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}, ".", "test", false)
+	m := Model{env: env, prompt: func() string { return "> " }, keys: newKeymap(nil), in: textinput.New(), searchText: textinput.New(), stdinInput: textinput.New()}
+	m.in.Focus()
+
+	run := func(code string) tea.Msg {
+		m = m.setInput(code)
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+
+		if !m.isEvaluating {
+			return nil
+		}
+
+		msg := evalResult(cmd)
+		updated, _ = m.Update(msg)
+		m = updated.(Model)
+
+		return msg
+	}
+
+	run(":time on")
+	if !m.timing {
+		t.Fatalf("':time on' didn't turn timing on")
+	}
+
+	if done := run("`echo a`; `echo b`").(doneEval); done.commands != 2 {
+		t.Fatalf("expected 2 commands to run, got %d", done.commands)
+	}
+
+	if done := run("1 + 1").(doneEval); done.commands != 0 {
+		t.Fatalf("expected no commands to run, got %d", done.commands)
+	}
+
+	run(":time off")
+	if m.timing {
+		t.Fatalf("':time off' didn't turn timing off")
+	}
+
+	tests := []struct {
+		commands int
+		expected string
+	}{
+		{0, "took 1.5ms (evaluation)"},
+		{1, "took 1.5ms (1 command)"},
+		{3, "took 1.5ms (3 commands)"},
+	}
+
+	for _, tt := range tests {
+		got := renderTiming(doneEval{elapsed: 1500 * time.Microsecond, commands: tt.commands})
+		if !strings.Contains(got, tt.expected) {
+			t.Fatalf("got %q exp %q", got, tt.expected)
+		}
+	}
+}
+
+func TestSuggestionUsage(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	env := object.NewEnvironment(&object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}, ".", "test", false)
+	runner.Run("usage_a = 1; usage_b = 2; usage_c = 3", env)
+	history := filepath.Join(t.TempDir(), "history")
+
+	suggest := func(m Model) []string {
+		p := parser.New(lexer.New("usage_"))
+		p.ParseProgram()
+		suggestions, _ := m.getSuggestions(p.AutocompleteSubject)
+
+		values := []string{}
+		for _, s := range suggestions {
+			values = append(values, s.Value)
+		}
+
+		return values
+	}
+
+	m := Model{env: env, usage: loadUsage(history, 10)}
+	if got := suggest(m); !slices.Equal(got, []string{"usage_a", "usage_b", "usage_c"}) {
+		t.Fatalf("expected suggestions in alphabetical order, got %v", got)
+	}
+
+	// another session, running side by side
+	other := loadUsage(history, 10)
+	other.pick(NewSuggestion("usage_b", SUGGESTION_IDENTIFIER, ""))
+
+	m.usage.pick(NewSuggestion("usage_c", SUGGESTION_IDENTIFIER, ""))
+	m.usage.pick(NewSuggestion("usage_c", SUGGESTION_IDENTIFIER, ""))
+	m.usage.pick(NewSuggestion("./usage_c", SUGGESTION_PATH, ""))
+
+	if got := suggest(m); !slices.Equal(got, []string{"usage_c", "usage_a", "usage_b"}) {
+		t.Fatalf("expected the most picked suggestions first, got %v", got)
+	}
+
+	if err := m.usage.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := other.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m = Model{env: env, usage: loadUsage(history, 10)}
+	if got := suggest(m); !slices.Equal(got, []string{"usage_c", "usage_b", "usage_a"}) {
+		t.Fatalf("expected usage to be saved by both sessions, got %v", got)
+	}
+
+	if _, err := os.Stat(usageFile(history)); err != nil {
+		t.Fatalf("expected usage to be saved next to the history: %s", err)
+	}
+}
+
+func TestResize(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	stdio := &object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}
+	env := object.NewEnvironment(stdio, ".", "test", false)
+	setup := "# Deploys the given service to all the hosts of the cluster, one by one, waiting for each to be healthy\ndeploy_service = f(name, hosts) { name }\ndeploy_all = 1"
+
+	if _, ok, errs := runner.Run(setup, env); !ok {
+		t.Fatalf("%v (code evaluated: %s)", errs, setup)
+	}
+
+	m := Model{env: env, keys: newKeymap(nil), in: textinput.New(), searchText: textinput.New()}
+	m.in.Focus()
+
+	press := func(msgs ...tea.Msg) {
+		for _, msg := range msgs {
+			updated, _ := m.Update(msg)
+			m = updated.(Model)
+		}
+	}
+
+	fits := func(width int) {
+		for _, line := range strings.Split(m.View(), "\n") {
+			if lipgloss.Width(line) > width {
+				t.Fatalf("expected lines to fit in %d columns, got %d:\n%s", width, lipgloss.Width(line), m.View())
+			}
+		}
+	}
+
+	press(tea.WindowSizeMsg{Width: 40, Height: 30}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("deploy_")}, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab})
+
+	if s, _ := m.selectedSuggestion(); s.Value != "deploy_service" {
+		t.Fatalf("expected deploy_service to be selected, got %v", s)
+	}
+
+	if !strings.Contains(m.View(), "# Deploys the give...") || strings.Contains(m.View(), "healthy") {
+		t.Fatalf("expected the comment to be cut to the width of the terminal, got:\n%s", m.View())
+	}
+	fits(40)
+
+	// once there's room, comments are shown in full
+	press(tea.WindowSizeMsg{Width: 160, Height: 30})
+	if !strings.Contains(m.View(), "waiting for each to be healthy") {
+		t.Fatalf("expected the comment to be shown in full, got:\n%s", m.View())
+	}
+
+	// on narrow terminals the debug panel
+	// goes under the prompt
+	m = m.toggleDebugPanel()
+	press(tea.WindowSizeMsg{Width: 60, Height: 30})
+	fits(60)
+
+	if !strings.Contains(m.View(), "F12 to close") {
+		t.Fatalf("expected the debug panel to be shown, got:\n%s", m.View())
+	}
+}
+
+func TestWatchInterval(t *testing.T) {
+	discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
+	env := object.NewEnvironment(&object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}, ".", "test", false)
+	m := Model{env: env, prompt: func() string { return "> " }, keys: newKeymap(nil), in: textinput.New(), searchText: textinput.New()}
+
+	if _, ok, errs := runner.Run("n = 0", env); !ok {
+		t.Fatalf("%v", errs)
+	}
+
+	// expressions that don't reference files
+	// are re-evaluated every couple of seconds
+	m, _ = m.watch("n")
+	if m.watching == nil || m.watching.interval != watchDefaultInterval {
+		t.Fatalf("expected to watch n every %s, got %+v", watchDefaultInterval, m.watching)
+	}
+
+	m, _ = m.stopWatching()
+	m, _ = m.watch("n += 1; n 0.01")
+	w := m.watching
+	if w.interval != 10*time.Millisecond {
+		t.Fatalf("expected an interval of 10ms, got %s", w.interval)
+	}
+
+	for i := 0; i < 3; i++ {
+		m, _ = m.onWatchTick(watchTick{w: w})
+		m, _ = m.onWatchResult(m.evalWatched(w)().(watchResult))
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "every 10ms: n += 1; n") || !strings.HasSuffix(view, "3") {
+		t.Fatalf("expected the last result to be shown in place, got:\n%s", view)
+	}
+
+	// results of a watch that's gone are ignored
+	m, _ = m.stopWatching()
+	if updated, cmd := m.onWatchResult(watchResult{w: w, output: "4"}); updated.watching != nil || cmd != nil {
+		t.Fatalf("expected a stale result to be ignored")
+	}
+}
+
+// Waits for the evaluation started along
+// with the spinner (see Model.evalCode)
+func evalResult(cmd tea.Cmd) tea.Msg {
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return msg
+	}
+
+	for _, c := range batch {
+		if c == nil {
+			continue
+		}
+
+		if done, ok := c().(doneEval); ok {
+			return done
+		}
+	}
+
+	return nil
+}
+
+func TestProgress(t *testing.T) {
+	m := Model{keys: newKeymap(nil), isEvaluating: true}
+	m, cmd := m.startProgress()
+
+	if cmd == nil {
+		t.Fatalf("expected the spinner to start")
+	}
+
+	if progress := m.renderProgress(m.evalStart.Add(100 * time.Millisecond)); progress != "" {
+		t.Fatalf("expected nothing to be shown for quick evaluations, got %q", progress)
+	}
+
+	if progress := m.renderProgress(m.evalStart.Add(3240 * time.Millisecond)); !strings.HasPrefix(progress, m.spinner.View()) || !strings.HasSuffix(progress, "3.2s (ctrl+c to abort)") {
+		t.Fatalf("expected the spinner and elapsed time, got %q", progress)
+	}
+
+	// the spinner stops once the evaluation is done
+	if _, cmd := m.onSpinnerTick(cmd().(spinner.TickMsg)); cmd == nil {
+		t.Fatalf("expected the spinner to keep going while evaluating")
+	}
+
+	m.isEvaluating = false
+	if _, cmd := m.onSpinnerTick(spinner.TickMsg{}); cmd != nil {
+		t.Fatalf("expected the spinner to stop")
+	}
+
+	// screen readers only get the hint,
+	// with the keys the user bound
+	m = Model{keys: newKeymap(map[string][]string{ACTION_INTERRUPT: {"ctrl+x", "ctrl+g"}}), accessible: true, isEvaluating: true}
+	m, _ = m.startProgress()
+
+	if progress := m.renderProgress(m.evalStart.Add(time.Second)); progress != styleFaint.Render("running (ctrl+g/ctrl+x to abort)") {
+		t.Fatalf("expected only the hint to be shown, got %q", progress)
+	}
+}
+
+func TestOutputStream(t *testing.T) {
+	msgs := []tea.Msg{}
+	out := &outputStream{send: func(msg tea.Msg) { msgs = append(msgs, msg) }}
+
+	out.Write([]byte("a"))
+	out.stream(true)
+	out.Write([]byte("b\nc"))
+	out.Write([]byte("d\n"))
+
+	// the terminal is told once, till it reads the lines
+	if len(msgs) != 1 {
+		t.Fatalf("expected a single notification, got %v", msgs)
+	}
+
+	if lines := out.lines(); lines != "ab\ncd" {
+		t.Fatalf("expected the complete lines, got %q", lines)
+	}
+
+	out.Write([]byte("e"))
+	out.Write([]byte("f\ng"))
+	if len(msgs) != 2 {
+		t.Fatalf("expected to be notified again, got %v", msgs)
+	}
+
+	out.lines()
+	out.stream(false)
+	out.Write([]byte("\nh\n"))
+	if len(msgs) != 2 {
+		t.Fatalf("expected no notifications when not streaming, got %v", msgs)
+	}
+
+	if b, _ := io.ReadAll(out); string(b) != "g\nh\n" {
+		t.Fatalf("expected the rest of the output to be read, got %q", b)
+	}
+}
+
+func TestStreamingOutput(t *testing.T) {
+	out := &outputStream{}
+	env := object.NewEnvironment(&object.Stdio{Stdin: &bytes.Buffer{}, Stdout: out, Stderr: out}, ".", "test", false)
+	m := Model{env: env, output: out, prompt: func() string { return "> " }, keys: newKeymap(nil), in: textinput.New(), searchText: textinput.New(), stdinInput: textinput.New()}
+	m.in.Focus()
+	m = m.setInput(`echo("one"); echo("two")`)
+
+	ready := make(chan tea.Msg, 1)
+	out.send = func(msg tea.Msg) { ready <- msg }
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	done := evalResult(cmd)
+
+	// the code, and the lines it printed, are
+	// printed before the evaluation is done
+	updated, cmd = m.Update(<-ready)
+	m = updated.(Model)
+	if printed := fmt.Sprint(cmd()); !strings.Contains(printed, `echo("one"); echo("two")`) || !strings.Contains(printed, "one\ntwo") {
+		t.Fatalf("expected the code and its output to be printed, got %s", printed)
+	}
+
+	updated, cmd = m.Update(done)
+	m = updated.(Model)
+	if printed := fmt.Sprint(cmd()); strings.Contains(printed, "echo") || strings.Contains(printed, "one") {
+		t.Fatalf("expected the code and its output not to be printed twice, got %s", printed)
+	}
+
+	if m.isEvaluating || m.output.streaming {
+		t.Fatalf("expected the evaluation to be done")
+	}
+}
+
+func TestJobs(t *testing.T) {
+	out := &outputStream{}
+	env := object.NewEnvironment(&object.Stdio{Stdin: &bytes.Buffer{}, Stdout: out, Stderr: out}, ".", "test", false)
+	m := Model{env: env, output: out, prompt: func() string { return "> " }, keys: newKeymap(nil), in: textinput.New(), searchText: textinput.New(), stdinInput: textinput.New()}
+	m.in.Focus()
+
+	run := func(code string) tea.Cmd {
+		m = m.setInput(code)
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+
+		return cmd
+	}
+
+	if printed := fmt.Sprint(run(":fg")()); !strings.Contains(printed, "no jobs") {
+		t.Fatalf("expected no jobs to bring to the foreground, got %s", printed)
+	}
+
+	// ctrl+z gives the prompt back while the code runs
+	wait := run("sleep(50); 42")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = updated.(Model)
+	if printed := fmt.Sprint(cmd()); !strings.Contains(printed, "[1] sleep(50); 42") {
+		t.Fatalf("expected the job to be printed, got %s", printed)
+	}
+
+	if m.isEvaluating || len(m.jobs) != 1 || m.input() != "" || !m.output.streaming {
+		t.Fatalf("expected the evaluation to be sent to the background")
+	}
+
+	if printed := fmt.Sprint(run(":jobs")()); !strings.Contains(printed, "[1] sleep(50); 42") {
+		t.Fatalf("expected the job to be listed, got %s", printed)
+	}
+
+	// only one evaluation runs at a time
+	if printed := fmt.Sprint(run("1 + 1")()); !strings.Contains(printed, "[1] is still running") {
+		t.Fatalf("expected the code not to be evaluated while the job runs, got %s", printed)
+	}
+
+	if m.isEvaluating || m.input() != "1 + 1" {
+		t.Fatalf("expected the code to be left in the input")
+	}
+
+	if printed := fmt.Sprint(run(":watch 1")()); !strings.Contains(printed, "[1] is still running") || m.watching != nil {
+		t.Fatalf("expected nothing to be watched while the job runs, got %s", printed)
+	}
+
+	updated, cmd = m.Update(evalResult(wait))
+	m = updated.(Model)
+	if printed := fmt.Sprint(cmd()); !strings.Contains(printed, "[1] done: sleep(50); 42") || !strings.Contains(printed, "42") {
+		t.Fatalf("expected the job to be done, got %s", printed)
+	}
+
+	if len(m.jobs) != 0 || m.output.streaming {
+		t.Fatalf("expected no jobs to be left, got %d", len(m.jobs))
+	}
+
+	// :fg waits for the job again
+	wait = run("sleep(50); 43")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = updated.(Model)
+
+	if printed := fmt.Sprint(run(":fg 2")()); !strings.Contains(printed, "no such job: 2") {
+		t.Fatalf("expected job 2 not to exist, got %s", printed)
+	}
+
+	run(":fg 1")
+	if !m.isEvaluating || len(m.jobs) != 0 || m.input() != "sleep(50); 43" {
+		t.Fatalf("expected the job to be in the foreground")
+	}
+
+	updated, cmd = m.Update(evalResult(wait))
+	m = updated.(Model)
+	if printed := fmt.Sprint(cmd()); strings.Contains(printed, "done") || !strings.Contains(printed, "> sleep(50); 43") || !strings.Contains(printed, "43") {
+		t.Fatalf("expected the evaluation to be done in the foreground, got %s", printed)
+	}
+
+	if m.isEvaluating {
+		t.Fatalf("expected the evaluation to be done")
+	}
+}
+
+func TestStdinInput(t *testing.T) {
+	out := &outputStream{}
+	in := newInputStream()
+	env := object.NewEnvironment(&object.Stdio{Stdin: in, Stdout: out, Stderr: out}, ".", "test", false)
+	m := Model{env: env, output: out, stdinRelay: in, prompt: func() string { return "> " }, keys: newKeymap(nil), in: textinput.New(), searchText: textinput.New(), stdinInput: textinput.New()}
+	m.in.Focus()
+
+	run := func(code string) tea.Cmd {
+		m = m.setInput(code)
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+
+		return cmd
+	}
+
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			updated, _ := m.Update(k)
+			m = updated.(Model)
+		}
+	}
+
+	// lines are edited locally, and only
+	// relayed once they're submitted
+	wait := run("stdin()")
+	press(
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hx")},
+		tea.KeyMsg{Type: tea.KeyBackspace},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i"), Paste: true},
+	)
+
+	if m.stdinInput.Value() != "hi" || in.buf.Len() != 0 {
+		t.Fatalf("expected the line to be edited locally, got %q (relayed: %q)", m.stdinInput.Value(), in.buf.String())
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.stdinInput.Value() != "" || len(m.stdinLines) != 1 || m.stdinLines[0] != "hi" {
+		t.Fatalf("expected the line to be submitted, got %q", m.stdinLines)
+	}
+
+	done := evalResult(wait).(doneEval)
+	if !done.ok || done.out.Inspect() != "hi" {
+		t.Fatalf("expected the code to read the line, got %s", done.out.Inspect())
+	}
+	updated, _ := m.Update(done)
+	m = updated.(Model)
+
+	// ctrl+u clears the line, ctrl+d on an
+	// empty one ends the input
+	wait = run("stdin()")
+	press(
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("nope")},
+		tea.KeyMsg{Type: tea.KeyCtrlU},
+		tea.KeyMsg{Type: tea.KeyCtrlD},
+	)
+
+	done = evalResult(wait).(doneEval)
+	if done.ok || done.out != object.EOF {
+		t.Fatalf("expected the code to read EOF, got %s", done.out.Inspect())
+	}
+	updated, _ = m.Update(done)
+	m = updated.(Model)
+
+	// reading carries on afterwards
+	wait = run("stdin()")
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("next")}, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if done = evalResult(wait).(doneEval); done.out.Inspect() != "next" {
+		t.Fatalf("expected the code to read the next line, got %s", done.out.Inspect())
+	}
+}
+
+func TestExecRequest(t *testing.T) {
+	m := Model{keys: newKeymap(nil), in: textinput.New(), searchText: textinput.New(), stdinInput: textinput.New(), isEvaluating: true}
+
+	// the evaluation in the foreground gets the
+	// terminal till the command is done
+	done := make(chan error, 1)
+	if _, cmd := m.Update(execRequest{cmd: exec.Command("true"), done: done}); cmd == nil {
+		t.Fatalf("expected the command to take over the terminal")
+	}
+
+	select {
+	case err := <-done:
+		t.Fatalf("expected the command not to be done before it ran, got %v", err)
+	default:
+	}
+
+	// jobs in the background don't
+	m.isEvaluating = false
+	m.jobs = []*job{{id: 1, eval: 1, code: `exec("vim")`}}
+	if _, cmd := m.Update(execRequest{cmd: exec.Command("true"), done: done}); cmd != nil {
+		t.Fatalf("expected the command not to take over the terminal")
+	}
+
+	if err := <-done; err == nil || !strings.Contains(err.Error(), "isn't available to jobs in the background") {
+		t.Fatalf("expected the command to fail, got %v", err)
+	}
+}
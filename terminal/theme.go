@@ -0,0 +1,161 @@
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/util"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Colors of the REPL that can be themed, along
+// with the styles each of them applies to
+var themeColors = map[string]func(c lipgloss.Color){
+	"prompt": func(c lipgloss.Color) {
+		stylePrompt = stylePrompt.Foreground(c)
+	},
+	"error": func(c lipgloss.Color) {
+		styleErr = styleErr.Foreground(c)
+		styleDiffRemoved = styleErr
+	},
+	"faint": func(c lipgloss.Color) {
+		styleFaint = styleFaint.Faint(false).Foreground(c)
+		styleHighlight[HIGHLIGHT_COMMENT] = styleFaint
+	},
+	"code": func(c lipgloss.Color) {
+		styleCode = styleCode.Foreground(c)
+		styleSearchText = styleCode
+		styleSearchPrompt = styleSearchPrompt.Foreground(c)
+		styleHighlight[HIGHLIGHT_COMMAND] = styleCode
+	},
+	"suggestion": func(c lipgloss.Color) {
+		for t, s := range styleSuggestions {
+			styleSuggestions[t] = s.Foreground(c)
+		}
+		styleHighlight[HIGHLIGHT_FUNCTION] = styleSuggestions[SUGGESTION_FUNCTION]
+	},
+	"selected": func(c lipgloss.Color) {
+		styleSelectedSuggestion = styleSelectedSuggestion.Foreground(c)
+		styleSelectedPrefix = styleSelectedSuggestion.Underline(false)
+	},
+	"match": func(c lipgloss.Color) {
+		styleSearchMatch = styleSearchMatch.Foreground(c)
+	},
+}
+
+// Colors that can be named in a theme,
+// rather than given as ANSI codes
+var namedColors = map[string]string{
+	"black":          "0",
+	"red":            "1",
+	"green":          "2",
+	"yellow":         "3",
+	"blue":           "4",
+	"magenta":        "5",
+	"cyan":           "6",
+	"white":          "7",
+	"bright-black":   "8",
+	"bright-red":     "9",
+	"bright-green":   "10",
+	"bright-yellow":  "11",
+	"bright-blue":    "12",
+	"bright-magenta": "13",
+	"bright-cyan":    "14",
+	"bright-white":   "15",
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Parses a color, either named (eg. "red"), an
+// ANSI code (eg. "205") or in hex (eg. "#ed4747")
+func parseColor(color string) (lipgloss.Color, error) {
+	color = strings.ToLower(strings.TrimSpace(color))
+
+	if code, ok := namedColors[color]; ok {
+		return lipgloss.Color(code), nil
+	}
+
+	if n, err := strconv.Atoi(color); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(color), nil
+	}
+
+	if hexColor.MatchString(color) {
+		return lipgloss.Color(color), nil
+	}
+
+	return "", fmt.Errorf("invalid color '%s' (eg. red, 205 or #ed4747)", color)
+}
+
+// Reads the theme from ABS_THEME, either a hash set in the
+// ABS init file (eg. {"error": "red", "prompt": "#4287f5"}) or
+// an OS environment variable, with the colors inline (eg.
+// "error=red,prompt=#4287f5") or in a JSON file (eg. ~/.abs_theme.json)
+func getTheme(env *object.Environment) (map[string]lipgloss.Color, error) {
+	colors := map[string]string{}
+
+	if v, ok := env.Get("ABS_THEME"); ok {
+		hash, ok := v.(*object.Hash)
+		if !ok {
+			return nil, fmt.Errorf("ABS_THEME must be a hash, got %s", v.Type())
+		}
+
+		for _, pair := range hash.Pairs {
+			colors[pair.Key.Inspect()] = pair.Value.Inspect()
+		}
+	} else if v := os.Getenv("ABS_THEME"); strings.Contains(v, "=") {
+		for _, color := range strings.Split(v, ",") {
+			name, value, _ := strings.Cut(color, "=")
+			colors[strings.TrimSpace(name)] = value
+		}
+	} else if v != "" {
+		path, err := util.ExpandPath(v)
+		if err != nil {
+			return nil, fmt.Errorf("ABS_THEME: %s", err.Error())
+		}
+
+		b, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(b, &colors)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("ABS_THEME: %s", err.Error())
+		}
+	}
+
+	theme := map[string]lipgloss.Color{}
+	for name, value := range colors {
+		if _, ok := themeColors[name]; !ok {
+			return nil, fmt.Errorf("ABS_THEME: unknown color '%s' (available: %s)", name, strings.Join(slices.Sorted(maps.Keys(themeColors)), ", "))
+		}
+
+		color, err := parseColor(value)
+		if err != nil {
+			return nil, fmt.Errorf("ABS_THEME: %s: %s", name, err.Error())
+		}
+
+		theme[name] = color
+	}
+
+	return theme, nil
+}
+
+// Applies the theme to the styles of the REPL, dropping
+// colors altogether if NO_COLOR is set (https://no-color.org)
+func applyTheme(env *object.Environment, theme map[string]lipgloss.Color) {
+	for name, color := range theme {
+		themeColors[name](color)
+	}
+
+	if util.GetEnvVar(env, "NO_COLOR", "") != "" {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
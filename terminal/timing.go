@@ -0,0 +1,54 @@
+package terminal
+
+import (
+	"time"
+
+	"github.com/abs-lang/abs/i18n"
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/util"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Whether evaluations start off timed,
+// through ABS_REPL_TIMING=1
+func isTiming(env *object.Environment) bool {
+	v := util.GetEnvVar(env, "ABS_REPL_TIMING", "")
+
+	return v == "1" || v == "true"
+}
+
+// :time on
+func (m Model) setTiming(args string) (Model, tea.Cmd) {
+	line := m.currentLine()
+	m = m.clearInput()
+
+	switch args {
+	case "on":
+		m.timing = true
+	case "off":
+		m.timing = false
+	case "":
+	default:
+		return m, tea.Println(line + "\n" + styleErr.Render(i18n.T("usage: :time on|off")))
+	}
+
+	msg := i18n.T("timing is off")
+	if m.timing {
+		msg = i18n.T("timing is on")
+	}
+
+	return m, tea.Println(line + "\n" + styleFaint.Render(msg))
+}
+
+// How long an evaluation took, and whether
+// it ran commands or was pure evaluation
+func renderTiming(res doneEval) string {
+	kind := i18n.T("evaluation")
+	if res.commands == 1 {
+		kind = i18n.T("1 command")
+	} else if res.commands > 1 {
+		kind = i18n.Sprintf("%d commands", res.commands)
+	}
+
+	return styleFaint.Render(i18n.Sprintf("took %s (%s)", res.elapsed.Round(time.Microsecond), kind))
+}
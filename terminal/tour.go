@@ -0,0 +1,283 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/runner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A step of 'abs tour': some text explaining
+// a feature of the language and a task the user
+// should complete by evaluating code.
+type lesson struct {
+	title string
+	text  string
+	task  string
+	// code that completes the task,
+	// shown when the user asks for it
+	solution string
+	// validates what the user evaluated
+	check func(out object.Object, env *object.Environment) bool
+}
+
+// The result must match the given string
+func resultIs(expected string) func(object.Object, *object.Environment) bool {
+	return func(out object.Object, env *object.Environment) bool {
+		return out.Inspect() == expected
+	}
+}
+
+// The given ABS code must evaluate to true
+// after the user's code ran, eg. "x == 1"
+func holds(code string) func(object.Object, *object.Environment) bool {
+	return func(out object.Object, env *object.Environment) bool {
+		res, ok, _ := runner.Run(code, env)
+
+		return ok && res == object.TRUE
+	}
+}
+
+var lessons = []lesson{
+	{
+		title:    "Hello ABS",
+		text:     "Welcome to the ABS tour! ABS is a scripting language that makes\nit easy to work with the shell. Each lesson comes with a task:\ntype some code, hit enter and ABS will evaluate it.",
+		task:     "Let's start simple: how much is 1 + 1?",
+		solution: "1 + 1",
+		check:    resultIs("2"),
+	},
+	{
+		title:    "Variables",
+		text:     "Variables are assigned with '=', and don't need to be declared:\n\n  name = \"ABS\"",
+		task:     "Assign 42 to a variable called 'answer'.",
+		solution: "answer = 42",
+		check:    holds("answer == 42"),
+	},
+	{
+		title:    "Strings",
+		text:     "Strings come with plenty of methods, such as split(),\nreplace() or upper():\n\n  \"hello world\".split(\" \") # [\"hello\", \"world\"]",
+		task:     "Turn \"hello\" into \"HELLO\".",
+		solution: "\"hello\".upper()",
+		check:    resultIs("HELLO"),
+	},
+	{
+		title:    "Arrays",
+		text:     "Arrays can hold values of any type, and have methods\nsuch as map() and filter() that take a function:\n\n  [1, 2, 3].filter(f(x) { x > 1 }) # [2, 3]",
+		task:     "Double every number in [1, 2, 3] (the result should be [2, 4, 6]).",
+		solution: "[1, 2, 3].map(f(x) { x * 2 })",
+		check:    resultIs("[2, 4, 6]"),
+	},
+	{
+		title:    "Hashes",
+		text:     "Hashes map keys to values. You can access them with\nbrackets or, more concisely, with a dot:\n\n  h = {\"lang\": \"abs\"}\n  h[\"lang\"] # \"abs\"",
+		task:     "Get the value of 'lang' out of {\"lang\": \"abs\"}.",
+		solution: "{\"lang\": \"abs\"}.lang",
+		check:    resultIs("abs"),
+	},
+	{
+		title:    "Functions",
+		text:     "Functions are declared with 'f', and return the value\nof their last expression:\n\n  f greet(name) { \"hello \" + name }",
+		task:     "Write a function called 'double' that doubles a number.",
+		solution: "f double(x) { x * 2 }",
+		check:    holds("double(21) == 42"),
+	},
+	{
+		title:    "Commands",
+		text:     "This is where ABS shines: system commands are wrapped in\nbackticks, and return their output as a string:\n\n  `date`",
+		task:     "Run the 'echo hello' command.",
+		solution: "`echo hello`",
+		check:    resultIs("hello"),
+	},
+	{
+		title:    "Did it work?",
+		text:     "The output of a command comes with an 'ok' property,\ntelling you whether the command succeeded:\n\n  `ls`.ok # true",
+		task:     "Check whether `ls /this/does/not/exist` succeeds (it shouldn't!).",
+		solution: "`ls /this/does/not/exist`.ok",
+		check:    resultIs("false"),
+	},
+	{
+		title: "That's it!",
+		text:  "You've completed the tour, congratulations! There's much more\nto learn: type 'help' in the REPL or head to https://www.abs-lang.org.",
+	},
+}
+
+var styleTourTitle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#4287f5"))
+var styleTourTask = styleCode
+var styleTourSuccess = styleDiffAdded
+
+// State of 'abs tour'
+type TourModel struct {
+	env     *object.Environment
+	lessons []lesson
+	current int
+	in      textinput.Model
+	// outcome of the last evaluation
+	output string
+	passed bool
+	// whether the user asked to see
+	// the solution of the current lesson
+	showSolution bool
+	isEvaluating bool
+}
+
+func NewTour(env *object.Environment) *tea.Program {
+	in := textinput.New()
+	in.Prompt = getPrompt(env)
+	in.Focus()
+
+	return tea.NewProgram(TourModel{env: env, lessons: lessons, in: in})
+}
+
+// result of evaluating the user's code
+type tourResult struct {
+	output string
+	passed bool
+}
+
+func (m TourModel) Init() tea.Cmd {
+	return tea.SetWindowTitle("abs-tour")
+}
+
+func (m TourModel) View() string {
+	l := m.lessons[m.current]
+	lines := Lines{}
+
+	lines.Add(styleTourTitle.Render(fmt.Sprintf("Lesson %d/%d: %s", m.current+1, len(m.lessons), l.title)))
+	lines.Add("")
+	lines.Add(l.text)
+
+	if l.check != nil {
+		lines.Add("")
+		lines.Add(styleTourTask.Render("Task: " + l.task))
+		lines.Add("")
+		lines.Add(m.in.View())
+	}
+
+	if m.output != "" {
+		lines.Add(m.output)
+	}
+
+	if m.passed {
+		lines.Add(styleTourSuccess.Render("✔ well done! Press enter to move on."))
+	}
+
+	if m.showSolution {
+		lines.Add(styleFaint.Render("solution: ") + l.solution)
+	}
+
+	lines.Add("")
+	lines.Add(styleFaint.Render("enter: run · ctrl+n: next · ctrl+p: previous · ctrl+s: solution · esc: quit"))
+
+	return lines.Join() + "\n"
+}
+
+func (m TourModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tourResult:
+		m.isEvaluating = false
+		m.output = msg.output
+		m.passed = msg.passed
+
+		return m, nil
+	case tea.KeyMsg:
+		if m.isEvaluating {
+			return m, nil
+		}
+
+		switch msg.Type {
+		case tea.KeyEsc, tea.KeyCtrlC, tea.KeyCtrlD:
+			return m, tea.Quit
+		case tea.KeyCtrlN:
+			return m.goTo(m.current + 1), nil
+		case tea.KeyCtrlP:
+			return m.goTo(m.current - 1), nil
+		case tea.KeyCtrlS:
+			m.showSolution = true
+			return m, nil
+		case tea.KeyEnter:
+			if m.passed || m.lessons[m.current].check == nil {
+				if m.current == len(m.lessons)-1 {
+					return m, tea.Quit
+				}
+
+				return m.goTo(m.current + 1), nil
+			}
+
+			return m.eval()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.in, cmd = m.in.Update(msg)
+
+	return m, cmd
+}
+
+func (m TourModel) goTo(i int) TourModel {
+	if i < 0 || i >= len(m.lessons) {
+		return m
+	}
+
+	m.current = i
+	m.output = ""
+	m.passed = false
+	m.showSolution = false
+	m.in.Reset()
+
+	return m
+}
+
+func (m TourModel) eval() (TourModel, tea.Cmd) {
+	code := m.in.Value()
+
+	if strings.TrimSpace(code) == "" {
+		return m, nil
+	}
+
+	m.isEvaluating = true
+	l := m.lessons[m.current]
+
+	return m, func() tea.Msg {
+		out, passed := runLesson(l, code, m.env)
+		return tourResult{out, passed}
+	}
+}
+
+// Evaluates the user's code and checks
+// whether it completes the lesson's task
+func runLesson(l lesson, code string, env *object.Environment) (string, bool) {
+	out, ok, parseErrors := runner.Run(code, env)
+	b, _ := io.ReadAll(env.Stdio.Stdout)
+	printed := strings.TrimSuffix(string(b), "\n")
+
+	if len(parseErrors) > 0 {
+		return styleErr.Render(strings.Join(parseErrors, "\n")), false
+	}
+
+	if !ok {
+		return styleErr.Render(out.Inspect()), false
+	}
+
+	lines := Lines{}
+
+	if printed != "" {
+		lines.Add(printed)
+	}
+
+	if out != object.NULL {
+		lines.Add(out.Inspect())
+	}
+
+	passed := l.check(out, env)
+
+	if !passed {
+		lines.Add(styleErr.Render("✘ not quite, try again (ctrl+s shows the solution)"))
+	}
+
+	return lines.Join(), passed
+}
@@ -0,0 +1,100 @@
+package terminal
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"sort"
+)
+
+// How often suggestions are picked, so that the most used ones
+// are suggested first. Counts are saved, as JSON, in a file next
+// to the history (eg. ~/.abs_history_usage), adding up the picks
+// of the sessions that ran since it was read.
+type suggestionUsage struct {
+	file string
+	// picks, including the ones of this session
+	counts map[string]int
+	// picks of this session only
+	session map[string]int
+}
+
+func usageFile(historyFile string) string {
+	return historyFile + "_usage"
+}
+
+// Loads how often suggestions have been picked,
+// unless the history is turned off
+func loadUsage(historyFile string, maxLines int) *suggestionUsage {
+	u := &suggestionUsage{counts: map[string]int{}, session: map[string]int{}}
+
+	if maxLines == 0 {
+		return u
+	}
+
+	u.file = usageFile(historyFile)
+	u.counts = readUsage(u.file)
+
+	return u
+}
+
+func readUsage(file string) map[string]int {
+	counts := map[string]int{}
+
+	if b, err := os.ReadFile(file); err == nil {
+		json.Unmarshal(b, &counts)
+	}
+
+	return counts
+}
+
+// Records that a suggestion has been picked: paths and
+// programs aren't tracked, as they depend on where
+// the REPL runs
+func (u *suggestionUsage) pick(s Suggestion) {
+	if u == nil || s.Type == SUGGESTION_PATH || s.Type == SUGGESTION_EXECUTABLE {
+		return
+	}
+
+	u.counts[s.Value]++
+	u.session[s.Value]++
+}
+
+func (u *suggestionUsage) count(value string) int {
+	if u == nil {
+		return 0
+	}
+
+	return u.counts[value]
+}
+
+// Adds the picks of this session to the ones
+// saved by other sessions in the meantime
+func (u *suggestionUsage) save() error {
+	if u == nil || u.file == "" || len(u.session) == 0 {
+		return nil
+	}
+
+	counts := readUsage(u.file)
+	for value, n := range u.session {
+		counts[value] += n
+	}
+
+	b, _ := json.Marshal(counts)
+	if err := os.WriteFile(u.file, b, 0664); err != nil {
+		return err
+	}
+
+	u.counts = maps.Clone(counts)
+	u.session = map[string]int{}
+
+	return nil
+}
+
+// Ranks suggestions by how often they've been picked, keeping
+// the ones picked as often in the order they're in
+func (u *suggestionUsage) rank(suggestions []Suggestion) {
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return u.count(suggestions[i].Value) > u.count(suggestions[j].Value)
+	})
+}
@@ -1,57 +1,70 @@
 package terminal
 
 import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"maps"
+	mrand "math/rand"
 	"os"
 	"os/user"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/abs-lang/abs/object"
 	"github.com/abs-lang/abs/util"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
 const ABS_DEFAULT_PROMPT = "> "
 
-var exampleStatements = []string{
-	"`ls -la`",
-	"`cat /etc/hosts`",
-	"['a', 'b', 'c'].map(f(l) {l.upper()})",
-	"1..10",
-	"1 in [0,1,2,3,4]",
-	"'string' ~ 'sTrINg'",
-	"true || sleep(1000)",
-	"true && sleep(1000)",
-	"one, two, three = [1, 2, 3]",
-	"!!true",
-	"10 ** 2",
-	"6 <=> 5",
-	"{'x': 1}?.x?.x",
-	"defer echo(1); echo(2)",
-	"\"hello world\"[-2]",
-	"\"hello world\"[:5]",
-	"\"hello %s\".fmt(\"world\")",
-	"`cat /etc/hosts`.lines()",
-	"10.3.ceil()",
-	"[1, 2] + [3]",
-	"[{'name': 'Lebron', 'age': 40}, {'name': 'Michael', 'age': 'older...'}].tsv()",
-	"f greeter(greeting = 'hello'){ '%s world'.fmt(greeting) }",
-	"f increment(n, i = 1) {n+i}",
-	"for x in 1..100 { echo(x**2) }",
-	"`touch /tmp/file.txt`.ok",
-	"'ach' in 'zachary'",
-	"'my_string'.camel()'",
-	"'myString'.kebab()'",
-	"x, y, z = [1, 2, 3]",
-	"[1.1, 2.2, 3.3].map(int)",
-	"f numargs() { return ....len() }; numargs(1,2,3,4)",
-	"sleep(1000)",
-	"u = require('@util'); @u.memoize(60) f slow() {sleep(1000)}; slow(); echo(1); slow(); echo(2)",
+// Examples of ABS code, by topic (eg. "strings"),
+// loaded from the files in the examples directory:
+// each line holds a statement, lines starting with
+// '#' are comments.
+//
+//go:embed examples/*.abs
+var examplesFS embed.FS
+var examples = loadExamples()
+
+func loadExamples() map[string][]string {
+	examples := map[string][]string{}
+	files, _ := fs.Glob(examplesFS, "examples/*.abs")
+
+	for _, file := range files {
+		b, _ := examplesFS.ReadFile(file)
+		topic := strings.TrimSuffix(path.Base(file), ".abs")
+
+		for _, l := range strings.Split(string(b), "\n") {
+			l = strings.TrimSpace(l)
+
+			if l == "" || strings.HasPrefix(l, "#") {
+				continue
+			}
+
+			examples[topic] = append(examples[topic], l)
+		}
+	}
+
+	return examples
+}
+
+func exampleTopics() []string {
+	return slices.Sorted(maps.Keys(examples))
+}
+
+func randomExample() string {
+	statements := examples[exampleTopics()[mrand.Intn(len(examples))]]
+
+	return statements[mrand.Intn(len(statements))]
 }
 
 func getPrompt(env *object.Environment) string {
 	prompt := util.GetEnvVar(env, "ABS_PROMPT_PREFIX", ABS_DEFAULT_PROMPT)
-	prompt = lipgloss.NewStyle().Foreground(lipgloss.Color("#4287f5")).Render(prompt)
+	prompt = stylePrompt.Render(prompt)
 	livePrompt := util.GetEnvVar(env, "ABS_PROMPT_LIVE_PREFIX", "false")
 
 	if livePrompt == "true" {
@@ -106,3 +119,117 @@ func applySuggestion(s, textToReplace, suggestion string) string {
 
 	return head + suggestion + tail
 }
+
+// Line-by-line diff between 2 outputs: lines
+// only found in the old one are prefixed by "-",
+// lines only found in the new one by "+".
+func diffLines(old, new []string) []string {
+	// lcs[i][j] is the length of the longest common
+	// subsequence of old[i:] and new[j:]
+	lcs := make([][]int, len(old)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, len(new)+1)
+	}
+
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			if old[i] == new[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := []string{}
+	i, j := 0, 0
+
+	for i < len(old) && j < len(new) {
+		switch {
+		case old[i] == new[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "- "+old[i])
+			i++
+		default:
+			diff = append(diff, "+ "+new[j])
+			j++
+		}
+	}
+
+	for ; i < len(old); i++ {
+		diff = append(diff, "- "+old[i])
+	}
+
+	for ; j < len(new); j++ {
+		diff = append(diff, "+ "+new[j])
+	}
+
+	return diff
+}
+
+func renderDiff(diff []string) string {
+	lines := Lines{}
+
+	for _, l := range diff {
+		if strings.HasPrefix(l, "-") {
+			lines.Add(styleDiffRemoved.Render(l))
+		} else {
+			lines.Add(styleDiffAdded.Render(l))
+		}
+	}
+
+	return lines.Join()
+}
+
+// Where a parse error happened, as reported
+// by the parser: "\t[line:column]\tsource line"
+var parseErrorLocation = regexp.MustCompile(`^\t\[(\d+):(\d+)\]\t(.*)$`)
+
+// Renders parse errors with the line they're
+// on and a caret pointing at their column, eg.
+//
+//	expected next token to be NUMBER, got } instead
+//	3 |   b = [1,2
+//	  |          ^
+func renderParseErrors(errors []string) Lines {
+	lines := Lines{}
+
+	for n, e := range errors {
+		msg, location, _ := strings.Cut(e, "\n")
+		prefix := fmt.Sprintf("%d) ", n+1)
+		indent := strings.Repeat(" ", len(prefix))
+		lines.Add(styleErr.Render("  " + prefix + msg))
+
+		match := parseErrorLocation.FindStringSubmatch(location)
+		if match == nil {
+			if location != "" {
+				lines.Add(styleErr.Render("  " + indent + location))
+			}
+			continue
+		}
+
+		column, _ := strconv.Atoi(match[2])
+		source := match[3]
+		gutter := strings.Repeat(" ", len(match[1]))
+
+		// columns count bytes, and tabs are kept
+		// (and left unstyled, as lipgloss expands
+		// them) so that the caret lines up with
+		// the source
+		before := source[:min(max(column-1, 0), len(source))]
+		padding := strings.Map(func(r rune) rune {
+			if r == '\t' {
+				return r
+			}
+			return ' '
+		}, before)
+
+		lines.Add(styleFaint.Render("  "+indent+match[1]+" | ") + source)
+		lines.Add(styleFaint.Render("  "+indent+gutter+" | ") + padding + styleErr.Render("^"))
+	}
+
+	return lines
+}
@@ -3,15 +3,30 @@ package terminal
 import (
 	"bufio"
 	"io"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/abs-lang/abs/lexer"
 	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/parser"
 	"github.com/abs-lang/abs/runner"
 )
 
 func TestAssignStatements(t *testing.T) {
-	for _, stmt := range exampleStatements {
+	statements := []string{}
+
+	for topic, s := range examples {
+		// these need network access
+		if topic == "http" {
+			continue
+		}
+
+		statements = append(statements, s...)
+	}
+
+	for _, stmt := range statements {
 		discard := bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bufio.NewWriter(io.Discard))
 		stdio := &object.Stdio{Stdin: discard, Stdout: discard, Stderr: discard}
 		_, ok, errs := runner.Run(stmt, object.NewEnvironment(stdio, ".", "test", false))
@@ -35,3 +50,110 @@ func TestApplySuggestions(t *testing.T) {
 		}
 	}
 }
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		old      []string
+		new      []string
+		expected []string
+	}{
+		{[]string{"a", "b"}, []string{"a", "b"}, []string{}},
+		{[]string{"a", "b"}, []string{"a", "b", "c"}, []string{"+ c"}},
+		{[]string{"a", "b", "c"}, []string{"a", "c"}, []string{"- b"}},
+		{[]string{"a", "b"}, []string{"a", "x"}, []string{"- b", "+ x"}},
+		{[]string{}, []string{"a"}, []string{"+ a"}},
+	}
+
+	for _, tt := range tests {
+		diff := diffLines(tt.old, tt.new)
+
+		if strings.Join(diff, "|") != strings.Join(tt.expected, "|") {
+			t.Fatalf("diff of %v and %v: got %v exp %v", tt.old, tt.new, diff, tt.expected)
+		}
+	}
+}
+
+func TestRenderParseErrors(t *testing.T) {
+	p := parser.New(lexer.New("a = 1\nif a {\n\tb = [1,2\n}"))
+	p.ParseProgram()
+
+	lines := renderParseErrors(append(p.Errors(), "an error without location"))
+	exp := []string{
+		"  1) expected next token to be NUMBER, got } instead",
+		"     3 | \tb = [1,2",
+		"       | \t       ^",
+		"  2) an error without location",
+	}
+
+	if strings.Join(lines, "\n") != strings.Join(exp, "\n") {
+		t.Fatalf("wrong parse errors:\n%s\nexpected:\n%s", strings.Join(lines, "\n"), strings.Join(exp, "\n"))
+	}
+}
+
+func TestReferencedFiles(t *testing.T) {
+	f, err := os.CreateTemp("", "abs-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{"1 + 1", []string{}},
+		{"`cat " + f.Name() + "`", []string{f.Name()}},
+		{"`cat " + f.Name() + " " + f.Name() + "`", []string{f.Name()}},
+		{"x = \"" + f.Name() + "\"", []string{f.Name()}},
+		{"`cat /this/does/not/exist`", []string{}},
+	}
+
+	for _, tt := range tests {
+		paths := referencedFiles(tt.expr)
+
+		if strings.Join(paths, "|") != strings.Join(tt.expected, "|") {
+			t.Fatalf("files referenced by %s: got %v exp %v", tt.expr, paths, tt.expected)
+		}
+	}
+}
+
+func TestParseWatchArgs(t *testing.T) {
+	tests := []struct {
+		args     string
+		expr     string
+		interval time.Duration
+	}{
+		{"`uptime`", "`uptime`", 0},
+		{"`uptime` 5", "`uptime`", 5 * time.Second},
+		{"x 0.5", "x", 500 * time.Millisecond},
+		{"x + 5", "x + 5", 0},
+		{"x 0", "x 0", 0},
+		{"5", "5", 0},
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		expr, interval := parseWatchArgs(tt.args)
+
+		if expr != tt.expr || interval != tt.interval {
+			t.Fatalf("':watch %s': got (%s, %s) exp (%s, %s)", tt.args, expr, interval, tt.expr, tt.interval)
+		}
+	}
+}
+
+func TestExamplesParse(t *testing.T) {
+	for _, topic := range []string{"arrays", "commands", "http", "language", "strings"} {
+		if len(examples[topic]) == 0 {
+			t.Fatalf("no examples about %s", topic)
+		}
+
+		for _, stmt := range examples[topic] {
+			p := parser.New(lexer.New(stmt))
+			p.ParseProgram()
+
+			if len(p.Errors()) > 0 {
+				t.Fatalf("%s (code parsed: %s)", p.Errors()[0], stmt)
+			}
+		}
+	}
+}
@@ -0,0 +1,497 @@
+package terminal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/util"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Editing modes of the input, see getEditingMode()
+const (
+	EDITING_MODE_EMACS = "emacs"
+	EDITING_MODE_VI    = "vi"
+)
+
+// How many changes 'u' can undo in vi mode
+const VI_MAX_UNDO = 100
+
+// State of the vi editing mode (ABS_EDITING_MODE=vi): in insert
+// mode keys are typed into the input, as usual, while in normal
+// mode they move around and edit it (eg. 'w', 'dd' or '2cw').
+type viEditor struct {
+	normal bool
+	// count, operator and motion typed so
+	// far in normal mode, eg. "2d" or "df"
+	pending []rune
+	// text deleted or yanked, put back by 'p'
+	register []rune
+	// the input before each change, for 'u'
+	undo []viSnapshot
+}
+
+type viSnapshot struct {
+	value  []rune
+	cursor int
+}
+
+// A command of the normal mode, eg. "2dw" (count 2,
+// operator d, motion w) or "fx" (motion f, char x)
+type viCommand struct {
+	count  int
+	op     rune
+	motion rune
+	char   rune
+}
+
+// Reads the editing mode from ABS_EDITING_MODE, returning
+// the vi editor in vi mode and nil in emacs mode, where
+// bubbletea's bindings (ctrl+a, ctrl+e...) are all there is
+func getEditingMode(env *object.Environment) *viEditor {
+	mode := util.GetEnvVar(env, "ABS_EDITING_MODE", EDITING_MODE_EMACS)
+
+	switch mode {
+	case EDITING_MODE_VI:
+		return newViEditor()
+	case EDITING_MODE_EMACS:
+	default:
+		fmt.Printf("ABS_EDITING_MODE must be either emacs or vi: %s; using default: %s\n", mode, EDITING_MODE_EMACS)
+	}
+
+	return nil
+}
+
+// Like shells, we start (and get back to, once
+// something is submitted) in insert mode
+func newViEditor() *viEditor {
+	return &viEditor{undo: []viSnapshot{{value: []rune{}}}}
+}
+
+// Handles keys in vi mode, returning whether they were:
+// keys that aren't are left to the keymap, so that enter
+// submits the input and ctrl+d quits in both modes
+func (m Model) onViKey(msg tea.KeyMsg) (Model, bool) {
+	v := m.vi
+
+	if !v.normal {
+		if msg.Type != tea.KeyEsc {
+			return m, false
+		}
+
+		value, cursor := v.exitInsert([]rune(m.in.Value()), m.in.Position())
+		m.in.SetValue(string(value))
+		m.in.SetCursor(cursor)
+
+		return m, true
+	}
+
+	// esc cancels what's been typed so far
+	if msg.Type == tea.KeyEsc {
+		v.pending = nil
+		return m, true
+	}
+
+	if m.keys.action(msg) != "" {
+		v.pending = nil
+		return m, false
+	}
+
+	if len(v.pending) == 0 {
+		switch msg.String() {
+		case "k":
+			return m.prevHistory(), true
+		case "j":
+			return m.nextHistory(), true
+		}
+	}
+
+	value, cursor := v.key(msg.String(), []rune(m.in.Value()), m.in.Position())
+	m.in.SetValue(string(value))
+	m.in.SetCursor(cursor)
+
+	return m, true
+}
+
+// Whether keys should be kept away from the input,
+// as they are commands rather than text
+func (m Model) isViNormal() bool {
+	return m.vi != nil && m.vi.normal
+}
+
+// Back to insert mode, once the input is submitted
+func (v *viEditor) reset() {
+	v.normal = false
+	v.pending = nil
+	v.undo = []viSnapshot{{value: []rune{}}}
+}
+
+// Leaves insert mode, with the cursor moving back
+// on the last character typed, like in vi
+func (v *viEditor) exitInsert(value []rune, cursor int) ([]rune, int) {
+	v.normal = true
+	v.pending = nil
+
+	// nothing was typed, so there's nothing to undo
+	if len(v.undo) > 0 && slices.Equal(v.undo[len(v.undo)-1].value, value) {
+		v.undo = v.undo[:len(v.undo)-1]
+	}
+
+	return value, max(0, cursor-1)
+}
+
+// Handles a key in normal mode, returning the new
+// input and the position of the cursor
+func (v *viEditor) key(key string, value []rune, cursor int) ([]rune, int) {
+	switch key {
+	case "left", "backspace":
+		key = "h"
+	case "right":
+		key = "l"
+	case "home":
+		key = "0"
+	case "end":
+		key = "$"
+	}
+
+	r := []rune(key)
+	if len(r) != 1 {
+		v.pending = nil
+		return value, cursor
+	}
+
+	cmd, complete, ok := parseViCommand(append(v.pending, r[0]))
+	if !ok {
+		v.pending = nil
+		return value, cursor
+	}
+
+	if !complete {
+		v.pending = append(v.pending, r[0])
+		return value, cursor
+	}
+
+	v.pending = nil
+
+	if cmd.op == 0 && cmd.motion == 'u' {
+		if len(v.undo) == 0 {
+			return value, cursor
+		}
+
+		last := v.undo[len(v.undo)-1]
+		v.undo = v.undo[:len(v.undo)-1]
+
+		return slices.Clone(last.value), min(last.cursor, max(0, len(last.value)-1))
+	}
+
+	before := viSnapshot{value: value, cursor: cursor}
+	value, cursor = v.apply(cmd, slices.Clone(value), cursor)
+
+	// changes, and the text typed after them,
+	// are undone at once
+	if !v.normal || !slices.Equal(before.value, value) {
+		v.undo = append(v.undo, before)
+		v.undo = v.undo[max(0, len(v.undo)-VI_MAX_UNDO):]
+	}
+
+	if v.normal && len(value) > 0 {
+		cursor = min(cursor, len(value)-1)
+	}
+
+	return value, max(0, cursor)
+}
+
+// Parses the keys typed in normal mode, eg. "2dw" or "dfx",
+// telling whether they're a complete command and whether
+// they could be one at all
+func parseViCommand(keys []rune) (cmd viCommand, complete bool, ok bool) {
+	i := 0
+	count := func() int {
+		n := 0
+		for i < len(keys) && unicode.IsDigit(keys[i]) && (n > 0 || keys[i] != '0') {
+			n = n*10 + int(keys[i]-'0')
+			i++
+		}
+
+		return max(1, n)
+	}
+
+	cmd.count = count()
+
+	if i < len(keys) && strings.ContainsRune("dcy", keys[i]) {
+		cmd.op = keys[i]
+		i++
+		cmd.count *= count()
+	}
+
+	if i == len(keys) {
+		return cmd, false, true
+	}
+
+	cmd.motion = keys[i]
+	i++
+
+	if strings.ContainsRune("fFtTr", cmd.motion) {
+		if i == len(keys) {
+			return cmd, false, true
+		}
+
+		cmd.char = keys[i]
+		i++
+	}
+
+	return cmd, true, i == len(keys)
+}
+
+func (v *viEditor) apply(cmd viCommand, value []rune, cursor int) ([]rune, int) {
+	// Operators, either on the whole input (dd, cc, yy)
+	// or from the cursor to where a motion lands
+	if cmd.op != 0 {
+		from, to := 0, len(value)
+
+		if cmd.motion != cmd.op {
+			motion := cmd.motion
+
+			// cw changes the rest of the word, not
+			// the space after it, like ce
+			if cmd.op == 'c' && cursor < len(value) && !unicode.IsSpace(value[cursor]) {
+				switch motion {
+				case 'w':
+					motion = 'e'
+				case 'W':
+					motion = 'E'
+				}
+			}
+
+			target, inclusive, ok := viMotion(value, cursor, viCommand{count: cmd.count, motion: motion, char: cmd.char})
+			if !ok {
+				return value, cursor
+			}
+
+			from, to = min(cursor, target), max(cursor, target)
+			if inclusive {
+				to = min(len(value), to+1)
+			}
+		}
+
+		v.register = slices.Clone(value[from:to])
+
+		switch cmd.op {
+		case 'y':
+			return value, from
+		case 'c':
+			v.normal = false
+		}
+
+		return slices.Delete(value, from, to), from
+	}
+
+	switch cmd.motion {
+	case 'i':
+		v.normal = false
+	case 'a':
+		v.normal = false
+		cursor = min(len(value), cursor+1)
+	case 'I':
+		v.normal = false
+		cursor = firstNonSpace(value)
+	case 'A':
+		v.normal = false
+		cursor = len(value)
+	case 'x':
+		return v.apply(viCommand{count: cmd.count, op: 'd', motion: 'l'}, value, cursor)
+	case 'X':
+		return v.apply(viCommand{count: cmd.count, op: 'd', motion: 'h'}, value, cursor)
+	case 's':
+		return v.apply(viCommand{count: cmd.count, op: 'c', motion: 'l'}, value, cursor)
+	case 'D':
+		return v.apply(viCommand{count: 1, op: 'd', motion: '$'}, value, cursor)
+	case 'C':
+		return v.apply(viCommand{count: 1, op: 'c', motion: '$'}, value, cursor)
+	case 'S':
+		return v.apply(viCommand{count: 1, op: 'c', motion: 'c'}, value, cursor)
+	case 'Y':
+		return v.apply(viCommand{count: 1, op: 'y', motion: 'y'}, value, cursor)
+	case 'p', 'P':
+		if len(v.register) == 0 {
+			return value, cursor
+		}
+
+		at := cursor
+		if cmd.motion == 'p' && len(value) > 0 {
+			at = min(len(value), cursor+1)
+		}
+
+		text := slices.Repeat(v.register, cmd.count)
+		return slices.Insert(value, at, text...), at + len(text) - 1
+	case 'r':
+		if cursor+cmd.count > len(value) {
+			return value, cursor
+		}
+
+		for i := cursor; i < cursor+cmd.count; i++ {
+			value[i] = cmd.char
+		}
+
+		return value, cursor + cmd.count - 1
+	case '~':
+		end := min(len(value), cursor+cmd.count)
+		for i := cursor; i < end; i++ {
+			if unicode.IsUpper(value[i]) {
+				value[i] = unicode.ToLower(value[i])
+			} else {
+				value[i] = unicode.ToUpper(value[i])
+			}
+		}
+
+		return value, end
+	default:
+		if target, _, ok := viMotion(value, cursor, cmd); ok {
+			cursor = target
+		}
+	}
+
+	return value, cursor
+}
+
+// Where a motion (eg. "w" or "fx") lands from the cursor, and
+// whether operators should include the character it lands on
+func viMotion(value []rune, cursor int, cmd viCommand) (target int, inclusive bool, ok bool) {
+	target = cursor
+
+	for n := 0; n < cmd.count; n++ {
+		switch cmd.motion {
+		case 'h':
+			target = max(0, target-1)
+		case 'l', ' ':
+			target = min(len(value), target+1)
+		case '0':
+			return 0, false, true
+		case '^':
+			return firstNonSpace(value), false, true
+		case '$':
+			return len(value), false, true
+		case 'w', 'W':
+			target = nextWord(value, target, cmd.motion == 'W')
+		case 'b', 'B':
+			target = prevWord(value, target, cmd.motion == 'B')
+		case 'e', 'E':
+			target, inclusive = endOfWord(value, target, cmd.motion == 'E'), true
+		case 'f', 't':
+			i := slices.Index(value[min(len(value), target+1):], cmd.char)
+			if i < 0 {
+				return cursor, false, false
+			}
+
+			target, inclusive = target+1+i, true
+		case 'F', 'T':
+			i := slices.Index(reversed(value[:target]), cmd.char)
+			if i < 0 {
+				return cursor, false, false
+			}
+
+			target = target - 1 - i
+		default:
+			return cursor, false, false
+		}
+	}
+
+	// t and T stop right before the character
+	switch cmd.motion {
+	case 't':
+		target--
+	case 'T':
+		target++
+	}
+
+	return target, inclusive, true
+}
+
+// Characters of a word: words are made of letters,
+// digits and underscores, or of other symbols, while
+// WORDS (eg. 'W') are anything but spaces
+func wordClass(r rune, bigWord bool) int {
+	switch {
+	case unicode.IsSpace(r):
+		return 0
+	case bigWord, unicode.IsLetter(r), unicode.IsDigit(r), r == '_':
+		return 1
+	default:
+		return 2
+	}
+}
+
+func nextWord(value []rune, i int, bigWord bool) int {
+	if i >= len(value) {
+		return len(value)
+	}
+
+	class := wordClass(value[i], bigWord)
+	for i < len(value) && class != 0 && wordClass(value[i], bigWord) == class {
+		i++
+	}
+
+	for i < len(value) && wordClass(value[i], bigWord) == 0 {
+		i++
+	}
+
+	return i
+}
+
+func prevWord(value []rune, i int, bigWord bool) int {
+	i = min(i, len(value)) - 1
+
+	for i > 0 && wordClass(value[i], bigWord) == 0 {
+		i--
+	}
+
+	if i <= 0 {
+		return 0
+	}
+
+	class := wordClass(value[i], bigWord)
+	for i > 0 && wordClass(value[i-1], bigWord) == class {
+		i--
+	}
+
+	return i
+}
+
+func endOfWord(value []rune, i int, bigWord bool) int {
+	i++
+
+	for i < len(value) && wordClass(value[i], bigWord) == 0 {
+		i++
+	}
+
+	if i >= len(value) {
+		return max(0, len(value)-1)
+	}
+
+	class := wordClass(value[i], bigWord)
+	for i+1 < len(value) && wordClass(value[i+1], bigWord) == class {
+		i++
+	}
+
+	return i
+}
+
+func firstNonSpace(value []rune) int {
+	for i, r := range value {
+		if !unicode.IsSpace(r) {
+			return i
+		}
+	}
+
+	return len(value)
+}
+
+func reversed(value []rune) []rune {
+	r := slices.Clone(value)
+	slices.Reverse(r)
+
+	return r
+}
@@ -0,0 +1,295 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abs-lang/abs/i18n"
+	"github.com/abs-lang/abs/lexer"
+	"github.com/abs-lang/abs/object"
+	"github.com/abs-lang/abs/parser"
+	"github.com/abs-lang/abs/runner"
+	"github.com/abs-lang/abs/token"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// How often we check whether
+// watched files have changed
+const watchPollInterval = 500 * time.Millisecond
+
+// How often expressions that don't reference
+// any file are re-evaluated, as in watch(1)
+const watchDefaultInterval = 2 * time.Second
+
+// State of a ':watch expr' session: the expression
+// is re-evaluated whenever one of the files it
+// references changes, and we print what changed
+// in its output. With an interval, it's re-evaluated
+// that often instead, and its output redrawn in place.
+type watcher struct {
+	expr     string
+	paths    []string
+	mtimes   map[string]time.Time
+	interval time.Duration
+	output   string
+	// when the output was last updated
+	at time.Time
+	// whether we evaluated the expression
+	// at least once
+	started bool
+}
+
+// the watched expression has been evaluated
+type watchResult struct {
+	w      *watcher
+	output string
+}
+
+// time to check whether watched files changed
+type watchTick struct {
+	w *watcher
+}
+
+// :watch `tail -n 5 /var/log/syslog` or :watch `uptime` 5
+func (m Model) watch(args string) (Model, tea.Cmd) {
+	if len(m.jobs) > 0 {
+		return m.jobRunning()
+	}
+
+	line := m.currentLine()
+	m = m.clearInput()
+	expr, interval := parseWatchArgs(args)
+
+	if expr == "" {
+		return m, tea.Println(line + "\n" + styleErr.Render(i18n.T("usage: :watch expr [interval]")))
+	}
+
+	w := &watcher{expr: expr, interval: interval}
+	if interval == 0 {
+		w.paths = referencedFiles(expr)
+		w.mtimes = w.stat()
+
+		if len(w.paths) == 0 {
+			w.interval = watchDefaultInterval
+		}
+	}
+
+	m.watching = w
+	m.in.Blur()
+
+	// with an interval, the output is
+	// redrawn in place (see View)
+	if w.interval > 0 {
+		return m, tea.Sequence(tea.Println(line), m.evalWatched(w))
+	}
+
+	msg := line + "\n" + styleFaint.Render(i18n.Sprintf("watching %s (ctrl+c to stop)", strings.Join(w.paths, ", ")))
+
+	return m, tea.Sequence(tea.Println(msg), m.evalWatched(w))
+}
+
+// Splits ':watch expr 5' into the expression and how
+// often to evaluate it: a trailing number is only an
+// interval if what comes before it is an expression
+// on its own (eg. not in ':watch x + 5')
+func parseWatchArgs(args string) (string, time.Duration) {
+	args = strings.TrimSpace(args)
+	i := strings.LastIndexAny(args, " \t")
+	if i < 0 {
+		return args, 0
+	}
+
+	seconds, err := strconv.ParseFloat(args[i+1:], 64)
+	expr := strings.TrimSpace(args[:i])
+
+	if err != nil || seconds <= 0 || expr == "" {
+		return args, 0
+	}
+
+	p := parser.New(lexer.New(expr))
+	p.ParseProgram()
+
+	if len(p.Errors()) > 0 {
+		return args, 0
+	}
+
+	return expr, time.Duration(seconds * float64(time.Second))
+}
+
+func (m Model) stopWatching() (Model, tea.Cmd) {
+	w := m.watching
+	m.watching = nil
+	m.in.Focus()
+
+	msg := styleFaint.Render(i18n.T("stopped watching"))
+
+	// what was redrawn in place stays
+	// around once we stop watching
+	if w.interval > 0 && w.started {
+		msg = w.output + "\n" + msg
+	}
+
+	return m, tea.Println(msg)
+}
+
+// The header and output of a watch
+// with an interval, refreshed in place
+func (m Model) renderWatching() string {
+	w := m.watching
+
+	if w.interval == 0 {
+		return styleFaint.Render(i18n.Sprintf("watching %s (ctrl+c to stop)", w.expr))
+	}
+
+	header := styleFaint.Render(i18n.Sprintf("every %s: %s (ctrl+c to stop)", w.interval, w.expr))
+	if !w.started {
+		return header
+	}
+
+	return header + "  " + styleFaint.Render(w.at.Format(time.TimeOnly)) + "\n\n" + w.output
+}
+
+func (m Model) onWatchResult(res watchResult) (Model, tea.Cmd) {
+	// stale result from a watch that's gone
+	if res.w != m.watching {
+		return m, nil
+	}
+
+	w := m.watching
+	var print tea.Cmd
+
+	if w.interval > 0 {
+		w.started = true
+		w.output, w.at = res.output, time.Now()
+
+		return m, watchAfter(w, w.interval)
+	}
+
+	if !w.started {
+		w.started = true
+		print = tea.Println(res.output)
+	} else if diff := diffLines(lines(w.output), lines(res.output)); len(diff) > 0 {
+		print = tea.Println(renderDiff(diff))
+	}
+
+	w.output = res.output
+
+	return m, tea.Batch(print, watchAfter(w, watchPollInterval))
+}
+
+func (m Model) onWatchTick(tick watchTick) (Model, tea.Cmd) {
+	if tick.w != m.watching {
+		return m, nil
+	}
+
+	w := m.watching
+	if w.interval > 0 {
+		return m, m.evalWatched(w)
+	}
+
+	mtimes := w.stat()
+	changed := false
+
+	for path, mtime := range mtimes {
+		if !w.mtimes[path].Equal(mtime) {
+			changed = true
+		}
+	}
+
+	w.mtimes = mtimes
+
+	if changed {
+		return m, m.evalWatched(w)
+	}
+
+	return m, watchAfter(w, watchPollInterval)
+}
+
+// Evaluates the watched expression in background,
+// collecting anything it printed along with its result.
+func (m Model) evalWatched(w *watcher) tea.Cmd {
+	return func() tea.Msg {
+		out, ok, parseErrors := runner.Run(w.expr, m.env)
+		b, _ := io.ReadAll(m.env.Stdio.Stdout)
+		output := strings.TrimSuffix(string(b), "\n")
+
+		if len(parseErrors) > 0 {
+			output = styleErr.Render(strings.Join(parseErrors, "\n"))
+		} else if out != object.NULL {
+			res := m.renderResult(out, ok)
+
+			if output != "" {
+				output += "\n"
+			}
+
+			output += res
+		}
+
+		return watchResult{w: w, output: output}
+	}
+}
+
+func watchAfter(w *watcher, d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return watchTick{w: w}
+	})
+}
+
+// Modification times of the watched files -- files
+// that were removed show up with a zero time.
+func (w *watcher) stat() map[string]time.Time {
+	mtimes := map[string]time.Time{}
+
+	for _, path := range w.paths {
+		mtime := time.Time{}
+
+		if info, err := os.Stat(path); err == nil {
+			mtime = info.ModTime()
+		}
+
+		mtimes[path] = mtime
+	}
+
+	return mtimes
+}
+
+// Finds the files (or directories) an expression references,
+// either through strings ("/etc/hosts") or
+// commands (`cat /etc/hosts`).
+func referencedFiles(expr string) []string {
+	paths := []string{}
+	seen := map[string]bool{}
+	l := lexer.New(expr)
+
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		if tok.Type != token.STRING && tok.Type != token.COMMAND {
+			continue
+		}
+
+		for _, word := range strings.Fields(tok.Literal) {
+			word = strings.Trim(word, `"'`)
+
+			if seen[word] {
+				continue
+			}
+
+			if _, err := os.Stat(word); err == nil {
+				seen[word] = true
+				paths = append(paths, word)
+			}
+		}
+	}
+
+	return paths
+}
+
+func lines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+
+	return strings.Split(s, "\n")
+}
@@ -7,12 +7,14 @@ const (
 	EOF     = "EOF"
 
 	// Identifiers + literals
-	IDENT        = "IDENT"  // add, foobar, x, y, ...
-	NUMBER       = "NUMBER" // 1343456, 1.23456
-	STRING       = "STRING" // "foobar"
-	AT           = "@"      // @ At symbol
-	NULL         = "NULL"   // # null
-	CURRENT_ARGS = "..."    // # ... function args
+	IDENT        = "IDENT"    // add, foobar, x, y, ...
+	NUMBER       = "NUMBER"   // 1343456, 1.23456
+	DURATION     = "DURATION" // 5s, 200ms
+	SIZE         = "SIZE"     // 10MB, 4KiB
+	STRING       = "STRING"   // "foobar"
+	AT           = "@"        // @ At symbol
+	NULL         = "NULL"     // # null
+	CURRENT_ARGS = "..."      // # ... function args
 
 	// Operators
 	TILDE         = "~"
@@ -30,6 +32,8 @@ const (
 	COMP_SLASH    = "/="
 	COMP_EXPONENT = "**="
 	COMP_MODULO   = "%="
+	COMP_OR       = "||="
+	COMP_NULLISH  = "??="
 	RANGE         = ".."
 
 	// Logical operators
@@ -116,6 +120,31 @@ var NumberAbbreviations = map[string]float64{
 	"t": 1000000000000,
 }
 
+// DurationUnits are the suffixes of a duration literal (eg. 5s, 200ms),
+// expressed in nanoseconds
+var DurationUnits = map[string]float64{
+	"ns":  1,
+	"us":  1e3,
+	"ms":  1e6,
+	"s":   1e9,
+	"min": 60e9,
+	"h":   3600e9,
+	"d":   86400e9,
+}
+
+// SizeUnits are the suffixes of a size literal (eg. 10MB, 4KiB),
+// expressed in bytes
+var SizeUnits = map[string]float64{
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+}
+
 // NumberSeparator is a separator for numbers eg. 1_000_000
 var NumberSeparator = '_'
 
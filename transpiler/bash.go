@@ -0,0 +1,632 @@
+// Package transpiler converts ABS scripts to other
+// languages, for the environments that can't run
+// the abs binary. It's experimental: only the subset
+// of ABS that maps cleanly to the target is supported,
+// anything else is reported rather than approximated.
+package transpiler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/abs-lang/abs/ast"
+	"github.com/abs-lang/abs/lexer"
+	"github.com/abs-lang/abs/parser"
+)
+
+// The kinds of values the bash target knows about:
+// bash has no types, so we keep track of them to
+// pick the right operators (eg. -eq vs =)
+type kind int
+
+const (
+	kindString kind = iota
+	kindNumber
+	kindBool
+	// the output of a command, whose
+	// exit code is kept next to it
+	kindCommand
+)
+
+// Error is returned when a script uses
+// something the target doesn't support
+type Error struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+type bash struct {
+	lex   *lexer.Lexer
+	vars  map[string]kind
+	lines []string
+	depth int
+}
+
+// Bash (code, name) converts an ABS script to bash. It supports
+// variables (strings, whole numbers, booleans and the output
+// of commands), commands, if / else if / else, echo(...),
+// exit(...) and env(...).
+func Bash(code string, name string) (string, error) {
+	l := lexer.New(code)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		return "", fmt.Errorf("parser errors:\n\t%s", strings.Join(p.Errors(), "\n\t"))
+	}
+
+	b := &bash{lex: l, vars: map[string]kind{}}
+	b.emit("#!/usr/bin/env bash")
+	b.emit(fmt.Sprintf("# Transpiled from %s by abs transpile --target bash (experimental)", name))
+	b.emit("")
+
+	if err := b.statements(program.Statements); err != nil {
+		return "", err
+	}
+
+	return strings.Join(b.lines, "\n") + "\n", nil
+}
+
+func (b *bash) emit(line string) {
+	if line == "" {
+		b.lines = append(b.lines, "")
+		return
+	}
+
+	b.lines = append(b.lines, strings.Repeat("  ", b.depth)+line)
+}
+
+func (b *bash) unsupported(node ast.Node, format string, a ...interface{}) *Error {
+	pos := 0
+	if tok, ok := nodeToken(node); ok {
+		pos = tok
+	}
+
+	line, column, _ := b.lex.ErrorLine(pos)
+
+	return &Error{line, column, fmt.Sprintf(format, a...)}
+}
+
+// The position of the token of a node, for errors
+func nodeToken(node ast.Node) (int, bool) {
+	switch n := node.(type) {
+	case *ast.ExpressionStatement:
+		return n.Token.Position, true
+	case *ast.AssignStatement:
+		return n.Token.Position, true
+	case *ast.Identifier:
+		return n.Token.Position, true
+	case *ast.CallExpression:
+		return n.Token.Position, true
+	case *ast.InfixExpression:
+		return n.Token.Position, true
+	case *ast.PrefixExpression:
+		return n.Token.Position, true
+	case *ast.PropertyExpression:
+		return n.Token.Position, true
+	case *ast.IfExpression:
+		return n.Token.Position, true
+	case *ast.StringLiteral:
+		return n.Token.Position, true
+	case *ast.NumberLiteral:
+		return n.Token.Position, true
+	case *ast.CommandExpression:
+		return n.Token.Position, true
+	case *ast.CompoundAssignment:
+		return n.Token.Position, true
+	case *ast.ComparisonChain:
+		return n.Token.Position, true
+	case *ast.MethodExpression:
+		return n.Token.Position, true
+	case *ast.IndexExpression:
+		return n.Token.Position, true
+	case *ast.FunctionLiteral:
+		return n.Token.Position, true
+	case *ast.ArrayLiteral:
+		return n.Token.Position, true
+	case *ast.HashLiteral:
+		return n.Token.Position, true
+	case *ast.NullLiteral:
+		return n.Token.Position, true
+	case *ast.WhileExpression:
+		return n.Token.Position, true
+	case *ast.ForExpression:
+		return n.Token.Position, true
+	case *ast.ForInExpression:
+		return n.Token.Position, true
+	}
+
+	return 0, false
+}
+
+func (b *bash) statements(statements []ast.Statement) error {
+	for _, s := range statements {
+		if err := b.statement(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *bash) statement(s ast.Statement) error {
+	switch s := s.(type) {
+	case *ast.AssignStatement:
+		if s.Name == nil || len(s.Names) > 0 {
+			return b.unsupported(s, "only assignments to a variable (eg. x = 1) are supported")
+		}
+
+		return b.assign(s.Name.Value, s.Value)
+	case *ast.ExpressionStatement:
+		return b.expressionStatement(s)
+	}
+
+	return b.unsupported(s, "%T isn't supported by the bash target", s)
+}
+
+func (b *bash) assign(name string, value ast.Expression) error {
+	// The output of a command goes in the
+	// variable, its exit code next to it
+	if cmd, ok := value.(*ast.CommandExpression); ok {
+		b.emit(fmt.Sprintf("%s=$(%s)", name, cmd.Value))
+		b.emit(fmt.Sprintf("%s=$?", okVar(name)))
+		b.vars[name] = kindCommand
+		return nil
+	}
+
+	k, word, err := b.word(value)
+	if err != nil {
+		return err
+	}
+
+	b.emit(fmt.Sprintf("%s=%s", name, word))
+	b.vars[name] = k
+
+	return nil
+}
+
+// Where the exit code of the command whose
+// output is stored in a variable is kept
+func okVar(name string) string {
+	return "__" + name + "_ok"
+}
+
+func (b *bash) expressionStatement(s *ast.ExpressionStatement) error {
+	switch e := s.Expression.(type) {
+	case *ast.CommandExpression:
+		// ABS doesn't print the output of
+		// commands whose value is unused
+		b.emit(fmt.Sprintf("%s >/dev/null 2>&1", e.Value))
+		return nil
+	case *ast.IfExpression:
+		return b.ifExpression(e)
+	case *ast.CallExpression:
+		return b.call(e)
+	case *ast.CompoundAssignment:
+		name, ok := e.Left.(*ast.Identifier)
+		if !ok {
+			return b.unsupported(e, "only compound assignments to a variable (eg. x += 1) are supported")
+		}
+
+		op := strings.TrimSuffix(e.Operator, "=")
+		return b.assign(name.Value, &ast.InfixExpression{Token: e.Token, Left: name, Operator: op, Right: e.Right})
+	}
+
+	return b.unsupported(s, "%s isn't supported by the bash target", s.Expression.String())
+}
+
+func (b *bash) ifExpression(e *ast.IfExpression) error {
+	for i, s := range e.Scenarios {
+		// The parser turns "else" into a
+		// scenario whose condition is true
+		isElse := false
+		if c, ok := s.Condition.(*ast.Boolean); ok && c.Token.Position == -99 {
+			isElse = true
+		}
+
+		switch {
+		case isElse:
+			b.emit("else")
+		default:
+			cond, err := b.condition(s.Condition)
+			if err != nil {
+				return err
+			}
+
+			keyword := "if"
+			if i > 0 {
+				keyword = "elif"
+			}
+			b.emit(fmt.Sprintf("%s %s; then", keyword, cond))
+		}
+
+		b.depth++
+		if len(s.Consequence.Statements) == 0 {
+			b.emit(":")
+		}
+		if err := b.statements(s.Consequence.Statements); err != nil {
+			return err
+		}
+		b.depth--
+	}
+
+	b.emit("fi")
+
+	return nil
+}
+
+func (b *bash) call(e *ast.CallExpression) error {
+	fn, ok := e.Function.(*ast.Identifier)
+	if !ok {
+		return b.unsupported(e, "%s isn't supported by the bash target", e.String())
+	}
+
+	switch fn.Value {
+	case "echo":
+		if len(e.Arguments) == 0 {
+			b.emit("echo")
+			return nil
+		}
+
+		if len(e.Arguments) == 1 {
+			_, word, err := b.word(e.Arguments[0])
+			if err != nil {
+				return err
+			}
+
+			b.emit("echo " + word)
+			return nil
+		}
+
+		// echo("%s is %s", a, b) -- only %s maps to printf
+		format, ok := e.Arguments[0].(*ast.StringLiteral)
+		if !ok || strings.Count(format.Value, "%") != 2*strings.Count(format.Value, "%%")+strings.Count(format.Value, "%s") {
+			return b.unsupported(e, "echo(...) with arguments is only supported with a literal format using %%s")
+		}
+
+		// printf interprets backslashes in its format
+		words := []string{"\"" + escape(strings.ReplaceAll(format.Value, `\`, `\\`)) + `\n"`}
+		for _, arg := range e.Arguments[1:] {
+			_, word, err := b.word(arg)
+			if err != nil {
+				return err
+			}
+			words = append(words, word)
+		}
+
+		b.emit("printf " + strings.Join(words, " "))
+		return nil
+	case "exit":
+		if len(e.Arguments) == 0 || len(e.Arguments) > 2 {
+			return b.unsupported(e, "exit(...) requires a code and, optionally, a message")
+		}
+
+		k, code, err := b.word(e.Arguments[0])
+		if err != nil {
+			return err
+		}
+		if k != kindNumber {
+			return b.unsupported(e, "exit(...) requires a number")
+		}
+
+		if len(e.Arguments) == 2 {
+			_, msg, err := b.word(e.Arguments[1])
+			if err != nil {
+				return err
+			}
+			b.emit("printf '%s' " + msg)
+		}
+
+		b.emit("exit " + code)
+		return nil
+	case "env":
+		if len(e.Arguments) != 2 {
+			return b.unsupported(e, "env(...) is only supported to set a variable here, eg. env(\"KEY\", \"value\")")
+		}
+
+		key, ok := e.Arguments[0].(*ast.StringLiteral)
+		if !ok || !isName(key.Value) {
+			return b.unsupported(e, "env(...) requires a literal name")
+		}
+
+		_, value, err := b.word(e.Arguments[1])
+		if err != nil {
+			return err
+		}
+
+		b.emit(fmt.Sprintf("export %s=%s", key.Value, value))
+		return nil
+	}
+
+	return b.unsupported(e, "%s(...) isn't supported by the bash target", fn.Value)
+}
+
+// Converts an expression into a shell word, eg. "hello ${name}"
+func (b *bash) word(e ast.Expression) (kind, string, error) {
+	switch e := e.(type) {
+	case *ast.Boolean:
+		return kindBool, strconv.FormatBool(e.Value), nil
+	case *ast.Identifier:
+		k, ok := b.vars[e.Value]
+		if !ok {
+			return 0, "", b.unsupported(e, "identifier not found: %s", e.Value)
+		}
+
+		if k == kindCommand {
+			k = kindString
+		}
+
+		return k, fmt.Sprintf("\"${%s}\"", e.Value), nil
+	case *ast.PrefixExpression, *ast.ComparisonChain, *ast.PropertyExpression:
+		cond, err := b.condition(e)
+		if err != nil {
+			return 0, "", err
+		}
+
+		return kindBool, fmt.Sprintf("$(%s && echo true || echo false)", cond), nil
+	case *ast.InfixExpression:
+		if isComparison(e.Operator) || e.Operator == "&&" || e.Operator == "||" {
+			cond, err := b.condition(e)
+			if err != nil {
+				return 0, "", err
+			}
+
+			return kindBool, fmt.Sprintf("$(%s && echo true || echo false)", cond), nil
+		}
+	}
+
+	k, text, err := b.value(e)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if k == kindNumber {
+		if _, err := strconv.Atoi(text); err == nil {
+			return k, text, nil
+		}
+
+		// Arithmetic is already wrapped in parenthesis
+		if _, ok := e.(*ast.InfixExpression); ok {
+			text = text[1 : len(text)-1]
+		}
+
+		return k, fmt.Sprintf("$((%s))", text), nil
+	}
+
+	return k, "\"" + text + "\"", nil
+}
+
+// Converts an expression into either the content of a
+// double-quoted string (strings) or an arithmetic
+// expression (numbers), so that they can be combined
+func (b *bash) value(e ast.Expression) (kind, string, error) {
+	switch e := e.(type) {
+	case *ast.StringLiteral:
+		return kindString, escape(e.Value), nil
+	case *ast.NumberLiteral:
+		if e.Value != float64(int64(e.Value)) {
+			return 0, "", b.unsupported(e, "only whole numbers are supported by the bash target, got %s", e.String())
+		}
+
+		return kindNumber, strconv.FormatInt(int64(e.Value), 10), nil
+	case *ast.Identifier:
+		k, ok := b.vars[e.Value]
+		if !ok {
+			return 0, "", b.unsupported(e, "identifier not found: %s", e.Value)
+		}
+
+		switch k {
+		case kindNumber:
+			return k, e.Value, nil
+		case kindCommand:
+			return kindString, "${" + e.Value + "}", nil
+		}
+
+		return k, "${" + e.Value + "}", nil
+	case *ast.InfixExpression:
+		lk, left, err := b.value(e.Left)
+		if err != nil {
+			return 0, "", err
+		}
+
+		rk, right, err := b.value(e.Right)
+		if err != nil {
+			return 0, "", err
+		}
+
+		switch {
+		case lk == kindString && rk == kindString && e.Operator == "+":
+			return kindString, left + right, nil
+		case lk == kindNumber && rk == kindNumber && strings.Contains("+ - * % **", e.Operator):
+			return kindNumber, fmt.Sprintf("(%s %s %s)", left, e.Operator, right), nil
+		case lk == kindNumber && rk == kindNumber && e.Operator == "/":
+			return 0, "", b.unsupported(e, "/ isn't supported by the bash target, as bash only does integer division")
+		}
+
+		return 0, "", b.unsupported(e, "%s isn't supported by the bash target", e.String())
+	case *ast.Boolean:
+		return kindBool, strconv.FormatBool(e.Value), nil
+	case *ast.CallExpression:
+		// env("KEY")
+		if fn, ok := e.Function.(*ast.Identifier); ok && fn.Value == "env" && len(e.Arguments) == 1 {
+			if key, ok := e.Arguments[0].(*ast.StringLiteral); ok && isName(key.Value) {
+				return kindString, fmt.Sprintf("${%s:-}", key.Value), nil
+			}
+		}
+	}
+
+	return 0, "", b.unsupported(e, "%s isn't supported by the bash target", e.String())
+}
+
+// Converts an expression into a condition for if, eg. [ "${a}" = "b" ]
+func (b *bash) condition(e ast.Expression) (string, error) {
+	switch e := e.(type) {
+	case *ast.Boolean:
+		return strconv.FormatBool(e.Value), nil
+	case *ast.Identifier:
+		k, ok := b.vars[e.Value]
+		if !ok {
+			return "", b.unsupported(e, "identifier not found: %s", e.Value)
+		}
+
+		if k != kindBool {
+			return "", b.unsupported(e, "only booleans can be used as conditions, %s isn't one", e.Value)
+		}
+
+		return fmt.Sprintf("[ \"${%s}\" = true ]", e.Value), nil
+	case *ast.PropertyExpression:
+		// x.ok, where x is the output of a command,
+		// or `cmd`.ok to just check whether it works
+		if p, ok := e.Property.(*ast.Identifier); !ok || p.Value != "ok" {
+			break
+		}
+
+		switch o := e.Object.(type) {
+		case *ast.Identifier:
+			if b.vars[o.Value] == kindCommand {
+				return fmt.Sprintf("[ \"${%s}\" -eq 0 ]", okVar(o.Value)), nil
+			}
+		case *ast.CommandExpression:
+			return fmt.Sprintf("{ %s; } >/dev/null 2>&1", o.Value), nil
+		}
+	case *ast.PrefixExpression:
+		if e.Operator != "!" {
+			break
+		}
+
+		cond, err := b.condition(e.Right)
+		if err != nil {
+			return "", err
+		}
+
+		return "! " + group(cond), nil
+	case *ast.ComparisonChain:
+		conds := []string{}
+		for i, op := range e.Operators {
+			cond, err := b.comparison(e, e.Operands[i], op, e.Operands[i+1])
+			if err != nil {
+				return "", err
+			}
+			conds = append(conds, cond)
+		}
+
+		return strings.Join(conds, " && "), nil
+	case *ast.InfixExpression:
+		if e.Operator == "&&" || e.Operator == "||" {
+			left, err := b.condition(e.Left)
+			if err != nil {
+				return "", err
+			}
+
+			right, err := b.condition(e.Right)
+			if err != nil {
+				return "", err
+			}
+
+			return fmt.Sprintf("%s %s %s", group(left), e.Operator, group(right)), nil
+		}
+
+		if isComparison(e.Operator) {
+			return b.comparison(e, e.Left, e.Operator, e.Right)
+		}
+	}
+
+	return "", b.unsupported(e, "%s isn't supported as a condition by the bash target", e.String())
+}
+
+func (b *bash) comparison(node ast.Node, left ast.Expression, op string, right ast.Expression) (string, error) {
+	lk, l, err := b.word(left)
+	if err != nil {
+		return "", err
+	}
+
+	rk, r, err := b.word(right)
+	if err != nil {
+		return "", err
+	}
+
+	if lk == kindNumber && rk == kindNumber {
+		ops := map[string]string{"==": "-eq", "!=": "-ne", "<": "-lt", ">": "-gt", "<=": "-le", ">=": "-ge"}
+		return fmt.Sprintf("[ %s %s %s ]", l, ops[op], r), nil
+	}
+
+	if lk == rk && (op == "==" || op == "!=") {
+		ops := map[string]string{"==": "=", "!=": "!="}
+		return fmt.Sprintf("[ %s %s %s ]", l, ops[op], r), nil
+	}
+
+	return "", b.unsupported(node, "%s %s %s isn't supported by the bash target, as it compares values of different types (or strings by order)", left.String(), op, right.String())
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", ">", "<=", ">=":
+		return true
+	}
+
+	return false
+}
+
+// Groups && / || conditions, so that
+// they can be combined with others
+func group(cond string) string {
+	if strings.HasPrefix(cond, "! ") {
+		return cond
+	}
+
+	if strings.Contains(cond, " && ") || strings.Contains(cond, " || ") {
+		return "{ " + cond + "; }"
+	}
+
+	return cond
+}
+
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i, r := range s {
+		if r != '_' && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Escapes an ABS string so that it can be used within
+// double quotes: $var and ${var} are interpolated by
+// both ABS and bash, so they're left alone
+func escape(s string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		next := byte(0)
+		if i+1 < len(s) {
+			next = s[i+1]
+		}
+
+		switch {
+		case c == '\\' && next == '$':
+			out.WriteString(`\$`)
+			i++
+		case c == '\\' || c == '"' || c == '`':
+			out.WriteByte('\\')
+			out.WriteByte(c)
+		case c == '$' && !(next == '{' || next == '_' || (next >= 'a' && next <= 'z') || (next >= 'A' && next <= 'Z')):
+			out.WriteString(`\$`)
+		default:
+			out.WriteByte(c)
+		}
+	}
+
+	return out.String()
+}
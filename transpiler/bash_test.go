@@ -0,0 +1,73 @@
+package transpiler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBash(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`name = "world"; echo("hello $name")`, "name=\"world\"\necho \"hello $name\""},
+		{`n = 1; n += 2 * 3`, "n=1\nn=$((n + (2 * 3)))"},
+		{`debug = false; echo(debug)`, "debug=false\necho \"${debug}\""},
+		{`echo("a \"b\" $1 \\ ` + "`c`" + `")`, "echo \"a \\\"b\\\" \\$1 \\\\\\\\ \\`c\\`\""},
+		{"ls = `ls -la`; if ls.ok { echo(ls) }", "ls=$(ls -la)\n__ls_ok=$?\nif [ \"${__ls_ok}\" -eq 0 ]; then\n  echo \"${ls}\"\nfi"},
+		{"`mkdir -p /tmp/x`", "mkdir -p /tmp/x >/dev/null 2>&1"},
+		{"if `which git`.ok { exit(0) }", "if { which git; } >/dev/null 2>&1; then\n  exit 0\nfi"},
+		{`x = 1; if x > 2 { echo("a") } else if x == 1 { echo("b") } else { echo("c") }`, "x=1\nif [ \"${x}\" -gt 2 ]; then\n  echo \"a\"\nelif [ \"${x}\" -eq 1 ]; then\n  echo \"b\"\nelse\n  echo \"c\"\nfi"},
+		{`a = "x"; if a != "y" && !(1 < 2 < 3) { echo(a) }`, "a=\"x\"\nif [ \"${a}\" != \"y\" ] && ! { [ 1 -lt 2 ] && [ 2 -lt 3 ]; }; then\n  echo \"${a}\"\nfi"},
+		{`echo("%s is %s", "a", 1)`, "printf \"%s is %s\\n\" \"a\" 1"},
+		{`exit(1, "oops")`, "printf '%s' \"oops\"\nexit 1"},
+		{`env("PATH", "/bin:" + env("PATH")); home = env("HOME")`, "export PATH=\"/bin:${PATH:-}\"\nhome=\"${HOME:-}\""},
+		{`same = "a" == "b"`, "same=$([ \"a\" = \"b\" ] && echo true || echo false)"},
+	}
+
+	for _, tt := range tests {
+		out, err := Bash(tt.input, "test.abs")
+		if err != nil {
+			t.Errorf("unexpected error for %q: %s", tt.input, err)
+			continue
+		}
+
+		if !strings.HasPrefix(out, "#!/usr/bin/env bash\n") {
+			t.Errorf("expected a bash shebang for %q, got %q", tt.input, out)
+		}
+
+		body := strings.TrimSpace(strings.SplitN(out, "\n\n", 2)[1])
+		if body != tt.expected {
+			t.Errorf("wrong output for %q.\nexpected:\n%s\ngot:\n%s", tt.input, tt.expected, body)
+		}
+	}
+}
+
+func TestBashUnsupported(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`x = 1 / 2`, "1:7: / isn't supported by the bash target"},
+		{`x = 1.5`, "1:5: only whole numbers are supported by the bash target, got 1.5"},
+		{`echo(y)`, "1:6: identifier not found: y"},
+		{`x = [1, 2]`, "1:5: [1, 2] isn't supported by the bash target"},
+		{`x = "a"; if x { echo(1) }`, "1:13: only booleans can be used as conditions, x isn't one"},
+		{"x = 1\nif x < \"a\" { echo(1) }", "2:6: x < a isn't supported by the bash target"},
+		{"g = f() { 1 }", "1:5: "},
+		{`echo("%d", 1)`, "1:5: echo(...) with arguments is only supported with a literal format using %s"},
+		{`x = `, "parser errors:"},
+	}
+
+	for _, tt := range tests {
+		_, err := Bash(tt.input, "test.abs")
+		if err == nil {
+			t.Errorf("expected an error for %q", tt.input)
+			continue
+		}
+
+		if !strings.HasPrefix(err.Error(), tt.expected) {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, err.Error())
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSRequest is the description of an HTTP
+// request that needs to be signed with SigV4
+type AWSRequest struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    string
+	Region  string
+	Service string
+	Time    time.Time
+}
+
+// AWSCredentials holds the keys used to sign requests
+type AWSCredentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+const awsTimeFormat = "20060102T150405Z"
+
+// SignAWSRequest (req, creds)
+// Signs the request using AWS Signature Version 4,
+// returning the headers the request needs to be sent with
+// (the original ones plus Authorization, X-Amz-Date etc)
+func SignAWSRequest(req AWSRequest, creds AWSCredentials) (map[string]string, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("the URL to sign must be absolute, got: %s", req.URL)
+	}
+
+	if req.Region == "" || req.Service == "" {
+		return nil, fmt.Errorf("a region and a service are required in order to sign a request")
+	}
+
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return nil, fmt.Errorf("an access key and a secret key are required in order to sign a request")
+	}
+
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	t := req.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	amzDate := t.UTC().Format(awsTimeFormat)
+	date := amzDate[:8]
+	payloadHash := sha256Hex(req.Body)
+
+	headers := map[string]string{}
+	for k, v := range req.Headers {
+		headers[k] = v
+	}
+	headers["Host"] = u.Host
+	headers["X-Amz-Date"] = amzDate
+
+	// S3 requires the payload hash to be sent along
+	if req.Service == "s3" {
+		headers["X-Amz-Content-Sha256"] = payloadHash
+	}
+
+	if creds.SessionToken != "" {
+		headers["X-Amz-Security-Token"] = creds.SessionToken
+	}
+
+	canonical := map[string]string{}
+	names := []string{}
+	for k, v := range headers {
+		name := strings.ToLower(k)
+		canonical[name] = strings.Join(strings.Fields(v), " ")
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + canonical[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		awsCanonicalPath(u.EscapedPath()),
+		awsCanonicalQuery(u.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, req.Region, req.Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex(canonicalRequest)}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretKey), date)
+	key = hmacSHA256(key, req.Region)
+	key = hmacSHA256(key, req.Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	headers["Authorization"] = fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", creds.AccessKey, scope, signedHeaders, signature)
+
+	return headers, nil
+}
+
+// AWSMetadata (endpoint, path)
+// Fetches a value from the EC2 instance metadata service,
+// eg. AWSMetadata("http://169.254.169.254", "placement/region").
+// IMDSv2 is used when available, falling back to IMDSv1.
+func AWSMetadata(endpoint, path string) (string, error) {
+	client := NewHTTPClient(2 * time.Second)
+	endpoint = strings.TrimRight(endpoint, "/")
+
+	token := ""
+	tokenReq, err := http.NewRequest("PUT", endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	tokenReq.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "21600")
+
+	if res, err := client.Do(tokenReq); err == nil {
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		if res.StatusCode == http.StatusOK {
+			token = string(body)
+		}
+	}
+
+	req, err := http.NewRequest("GET", endpoint+"/latest/meta-data/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if token != "" {
+		req.Header.Set("X-aws-ec2-metadata-token", token)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to fetch metadata '%s': %s", path, res.Status)
+	}
+
+	return string(body), nil
+}
+
+func awsCanonicalPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		unescaped, err := url.PathUnescape(s)
+		if err != nil {
+			unescaped = s
+		}
+		segments[i] = awsEscape(unescaped)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func awsCanonicalQuery(query url.Values) string {
+	params := []string{}
+	for k, values := range query {
+		for _, v := range values {
+			params = append(params, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	sort.Strings(params)
+
+	return strings.Join(params, "&")
+}
+
+// AWS wants everything but the RFC 3986
+// unreserved characters to be percent-encoded
+func awsEscape(s string) string {
+	var out strings.Builder
+
+	for _, c := range []byte(s) {
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			out.WriteByte(c)
+			continue
+		}
+
+		fmt.Fprintf(&out, "%%%02X", c)
+	}
+
+	return out.String()
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -22,7 +22,20 @@ func UpdateAvailable(version string) (string, bool) {
 	}
 
 	latest := strings.TrimSpace(string(body))
-	if version != latest {
+
+	// Versions that aren't semantic (eg. development
+	// builds) are simply compared as strings
+	current, err := ParseVersion(version)
+	if err != nil {
+		return latest, version != latest
+	}
+
+	l, err := ParseVersion(latest)
+	if err != nil {
+		return version, false
+	}
+
+	if CompareVersions(l, current) > 0 {
 		return latest, true
 	}
 
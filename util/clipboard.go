@@ -0,0 +1,59 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// ErrClipboardUnavailable is returned when there's no way
+// to reach the clipboard, eg. on Linux without any of
+// xsel, xclip or wl-clipboard installed
+var ErrClipboardUnavailable = fmt.Errorf("no clipboard available (on Linux, install xsel, xclip or wl-clipboard)")
+
+// ReadClipboard ()
+// Reads the text on the clipboard, through pbpaste on
+// macOS, the Windows API or xsel / xclip / wl-paste
+func ReadClipboard() (string, error) {
+	if !clipboardSupported() {
+		return "", ErrClipboardUnavailable
+	}
+
+	return clipboardRead()
+}
+
+// WriteClipboard (text, terminal)
+// Puts text on the clipboard. When there's no clipboard
+// to write to (eg. over SSH), and terminal is given, the
+// terminal is asked to do it instead, through OSC 52
+func WriteClipboard(text string, terminal io.Writer) error {
+	if clipboardSupported() {
+		return clipboardWrite(text)
+	}
+
+	if terminal == nil {
+		return ErrClipboardUnavailable
+	}
+
+	_, err := osc52Sequence(text, os.Getenv("TERM"), os.Getenv("TMUX") != "").WriteTo(terminal)
+
+	return err
+}
+
+// The OSC 52 sequence setting the clipboard, wrapped so
+// that tmux and screen pass it on to the terminal
+func osc52Sequence(text string, term string, tmux bool) osc52.Sequence {
+	seq := osc52.New(text)
+
+	switch {
+	case tmux:
+		seq = seq.Tmux()
+	case strings.HasPrefix(term, "screen"):
+		seq = seq.Screen()
+	}
+
+	return seq
+}
@@ -0,0 +1,17 @@
+//go:build js
+
+package util
+
+// In the browser there's no
+// clipboard we can reach
+func clipboardSupported() bool {
+	return false
+}
+
+func clipboardRead() (string, error) {
+	return "", ErrClipboardUnavailable
+}
+
+func clipboardWrite(text string) error {
+	return ErrClipboardUnavailable
+}
@@ -0,0 +1,17 @@
+//go:build !js
+
+package util
+
+import "github.com/atotto/clipboard"
+
+func clipboardSupported() bool {
+	return !clipboard.Unsupported
+}
+
+func clipboardRead() (string, error) {
+	return clipboard.ReadAll()
+}
+
+func clipboardWrite(text string) error {
+	return clipboard.WriteAll(text)
+}
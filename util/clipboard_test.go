@@ -0,0 +1,42 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOsc52Sequence(t *testing.T) {
+	tests := []struct {
+		term     string
+		tmux     bool
+		expected string
+	}{
+		{"xterm-256color", false, "\x1b]52;c;aGVsbG8=\x07"},
+		{"screen-256color", false, "\x1bP\x1b]52;c;aGVsbG8=\x07\x1b\\"},
+		{"screen-256color", true, "\x1bPtmux;\x1b\x1b]52;c;aGVsbG8=\x07\x1b\\"},
+	}
+
+	for _, tt := range tests {
+		if seq := osc52Sequence("hello", tt.term, tt.tmux).String(); seq != tt.expected {
+			t.Fatalf("%s (tmux: %v): expected %q, got %q", tt.term, tt.tmux, tt.expected, seq)
+		}
+	}
+}
+
+func TestWriteClipboardThroughTerminal(t *testing.T) {
+	if clipboardSupported() {
+		t.Skip("the clipboard is available")
+	}
+
+	t.Setenv("TERM", "xterm")
+	t.Setenv("TMUX", "")
+
+	if err := WriteClipboard("hello", nil); err != ErrClipboardUnavailable {
+		t.Fatalf("expected the clipboard to be unavailable, got %v", err)
+	}
+
+	var terminal bytes.Buffer
+	if err := WriteClipboard("hello", &terminal); err != nil || terminal.String() != "\x1b]52;c;aGVsbG8=\x07" {
+		t.Fatalf("expected an OSC 52 sequence, got %q (%v)", terminal.String(), err)
+	}
+}
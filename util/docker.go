@@ -0,0 +1,299 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const DockerDefaultHost = "unix:///var/run/docker.sock"
+
+// DockerClient talks to the Docker engine API, either
+// through its unix socket or over TCP (see NewDockerClient)
+type DockerClient struct {
+	http *http.Client
+	base string
+}
+
+// DockerContainer is how a container is listed by DockerClient.Containers
+type DockerContainer struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	Command string            `json:"Command"`
+	Created int64             `json:"Created"`
+	State   string            `json:"State"`
+	Status  string            `json:"Status"`
+	Labels  map[string]string `json:"Labels"`
+}
+
+// DockerRunOptions describes the container DockerClient.Create creates
+type DockerRunOptions struct {
+	Image   string
+	Name    string
+	Cmd     []string
+	Env     map[string]string
+	Volumes []string
+	// host port to container port, eg. "8080": "80"
+	Ports   map[string]string
+	Workdir string
+}
+
+// NewDockerClient (host)
+// Returns a client for the engine listening at host, as
+// in DOCKER_HOST: eg. unix:///var/run/docker.sock (the
+// default, when host is empty) or tcp://127.0.0.1:2375
+func NewDockerClient(host string) (*DockerClient, error) {
+	if host == "" {
+		host = DockerDefaultHost
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker host '%s'", host)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		dialer := net.Dialer{}
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		}
+
+		return &DockerClient{http: &http.Client{Transport: transport}, base: "http://docker"}, nil
+	case "tcp", "http":
+		return &DockerClient{http: &http.Client{}, base: "http://" + u.Host}, nil
+	default:
+		return nil, fmt.Errorf("unsupported docker host '%s', expected unix:// or tcp://", host)
+	}
+}
+
+// Sends a request to the engine, returning an error
+// holding the engine's message if it didn't succeed
+func (c *DockerClient) do(method string, path string, body interface{}) (*http.Response, error) {
+	var payload io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		payload = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.base+path, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to reach the docker engine: %s", err.Error())
+	}
+
+	if res.StatusCode >= 300 {
+		defer res.Body.Close()
+		e := struct {
+			Message string `json:"message"`
+		}{}
+
+		if json.NewDecoder(res.Body).Decode(&e) != nil || e.Message == "" {
+			e.Message = res.Status
+		}
+
+		return res, fmt.Errorf("%s", e.Message)
+	}
+
+	return res, nil
+}
+
+// Sends a request, decoding its response into v (unless nil)
+func (c *DockerClient) call(method string, path string, body interface{}, v interface{}) error {
+	res, err := c.do(method, path, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if v == nil {
+		io.Copy(io.Discard, res.Body)
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// Containers (all)
+// Lists the running containers, or all of them
+func (c *DockerClient) Containers(all bool) ([]DockerContainer, error) {
+	containers := []DockerContainer{}
+	err := c.call("GET", fmt.Sprintf("/containers/json?all=%t", all), nil, &containers)
+
+	return containers, err
+}
+
+// Create (opts)
+// Creates a container, pulling its image if it's
+// not around, and returns the ID of the container
+func (c *DockerClient) Create(opts DockerRunOptions) (string, error) {
+	env := []string{}
+	for k, v := range opts.Env {
+		env = append(env, k+"="+v)
+	}
+
+	exposed := map[string]struct{}{}
+	bindings := map[string][]map[string]string{}
+	for host, container := range opts.Ports {
+		if !strings.Contains(container, "/") {
+			container += "/tcp"
+		}
+
+		exposed[container] = struct{}{}
+		bindings[container] = append(bindings[container], map[string]string{"HostPort": host})
+	}
+
+	config := map[string]interface{}{
+		"Image":        opts.Image,
+		"Env":          env,
+		"WorkingDir":   opts.Workdir,
+		"ExposedPorts": exposed,
+		"HostConfig": map[string]interface{}{
+			"Binds":        opts.Volumes,
+			"PortBindings": bindings,
+		},
+	}
+
+	if len(opts.Cmd) > 0 {
+		config["Cmd"] = opts.Cmd
+	}
+
+	path := "/containers/create"
+	if opts.Name != "" {
+		path += "?name=" + url.QueryEscape(opts.Name)
+	}
+
+	created := struct {
+		ID string `json:"Id"`
+	}{}
+
+	res, err := c.do("POST", path, config)
+	if err != nil && res != nil && res.StatusCode == http.StatusNotFound {
+		// the image isn't around, like
+		// the CLI does, let's pull it
+		if err = c.Pull(opts.Image); err == nil {
+			res, err = c.do("POST", path, config)
+		}
+	}
+
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	err = json.NewDecoder(res.Body).Decode(&created)
+
+	return created.ID, err
+}
+
+// Pull (image)
+// Pulls an image, eg. alpine or alpine:3.20
+func (c *DockerClient) Pull(image string) error {
+	name, tag := image, "latest"
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		name, tag = image[:i], image[i+1:]
+	}
+
+	return c.call("POST", "/images/create?fromImage="+url.QueryEscape(name)+"&tag="+url.QueryEscape(tag), nil, nil)
+}
+
+// Start (id)
+func (c *DockerClient) Start(id string) error {
+	return c.call("POST", "/containers/"+url.PathEscape(id)+"/start", nil, nil)
+}
+
+// Wait (id)
+// Waits for a container to stop, returning its exit code
+func (c *DockerClient) Wait(id string) (int, error) {
+	res := struct {
+		StatusCode int `json:"StatusCode"`
+	}{}
+	err := c.call("POST", "/containers/"+url.PathEscape(id)+"/wait", nil, &res)
+
+	return res.StatusCode, err
+}
+
+// Remove (id)
+func (c *DockerClient) Remove(id string) error {
+	return c.call("DELETE", "/containers/"+url.PathEscape(id)+"?force=true", nil, nil)
+}
+
+// Logs (id, follow, fn)
+// Calls fn with each line a container printed (both to its stdout
+// and stderr), and the ones it prints from then on if following,
+// until it stops or fn returns false
+func (c *DockerClient) Logs(id string, follow bool, fn func(line string) bool) error {
+	res, err := c.do("GET", fmt.Sprintf("/containers/%s/logs?stdout=true&stderr=true&follow=%t", url.PathEscape(id), follow), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// unless the container has a TTY, its
+	// output is multiplexed into frames
+	var r io.Reader = res.Body
+	if res.Header.Get("Content-Type") != "application/vnd.docker.raw-stream" {
+		r = &dockerDemuxer{r: res.Body}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		if !fn(scanner.Text()) {
+			return nil
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Reads the payload of the frames of a multiplexed stream,
+// each with an 8 bytes header: the stream (stdout, stderr)
+// the payload comes from and, in the last 4 bytes, its size
+type dockerDemuxer struct {
+	r         io.Reader
+	remaining int
+}
+
+func (d *dockerDemuxer) Read(p []byte) (int, error) {
+	for d.remaining == 0 {
+		header := make([]byte, 8)
+		if _, err := io.ReadFull(d.r, header); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = io.EOF
+			}
+			return 0, err
+		}
+
+		d.remaining = int(binary.BigEndian.Uint32(header[4:]))
+	}
+
+	n, err := d.r.Read(p[:min(len(p), d.remaining)])
+	d.remaining -= n
+
+	return n, err
+}
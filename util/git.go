@@ -0,0 +1,364 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// GitRepo runs git in a directory, reading its machine-readable
+// output (NUL-separated, porcelain v2) rather than the one
+// meant for humans, which changes across versions and locales
+type GitRepo struct {
+	Dir string
+	// Run, when set, runs git commands in place of
+	// their own Run(), eg. to check or record them
+	Run func(c *exec.Cmd) error
+}
+
+// GitStatus is the state of the working tree (see GitRepo.Status)
+type GitStatus struct {
+	Branch   string
+	Head     string
+	Upstream string
+	Ahead    int
+	Behind   int
+	Files    []GitFileStatus
+}
+
+// GitFileStatus is a file that changed in the working tree:
+// Staged and Unstaged are single letters as in git status
+// (M, A, D, R...), "." when unchanged and "?" when untracked
+type GitFileStatus struct {
+	Path     string
+	OrigPath string
+	Staged   string
+	Unstaged string
+}
+
+// GitCommit is how commits are listed by GitRepo.Log
+type GitCommit struct {
+	Hash    string
+	Author  string
+	Email   string
+	Date    int64
+	Subject string
+	Body    string
+}
+
+// GitBranch is how branches are listed by GitRepo.Branches
+type GitBranch struct {
+	Name     string
+	Hash     string
+	Upstream string
+	Current  bool
+}
+
+// GitDiffFile is a file that changed in a diff: Additions
+// and Deletions are -1 when the file is binary
+type GitDiffFile struct {
+	Path      string
+	OrigPath  string
+	Status    string
+	Additions int
+	Deletions int
+}
+
+// Runs git with the given args, returning its stdout or
+// an error holding what it printed to stderr
+func (r GitRepo) run(args ...string) (string, error) {
+	c := exec.Command("git", args...)
+	c.Dir = r.Dir
+	// git shouldn't wait for credentials
+	// nor open an editor
+	c.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_EDITOR=true", "LC_ALL=C")
+
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	run := c.Run
+	if r.Run != nil {
+		run = func() error { return r.Run(c) }
+	}
+
+	if err := run(); err != nil {
+		var execErr *exec.Error
+		if errors.As(err, &execErr) {
+			return "", fmt.Errorf("git is not installed: %s", err.Error())
+		}
+
+		// git didn't even start, eg. as
+		// it wasn't allowed to by Run
+		if c.ProcessState == nil {
+			return "", err
+		}
+
+		// some errors, like there being nothing
+		// to commit, are printed to stdout
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+
+	return stdout.String(), nil
+}
+
+// Status ()
+// Returns the branch the repository is on, how far it is
+// from its upstream and the files that changed
+func (r GitRepo) Status() (GitStatus, error) {
+	status := GitStatus{Files: []GitFileStatus{}}
+	out, err := r.run("status", "--porcelain=v2", "--branch", "--untracked-files=all", "-z")
+	if err != nil {
+		return status, err
+	}
+
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		e := entries[i]
+		fields := strings.Fields(e)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "#":
+			// # branch.oid <commit>, # branch.head <name>,
+			// # branch.upstream <name>, # branch.ab +1 -2
+			switch {
+			case len(fields) > 2 && fields[1] == "branch.oid":
+				status.Head = strings.TrimPrefix(fields[2], "(initial)")
+			case len(fields) > 2 && fields[1] == "branch.head":
+				status.Branch = strings.TrimPrefix(fields[2], "(detached)")
+			case len(fields) > 2 && fields[1] == "branch.upstream":
+				status.Upstream = fields[2]
+			case len(fields) > 3 && fields[1] == "branch.ab":
+				status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+				status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+			}
+		case "1":
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			parts := strings.SplitN(e, " ", 9)
+			status.Files = append(status.Files, gitFileStatus(parts[1], parts[8], ""))
+		case "2":
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path>,
+			// followed by the original path in the next entry
+			parts := strings.SplitN(e, " ", 10)
+			orig := ""
+			if i+1 < len(entries) {
+				i++
+				orig = entries[i]
+			}
+			status.Files = append(status.Files, gitFileStatus(parts[1], parts[9], orig))
+		case "u":
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			parts := strings.SplitN(e, " ", 11)
+			status.Files = append(status.Files, gitFileStatus(parts[1], parts[10], ""))
+		case "?":
+			status.Files = append(status.Files, GitFileStatus{Path: e[2:], Staged: "?", Unstaged: "?"})
+		}
+	}
+
+	return status, nil
+}
+
+func gitFileStatus(xy string, path string, orig string) GitFileStatus {
+	return GitFileStatus{Path: path, OrigPath: orig, Staged: xy[:1], Unstaged: xy[1:]}
+}
+
+// Log (n, ref, path)
+// Lists the last n commits (all of them when n is 0) reachable
+// from ref (HEAD when empty), only the ones touching path if given
+func (r GitRepo) Log(n int, ref string, path string) ([]GitCommit, error) {
+	commits := []GitCommit{}
+	args := []string{"log", "-z", "--format=%H%x1f%an%x1f%ae%x1f%at%x1f%s%x1f%b"}
+	if n > 0 {
+		args = append(args, "-n", strconv.Itoa(n))
+	}
+	if ref != "" {
+		args = append(args, ref)
+	}
+	if path != "" {
+		args = append(args, "--", path)
+	}
+
+	out, err := r.run(args...)
+	if err != nil {
+		// a repository without commits has no log
+		if strings.Contains(err.Error(), "does not have any commits") {
+			return commits, nil
+		}
+		return commits, err
+	}
+
+	for _, entry := range strings.Split(out, "\x00") {
+		fields := strings.Split(entry, "\x1f")
+		if len(fields) != 6 {
+			continue
+		}
+
+		date, _ := strconv.ParseInt(fields[3], 10, 64)
+		commits = append(commits, GitCommit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Email:   fields[2],
+			Date:    date,
+			Subject: fields[4],
+			Body:    strings.TrimSpace(fields[5]),
+		})
+	}
+
+	return commits, nil
+}
+
+// Branches ()
+// Lists the local branches
+func (r GitRepo) Branches() ([]GitBranch, error) {
+	branches := []GitBranch{}
+	out, err := r.run("for-each-ref", "--format=%(HEAD)%1f%(refname:short)%1f%(objectname)%1f%(upstream:short)", "refs/heads")
+	if err != nil {
+		return branches, err
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+
+		branches = append(branches, GitBranch{
+			Current:  fields[0] == "*",
+			Name:     fields[1],
+			Hash:     fields[2],
+			Upstream: fields[3],
+		})
+	}
+
+	return branches, nil
+}
+
+// CreateBranch (name, checkout)
+// Creates a branch at HEAD, switching to it if checkout is set
+func (r GitRepo) CreateBranch(name string, checkout bool) error {
+	if checkout {
+		_, err := r.run("switch", "-c", name)
+		return err
+	}
+
+	_, err := r.run("branch", name)
+	return err
+}
+
+// Commit (message, files, all)
+// Stages files (or every tracked file that changed, when all
+// is set) and commits them, returning the hash of the commit
+func (r GitRepo) Commit(message string, files []string, all bool) (string, error) {
+	if len(files) > 0 {
+		if _, err := r.run(append([]string{"add", "--"}, files...)...); err != nil {
+			return "", err
+		}
+	}
+
+	args := []string{"commit", "--quiet", "-m", message}
+	if all {
+		args = append(args, "--all")
+	}
+
+	if _, err := r.run(args...); err != nil {
+		return "", err
+	}
+
+	out, err := r.run("rev-parse", "HEAD")
+
+	return strings.TrimSpace(out), err
+}
+
+// Clone (url, dir, branch, depth)
+// Clones a repository into dir, checking out branch
+// if given and fetching only depth commits if positive
+func (r GitRepo) Clone(url string, dir string, branch string, depth int) error {
+	args := []string{"clone", "--quiet"}
+	if branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	if depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+
+	_, err := r.run(append(args, "--", url, dir)...)
+
+	return err
+}
+
+// Diff (ref, staged, path)
+// Lists the files that changed in the working tree (or the
+// index, when staged is set) compared to ref (the index, or
+// HEAD when staged, if empty), only the ones under path if given
+func (r GitRepo) Diff(ref string, staged bool, path string) ([]GitDiffFile, error) {
+	files := []GitDiffFile{}
+	args := []string{"diff", "-z", "--find-renames"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	if ref != "" {
+		args = append(args, ref)
+	}
+	args = append(args, "--")
+	if path != "" {
+		args = append(args, path)
+	}
+
+	// the status and the number of lines
+	// changed come in separate listings
+	numstat, err := r.run(append([]string{args[0], "--numstat"}, args[1:]...)...)
+	if err != nil {
+		return files, err
+	}
+
+	names, err := r.run(append([]string{args[0], "--name-status"}, args[1:]...)...)
+	if err != nil {
+		return files, err
+	}
+
+	// <status>\0<path>\0, or <status>\0<orig>\0<path>\0 for renames and copies
+	entries := strings.Split(names, "\x00")
+	for i := 0; i+1 < len(entries) && entries[i] != ""; i += 2 {
+		f := GitDiffFile{Status: entries[i][:1], Path: entries[i+1]}
+		if (f.Status == "R" || f.Status == "C") && i+2 < len(entries) {
+			f.OrigPath, f.Path = entries[i+1], entries[i+2]
+			i++
+		}
+		files = append(files, f)
+	}
+
+	// <added>\t<deleted>\t<path>\0, or <added>\t<deleted>\t\0<orig>\0<path>\0
+	entries = strings.Split(numstat, "\x00")
+	for i, n := 0, 0; i < len(entries) && n < len(files); i, n = i+1, n+1 {
+		fields := strings.SplitN(entries[i], "\t", 3)
+		if len(fields) != 3 {
+			break
+		}
+		if fields[2] == "" {
+			i += 2
+		}
+
+		files[n].Additions, files[n].Deletions = -1, -1
+		if fields[0] != "-" {
+			files[n].Additions, _ = strconv.Atoi(fields[0])
+			files[n].Deletions, _ = strconv.Atoi(fields[1])
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,117 @@
+package util
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// A repository with a single commit, holding
+// VERSION and "a file.txt", on master
+func newTestGitRepo(t *testing.T) GitRepo {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Alex")
+	t.Setenv("GIT_AUTHOR_EMAIL", "alex@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Alex")
+	t.Setenv("GIT_COMMITTER_EMAIL", "alex@example.com")
+
+	repo := GitRepo{Dir: t.TempDir()}
+	if _, err := repo.run("init", "--quiet", "--initial-branch=master"); err != nil {
+		t.Fatal(err)
+	}
+
+	os.WriteFile(filepath.Join(repo.Dir, "VERSION"), []byte("1.0\n"), 0644)
+	os.WriteFile(filepath.Join(repo.Dir, "a file.txt"), []byte("a\nb\n"), 0644)
+
+	if _, err := repo.Commit("Initial commit\n\nWith a body", []string{"."}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	return repo
+}
+
+func TestGitRepo(t *testing.T) {
+	repo := newTestGitRepo(t)
+
+	status, err := repo.Status()
+	if err != nil || status.Branch != "master" || len(status.Head) != 40 || len(status.Files) != 0 {
+		t.Fatalf("expected a clean repository on master, got %+v (%v)", status, err)
+	}
+
+	os.WriteFile(filepath.Join(repo.Dir, "VERSION"), []byte("1.1\n"), 0644)
+	os.WriteFile(filepath.Join(repo.Dir, "new.txt"), []byte("new\n"), 0644)
+	repo.run("mv", "a file.txt", "b file.txt")
+
+	status, _ = repo.Status()
+	expected := []GitFileStatus{
+		{Path: "VERSION", Staged: ".", Unstaged: "M"},
+		{Path: "b file.txt", OrigPath: "a file.txt", Staged: "R", Unstaged: "."},
+		{Path: "new.txt", Staged: "?", Unstaged: "?"},
+	}
+	if len(status.Files) != len(expected) {
+		t.Fatalf("expected %d files to have changed, got %+v", len(expected), status.Files)
+	}
+	for i, f := range expected {
+		if status.Files[i] != f {
+			t.Fatalf("expected %+v, got %+v", f, status.Files[i])
+		}
+	}
+
+	diff, err := repo.Diff("HEAD", false, "")
+	if err != nil || len(diff) != 2 {
+		t.Fatalf("expected 2 files in the diff, got %+v (%v)", diff, err)
+	}
+	if f := diff[0]; f.Path != "VERSION" || f.Status != "M" || f.Additions != 1 || f.Deletions != 1 {
+		t.Fatalf("wrong diff of VERSION: %+v", f)
+	}
+	if f := diff[1]; f.Path != "b file.txt" || f.OrigPath != "a file.txt" || f.Status != "R" || f.Additions != 0 {
+		t.Fatalf("wrong diff of the renamed file: %+v", f)
+	}
+
+	if staged, _ := repo.Diff("", true, ""); len(staged) != 1 || staged[0].Status != "R" {
+		t.Fatalf("expected only the rename to be staged, got %+v", staged)
+	}
+
+	hash, err := repo.Commit("Release 1.1", nil, true)
+	if err != nil || len(hash) != 40 {
+		t.Fatalf("expected a commit, got '%s' (%v)", hash, err)
+	}
+
+	commits, _ := repo.Log(0, "", "")
+	if len(commits) != 2 || commits[0].Hash != hash || commits[0].Subject != "Release 1.1" || commits[1].Body != "With a body" || commits[1].Author != "Alex" {
+		t.Fatalf("wrong log: %+v", commits)
+	}
+
+	if commits, _ := repo.Log(1, "", "new.txt"); len(commits) != 0 {
+		t.Fatalf("expected new.txt (untracked) to have no commits, got %+v", commits)
+	}
+
+	if err := repo.CreateBranch("release", true); err != nil {
+		t.Fatal(err)
+	}
+
+	branches, _ := repo.Branches()
+	if len(branches) != 2 || branches[0].Name != "master" || branches[0].Current || !branches[1].Current || branches[1].Hash != hash {
+		t.Fatalf("wrong branches: %+v", branches)
+	}
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	if err := repo.Clone(repo.Dir, clone, "master", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	status, _ = GitRepo{Dir: clone}.Status()
+	if status.Branch != "master" || status.Upstream != "origin/master" || status.Head != hash {
+		t.Fatalf("wrong status of the clone: %+v", status)
+	}
+
+	if err := repo.CreateBranch("release", false); err == nil || err.Error() != "git branch: fatal: a branch named 'release' already exists" {
+		t.Fatalf("expected an error creating an existing branch, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+var httpInterceptorMux sync.RWMutex
+var httpInterceptor func(req *http.Request) (*http.Response, error)
+
+// SetHTTPInterceptor (fn)
+// Gives fn a chance to answer the requests sent through
+// clients from NewHTTPClient before they reach the network,
+// eg. to mock them in tests. When fn returns neither a
+// response nor an error, the request goes through.
+func SetHTTPInterceptor(fn func(req *http.Request) (*http.Response, error)) {
+	httpInterceptorMux.Lock()
+	defer httpInterceptorMux.Unlock()
+
+	httpInterceptor = fn
+}
+
+type interceptingTransport struct{}
+
+func (interceptingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	httpInterceptorMux.RLock()
+	intercept := httpInterceptor
+	httpInterceptorMux.RUnlock()
+
+	if intercept != nil {
+		if res, err := intercept(req); res != nil || err != nil {
+			return res, err
+		}
+	}
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// NewHTTPClient (timeout)
+// Returns the client requests made on behalf of
+// scripts should be sent with, so that they can
+// be intercepted (see SetHTTPInterceptor)
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: interceptingTransport{}}
+}
@@ -0,0 +1,149 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// HumanizeBytes (n, binary)
+// Formats a number of bytes with the largest unit that
+// keeps it above 1, eg. 1.5 MB, or 1.4 MiB with binary
+// (1024-based) units
+func HumanizeBytes(n float64, binary bool) string {
+	base := 1000.0
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB", "EB"}
+	if binary {
+		base = 1024
+		units = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	}
+
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	i := 0
+	for n >= base && i < len(units)-1 {
+		n /= base
+		i++
+	}
+
+	return sign + strconv.FormatFloat(math.Round(n*10)/10, 'f', -1, 64) + " " + units[i]
+}
+
+// Units used by HumanizeDuration, largest first
+var humanDurationUnits = []struct {
+	name string
+	size time.Duration
+}{
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+// HumanizeDuration (d)
+// Formats a duration with its 2 largest units,
+// eg. 2h 5m or 1d 3h, rounding the rest
+func HumanizeDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	if d < time.Second {
+		return sign + strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+	}
+
+	// Rounding might bump the duration to a larger
+	// unit (eg. 59m 59.9s), so we round twice
+	unit := humanDurationUnit(d)
+	if unit < len(humanDurationUnits)-1 {
+		d = d.Round(humanDurationUnits[unit+1].size)
+	} else {
+		d = d.Round(time.Second)
+	}
+	unit = humanDurationUnit(d)
+
+	major := humanDurationUnits[unit]
+	s := fmt.Sprintf("%d%s", d/major.size, major.name)
+
+	if unit < len(humanDurationUnits)-1 {
+		minor := humanDurationUnits[unit+1]
+		if n := (d % major.size) / minor.size; n > 0 {
+			s += fmt.Sprintf(" %d%s", n, minor.name)
+		}
+	}
+
+	return sign + s
+}
+
+func humanDurationUnit(d time.Duration) int {
+	for i, u := range humanDurationUnits {
+		if d >= u.size {
+			return i
+		}
+	}
+
+	return len(humanDurationUnits) - 1
+}
+
+// HumanizeNumber (n)
+// Formats a number with thousands separators,
+// eg. 1,234,567.89
+func HumanizeNumber(n float64) string {
+	return FormatNumber(n, -1, ",", ".")
+}
+
+// Units used by HumanizeTimeAgo, largest first
+var timeAgoUnits = []struct {
+	name string
+	size time.Duration
+}{
+	{"year", 365 * 24 * time.Hour},
+	{"month", 30 * 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour},
+	{"day", 24 * time.Hour},
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+	{"second", time.Second},
+}
+
+// HumanizeTimeAgo (t, now)
+// Describes how long ago t was, relative to now,
+// eg. "5 minutes ago" or "in 2 days"
+func HumanizeTimeAgo(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	if d < 10*time.Second {
+		return "just now"
+	}
+
+	for _, u := range timeAgoUnits {
+		if d < u.size {
+			continue
+		}
+
+		n := int64(d / u.size)
+		s := fmt.Sprintf("%d %s", n, u.name)
+		if n > 1 {
+			s += "s"
+		}
+
+		if future {
+			return "in " + s
+		}
+
+		return s + " ago"
+	}
+
+	return "just now"
+}
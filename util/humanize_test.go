@@ -0,0 +1,92 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanizeBytes(t *testing.T) {
+	tests := []struct {
+		n        float64
+		binary   bool
+		expected string
+	}{
+		{0, false, "0 B"},
+		{999, false, "999 B"},
+		{1500, false, "1.5 KB"},
+		{1500000, false, "1.5 MB"},
+		{1536, true, "1.5 KiB"},
+		{1073741824, true, "1 GiB"},
+		{-2048, true, "-2 KiB"},
+	}
+
+	for _, tt := range tests {
+		if got := HumanizeBytes(tt.n, tt.binary); got != tt.expected {
+			t.Fatalf("expected %s, got %s", tt.expected, got)
+		}
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{250 * time.Millisecond, "250ms"},
+		{1500 * time.Millisecond, "2s"},
+		{42 * time.Second, "42s"},
+		{90 * time.Second, "1m 30s"},
+		{time.Hour, "1h"},
+		{2*time.Hour + 5*time.Minute + 29*time.Second, "2h 5m"},
+		{59*time.Minute + 59*time.Second + 900*time.Millisecond, "1h"},
+		{27 * time.Hour, "1d 3h"},
+		{-90 * time.Second, "-1m 30s"},
+	}
+
+	for _, tt := range tests {
+		if got := HumanizeDuration(tt.d); got != tt.expected {
+			t.Fatalf("expected %s, got %s", tt.expected, got)
+		}
+	}
+}
+
+func TestHumanizeNumber(t *testing.T) {
+	tests := []struct {
+		n        float64
+		expected string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{1234567.89, "1,234,567.89"},
+		{-1234, "-1,234"},
+	}
+
+	for _, tt := range tests {
+		if got := HumanizeNumber(tt.n); got != tt.expected {
+			t.Fatalf("expected %s, got %s", tt.expected, got)
+		}
+	}
+}
+
+func TestHumanizeTimeAgo(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t        time.Time
+		expected string
+	}{
+		{now.Add(-5 * time.Second), "just now"},
+		{now.Add(-30 * time.Second), "30 seconds ago"},
+		{now.Add(-time.Minute), "1 minute ago"},
+		{now.Add(-5 * time.Hour), "5 hours ago"},
+		{now.Add(-10 * 24 * time.Hour), "1 week ago"},
+		{now.Add(-400 * 24 * time.Hour), "1 year ago"},
+		{now.Add(3 * 24 * time.Hour), "in 3 days"},
+	}
+
+	for _, tt := range tests {
+		if got := HumanizeTimeAgo(tt.t, now); got != tt.expected {
+			t.Fatalf("expected %s, got %s", tt.expected, got)
+		}
+	}
+}
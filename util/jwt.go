@@ -0,0 +1,184 @@
+package util
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Algorithms JWTs can be signed with
+var JWTAlgorithms = []string{"HS256", "RS256"}
+
+// SignJWT (claims, key, alg)
+// Encodes the claims into a JWT signed with either a secret
+// (HS256) or a PEM-encoded RSA private key (RS256)
+func SignJWT(claims map[string]interface{}, key string, alg string) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	input := jwtEncode(header) + "." + jwtEncode(payload)
+	var sig []byte
+
+	switch alg {
+	case "HS256":
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(input))
+		sig = mac.Sum(nil)
+	case "RS256":
+		private, err := parseRSAPrivateKey(key)
+		if err != nil {
+			return "", err
+		}
+
+		digest := sha256.Sum256([]byte(input))
+		sig, err = rsa.SignPKCS1v15(rand.Reader, private, crypto.SHA256, digest[:])
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unsupported algorithm '%s' (supported: %s)", alg, strings.Join(JWTAlgorithms, ", "))
+	}
+
+	return input + "." + jwtEncode(sig), nil
+}
+
+// DecodeJWT (token)
+// Decodes the header and claims of a JWT,
+// without verifying its signature
+func DecodeJWT(token string) (map[string]interface{}, map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, fmt.Errorf("invalid token, expected 3 parts separated by dots, got %d", len(parts))
+	}
+
+	header := map[string]interface{}{}
+	claims := map[string]interface{}{}
+
+	for i, v := range []*map[string]interface{}{&header, &claims} {
+		b, err := base64.RawURLEncoding.DecodeString(parts[i])
+		if err == nil {
+			err = json.Unmarshal(b, v)
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid token: %s", err.Error())
+		}
+	}
+
+	return header, claims, nil
+}
+
+// VerifyJWT (token, key, now)
+// Decodes the claims of a JWT, checking its signature against
+// a secret (HS256) or a PEM-encoded RSA public key or certificate
+// (RS256), and that it's not expired (exp) nor used before
+// it's valid (nbf)
+func VerifyJWT(token string, key string, now time.Time) (map[string]interface{}, error) {
+	header, claims, err := DecodeJWT(token)
+	if err != nil {
+		return nil, err
+	}
+
+	i := strings.LastIndex(token, ".")
+	input := token[:i]
+	sig, err := base64.RawURLEncoding.DecodeString(token[i+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	// the algorithm is picked by whoever made the token,
+	// so tokens that aren't signed (alg: none) never pass
+	switch header["alg"] {
+	case "HS256":
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(input))
+
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return nil, fmt.Errorf("signature mismatch")
+		}
+	case "RS256":
+		public, err := parseRSAPublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		digest := sha256.Sum256([]byte(input))
+		if rsa.VerifyPKCS1v15(public, crypto.SHA256, digest[:], sig) != nil {
+			return nil, fmt.Errorf("signature mismatch")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm '%v' (supported: %s)", header["alg"], strings.Join(JWTAlgorithms, ", "))
+	}
+
+	if exp, ok := claims["exp"].(float64); ok && now.Unix() >= int64(exp) {
+		return nil, fmt.Errorf("the token expired at %s", time.Unix(int64(exp), 0).UTC().Format(time.RFC3339))
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Unix() < int64(nbf) {
+		return nil, fmt.Errorf("the token is not valid before %s", time.Unix(int64(nbf), 0).UTC().Format(time.RFC3339))
+	}
+
+	return claims, nil
+}
+
+func jwtEncode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func parseRSAPrivateKey(key string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, fmt.Errorf("invalid key, expected a PEM-encoded RSA private key")
+	}
+
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if private, ok := k.(*rsa.PrivateKey); err == nil && ok {
+		return private, nil
+	}
+
+	return nil, fmt.Errorf("invalid key, expected a PEM-encoded RSA private key")
+}
+
+func parseRSAPublicKey(key string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, fmt.Errorf("invalid key, expected a PEM-encoded RSA public key")
+	}
+
+	var k interface{}
+	var err error
+
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		k, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			k = cert.PublicKey
+		}
+	default:
+		k, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+
+	if public, ok := k.(*rsa.PublicKey); err == nil && ok {
+		return public, nil
+	}
+
+	return nil, fmt.Errorf("invalid key, expected a PEM-encoded RSA public key")
+}
@@ -0,0 +1,283 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale describes how numbers and dates
+// are written in a given language and region
+type Locale struct {
+	Name         string
+	DecimalSep   string
+	ThousandsSep string
+	// Date patterns by style (short, medium, long, full),
+	// see FormatDate for the supported fields
+	Dates  map[string]string
+	Time   string
+	Months []string
+	// Abbreviated month names
+	Mon  []string
+	Days []string
+}
+
+// Locales supported by FormatNumber and FormatDate,
+// in order of preference when looking up a language
+var locales = []*Locale{
+	{
+		Name: "en-US", DecimalSep: ".", ThousandsSep: ",",
+		Dates: map[string]string{
+			"short":  "{M}/{d}/{yyyy}",
+			"medium": "{MMM} {d}, {yyyy}",
+			"long":   "{MMMM} {d}, {yyyy}",
+			"full":   "{EEEE}, {MMMM} {d}, {yyyy}",
+		},
+		Time:   "{h}:{mm} {a}",
+		Months: []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		Mon:    []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Days:   []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	},
+	{
+		Name: "en-GB", DecimalSep: ".", ThousandsSep: ",",
+		Dates: map[string]string{
+			"short":  "{dd}/{MM}/{yyyy}",
+			"medium": "{d} {MMM} {yyyy}",
+			"long":   "{d} {MMMM} {yyyy}",
+			"full":   "{EEEE}, {d} {MMMM} {yyyy}",
+		},
+		Time:   "{HH}:{mm}",
+		Months: []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		Mon:    []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sept", "Oct", "Nov", "Dec"},
+		Days:   []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	},
+	{
+		Name: "de-DE", DecimalSep: ",", ThousandsSep: ".",
+		Dates: map[string]string{
+			"short":  "{dd}.{MM}.{yy}",
+			"medium": "{dd}.{MM}.{yyyy}",
+			"long":   "{d}. {MMMM} {yyyy}",
+			"full":   "{EEEE}, {d}. {MMMM} {yyyy}",
+		},
+		Time:   "{HH}:{mm}",
+		Months: []string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		Mon:    []string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		Days:   []string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+	},
+	{
+		// French uses a narrow no-break space to group digits
+		Name: "fr-FR", DecimalSep: ",", ThousandsSep: "\u202f",
+		Dates: map[string]string{
+			"short":  "{dd}/{MM}/{yyyy}",
+			"medium": "{d} {MMM} {yyyy}",
+			"long":   "{d} {MMMM} {yyyy}",
+			"full":   "{EEEE} {d} {MMMM} {yyyy}",
+		},
+		Time:   "{HH}:{mm}",
+		Months: []string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		Mon:    []string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Days:   []string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+	},
+	{
+		Name: "es-ES", DecimalSep: ",", ThousandsSep: ".",
+		Dates: map[string]string{
+			"short":  "{d}/{M}/{yy}",
+			"medium": "{d} {MMM} {yyyy}",
+			"long":   "{d} de {MMMM} de {yyyy}",
+			"full":   "{EEEE}, {d} de {MMMM} de {yyyy}",
+		},
+		Time:   "{H}:{mm}",
+		Months: []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		Mon:    []string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		Days:   []string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+	},
+	{
+		Name: "it-IT", DecimalSep: ",", ThousandsSep: ".",
+		Dates: map[string]string{
+			"short":  "{dd}/{MM}/{yy}",
+			"medium": "{d} {MMM} {yyyy}",
+			"long":   "{d} {MMMM} {yyyy}",
+			"full":   "{EEEE} {d} {MMMM} {yyyy}",
+		},
+		Time:   "{HH}:{mm}",
+		Months: []string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		Mon:    []string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		Days:   []string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+	},
+	{
+		Name: "nl-NL", DecimalSep: ",", ThousandsSep: ".",
+		Dates: map[string]string{
+			"short":  "{dd}-{MM}-{yyyy}",
+			"medium": "{d} {MMM} {yyyy}",
+			"long":   "{d} {MMMM} {yyyy}",
+			"full":   "{EEEE} {d} {MMMM} {yyyy}",
+		},
+		Time:   "{HH}:{mm}",
+		Months: []string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		Mon:    []string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		Days:   []string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+	},
+	{
+		Name: "pt-BR", DecimalSep: ",", ThousandsSep: ".",
+		Dates: map[string]string{
+			"short":  "{dd}/{MM}/{yyyy}",
+			"medium": "{d} de {MMM} de {yyyy}",
+			"long":   "{d} de {MMMM} de {yyyy}",
+			"full":   "{EEEE}, {d} de {MMMM} de {yyyy}",
+		},
+		Time:   "{HH}:{mm}",
+		Months: []string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		Mon:    []string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		Days:   []string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+	},
+}
+
+// LocaleNames returns the names of the supported locales
+func LocaleNames() []string {
+	names := []string{}
+	for _, l := range locales {
+		names = append(names, l.Name)
+	}
+
+	return names
+}
+
+// LookupLocale (name)
+// Finds a locale by name, accepting both de-DE and
+// de_DE.UTF-8. A language on its own (eg. de) picks
+// the first locale for that language.
+func LookupLocale(name string) (*Locale, bool) {
+	name, _, _ = strings.Cut(name, ".")
+	name = strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+
+	for _, l := range locales {
+		if strings.ToLower(l.Name) == name {
+			return l, true
+		}
+	}
+
+	for _, l := range locales {
+		if lang, _, _ := strings.Cut(strings.ToLower(l.Name), "-"); lang == name {
+			return l, true
+		}
+	}
+
+	return nil, false
+}
+
+// DefaultLocale returns the locale set through the
+// environment (LC_ALL, then LANG), falling back to en-US
+func DefaultLocale() *Locale {
+	for _, v := range []string{"LC_ALL", "LANG"} {
+		if name := os.Getenv(v); name != "" {
+			if l, ok := LookupLocale(name); ok {
+				return l
+			}
+		}
+	}
+
+	return locales[0]
+}
+
+// FormatNumber (n, decimals, thousandsSep, decimalSep)
+// Formats a number with the given separators. With decimals
+// below 0 the number is printed with as many decimals
+// as it needs, otherwise it's rounded.
+func FormatNumber(n float64, decimals int, thousandsSep string, decimalSep string) string {
+	abs := math.Abs(n)
+	if decimals >= 0 {
+		// FormatFloat rounds half to even, while
+		// reports expect 0.5 to be rounded up
+		scale := math.Pow(10, float64(decimals))
+		abs = math.Round(abs*scale) / scale
+	}
+
+	s := strconv.FormatFloat(abs, 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(s, ".")
+
+	out := strings.Builder{}
+	if n < 0 && strings.Trim(s, "0.") != "" {
+		out.WriteString("-")
+	}
+
+	for i, c := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			out.WriteString(thousandsSep)
+		}
+		out.WriteRune(c)
+	}
+
+	if fraction != "" {
+		out.WriteString(decimalSep + fraction)
+	}
+
+	return out.String()
+}
+
+// FormatDate (t, style, withTime)
+// Formats a date in one of the locale's styles (short,
+// medium, long or full), optionally followed by the time.
+// Patterns are made of fields such as {d} or {MMMM}:
+//
+//	{d} {dd}            day, 2 digits with dd
+//	{M} {MM}            month number, 2 digits with MM
+//	{MMM} {MMMM}        abbreviated and full month name
+//	{yy} {yyyy}         year, 2 or 4 digits
+//	{EEEE}              name of the day
+//	{H} {HH} {h} {mm}   24h hour, 12h hour and minutes
+//	{a}                 AM or PM
+func (l *Locale) FormatDate(t time.Time, style string, withTime bool) (string, error) {
+	pattern, ok := l.Dates[style]
+	if !ok {
+		return "", fmt.Errorf("unknown date style '%s' (valid styles are short, medium, long and full)", style)
+	}
+
+	if withTime {
+		pattern += " " + l.Time
+	}
+
+	hour12 := t.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+
+	ampm := "AM"
+	if t.Hour() >= 12 {
+		ampm = "PM"
+	}
+
+	fields := map[string]string{
+		"d":    strconv.Itoa(t.Day()),
+		"dd":   fmt.Sprintf("%02d", t.Day()),
+		"M":    strconv.Itoa(int(t.Month())),
+		"MM":   fmt.Sprintf("%02d", int(t.Month())),
+		"MMM":  l.Mon[t.Month()-1],
+		"MMMM": l.Months[t.Month()-1],
+		"yy":   fmt.Sprintf("%02d", t.Year()%100),
+		"yyyy": strconv.Itoa(t.Year()),
+		"EEEE": l.Days[t.Weekday()],
+		"H":    strconv.Itoa(t.Hour()),
+		"HH":   fmt.Sprintf("%02d", t.Hour()),
+		"h":    strconv.Itoa(hour12),
+		"mm":   fmt.Sprintf("%02d", t.Minute()),
+		"a":    ampm,
+	}
+
+	out := strings.Builder{}
+	for pattern != "" {
+		before, rest, found := strings.Cut(pattern, "{")
+		out.WriteString(before)
+		if !found {
+			break
+		}
+
+		field, after, _ := strings.Cut(rest, "}")
+		out.WriteString(fields[field])
+		pattern = after
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,108 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"de-DE", "de-DE"},
+		{"de_DE.UTF-8", "de-DE"},
+		{"en-gb", "en-GB"},
+		{"en", "en-US"},
+		{"fr", "fr-FR"},
+		{"xx-YY", ""},
+	}
+
+	for _, tt := range tests {
+		l, ok := LookupLocale(tt.name)
+		if tt.expected == "" {
+			if ok {
+				t.Fatalf("expected %s not to be found, got %s", tt.name, l.Name)
+			}
+			continue
+		}
+
+		if !ok || l.Name != tt.expected {
+			t.Fatalf("expected %s to find %s, got %v", tt.name, tt.expected, l)
+		}
+	}
+}
+
+func TestDefaultLocale(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LANG", "it_IT.UTF-8")
+	if l := DefaultLocale(); l.Name != "it-IT" {
+		t.Fatalf("expected it-IT, got %s", l.Name)
+	}
+
+	t.Setenv("LANG", "C")
+	if l := DefaultLocale(); l.Name != "en-US" {
+		t.Fatalf("expected en-US, got %s", l.Name)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		n        float64
+		decimals int
+		thousand string
+		decimal  string
+		expected string
+	}{
+		{1234567.891, -1, ",", ".", "1,234,567.891"},
+		{1234567.891, 2, ".", ",", "1.234.567,89"},
+		{1234.5, 0, ",", ".", "1,235"},
+		{-1234.5, 2, "'", ".", "-1'234.50"},
+		{-0.001, 2, ",", ".", "0.00"},
+		{999, -1, ",", ".", "999"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatNumber(tt.n, tt.decimals, tt.thousand, tt.decimal); got != tt.expected {
+			t.Fatalf("expected %s, got %s", tt.expected, got)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2024, time.March, 5, 14, 7, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale   string
+		style    string
+		withTime bool
+		expected string
+	}{
+		{"en-US", "short", false, "3/5/2024"},
+		{"en-US", "medium", true, "Mar 5, 2024 2:07 PM"},
+		{"en-US", "full", false, "Tuesday, March 5, 2024"},
+		{"en-GB", "short", true, "05/03/2024 14:07"},
+		{"de-DE", "medium", false, "05.03.2024"},
+		{"de-DE", "full", false, "Dienstag, 5. März 2024"},
+		{"fr-FR", "long", false, "5 mars 2024"},
+		{"es-ES", "long", true, "5 de marzo de 2024 14:07"},
+		{"pt-BR", "full", false, "terça-feira, 5 de março de 2024"},
+	}
+
+	for _, tt := range tests {
+		l, _ := LookupLocale(tt.locale)
+		got, err := l.FormatDate(date, tt.style, tt.withTime)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if got != tt.expected {
+			t.Fatalf("expected %s, got %s", tt.expected, got)
+		}
+	}
+
+	l, _ := LookupLocale("en-US")
+	if _, err := l.FormatDate(date, "tiny", false); err == nil {
+		t.Fatalf("expected an error for an unknown style")
+	}
+}
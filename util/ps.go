@@ -0,0 +1,113 @@
+package util
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Process is how processes are listed by ListProcesses:
+// Cpu is the share of a CPU it used since it started (as
+// a percentage, as in ps) and Mem its resident memory,
+// in bytes
+type Process struct {
+	Pid int
+	Cmd string
+	Cpu float64
+	Mem int64
+}
+
+// Reads the output of ps -o pid=,pcpu=,rss=,args=
+// (rss being in kilobytes)
+func parsePs(out string) ([]Process, error) {
+	processes := []Process{}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("cannot read process from ps: '%s'", line)
+		}
+
+		pid, errp := strconv.Atoi(fields[0])
+		cpu, errc := strconv.ParseFloat(fields[1], 64)
+		rss, errm := strconv.ParseInt(fields[2], 10, 64)
+		if errp != nil || errc != nil || errm != nil {
+			return nil, fmt.Errorf("cannot read process from ps: '%s'", line)
+		}
+
+		processes = append(processes, Process{pid, strings.Join(fields[3:], " "), cpu, rss * 1024})
+	}
+
+	return processes, nil
+}
+
+// Reads the output of tasklist /fo csv /nh, eg.
+// "abs.exe","1234","Console","1","12,345 K"
+func parseTasklist(out string) ([]Process, error) {
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read processes from tasklist: %s", err.Error())
+	}
+
+	processes := []Process{}
+	for _, r := range records {
+		if len(r) < 5 {
+			return nil, fmt.Errorf("cannot read process from tasklist: '%s'", strings.Join(r, ","))
+		}
+
+		pid, errp := strconv.Atoi(r[1])
+		// thousands are separated according to the locale
+		kb, errm := strconv.ParseInt(strings.Map(func(c rune) rune {
+			if c >= '0' && c <= '9' {
+				return c
+			}
+			return -1
+		}, r[4]), 10, 64)
+		if errp != nil || errm != nil {
+			return nil, fmt.Errorf("cannot read process from tasklist: '%s'", strings.Join(r, ","))
+		}
+
+		processes = append(processes, Process{pid, r[0], 0, kb * 1024})
+	}
+
+	return processes, nil
+}
+
+// Runs a command listing processes through run, when set
+// (eg. to check or record it), returning its stdout
+func processOutput(c *exec.Cmd, run func(c *exec.Cmd) error) ([]byte, error) {
+	if run == nil {
+		run = func(c *exec.Cmd) error { return c.Run() }
+	}
+
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	if err := run(c); err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			exit.Stderr = stderr.Bytes()
+		}
+
+		return nil, err
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// The error of a command listing processes,
+// with what it wrote to stderr if it failed
+func processError(name string, err error) error {
+	if exit, ok := err.(*exec.ExitError); ok && len(exit.Stderr) > 0 {
+		return fmt.Errorf("%s: %s", name, strings.TrimSpace(string(exit.Stderr)))
+	}
+
+	return fmt.Errorf("%s: %s", name, err.Error())
+}
@@ -0,0 +1,19 @@
+//go:build js
+
+package util
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// In the browser there are no processes to look at
+var errNoProcesses = errors.New("processes cannot be listed in the browser")
+
+func ListProcesses(run func(c *exec.Cmd) error) ([]Process, error) {
+	return nil, errNoProcesses
+}
+
+func ProcessExists(pid int) (bool, error) {
+	return false, errNoProcesses
+}
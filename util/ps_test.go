@@ -0,0 +1,72 @@
+package util
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParsePs(t *testing.T) {
+	out := "    1  0.0  9452 /sbin/init splash\n  812 12.5 20480 abs  script.abs\n   42  0.0     0 \n"
+
+	processes, err := parsePs(out)
+	expected := []Process{
+		{1, "/sbin/init splash", 0, 9452 * 1024},
+		{812, "abs script.abs", 12.5, 20480 * 1024},
+		{42, "", 0, 0},
+	}
+	if err != nil || !reflect.DeepEqual(processes, expected) {
+		t.Fatalf("expected %v, got %v (%v)", expected, processes, err)
+	}
+
+	if _, err := parsePs("abc 0.0 1 init"); err == nil {
+		t.Fatalf("expected an error reading a malformed line")
+	}
+}
+
+func TestParseTasklist(t *testing.T) {
+	out := "\"System Idle Process\",\"0\",\"Services\",\"0\",\"8 K\"\r\n\"abs.exe\",\"1234\",\"Console\",\"1\",\"12,345 K\"\r\n"
+
+	processes, err := parseTasklist(out)
+	expected := []Process{
+		{0, "System Idle Process", 0, 8 * 1024},
+		{1234, "abs.exe", 0, 12345 * 1024},
+	}
+	if err != nil || !reflect.DeepEqual(processes, expected) {
+		t.Fatalf("expected %v, got %v (%v)", expected, processes, err)
+	}
+}
+
+func TestListProcesses(t *testing.T) {
+	processes, err := ListProcesses(nil)
+	if err != nil {
+		t.Skipf("cannot list processes: %s", err.Error())
+	}
+
+	for _, p := range processes {
+		if p.Pid == os.Getpid() {
+			return
+		}
+	}
+
+	t.Fatalf("expected the test's process to be listed")
+}
+
+func TestProcessExists(t *testing.T) {
+	tests := []struct {
+		pid      int
+		expected bool
+	}{
+		{os.Getpid(), true},
+		{0, false},
+		{-1, false},
+		// above the highest pid on Linux
+		{1 << 30, false},
+	}
+
+	for _, tt := range tests {
+		if exists, err := ProcessExists(tt.pid); err != nil || exists != tt.expected {
+			t.Fatalf("%d: expected %v, got %v (%v)", tt.pid, tt.expected, exists, err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+//go:build !windows && !js
+
+package util
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// ListProcesses returns the processes running on
+// the system, as reported by ps: run, when set, runs
+// ps in place of its own Run()
+func ListProcesses(run func(c *exec.Cmd) error) ([]Process, error) {
+	cmd := exec.Command("ps", "-A", "-o", "pid=", "-o", "pcpu=", "-o", "rss=", "-o", "args=")
+	// decimals are separated according to the locale
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+
+	out, err := processOutput(cmd, run)
+	if err != nil {
+		return nil, processError("ps", err)
+	}
+
+	return parsePs(string(out))
+}
+
+// ProcessExists returns whether a process is running,
+// even if it belongs to someone else
+func ProcessExists(pid int) (bool, error) {
+	// 0 and negative pids signal process groups
+	if pid <= 0 {
+		return false, nil
+	}
+
+	err := syscall.Kill(pid, 0)
+	switch {
+	case err == nil, errors.Is(err, syscall.EPERM):
+		return true, nil
+	case errors.Is(err, syscall.ESRCH):
+		return false, nil
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,51 @@
+//go:build windows
+
+package util
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// ListProcesses returns the processes running on the
+// system, as reported by tasklist (which doesn't tell
+// how much CPU they use): run, when set, runs tasklist
+// in place of its own Run()
+func ListProcesses(run func(c *exec.Cmd) error) ([]Process, error) {
+	out, err := processOutput(exec.Command("tasklist", "/fo", "csv", "/nh"), run)
+	if err != nil {
+		return nil, processError("tasklist", err)
+	}
+
+	return parseTasklist(string(out))
+}
+
+// ProcessExists returns whether a process is running,
+// even if it belongs to someone else
+func ProcessExists(pid int) (bool, error) {
+	if pid <= 0 {
+		return false, nil
+	}
+
+	const PROCESS_QUERY_LIMITED_INFORMATION = 0x1000
+	const STILL_ACTIVE = 259
+
+	h, err := syscall.OpenProcess(PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if errors.Is(err, syscall.ERROR_ACCESS_DENIED) {
+		return true, nil
+	}
+	if err != nil {
+		// ERROR_INVALID_PARAMETER: there's no such process
+		return false, nil
+	}
+	defer syscall.CloseHandle(h)
+
+	// exited processes linger while handles to them are open
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false, err
+	}
+
+	return code == STILL_ACTIVE, nil
+}
@@ -0,0 +1,132 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrSecretNotFound is returned when the OS keychain
+// does not hold a secret for the given service / account
+var ErrSecretNotFound = errors.New("secret not found")
+
+// GetSecret (service, account)
+// Reads a secret from the OS keychain: the macOS keychain,
+// the Secret Service (libsecret) on Linux or the Windows
+// Credential Manager
+func GetSecret(service, account string) (string, error) {
+	var c *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "windows":
+		c = powershellSecret(service, account, "", `$c = $v.Retrieve($env:ABS_SECRET_SERVICE, $env:ABS_SECRET_ACCOUNT); $c.RetrievePassword(); Write-Output $c.Password`)
+	default:
+		c = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	}
+
+	out, err := runSecretCommand(c, "")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(out, "\r\n"), nil
+}
+
+// SetSecret (service, account, secret)
+// Stores a secret in the OS keychain, replacing
+// any value that was previously stored
+func SetSecret(service, account, secret string) error {
+	var c *exec.Cmd
+	stdin := ""
+
+	switch runtime.GOOS {
+	case "darwin":
+		// The secret isn't passed as an argument, where
+		// anyone could see it through ps(1): -w, when
+		// last, has security(1) prompt for it (twice,
+		// to confirm it) and read it from stdin instead
+		if strings.ContainsAny(secret, "\r\n") {
+			return errors.New("unable to access the keychain (security): secrets can't span multiple lines")
+		}
+		c = exec.Command("security", "add-generic-password", "-U", "-s", service, "-a", account, "-w")
+		stdin = secret + "\n" + secret + "\n"
+	case "windows":
+		c = powershellSecret(service, account, secret, `$v.Add((New-Object Windows.Security.Credentials.PasswordCredential($env:ABS_SECRET_SERVICE, $env:ABS_SECRET_ACCOUNT, $env:ABS_SECRET_VALUE)))`)
+	default:
+		c = exec.Command("secret-tool", "store", "--label", service+" ("+account+")", "service", service, "account", account)
+		stdin = secret
+	}
+
+	_, err := runSecretCommand(c, stdin)
+	return err
+}
+
+// DeleteSecret (service, account)
+// Removes a secret from the OS keychain
+func DeleteSecret(service, account string) error {
+	var c *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.Command("security", "delete-generic-password", "-s", service, "-a", account)
+	case "windows":
+		c = powershellSecret(service, account, "", `$v.Remove($v.Retrieve($env:ABS_SECRET_SERVICE, $env:ABS_SECRET_ACCOUNT))`)
+	default:
+		c = exec.Command("secret-tool", "clear", "service", service, "account", account)
+	}
+
+	_, err := runSecretCommand(c, "")
+	return err
+}
+
+// Windows doesn't ship a CLI able to read credentials back,
+// so we go through the PasswordVault WinRT API. Values are
+// passed through the environment to avoid quoting issues.
+func powershellSecret(service, account, secret, script string) *exec.Cmd {
+	prelude := `$ErrorActionPreference = 'Stop'; [void][Windows.Security.Credentials.PasswordVault,Windows.Security.Credentials,ContentType=WindowsRuntime]; $v = New-Object Windows.Security.Credentials.PasswordVault; `
+	wrapped := prelude + `try { ` + script + ` } catch { if ($_.Exception.HResult -eq -2147023728) { exit 44 }; Write-Error $_; exit 1 }`
+
+	c := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", wrapped)
+	c.Env = append(os.Environ(), "ABS_SECRET_SERVICE="+service, "ABS_SECRET_ACCOUNT="+account, "ABS_SECRET_VALUE="+secret)
+
+	return c
+}
+
+func runSecretCommand(c *exec.Cmd, stdin string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	if stdin != "" {
+		c.Stdin = strings.NewReader(stdin)
+	}
+
+	err := c.Run()
+
+	if err == nil {
+		return stdout.String(), nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		// security(1) exits with 44 when the item can't be found,
+		// secret-tool(1) exits with 1 and doesn't print anything
+		if code == 44 || (code == 1 && stderr.Len() == 0 && c.Args[0] == "secret-tool") {
+			return "", ErrSecretNotFound
+		}
+	}
+
+	msg := strings.TrimSpace(stderr.String())
+	if msg == "" {
+		msg = err.Error()
+	}
+
+	return "", fmt.Errorf("unable to access the keychain (%s): %s", c.Args[0], msg)
+}
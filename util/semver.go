@@ -0,0 +1,315 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version (https://semver.org),
+// eg. 1.2.3-beta.1+build.5
+type Version struct {
+	Major      int64
+	Minor      int64
+	Patch      int64
+	Prerelease []string
+	Build      string
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+
+	return s
+}
+
+// ParseVersion (s)
+// Parses a semantic version. A leading "v" is accepted,
+// and so are partial versions such as "1.2", where
+// the missing parts are 0
+func ParseVersion(s string) (Version, error) {
+	v, parts, err := parsePartialVersion(s)
+	if err != nil {
+		return v, err
+	}
+
+	for _, p := range parts {
+		if p == "x" || p == "X" || p == "*" {
+			return v, fmt.Errorf("invalid version '%s': wildcards are only allowed in ranges", s)
+		}
+	}
+
+	return v, nil
+}
+
+// Parses a version, returning the parts of
+// its version core (eg. ["1", "x"] for 1.x)
+func parsePartialVersion(s string) (Version, []string, error) {
+	v := Version{}
+	core := strings.TrimPrefix(strings.TrimSpace(s), "v")
+
+	if i := strings.Index(core, "+"); i != -1 {
+		v.Build = core[i+1:]
+		core = core[:i]
+
+		if v.Build == "" {
+			return v, nil, fmt.Errorf("invalid version '%s': empty build metadata", s)
+		}
+	}
+
+	if i := strings.Index(core, "-"); i != -1 {
+		v.Prerelease = strings.Split(core[i+1:], ".")
+		core = core[:i]
+
+		for _, id := range v.Prerelease {
+			if id == "" {
+				return v, nil, fmt.Errorf("invalid version '%s': empty pre-release identifier", s)
+			}
+		}
+	}
+
+	parts := strings.Split(core, ".")
+	if core == "" || len(parts) > 3 {
+		return v, nil, fmt.Errorf("invalid version '%s'", s)
+	}
+
+	numbers := []*int64{&v.Major, &v.Minor, &v.Patch}
+	wildcard := false
+	for i, p := range parts {
+		if p == "x" || p == "X" || p == "*" {
+			wildcard = true
+			continue
+		}
+
+		if wildcard {
+			return v, nil, fmt.Errorf("invalid version '%s': only the last parts can be wildcards", s)
+		}
+
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || n < 0 {
+			return v, nil, fmt.Errorf("invalid version '%s': %s is not a number", s, p)
+		}
+
+		*numbers[i] = n
+	}
+
+	return v, parts, nil
+}
+
+// CompareVersions (a, b)
+// Returns -1, 0 or 1 if a is lower, equal or greater
+// than b, following semver precedence: build metadata
+// is ignored, and pre-releases come before releases
+func CompareVersions(a, b Version) int {
+	for _, c := range [][2]int64{{a.Major, b.Major}, {a.Minor, b.Minor}, {a.Patch, b.Patch}} {
+		if c[0] != c[1] {
+			return compareInts(c[0], c[1])
+		}
+	}
+
+	switch {
+	case len(a.Prerelease) == 0 && len(b.Prerelease) == 0:
+		return 0
+	case len(a.Prerelease) == 0:
+		return 1
+	case len(b.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a.Prerelease) && i < len(b.Prerelease); i++ {
+		x, y := a.Prerelease[i], b.Prerelease[i]
+		nx, errx := strconv.ParseInt(x, 10, 64)
+		ny, erry := strconv.ParseInt(y, 10, 64)
+
+		switch {
+		case errx == nil && erry == nil:
+			if nx != ny {
+				return compareInts(nx, ny)
+			}
+		// numeric identifiers come before alphanumeric ones
+		case errx == nil:
+			return -1
+		case erry == nil:
+			return 1
+		case x != y:
+			return strings.Compare(x, y)
+		}
+	}
+
+	return compareInts(int64(len(a.Prerelease)), int64(len(b.Prerelease)))
+}
+
+func compareInts(a, b int64) int {
+	if a < b {
+		return -1
+	}
+
+	if a > b {
+		return 1
+	}
+
+	return 0
+}
+
+// A comparison such as ">=1.2.0"
+type versionComparator struct {
+	op      string
+	version Version
+}
+
+func (c versionComparator) matches(v Version) bool {
+	cmp := CompareVersions(v, c.version)
+
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+
+	return cmp == 0
+}
+
+// Turns a single constraint, such as "^1.2" or "1.x",
+// into the comparisons a version needs to match
+func parseVersionConstraint(s string) ([]versionComparator, error) {
+	op := ""
+	for _, o := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(s, o) {
+			op = o
+			break
+		}
+	}
+
+	v, parts, err := parsePartialVersion(s[len(op):])
+	if err != nil {
+		return nil, err
+	}
+
+	// How many parts of the version were given,
+	// eg. 2 for 1.2, 1.2.x or 1.2.*
+	given := 0
+	for _, p := range parts {
+		if p == "x" || p == "X" || p == "*" {
+			break
+		}
+		given++
+	}
+
+	if given == 0 {
+		// * or x matches anything
+		return nil, nil
+	}
+
+	lower := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, Prerelease: v.Prerelease}
+	var upper Version
+
+	switch {
+	case op == "^":
+		// the first non-zero part can't change
+		switch {
+		case v.Major > 0 || given == 1:
+			upper = Version{Major: v.Major + 1}
+		case v.Minor > 0 || given == 2:
+			upper = Version{Minor: v.Minor + 1}
+		default:
+			upper = Version{Patch: v.Patch + 1}
+		}
+	case op == "~":
+		// patch updates only, or minor ones if
+		// only the major version is given
+		if given == 1 {
+			upper = Version{Major: v.Major + 1}
+		} else {
+			upper = Version{Major: v.Major, Minor: v.Minor + 1}
+		}
+	case given < 3 && (op == "" || op == "="):
+		// 1.2 is the same as 1.2.x
+		if given == 1 {
+			upper = Version{Major: v.Major + 1}
+		} else {
+			upper = Version{Major: v.Major, Minor: v.Minor + 1}
+		}
+	case given < 3 && (op == ">" || op == "<="):
+		// >1.2 means >=1.3.0, <=1.2 means <1.3.0
+		if given == 1 {
+			lower = Version{Major: v.Major + 1}
+		} else {
+			lower = Version{Major: v.Major, Minor: v.Minor + 1}
+		}
+
+		if op == ">" {
+			return []versionComparator{{">=", lower}}, nil
+		}
+
+		return []versionComparator{{"<", lower}}, nil
+	default:
+		if op == "" {
+			op = "="
+		}
+
+		return []versionComparator{{op, lower}}, nil
+	}
+
+	// pre-releases of the upper bound (eg. 2.0.0-beta for ^1.2)
+	// are excluded, as they come before the bound itself
+	upper.Prerelease = []string{"0"}
+
+	return []versionComparator{{">=", lower}, {"<", upper}}, nil
+}
+
+// SatisfiesVersion (v, constraint)
+// Checks whether v satisfies the constraint, which can use
+// the npm-style operators =, >, >=, <, <=, ^ (compatible
+// with) and ~ (patch updates), as well as wildcards (1.x).
+// Constraints separated by a space must all be satisfied,
+// while || separates alternatives, eg. ">=1.2 <2 || ^3.1".
+func SatisfiesVersion(v Version, constraint string) (bool, error) {
+	for _, alternative := range strings.Split(constraint, "||") {
+		matches := true
+
+		// operators can be separated from their
+		// version, as in ">= 1.2"
+		constraints := []string{}
+		op := ""
+		for _, c := range strings.Fields(alternative) {
+			if strings.Trim(c, "<>=^~") == "" {
+				op = c
+				continue
+			}
+
+			constraints = append(constraints, op+c)
+			op = ""
+		}
+
+		for _, c := range constraints {
+			comparators, err := parseVersionConstraint(c)
+			if err != nil {
+				return false, err
+			}
+
+			for _, comparator := range comparators {
+				if !comparator.matches(v) {
+					matches = false
+				}
+			}
+		}
+
+		if matches {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,112 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{"1.2.3", "1.2.3", ""},
+		{"v1.2.3", "1.2.3", ""},
+		{"1.2", "1.2.0", ""},
+		{"1.2.3-beta.1+build.5", "1.2.3-beta.1+build.5", ""},
+		{"1.2.3+build", "1.2.3+build", ""},
+		{"1.2.3.4", "", "invalid version '1.2.3.4'"},
+		{"1.a.3", "", "invalid version '1.a.3': a is not a number"},
+		{"1.x", "", "invalid version '1.x': wildcards are only allowed in ranges"},
+		{"1.2.3-", "", "invalid version '1.2.3-': empty pre-release identifier"},
+		{"", "", "invalid version ''"},
+	}
+
+	for _, tt := range tests {
+		v, err := ParseVersion(tt.input)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("expected error %s, got %v", tt.err, err)
+			}
+			continue
+		}
+
+		if err != nil || v.String() != tt.expected {
+			t.Fatalf("expected %s, got %s (%v)", tt.expected, v, err)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-rc.1", "1.0.0-beta.11", 1},
+		{"1.0.0+a", "1.0.0+b", 0},
+	}
+
+	for _, tt := range tests {
+		a, _ := ParseVersion(tt.a)
+		b, _ := ParseVersion(tt.b)
+
+		if got := CompareVersions(a, b); got != tt.expected {
+			t.Fatalf("comparing %s and %s: expected %d, got %d", tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
+
+func TestSatisfiesVersion(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		expected   bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"1.2.4", "=1.2.3", false},
+		{"1.5.0", "^1.2", true},
+		{"2.0.0", "^1.2", false},
+		{"2.0.0-beta", "^1.2", false},
+		{"1.2.0", "^1.2.1", false},
+		{"0.2.5", "^0.2.3", true},
+		{"0.3.0", "^0.2.3", false},
+		{"0.0.4", "^0.0.3", false},
+		{"1.2.9", "~1.2.3", true},
+		{"1.3.0", "~1.2.3", false},
+		{"1.9.0", "~1", true},
+		{"1.2.7", "1.2.x", true},
+		{"1.3.0", "1.2", false},
+		{"5.0.0", "*", true},
+		{"1.5.0", ">=1.2 <2", true},
+		{"2.1.0", ">=1.2 <2", false},
+		{"1.5.0", ">= 1.2 < 2", true},
+		{"3.2.0", ">=1.2 <2 || ^3.1", true},
+		{"1.3.0", ">1.2", true},
+		{"1.2.9", ">1.2", false},
+		{"1.2.9", "<=1.2", true},
+		{"1.3.0", "<=1.2", false},
+		{"1.0.0", "<1.0.0", false},
+	}
+
+	for _, tt := range tests {
+		v, _ := ParseVersion(tt.version)
+		got, err := SatisfiesVersion(v, tt.constraint)
+
+		if err != nil || got != tt.expected {
+			t.Fatalf("%s satisfies %s: expected %v, got %v (%v)", tt.version, tt.constraint, tt.expected, got, err)
+		}
+	}
+
+	if _, err := SatisfiesVersion(Version{}, "^1.a"); err == nil {
+		t.Fatalf("expected an error for an invalid constraint")
+	}
+}
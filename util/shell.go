@@ -0,0 +1,205 @@
+package util
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Words a POSIX shell reads as they are,
+// which don't need to be quoted
+var safeShellWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// ShellEscape (s)
+// Quotes s so that a POSIX shell reads it as a single
+// word, with no expansion: quotes within s are closed,
+// escaped with a backslash and reopened
+func ShellEscape(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	if safeShellWord.MatchString(s) {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ShellSplit (s)
+// Splits s into words the way a POSIX shell would,
+// honoring quotes and backslashes but without running
+// any expansion
+func ShellSplit(s string) ([]string, error) {
+	words := []string{}
+	word := strings.Builder{}
+	// whether we are in the middle of a word: ''
+	// is an empty word, not a missing one
+	inWord := false
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\\':
+			if i+1 == len(runes) {
+				return nil, errors.New("trailing backslash")
+			}
+
+			i++
+			// a backslash-newline joins lines
+			if runes[i] != '\n' {
+				word.WriteRune(runes[i])
+				inWord = true
+			}
+		case c == '\'':
+			// nothing is special within single quotes
+			for i++; i < len(runes) && runes[i] != '\''; i++ {
+				word.WriteRune(runes[i])
+			}
+
+			if i == len(runes) {
+				return nil, errors.New("unterminated single quote")
+			}
+
+			inWord = true
+		case c == '"':
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				// within double quotes a backslash only
+				// escapes $, `, ", \ and newlines
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("$`\"\\\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						continue
+					}
+				}
+
+				word.WriteRune(runes[i])
+			}
+
+			if i == len(runes) {
+				return nil, errors.New("unterminated double quote")
+			}
+
+			inWord = true
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// WindowsShellEscape (s)
+// Quotes s so that programs parsing their command line
+// with the Microsoft C runtime rules (CommandLineToArgvW)
+// read it as a single argument
+func WindowsShellEscape(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n\v\"") {
+		return s
+	}
+
+	quoted := strings.Builder{}
+	quoted.WriteRune('"')
+	backslashes := 0
+
+	for _, c := range s {
+		switch c {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			// backslashes before a quote are doubled,
+			// plus one to escape the quote itself
+			quoted.WriteString(strings.Repeat(`\`, backslashes*2+1))
+		default:
+			quoted.WriteString(strings.Repeat(`\`, backslashes))
+		}
+
+		backslashes = 0
+		quoted.WriteRune(c)
+	}
+
+	// the closing quote must not be escaped
+	quoted.WriteString(strings.Repeat(`\`, backslashes*2))
+	quoted.WriteRune('"')
+
+	return quoted.String()
+}
+
+// WindowsShellSplit (s)
+// Splits s into arguments following the Microsoft C
+// runtime rules (CommandLineToArgvW): backslashes are
+// only special before a double quote
+func WindowsShellSplit(s string) []string {
+	args := []string{}
+	arg := strings.Builder{}
+	inArg := false
+	quoted := false
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+
+		switch {
+		case (c == ' ' || c == '\t' || c == '\n') && !quoted:
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case c == '\\':
+			backslashes := 0
+			for ; i < len(runes) && runes[i] == '\\'; i++ {
+				backslashes++
+			}
+
+			if i < len(runes) && runes[i] == '"' {
+				// 2n backslashes are n backslashes and a
+				// quote, 2n+1 are n backslashes and a "
+				arg.WriteString(strings.Repeat(`\`, backslashes/2))
+				if backslashes%2 == 1 {
+					arg.WriteRune('"')
+				} else {
+					i--
+				}
+			} else {
+				arg.WriteString(strings.Repeat(`\`, backslashes))
+				i--
+			}
+
+			inArg = true
+		case c == '"':
+			// "" within quotes is a literal quote
+			if quoted && i+1 < len(runes) && runes[i+1] == '"' {
+				arg.WriteRune('"')
+				i++
+			} else {
+				quoted = !quoted
+			}
+
+			inArg = true
+		default:
+			arg.WriteRune(c)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	return args
+}
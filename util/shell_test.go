@@ -0,0 +1,118 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShellEscape(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"abc", "abc"},
+		{"/tmp/a-b_c.txt", "/tmp/a-b_c.txt"},
+		{"", "''"},
+		{"a b", "'a b'"},
+		{"it's", `'it'\''s'`},
+		{"$(whoami); rm -rf /", "'$(whoami); rm -rf /'"},
+	}
+
+	for _, tt := range tests {
+		if got := ShellEscape(tt.input); got != tt.expected {
+			t.Fatalf("expected %s, got %s", tt.expected, got)
+		}
+	}
+}
+
+func TestShellSplit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+		err      string
+	}{
+		{"a b  c", []string{"a", "b", "c"}, ""},
+		{`echo 'a b' "c d"`, []string{"echo", "a b", "c d"}, ""},
+		{`a\ b`, []string{"a b"}, ""},
+		{`"a \"b\" \x"`, []string{`a "b" \x`}, ""},
+		{`'it'\''s'`, []string{"it's"}, ""},
+		{`'' ""`, []string{"", ""}, ""},
+		{"a \\\nb", []string{"a", "b"}, ""},
+		{"", []string{}, ""},
+		{`'abc`, nil, "unterminated single quote"},
+		{`"abc`, nil, "unterminated double quote"},
+		{`abc\`, nil, "trailing backslash"},
+	}
+
+	for _, tt := range tests {
+		words, err := ShellSplit(tt.input)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("expected error %s, got %v", tt.err, err)
+			}
+			continue
+		}
+
+		if err != nil || strings.Join(words, "|") != strings.Join(tt.expected, "|") || len(words) != len(tt.expected) {
+			t.Fatalf("expected %q, got %q (%v)", tt.expected, words, err)
+		}
+	}
+
+	// escaping and splitting back is lossless
+	for _, s := range []string{"", "a b", "it's", `"\$x`, "tab\tnewline\n"} {
+		words, err := ShellSplit(ShellEscape(s))
+		if err != nil || len(words) != 1 || words[0] != s {
+			t.Fatalf("expected %q, got %q (%v)", s, words, err)
+		}
+	}
+}
+
+func TestWindowsShellEscape(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"abc", "abc"},
+		{`C:\dir\file`, `C:\dir\file`},
+		{"", `""`},
+		{"a b", `"a b"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`C:\my dir\`, `"C:\my dir\\"`},
+		{`a\"b`, `"a\\\"b"`},
+	}
+
+	for _, tt := range tests {
+		if got := WindowsShellEscape(tt.input); got != tt.expected {
+			t.Fatalf("expected %s, got %s", tt.expected, got)
+		}
+	}
+}
+
+func TestWindowsShellSplit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`a b  c`, []string{"a", "b", "c"}},
+		{`"a b" c`, []string{"a b", "c"}},
+		{`C:\dir\file`, []string{`C:\dir\file`}},
+		{`a\\\"b`, []string{`a\"b`}},
+		{`"a\\" b`, []string{`a\`, "b"}},
+		{`"say ""hi"""`, []string{`say "hi"`}},
+		{`""`, []string{""}},
+	}
+
+	for _, tt := range tests {
+		got := WindowsShellSplit(tt.input)
+		if strings.Join(got, "|") != strings.Join(tt.expected, "|") || len(got) != len(tt.expected) {
+			t.Fatalf("expected %q, got %q", tt.expected, got)
+		}
+	}
+
+	for _, s := range []string{"", "a b", `say "hi"`, `C:\my dir\`, `a\"b`} {
+		got := WindowsShellSplit(WindowsShellEscape(s))
+		if len(got) != 1 || got[0] != s {
+			t.Fatalf("expected %q, got %q", s, got)
+		}
+	}
+}
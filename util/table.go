@@ -0,0 +1,139 @@
+package util
+
+import (
+	"strings"
+)
+
+// A column of a text table, spanning
+// from start to end (excluded)
+type tableColumn struct {
+	start int
+	end   int
+}
+
+// Splits column-aligned text, such as the output of
+// `docker ps` or `df -h`, into its header and rows. If
+// headers are given, the text is expected not to have
+// a header line.
+//
+// Columns are separated by the positions that are blank
+// on every line. As names (eg. "CONTAINER ID") or values
+// can contain spaces, columns without a header are merged
+// into the previous one, and so are columns with no values.
+func ParseTable(text string, headers []string) ([]string, [][]string) {
+	lines := []([]rune){}
+	width := 0
+
+	for _, l := range strings.Split(text, "\n") {
+		l = strings.TrimRight(strings.ReplaceAll(l, "\t", "        "), " \r")
+		if l == "" {
+			continue
+		}
+
+		lines = append(lines, []rune(l))
+		width = max(width, len(lines[len(lines)-1]))
+	}
+
+	if len(lines) == 0 {
+		return headers, [][]string{}
+	}
+
+	hasHeader := len(headers) == 0
+	columns := tableColumns(lines, width)
+	if hasHeader {
+		columns = mergeTableColumns(columns, lines[0], lines[1:])
+	} else if len(columns) > len(headers) {
+		// Extra columns belong to the last one
+		columns[len(headers)-1].end = columns[len(columns)-1].end
+		columns = columns[:len(headers)]
+	}
+
+	if hasHeader {
+		for _, c := range columns {
+			headers = append(headers, tableCell(lines[0], c))
+		}
+		lines = lines[1:]
+	}
+
+	rows := [][]string{}
+	for _, l := range lines {
+		row := make([]string, len(headers))
+		for i, c := range columns {
+			row[i] = tableCell(l, c)
+		}
+		rows = append(rows, row)
+	}
+
+	return headers, rows
+}
+
+// Finds the columns, separated by positions
+// that are blank on all lines
+func tableColumns(lines [][]rune, width int) []tableColumn {
+	columns := []tableColumn{}
+	start := -1
+
+	for i := 0; i <= width; i++ {
+		blank := true
+		for _, l := range lines {
+			if i < len(l) && l[i] != ' ' {
+				blank = false
+				break
+			}
+		}
+
+		switch {
+		case !blank && start == -1:
+			start = i
+		case blank && start != -1:
+			columns = append(columns, tableColumn{start, i})
+			start = -1
+		}
+	}
+
+	return columns
+}
+
+// Merges columns without a header, or without values,
+// into the previous one (or the next one, if it's the
+// first column)
+func mergeTableColumns(columns []tableColumn, header []rune, rows [][]rune) []tableColumn {
+	merged := []tableColumn{}
+
+	for _, c := range columns {
+		empty := tableCell(header, c) == ""
+		if !empty && len(rows) > 0 {
+			empty = true
+			for _, r := range rows {
+				if tableCell(r, c) != "" {
+					empty = false
+					break
+				}
+			}
+		}
+
+		if empty && len(merged) > 0 {
+			merged[len(merged)-1].end = c.end
+			continue
+		}
+
+		// A first column without a header is
+		// merged into the next one
+		if len(merged) == 1 && tableCell(header, merged[0]) == "" {
+			merged[0].end = c.end
+			continue
+		}
+
+		merged = append(merged, c)
+	}
+
+	return merged
+}
+
+func tableCell(line []rune, c tableColumn) string {
+	if c.start >= len(line) {
+		return ""
+	}
+
+	return strings.TrimSpace(string(line[c.start:min(c.end, len(line))]))
+}
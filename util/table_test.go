@@ -0,0 +1,65 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTable(t *testing.T) {
+	tests := []struct {
+		text     string
+		headers  []string
+		expected string
+	}{
+		{
+			"NAME                     READY   STATUS    RESTARTS   AGE\n" +
+				"web-7d4b9c8f6d-abcde     1/1     Running   0          5d\n" +
+				"worker-5f6c7d8e9f-xyz    0/1     Pending   3          10m\n",
+			nil,
+			"NAME|READY|STATUS|RESTARTS|AGE\nweb-7d4b9c8f6d-abcde|1/1|Running|0|5d\nworker-5f6c7d8e9f-xyz|0/1|Pending|3|10m",
+		},
+		{
+			"CONTAINER ID   IMAGE     COMMAND                  CREATED       STATUS       PORTS                               NAMES\n" +
+				"4c01db0b339c   nginx     \"/docker-entrypoint.…\"   2 hours ago   Up 2 hours   0.0.0.0:80->80/tcp, :::80->80/tcp   web\n",
+			nil,
+			"CONTAINER ID|IMAGE|COMMAND|CREATED|STATUS|PORTS|NAMES\n4c01db0b339c|nginx|\"/docker-entrypoint.…\"|2 hours ago|Up 2 hours|0.0.0.0:80->80/tcp, :::80->80/tcp|web",
+		},
+		{
+			"Filesystem      Size  Used Avail Use% Mounted on\n" +
+				"/dev/sda1        20G  5.0G   14G  27% /\n" +
+				"tmpfs           7.8G     0  7.8G   0% /dev/shm\n",
+			nil,
+			"Filesystem|Size|Used|Avail|Use%|Mounted on\n/dev/sda1|20G|5.0G|14G|27%|/\ntmpfs|7.8G|0|7.8G|0%|/dev/shm",
+		},
+		{
+			"root   1  0.0 /sbin/init splash\nuser  42  1.5 vim notes.txt\n",
+			[]string{"user", "pid", "cpu", "command"},
+			"user|pid|cpu|command\nroot|1|0.0|/sbin/init splash\nuser|42|1.5|vim notes.txt",
+		},
+		{
+			"A  B\n",
+			nil,
+			"A|B",
+		},
+		{
+			"",
+			nil,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		headers, rows := ParseTable(tt.text, tt.headers)
+		got := []string{}
+		if len(headers) > 0 {
+			got = append(got, strings.Join(headers, "|"))
+		}
+		for _, r := range rows {
+			got = append(got, strings.Join(r, "|"))
+		}
+
+		if strings.Join(got, "\n") != tt.expected {
+			t.Fatalf("expected:\n%s\ngot:\n%s", tt.expected, strings.Join(got, "\n"))
+		}
+	}
+}
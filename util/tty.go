@@ -0,0 +1,55 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// IsTTY (fd)
+// Whether the file descriptor (eg. 1 for stdout) is a terminal
+func IsTTY(fd uintptr) bool {
+	return term.IsTerminal(fd)
+}
+
+// TTYSize (fd, getenv)
+// Returns the width and height of the terminal fd is attached
+// to or, when it isn't (eg. the output is piped), the ones set
+// in COLUMNS and LINES, as shells do
+func TTYSize(fd uintptr, getenv func(string) string) (int, int, error) {
+	if width, height, err := term.GetSize(fd); err == nil {
+		return width, height, nil
+	}
+
+	width, errw := strconv.Atoi(getenv("COLUMNS"))
+	height, errh := strconv.Atoi(getenv("LINES"))
+	if errw != nil || errh != nil {
+		return 0, 0, fmt.Errorf("fd %d is not a terminal, and COLUMNS / LINES aren't set", fd)
+	}
+
+	return width, height, nil
+}
+
+// SupportsColor (fd, getenv)
+// Whether colors can be printed to fd: never with NO_COLOR
+// (https://no-color.org) or a dumb terminal, always with
+// FORCE_COLOR or CLICOLOR_FORCE, and otherwise only if fd
+// is a terminal
+func SupportsColor(fd uintptr, getenv func(string) string) bool {
+	if getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	for _, v := range []string{"FORCE_COLOR", "CLICOLOR_FORCE"} {
+		if value := getenv(v); value != "" && value != "0" {
+			return true
+		}
+	}
+
+	if getenv("TERM") == "dumb" {
+		return false
+	}
+
+	return IsTTY(fd)
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSupportsColor(t *testing.T) {
+	// the output of tests isn't a terminal
+	fd := os.Stdout.Fd()
+	if IsTTY(fd) {
+		t.Skip("stdout is a terminal")
+	}
+
+	tests := []struct {
+		env      map[string]string
+		expected bool
+	}{
+		{map[string]string{}, false},
+		{map[string]string{"FORCE_COLOR": "1"}, true},
+		{map[string]string{"FORCE_COLOR": "0"}, false},
+		{map[string]string{"CLICOLOR_FORCE": "1"}, true},
+		{map[string]string{"FORCE_COLOR": "1", "NO_COLOR": "1"}, false},
+		{map[string]string{"TERM": "dumb"}, false},
+	}
+
+	for _, tt := range tests {
+		if supported := SupportsColor(fd, func(name string) string { return tt.env[name] }); supported != tt.expected {
+			t.Fatalf("%v: expected %v, got %v", tt.env, tt.expected, supported)
+		}
+	}
+}
+
+func TestTTYSize(t *testing.T) {
+	fd := os.Stdout.Fd()
+	if IsTTY(fd) {
+		t.Skip("stdout is a terminal")
+	}
+
+	env := map[string]string{"COLUMNS": "100", "LINES": "30"}
+	if width, height, err := TTYSize(fd, func(name string) string { return env[name] }); err != nil || width != 100 || height != 30 {
+		t.Fatalf("expected the size from COLUMNS and LINES, got %dx%d (%v)", width, height, err)
+	}
+
+	if _, _, err := TTYSize(fd, func(string) string { return "" }); err == nil {
+		t.Fatalf("expected an error without a terminal nor COLUMNS / LINES")
+	}
+}
@@ -171,3 +171,126 @@ func Mapify(list []object.Object) map[string]object.Object {
 
 	return m
 }
+
+// CommandBinaries (cmd)
+// Returns the programs a shell command line would invoke,
+// eg. "FOO=1 ls -la | grep x && $(which git) status" returns
+// ["ls", "grep", "which", "git"]. This is a best-effort
+// parse, not a full shell parser.
+func CommandBinaries(cmd string) []string {
+	binaries := []string{}
+	word := strings.Builder{}
+	// Are we looking for the first
+	// word of a command?
+	expectBinary := true
+	var quote rune
+
+	flush := func() {
+		w := word.String()
+		word.Reset()
+
+		if w == "" || !expectBinary {
+			return
+		}
+
+		// Skip env assignments (FOO=bar ls)
+		// and redirections
+		if strings.Contains(w, "=") && !strings.HasPrefix(w, "=") || strings.ContainsAny(w[:1], "<>") {
+			return
+		}
+
+		binaries = append(binaries, w)
+		expectBinary = false
+	}
+
+	// Command substitutions opened within double quotes
+	// ("$(id)" or "`id`"): they start new commands and,
+	// once closed, we're back inside the quotes
+	type substitution struct {
+		closer rune
+		depth  int
+	}
+	nested := []substitution{}
+	depth := 0
+
+	runes := []rune(cmd)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if quote != 0 {
+			switch {
+			case r == quote:
+				quote = 0
+			case quote == '"' && r == '\\' && i+1 < len(runes):
+				i++
+				word.WriteRune(runes[i])
+			case quote == '"' && r == '`':
+				flush()
+				expectBinary = true
+				quote = 0
+				nested = append(nested, substitution{'`', depth})
+			case quote == '"' && r == '$' && i+1 < len(runes) && runes[i+1] == '(':
+				flush()
+				expectBinary = true
+				quote = 0
+				depth++
+				nested = append(nested, substitution{')', depth})
+				i++
+			default:
+				word.WriteRune(r)
+			}
+			continue
+		}
+
+		// Closing a substitution that was opened
+		// within double quotes
+		if len(nested) > 0 {
+			last := nested[len(nested)-1]
+
+			if r == last.closer && (r == '`' || last.depth == depth) {
+				flush()
+				// What follows is part of a quoted
+				// argument, not a command
+				expectBinary = false
+				quote = '"'
+				nested = nested[:len(nested)-1]
+
+				if r == ')' {
+					depth--
+				}
+				continue
+			}
+		}
+
+		switch {
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '\\' && i+1 < len(runes):
+			i++
+			word.WriteRune(runes[i])
+		case r == ' ' || r == '\t':
+			flush()
+		// Anything that starts a new command:
+		// pipes, lists, subshells
+		case r == '|' || r == '&' || r == ';' || r == '\n' || r == '(' || r == ')' || r == '`' || r == '{' || r == '}':
+			flush()
+			expectBinary = true
+
+			if r == '(' {
+				depth++
+			} else if r == ')' {
+				depth--
+			}
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '(':
+			flush()
+			expectBinary = true
+			depth++
+			i++
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+
+	return binaries
+}
@@ -2,7 +2,9 @@ package util
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/abs-lang/abs/object"
 )
@@ -130,3 +132,56 @@ func TestMapify(t *testing.T) {
 		t.Fatalf("number element not found")
 	}
 }
+
+// Test vectors from the AWS SigV4 test suite
+func TestSignAWSRequest(t *testing.T) {
+	tests := []struct {
+		url       string
+		signature string
+	}{
+		{"https://example.amazonaws.com/", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"https://example.amazonaws.com/?Param2=value2&Param1=value1", "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+	}
+
+	date, _ := time.Parse(awsTimeFormat, "20150830T123600Z")
+	creds := AWSCredentials{AccessKey: "AKIDEXAMPLE", SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+	for _, tt := range tests {
+		headers, err := SignAWSRequest(AWSRequest{Method: "GET", URL: tt.url, Region: "us-east-1", Service: "service", Time: date}, creds)
+
+		if err != nil {
+			t.Fatalf("error signing request: %s", err)
+		}
+
+		expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=" + tt.signature
+		if headers["Authorization"] != expected {
+			t.Fatalf("wrong signature for %s, expected %s, got %s", tt.url, expected, headers["Authorization"])
+		}
+	}
+}
+
+func TestCommandBinaries(t *testing.T) {
+	tests := []struct {
+		cmd      string
+		expected []string
+	}{
+		{"ls", []string{"ls"}},
+		{"ls -la /tmp", []string{"ls"}},
+		{"FOO=1 ls -la | grep 'x | y' && echo done", []string{"ls", "grep", "echo"}},
+		{"/bin/rm -rf x; echo $(which git) `date`", []string{"/bin/rm", "echo", "which", "date"}},
+		{"(cd /tmp && make) || true", []string{"cd", "make", "true"}},
+		{"echo \"a;b\" > out.txt", []string{"echo"}},
+		{"echo \"$(id -u)\"; rm x", []string{"echo", "id", "rm"}},
+		{"echo \"user `whoami` in $(pwd | tr a b) (ok)\"", []string{"echo", "whoami", "pwd", "tr"}},
+		{"echo \"$(echo \"$(id)\")\" done", []string{"echo", "echo", "id"}},
+		{"echo \"\\$(id)\"", []string{"echo"}},
+	}
+
+	for _, tt := range tests {
+		res := CommandBinaries(tt.cmd)
+
+		if strings.Join(res, ",") != strings.Join(tt.expected, ",") {
+			t.Fatalf("wrong binaries for '%s', expected %v, got %v", tt.cmd, tt.expected, res)
+		}
+	}
+}
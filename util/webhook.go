@@ -0,0 +1,124 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// How old a signed webhook can be, by default,
+// before it's considered a replay (both Stripe
+// and Slack recommend 5 minutes)
+const WebhookTolerance = 5 * time.Minute
+
+// VerifyGitHubWebhook (payload, signature, secret)
+// Checks the X-Hub-Signature-256 header of a GitHub
+// webhook (eg. "sha256=..."), or the older X-Hub-Signature
+// one (eg. "sha1=..."), against the HMAC of its payload
+func VerifyGitHubWebhook(payload string, signature string, secret string) error {
+	algorithm, sig, ok := strings.Cut(signature, "=")
+	if !ok {
+		return fmt.Errorf("invalid signature, expected a format like sha256=...")
+	}
+
+	switch algorithm {
+	case "sha256":
+		return verifyHMAC(sha256.New, secret, payload, sig)
+	case "sha1":
+		return verifyHMAC(sha1.New, secret, payload, sig)
+	default:
+		return fmt.Errorf("unsupported signature algorithm '%s'", algorithm)
+	}
+}
+
+// VerifyStripeWebhook (payload, header, secret, tolerance, now)
+// Checks the Stripe-Signature header of a Stripe webhook (eg.
+// "t=1492774577,v1=..."), rejecting the ones signed longer
+// than tolerance ago, so that they can't be replayed
+func VerifyStripeWebhook(payload string, header string, secret string, tolerance time.Duration, now time.Time) error {
+	timestamp := ""
+	signatures := []string{}
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("invalid signature header, expected a format like t=...,v1=...")
+	}
+
+	if err := checkWebhookAge(timestamp, tolerance, now); err != nil {
+		return err
+	}
+
+	// the secret might have been rolled, in which
+	// case the webhook is signed with both keys
+	for _, sig := range signatures {
+		if verifyHMAC(sha256.New, secret, timestamp+"."+payload, sig) == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("signature mismatch")
+}
+
+// VerifySlackWebhook (payload, timestamp, signature, secret, tolerance, now)
+// Checks the X-Slack-Signature header of a Slack request (eg.
+// "v0=..."), given its X-Slack-Request-Timestamp, rejecting
+// the ones signed longer than tolerance ago
+func VerifySlackWebhook(payload string, timestamp string, signature string, secret string, tolerance time.Duration, now time.Time) error {
+	version, sig, ok := strings.Cut(signature, "=")
+	if !ok || version != "v0" {
+		return fmt.Errorf("invalid signature, expected a format like v0=...")
+	}
+
+	if err := checkWebhookAge(timestamp, tolerance, now); err != nil {
+		return err
+	}
+
+	return verifyHMAC(sha256.New, secret, "v0:"+timestamp+":"+payload, sig)
+}
+
+func checkWebhookAge(timestamp string, tolerance time.Duration, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp '%s'", timestamp)
+	}
+
+	if age := now.Sub(time.Unix(seconds, 0)); tolerance > 0 && (age > tolerance || age < -tolerance) {
+		return fmt.Errorf("timestamp outside the tolerance of %s", tolerance)
+	}
+
+	return nil
+}
+
+// Compares, in constant time, the hex-encoded
+// signature with the HMAC of the message
+func verifyHMAC(h func() hash.Hash, secret string, message string, signature string) error {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature, expected a hex digest")
+	}
+
+	mac := hmac.New(h, []byte(secret))
+	mac.Write([]byte(message))
+
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyGitHubWebhook(t *testing.T) {
+	secret := "It's a Secret to Everybody"
+	tests := []struct {
+		signature string
+		err       string
+	}{
+		{"sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", ""},
+		{"sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e18", "signature mismatch"},
+		{"sha256=xyz", "invalid signature, expected a hex digest"},
+		{"md5=abc", "unsupported signature algorithm 'md5'"},
+		{"757107ea", "invalid signature, expected a format like sha256=..."},
+	}
+
+	for _, tt := range tests {
+		err := VerifyGitHubWebhook("Hello, World!", tt.signature, secret)
+		if (tt.err == "" && err != nil) || (tt.err != "" && (err == nil || err.Error() != tt.err)) {
+			t.Fatalf("%s: expected error '%s', got %v", tt.signature, tt.err, err)
+		}
+	}
+}
+
+func TestVerifyStripeWebhook(t *testing.T) {
+	sig := "ff7d36d5bb309a173a3a6972f8281e91d1594106ac01df380439e545a03f2919"
+	now := time.Unix(1700000060, 0)
+	tests := []struct {
+		header string
+		now    time.Time
+		err    string
+	}{
+		{"t=1700000000,v1=" + sig, now, ""},
+		{"t=1700000000,v1=00,v1=" + sig + ",v0=00", now, ""},
+		{"t=1700000000,v1=" + sig, now.Add(10 * time.Minute), "timestamp outside the tolerance of 5m0s"},
+		{"t=1700000001,v1=" + sig, now, "signature mismatch"},
+		{"v1=" + sig, now, "invalid signature header, expected a format like t=...,v1=..."},
+	}
+
+	for _, tt := range tests {
+		err := VerifyStripeWebhook(`{"id": 1}`, tt.header, "whsec_test", WebhookTolerance, tt.now)
+		if (tt.err == "" && err != nil) || (tt.err != "" && (err == nil || err.Error() != tt.err)) {
+			t.Fatalf("%s: expected error '%s', got %v", tt.header, tt.err, err)
+		}
+	}
+}
+
+func TestVerifySlackWebhook(t *testing.T) {
+	secret := "8f742231b10e8888abcd99yyyzzz85a5"
+	sig := "v0=7d1e505747e16b2e9a5cb99318cbd784d12290a7129487637e0b023164e4aafb"
+	now := time.Unix(1531420618, 0)
+
+	if err := VerifySlackWebhook("token=xyz", "1531420618", sig, secret, WebhookTolerance, now); err != nil {
+		t.Fatalf("expected the request to be verified, got %s", err)
+	}
+
+	if err := VerifySlackWebhook("token=abc", "1531420618", sig, secret, WebhookTolerance, now); err == nil {
+		t.Fatalf("expected a tampered request not to be verified")
+	}
+
+	if err := VerifySlackWebhook("token=xyz", "1531420618", sig, secret, WebhookTolerance, now.Add(time.Hour)); err == nil {
+		t.Fatalf("expected an old request not to be verified")
+	}
+}